@@ -1,6 +1,7 @@
 package artifacts
 
 import (
+	"context"
 	"fmt"
 	"path/filepath"
 
@@ -67,7 +68,7 @@ func InitDownload(requireAccessToken func() error, getService func() cli.Service
 			outputFileSet := cmd.Flags().Changed("output-file")
 
 			if taskKeySet {
-				return runDownloadWithTaskKey(svc, args, outputDirSet, outputFileSet, useJsonOutput())
+				return runDownloadWithTaskKey(cmd.Context(), svc, args, outputDirSet, outputFileSet, useJsonOutput())
 			}
 
 			taskID := args[0]
@@ -93,7 +94,7 @@ func InitDownload(requireAccessToken func() error, getService func() cli.Service
 				}
 
 				useJson := useJsonOutput()
-				_, err = svc.DownloadAllArtifacts(cli.DownloadAllArtifactsConfig{
+				_, err = svc.DownloadAllArtifacts(cmd.Context(), cli.DownloadAllArtifactsConfig{
 					TaskID:                 taskID,
 					OutputDir:              absOutputDir,
 					OutputDirExplicitlySet: outputDirSet,
@@ -134,7 +135,7 @@ func InitDownload(requireAccessToken func() error, getService func() cli.Service
 			}
 
 			useJson := useJsonOutput()
-			_, err = svc.DownloadArtifact(cli.DownloadArtifactConfig{
+			_, err = svc.DownloadArtifact(cmd.Context(), cli.DownloadArtifactConfig{
 				TaskID:                 taskID,
 				ArtifactKey:            artifactKey,
 				OutputDir:              absOutputDir,
@@ -159,7 +160,7 @@ func InitDownload(requireAccessToken func() error, getService func() cli.Service
 	DownloadCmd.Flags().StringVar(&downloadTaskKey, "task", "", "task key (e.g., ci.checks.lint); resolves the task by key instead of ID")
 }
 
-func runDownloadWithTaskKey(svc cli.Service, args []string, outputDirSet, outputFileSet bool, useJson bool) error {
+func runDownloadWithTaskKey(ctx context.Context, svc cli.Service, args []string, outputDirSet, outputFileSet bool, useJson bool) error {
 	var runID string
 	var artifactKey string
 	var err error
@@ -172,7 +173,7 @@ func runDownloadWithTaskKey(svc cli.Service, args []string, outputDirSet, output
 		if len(args) > 0 {
 			runID = args[0]
 		} else {
-			runID, err = svc.ResolveRunIDFromGitContext()
+			runID, err = svc.ResolveRunIDFromGitContext(ctx)
 			if err != nil {
 				return err
 			}
@@ -191,7 +192,7 @@ func runDownloadWithTaskKey(svc cli.Service, args []string, outputDirSet, output
 			return errors.Wrapf(err, "unable to resolve absolute path for %s", outputDir)
 		}
 
-		_, err = svc.DownloadAllArtifacts(cli.DownloadAllArtifactsConfig{
+		_, err = svc.DownloadAllArtifacts(ctx, cli.DownloadAllArtifactsConfig{
 			RunID:                  runID,
 			TaskKey:                downloadTaskKey,
 			OutputDir:              absOutputDir,
@@ -212,7 +213,7 @@ func runDownloadWithTaskKey(svc cli.Service, args []string, outputDirSet, output
 		artifactKey = args[1]
 	} else {
 		artifactKey = args[0]
-		runID, err = svc.ResolveRunIDFromGitContext()
+		runID, err = svc.ResolveRunIDFromGitContext(ctx)
 		if err != nil {
 			return err
 		}
@@ -244,7 +245,7 @@ func runDownloadWithTaskKey(svc cli.Service, args []string, outputDirSet, output
 		}
 	}
 
-	_, err = svc.DownloadArtifact(cli.DownloadArtifactConfig{
+	_, err = svc.DownloadArtifact(ctx, cli.DownloadArtifactConfig{
 		RunID:                  runID,
 		TaskKey:                downloadTaskKey,
 		ArtifactKey:            artifactKey,
@@ -266,7 +267,8 @@ func runDownloadWithTaskKey(svc cli.Service, args []string, outputDirSet, output
 func handleTaskKeyError(err error) error {
 	var ambiguousErr *api.AmbiguousTaskKeyError
 	if errors.As(err, &ambiguousErr) {
-		return errors.WrapSentinel(errors.New(ambiguousErr.Error()), errors.ErrAmbiguousTaskKey)
+		ambiguous := errors.WrapSentinel(errors.New(ambiguousErr.Error()), errors.ErrAmbiguousTaskKey)
+		return errors.WithID(ambiguous, "task_key", ambiguousErr.TaskKey)
 	}
 
 	return err
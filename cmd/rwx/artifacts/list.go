@@ -46,7 +46,7 @@ func InitList(requireAccessToken func() error, getService func() cli.Service, us
 				if len(args) > 0 {
 					runID = args[0]
 				} else {
-					runID, err = svc.ResolveRunIDFromGitContext()
+					runID, err = svc.ResolveRunIDFromGitContext(cmd.Context())
 					if err != nil {
 						return err
 					}
@@ -54,7 +54,7 @@ func InitList(requireAccessToken func() error, getService func() cli.Service, us
 				cfg.RunID = runID
 				cfg.TaskKey = listTaskKey
 
-				_, err = svc.ListArtifacts(cfg)
+				_, err = svc.ListArtifacts(cmd.Context(), cfg)
 				if err != nil {
 					return handleTaskKeyError(err)
 				}
@@ -62,7 +62,7 @@ func InitList(requireAccessToken func() error, getService func() cli.Service, us
 			}
 
 			cfg.TaskID = args[0]
-			_, err := svc.ListArtifacts(cfg)
+			_, err := svc.ListArtifacts(cmd.Context(), cfg)
 			return err
 		},
 		Short: "List artifacts for a task",
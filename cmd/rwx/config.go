@@ -0,0 +1,66 @@
+package main
+
+import (
+	"github.com/rwx-cloud/rwx/internal/cli"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	GroupID: "setup",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := service.ListSettings(cli.ListSettingsConfig{Json: useJsonOutput()})
+		return err
+	},
+	Short: "Show and change CLI settings",
+	Long: "Settings are read from the project's .rwx/cli.yml, then ~/.config/rwx/config.yml,\n" +
+		"then environment variables, then flags. Each one overrides the ones before it.\n" +
+		"`rwx config` lists every setting and where its value came from.",
+	Use: "config",
+}
+
+var configListCmd = &cobra.Command{
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := service.ListSettings(cli.ListSettingsConfig{Json: useJsonOutput()})
+		return err
+	},
+	Short: "List every setting and where its value came from",
+	Use:   "list",
+}
+
+var configGetCmd = &cobra.Command{
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		_, err := service.GetSetting(cli.GetSettingConfig{Key: args[0], Json: useJsonOutput()})
+		return err
+	},
+	Short: "Print the value of a setting",
+	Use:   "get <key>",
+}
+
+var (
+	configSetProject bool
+
+	configSetCmd = &cobra.Command{
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return service.SetSetting(cli.SetSettingConfig{
+				Key:     args[0],
+				Value:   args[1],
+				Project: configSetProject,
+			})
+		},
+		Short: "Set a setting in your user config file, or the project's with --project",
+		Use:   "set <key> <value>",
+	}
+)
+
+func init() {
+	configSetCmd.Flags().BoolVar(&configSetProject, "project", false, "write to the project's .rwx/cli.yml instead of ~/.config/rwx/config.yml")
+
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+}
@@ -2,22 +2,97 @@ package main
 
 import (
 	"github.com/rwx-cloud/rwx/internal/cli"
+	"github.com/rwx-cloud/rwx/internal/errors"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	debugRecord    string
+	debugTaskKey   string
+	debugWait      bool
+	debugExec      bool
+	debugPull      string
+	debugOutputDir string
+)
+
 var debugCmd = &cobra.Command{
 	GroupID: "execution",
-	Args:    cobra.ExactArgs(1),
+	Args: func(cmd *cobra.Command, args []string) error {
+		positional, command := splitDebugArgs(cmd, args)
+
+		if debugExec && len(command) == 0 {
+			return errors.New("no command specified. Usage: rwx debug <debugKey> --exec -- <command>")
+		}
+		if !debugExec && len(command) > 0 {
+			return errors.New("a command after -- requires --exec")
+		}
+
+		if cmd.Flags().Changed("task") {
+			if len(positional) > 1 {
+				return errors.New("accepts at most 1 arg (run-id) when --task is used")
+			}
+			return nil
+		}
+		return cobra.ExactArgs(1)(cmd, positional)
+	},
 	PreRunE: func(cmd *cobra.Command, args []string) error {
 		return requireAccessToken()
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return service.DebugTask(cli.DebugTaskConfig{DebugKey: args[0]})
+		positional, command := splitDebugArgs(cmd, args)
+
+		cfg := cli.DebugTaskConfig{
+			Wait:      debugWait,
+			Record:    debugRecord,
+			Command:   command,
+			Pull:      debugPull,
+			OutputDir: debugOutputDir,
+		}
+
+		if !cmd.Flags().Changed("task") {
+			cfg.DebugKey = positional[0]
+			return service.DebugTask(cmd.Context(), cfg)
+		}
+
+		if len(positional) > 0 {
+			cfg.RunID = positional[0]
+		} else {
+			runID, err := service.ResolveRunIDFromGitContext(cmd.Context())
+			if err != nil {
+				return err
+			}
+			cfg.RunID = runID
+		}
+		cfg.TaskKey = debugTaskKey
+
+		return handleTaskKeyError(service.DebugTask(cmd.Context(), cfg))
 	},
 	Short: "Debug a task",
-	Use:   "debug [flags] [debugKey]",
+	Long: `Debug a task that has hit a breakpoint.
+
+By default, an interactive shell is started on the task. Instead, use
+--exec -- <command> to run a single command and exit with its exit code, or
+--pull <remote-path> to copy a file or directory from the task. Neither needs
+a terminal, so they can be used from scripts.`,
+	Use: "debug [flags] [debugKey | run-id --task <key>] [--exec -- <command>]",
+}
+
+// splitDebugArgs splits the arguments into those before and after --.
+func splitDebugArgs(cmd *cobra.Command, args []string) ([]string, []string) {
+	dashIndex := cmd.ArgsLenAtDash()
+	if dashIndex < 0 {
+		return args, nil
+	}
+	return args[:dashIndex], args[dashIndex:]
 }
 
 func init() {
+	debugCmd.Flags().StringVar(&debugTaskKey, "task", "", "task key (e.g., ci.checks.lint); debugs the task with this key in the run")
+	debugCmd.Flags().BoolVar(&debugWait, "wait", false, "wait for the task to hit a breakpoint instead of failing when it isn't debuggable yet")
+	debugCmd.Flags().StringVar(&debugRecord, "record", "", "record the session to this file in asciicast format. Play it back with 'rwx replay'")
+	debugCmd.Flags().BoolVar(&debugExec, "exec", false, "run the command after -- on the task instead of starting a shell, and exit with its exit code")
+	debugCmd.Flags().StringVar(&debugPull, "pull", "", "copy this file or directory from the task instead of starting a shell")
+	debugCmd.Flags().StringVar(&debugOutputDir, "output-dir", "", "directory to copy pulled files into (defaults to the current directory)")
+	debugCmd.MarkFlagsMutuallyExclusive("exec", "pull")
 }
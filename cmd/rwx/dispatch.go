@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -36,7 +35,7 @@ var (
 			}
 
 			useJson := useJsonOutput()
-			dispatchResult, err := service.InitiateDispatch(cli.InitiateDispatchConfig{
+			dispatchResult, err := service.InitiateDispatch(cmd.Context(), cli.InitiateDispatchConfig{
 				DispatchKey: dispatchKey,
 				Params:      params,
 				Json:        useJson,
@@ -55,7 +54,7 @@ var (
 			var runs []cli.GetDispatchRun
 
 			for range ticker.C {
-				runs, err = service.GetDispatch(cli.GetDispatchConfig{DispatchId: dispatchResult.DispatchId})
+				runs, err = service.GetDispatch(cmd.Context(), cli.GetDispatchConfig{DispatchId: dispatchResult.DispatchId})
 				if errors.Is(err, errors.ErrRetry) {
 					continue
 				}
@@ -69,12 +68,9 @@ var (
 			}
 
 			if useJson {
-				dispatchResultJson, err := json.Marshal(runs)
-				if err != nil {
+				if err := printResult(runs); err != nil {
 					return err
 				}
-
-				fmt.Println(string(dispatchResultJson))
 			} else {
 				fmt.Printf("Run is watchable at %s\n", runs[0].RunUrl)
 			}
@@ -94,7 +90,7 @@ var (
 
 				for range ticker.C {
 					stopDebugSpinner()
-					err := service.DebugTask(cli.DebugTaskConfig{DebugKey: runs[0].RunID})
+					err := service.DebugTask(cmd.Context(), cli.DebugTaskConfig{DebugKey: runs[0].RunID})
 					if errors.Is(err, errors.ErrRetry) {
 						stopDebugSpinner = cli.Spin("Waiting for run to hit a breakpoint...", service.StdoutIsTTY, service.Stdout)
 						continue
@@ -1,8 +1,6 @@
 package main
 
 import (
-	"encoding/json"
-	"fmt"
 	"strings"
 
 	"github.com/rwx-cloud/rwx/internal/cli"
@@ -25,7 +23,7 @@ var docsSearchCmd = &cobra.Command{
 		query := strings.Join(args, " ")
 		useJson := useJsonOutput()
 
-		result, err := service.DocsSearch(cli.DocsSearchConfig{
+		result, err := service.DocsSearch(cmd.Context(), cli.DocsSearchConfig{
 			Query:       query,
 			Limit:       docsSearchLimit,
 			Json:        useJson,
@@ -36,11 +34,9 @@ var docsSearchCmd = &cobra.Command{
 		}
 
 		if useJson {
-			jsonOutput, err := json.Marshal(result)
-			if err != nil {
+			if err := printResult(result); err != nil {
 				return err
 			}
-			fmt.Println(string(jsonOutput))
 		}
 
 		return nil
@@ -54,7 +50,7 @@ var docsPullCmd = &cobra.Command{
 	RunE: func(cmd *cobra.Command, args []string) error {
 		useJson := useJsonOutput()
 
-		result, err := service.DocsPull(cli.DocsPullConfig{
+		result, err := service.DocsPull(cmd.Context(), cli.DocsPullConfig{
 			URL:  args[0],
 			Json: useJson,
 		})
@@ -63,11 +59,9 @@ var docsPullCmd = &cobra.Command{
 		}
 
 		if useJson {
-			jsonOutput, err := json.Marshal(result)
-			if err != nil {
+			if err := printResult(result); err != nil {
 				return err
 			}
-			fmt.Println(string(jsonOutput))
 		}
 
 		return nil
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/rwx-cloud/rwx/internal/cli"
+	internalerrors "github.com/rwx-cloud/rwx/internal/errors"
+)
+
+// errorCategory maps a sentinel error to the category reported in telemetry
+// and JSON errors, and to the exit code of the CLI. These exit codes are
+// documented in the README and must stay stable.
+type errorCategory struct {
+	sentinel error
+	name     string
+	exitCode int
+}
+
+// errorCategories are checked in order; the first one that matches is used.
+var errorCategories = []errorCategory{
+	{internalerrors.ErrBadRequest, "bad_request", 2},
+	{internalerrors.ErrNotFound, "not_found", 3},
+	{internalerrors.ErrUnauthorized, "unauthorized", 4},
+	{internalerrors.ErrGone, "gone", 5},
+	{internalerrors.ErrSSH, "ssh_failed", 6},
+	{internalerrors.ErrPatch, "patch_failed", 7},
+	{internalerrors.ErrTimeout, "timeout", 8},
+	{internalerrors.ErrLSP, "lsp_error", 9},
+	{internalerrors.ErrAmbiguousTaskKey, "ambiguous_task_key", 10},
+	{internalerrors.ErrNetworkTransient, "network_transient_error", 11},
+	{context.Canceled, "interrupted", 130},
+}
+
+const unknownErrorExitCode = 1
+
+func classifyError(err error) errorCategory {
+	for _, category := range errorCategories {
+		if errors.Is(err, category.sentinel) {
+			return category
+		}
+	}
+	return errorCategory{name: "unknown", exitCode: unknownErrorExitCode}
+}
+
+// ErrorOutput is written to stdout when a command fails with `--output json`.
+type ErrorOutput struct {
+	Error ErrorDetails `json:"error"`
+}
+
+type ErrorDetails struct {
+	Type     string            `json:"type"`
+	Message  string            `json:"message"`
+	ExitCode int               `json:"exit_code"`
+	Hints    []string          `json:"hints,omitempty"`
+	IDs      map[string]string `json:"ids,omitempty"`
+}
+
+// ExitCode returns the exit code for an error returned by a command.
+func ExitCode(err error) int {
+	var exitErr *cli.ExitCodeError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return classifyError(err).exitCode
+}
+
+// ReportError writes a failed command's error as JSON to stdout, or as text
+// to stderr. Handled errors and exit codes from remote commands have already
+// been reported, so nothing is written for them.
+func ReportError(stdout, stderr io.Writer, err error, jsonOutput, debug bool) {
+	if errors.Is(err, HandledError) || errors.Is(err, &cli.ExitCodeError{}) {
+		return
+	}
+
+	hints := internalerrors.Hints(err)
+
+	if jsonOutput {
+		category := classifyError(err)
+		encoded, encodeErr := json.Marshal(ErrorOutput{Error: ErrorDetails{
+			Type:     category.name,
+			Message:  err.Error(),
+			ExitCode: category.exitCode,
+			Hints:    hints,
+			IDs:      internalerrors.IDs(err),
+		}})
+		if encodeErr == nil {
+			fmt.Fprintln(stdout, string(encoded))
+			return
+		}
+	}
+
+	if debug {
+		// Enabling debug output will print stacktraces
+		fmt.Fprintf(stderr, "Error: %+v\n", err)
+	} else {
+		fmt.Fprintf(stderr, "Error: %s\n", err)
+	}
+	for _, hint := range hints {
+		fmt.Fprintf(stderr, "\n%s\n", hint)
+	}
+}
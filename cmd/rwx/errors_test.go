@@ -0,0 +1,100 @@
+package main_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	rwx "github.com/rwx-cloud/rwx/cmd/rwx"
+	"github.com/rwx-cloud/rwx/internal/api"
+	"github.com/rwx-cloud/rwx/internal/cli"
+	"github.com/rwx-cloud/rwx/internal/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCode(t *testing.T) {
+	t.Run("should use the exit code of the category", func(t *testing.T) {
+		err := errors.Wrap(errors.WrapSentinel(fmt.Errorf("Task abc not found"), errors.ErrNotFound), "unable to fetch logs")
+		require.Equal(t, 3, rwx.ExitCode(err))
+		require.Equal(t, 8, rwx.ExitCode(errors.WrapSentinel(fmt.Errorf("command timed out"), errors.ErrTimeout)))
+	})
+
+	t.Run("should exit with 4 when the API rejects the access token", func(t *testing.T) {
+		c := api.NewClientWithRoundTrip(func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Status:     "401 Unauthorized",
+				StatusCode: http.StatusUnauthorized,
+				Body:       io.NopCloser(strings.NewReader(`{"error":"Unauthorized"}`)),
+			}, nil
+		})
+
+		_, err := c.SetSecretsInVault(t.Context(), api.SetSecretsInVaultConfig{VaultName: "default"})
+		require.Equal(t, 4, rwx.ExitCode(errors.Wrap(err, "unable to set secrets")))
+
+		_, err = c.ListSandboxRuns(t.Context())
+		require.Equal(t, 4, rwx.ExitCode(errors.Wrap(err, "unable to list sandbox runs")))
+	})
+
+	t.Run("should exit with 130 when interrupted", func(t *testing.T) {
+		require.Equal(t, 130, rwx.ExitCode(errors.Wrap(context.Canceled, "unable to get run status")))
+	})
+
+	t.Run("should exit with 1 for other errors", func(t *testing.T) {
+		require.Equal(t, 1, rwx.ExitCode(errors.New("boom")))
+		require.Equal(t, 1, rwx.ExitCode(rwx.HandledError))
+	})
+
+	t.Run("should use the exit code of a remote command", func(t *testing.T) {
+		require.Equal(t, 42, rwx.ExitCode(&cli.ExitCodeError{Code: 42}))
+	})
+}
+
+func TestReportError(t *testing.T) {
+	err := errors.WrapSentinel(fmt.Errorf("Job 'abc' not found in sandbox."), errors.ErrNotFound)
+	err = errors.WithID(errors.WithHint(err, "Use 'rwx sandbox jobs' to see available jobs."), "job_id", "abc")
+
+	t.Run("should write a JSON error to stdout", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		rwx.ReportError(&stdout, &stderr, err, true, false)
+
+		var output rwx.ErrorOutput
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &output))
+		require.Equal(t, rwx.ErrorDetails{
+			Type:     "not_found",
+			Message:  "Job 'abc' not found in sandbox.",
+			ExitCode: 3,
+			Hints:    []string{"Use 'rwx sandbox jobs' to see available jobs."},
+			IDs:      map[string]string{"job_id": "abc"},
+		}, output.Error)
+		require.Empty(t, stderr.String())
+	})
+
+	t.Run("should write the error and hints to stderr as text", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		rwx.ReportError(&stdout, &stderr, err, false, false)
+
+		require.Empty(t, stdout.String())
+		require.Equal(t, "Error: Job 'abc' not found in sandbox.\n\nUse 'rwx sandbox jobs' to see available jobs.\n", stderr.String())
+	})
+
+	t.Run("should classify unknown errors", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		rwx.ReportError(&stdout, &stderr, errors.New("boom"), true, false)
+
+		require.JSONEq(t, `{"error":{"type":"unknown","message":"boom","exit_code":1}}`, stdout.String())
+	})
+
+	t.Run("should not report handled errors or remote exit codes", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		rwx.ReportError(&stdout, &stderr, rwx.HandledError, true, false)
+		rwx.ReportError(&stdout, &stderr, &cli.ExitCodeError{Code: 2}, false, false)
+
+		require.Empty(t, stdout.String())
+		require.Empty(t, stderr.String())
+	})
+}
@@ -73,7 +73,7 @@ func InitBuild(requireAccessToken func() error, parseInitParameters func([]strin
 				OutputJSON:       useJsonOutput(),
 			}
 
-			_, err = getService().ImageBuild(config)
+			_, err = getService().ImageBuild(cmd.Context(), config)
 			return err
 		},
 		Short: "Launch a targeted RWX run and pull its result as an OCI image",
@@ -30,7 +30,7 @@ func InitPull(requireAccessToken func() error, getService func() cli.Service, us
 				OutputJSON: useJsonOutput(),
 			}
 
-			_, err := getService().ImagePull(config)
+			_, err := getService().ImagePull(cmd.Context(), config)
 			return err
 		},
 		Short: "Pull an existing RWX task as an OCI image",
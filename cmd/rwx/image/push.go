@@ -33,7 +33,7 @@ func InitPush(requireAccessToken func() error, getService func() cli.Service, us
 				return err
 			}
 
-			_, err = getService().ImagePush(config)
+			_, err = getService().ImagePush(cmd.Context(), config)
 			return err
 		},
 		Short: "Push an RWX task to an OCI reference",
@@ -49,9 +49,11 @@ var (
 			}
 
 			err := service.Login(
+				cmd.Context(),
 				cli.LoginConfig{
 					DeviceName:         DeviceName,
 					AccessTokenBackend: accessTokenBackend,
+					Profile:            service.Profile,
 					OpenUrl:            openUrl,
 					PollInterval:       1 * time.Second,
 				},
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"github.com/rwx-cloud/rwx/internal/cli"
+	"github.com/rwx-cloud/rwx/internal/errors"
+
+	"github.com/spf13/cobra"
+)
+
+var logoutCmd = &cobra.Command{
+	GroupID: "setup",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if AccessToken != "" {
+			return errors.New("`rwx logout` only removes the access token stored by `rwx login`, but an access token was provided with --access-token or RWX_ACCESS_TOKEN. Unset it and try again.")
+		}
+
+		return service.Logout(cmd.Context(), cli.LogoutConfig{
+			AccessTokenBackend: accessTokenBackend,
+		})
+	},
+	Short: "Revoke the access token on this device and remove it, along with cached tokens",
+	Use:   "logout [flags]",
+}
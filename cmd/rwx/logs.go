@@ -83,7 +83,7 @@ var (
 				if len(args) > 0 {
 					runID = args[0]
 				} else {
-					runID, err = service.ResolveRunIDFromGitContext()
+					runID, err = service.ResolveRunIDFromGitContext(cmd.Context())
 					if err != nil {
 						return err
 					}
@@ -91,7 +91,7 @@ var (
 				cfg.RunID = runID
 				cfg.TaskKey = LogsTaskKey
 
-				_, err = service.DownloadLogs(cfg)
+				_, err = service.DownloadLogs(cmd.Context(), cfg)
 				if err != nil {
 					return handleTaskKeyError(err)
 				}
@@ -99,7 +99,7 @@ var (
 			}
 
 			cfg.TaskID = args[0]
-			_, err = service.DownloadLogs(cfg)
+			_, err = service.DownloadLogs(cmd.Context(), cfg)
 			return err
 		},
 		Short: "Download logs for a task",
@@ -125,7 +125,8 @@ func init() {
 func handleTaskKeyError(err error) error {
 	var ambiguousErr *api.AmbiguousTaskKeyError
 	if errors.As(err, &ambiguousErr) {
-		return errors.WrapSentinel(errors.New(ambiguousErr.Error()), errors.ErrAmbiguousTaskKey)
+		ambiguous := errors.WrapSentinel(errors.New(ambiguousErr.Error()), errors.ErrAmbiguousTaskKey)
+		return errors.WithID(ambiguous, "task_key", ambiguousErr.TaskKey)
 	}
 
 	return err
@@ -1,14 +1,15 @@
 package main
 
 import (
+	"context"
 	"errors"
-	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/rwx-cloud/rwx/internal/cli"
-	internalerrors "github.com/rwx-cloud/rwx/internal/errors"
 	"github.com/spf13/pflag"
 )
 
@@ -18,7 +19,17 @@ var HandledError = cli.HandledError
 
 func main() {
 	start := time.Now()
-	err := rootCmd.Execute()
+
+	// Interrupts cancel the command's context so that in-flight requests and
+	// polling stop and cleanup still runs. A second interrupt exits at once.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
 
 	recordTelemetry(err, start)
 
@@ -26,21 +37,8 @@ func main() {
 		return
 	}
 
-	var exitErr *cli.ExitCodeError
-	if errors.As(err, &exitErr) {
-		os.Exit(exitErr.Code)
-	}
-
-	if !errors.Is(err, HandledError) {
-		if Debug {
-			// Enabling debug output will print stacktraces
-			fmt.Fprintf(os.Stderr, "Error: %+v\n", err)
-		} else {
-			fmt.Fprintf(os.Stderr, "Error: %s\n", err)
-		}
-	}
-
-	os.Exit(1)
+	ReportError(os.Stdout, os.Stderr, err, useJsonOutput(), Debug)
+	os.Exit(ExitCode(err))
 }
 
 func recordTelemetry(err error, start time.Time) {
@@ -76,35 +74,10 @@ func recordTelemetry(err error, start time.Time) {
 		telem.Record("cli.error", map[string]any{
 			"command":    commandName,
 			"flags":      flagNames,
-			"error_type": classifyError(err),
+			"error_type": classifyError(err).name,
 			"handled":    errors.Is(err, HandledError),
 		})
 	}
 
 	telem.Flush()
 }
-
-func classifyError(err error) string {
-	switch {
-	case errors.Is(err, internalerrors.ErrBadRequest):
-		return "bad_request"
-	case errors.Is(err, internalerrors.ErrNotFound):
-		return "not_found"
-	case errors.Is(err, internalerrors.ErrGone):
-		return "gone"
-	case errors.Is(err, internalerrors.ErrSSH):
-		return "ssh_failed"
-	case errors.Is(err, internalerrors.ErrPatch):
-		return "patch_failed"
-	case errors.Is(err, internalerrors.ErrTimeout):
-		return "timeout"
-	case errors.Is(err, internalerrors.ErrLSP):
-		return "lsp_error"
-	case errors.Is(err, internalerrors.ErrAmbiguousTaskKey):
-		return "ambiguous_task_key"
-	case errors.Is(err, internalerrors.ErrNetworkTransient):
-		return "network_transient_error"
-	default:
-		return "unknown"
-	}
-}
@@ -18,7 +18,7 @@ var (
 
 	packagesListCmd = &cobra.Command{
 		RunE: func(cmd *cobra.Command, args []string) error {
-			_, err := service.ListPackages(cli.ListPackagesConfig{
+			_, err := service.ListPackages(cmd.Context(), cli.ListPackagesConfig{
 				Json: useJsonOutput(),
 			})
 			return err
@@ -31,7 +31,7 @@ var (
 	packagesShowCmd = &cobra.Command{
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			_, err := service.ShowPackage(cli.ShowPackageConfig{
+			_, err := service.ShowPackage(cmd.Context(), cli.ShowPackageConfig{
 				PackageName: args[0],
 				Json:        useJsonOutput(),
 				NoReadme:    PackagesShowNoReadme,
@@ -50,7 +50,7 @@ var (
 			}
 
 			useJson := useJsonOutput()
-			_, err := service.UpdatePackages(cli.UpdatePackagesConfig{
+			_, err := service.UpdatePackages(cmd.Context(), cli.UpdatePackagesConfig{
 				Files:                    args,
 				RwxDirectory:             RwxDirectory,
 				ReplacementVersionPicker: replacementVersionPicker,
@@ -4,6 +4,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/rwx-cloud/rwx/internal/cli"
 	"github.com/rwx-cloud/rwx/internal/errors"
 )
 
@@ -51,3 +52,27 @@ func ParseInitParameters(params []string) (map[string]string, error) {
 
 	return parsedParams, nil
 }
+
+// ParseBreakpoints converts a list of `task-key[:before|after]` breakpoints to a map of task keys to the position
+// of the breakpoint. The position defaults to `before`.
+func ParseBreakpoints(breakpoints []string) (map[string]string, error) {
+	parsed := make(map[string]string)
+
+	for _, breakpoint := range breakpoints {
+		key, position, found := strings.Cut(breakpoint, ":")
+		if !found {
+			position = cli.BreakpointBefore
+		}
+
+		if key == "" {
+			return nil, errors.Errorf("unable to parse breakpoint %q: missing task key", breakpoint)
+		}
+		if position != cli.BreakpointBefore && position != cli.BreakpointAfter {
+			return nil, errors.Errorf("unable to parse breakpoint %q: position must be %q or %q", breakpoint, cli.BreakpointBefore, cli.BreakpointAfter)
+		}
+
+		parsed[key] = position
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"github.com/rwx-cloud/rwx/internal/cli"
+	"github.com/spf13/cobra"
+)
+
+var profilesCmd = &cobra.Command{
+	GroupID: "setup",
+	Short:   "Manage profiles for multiple hosts and organizations",
+	Long: "Profiles keep a separate host, access token, and organization under a name.\n" +
+		"Create or refresh one with `rwx login --profile <name>`, and pick one for a single\n" +
+		"command with `--profile <name>` or the RWX_PROFILE environment variable.",
+	Use: "profiles",
+}
+
+var profilesListCmd = &cobra.Command{
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		useJson := useJsonOutput()
+		result, err := service.ListProfiles(cli.ListProfilesConfig{Json: useJson})
+		if err != nil {
+			return err
+		}
+
+		if useJson {
+			if err := printResult(result); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+	Short: "List profiles",
+	Use:   "list",
+}
+
+var profilesUseCmd = &cobra.Command{
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return service.UseProfile(args[0])
+	},
+	Short: "Use a profile by default",
+	Use:   "use <name>",
+}
+
+var profilesRemoveCmd = &cobra.Command{
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return service.RemoveProfile(args[0])
+	},
+	Short: "Remove a profile and its access token",
+	Use:   "remove <name>",
+}
+
+func init() {
+	profilesCmd.AddCommand(profilesListCmd)
+	profilesCmd.AddCommand(profilesUseCmd)
+	profilesCmd.AddCommand(profilesRemoveCmd)
+}
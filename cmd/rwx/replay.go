@@ -0,0 +1,38 @@
+package main
+
+import (
+	"time"
+
+	"github.com/rwx-cloud/rwx/internal/cli"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	replaySpeed         float64
+	replayIdleTimeLimit time.Duration
+
+	replayCmd = &cobra.Command{
+		GroupID: "execution",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return service.Replay(cli.ReplayConfig{
+				File:          args[0],
+				Speed:         replaySpeed,
+				IdleTimeLimit: replayIdleTimeLimit,
+			})
+		},
+		Short: "Play back a recorded session",
+		Long: `Play back a session recorded with 'rwx debug --record' or
+'rwx sandbox exec --record'.
+
+Recordings use the asciicast v2 format, so they can also be played with
+asciinema or shared with any asciinema player.`,
+		Use: "replay [flags] <file>",
+	}
+)
+
+func init() {
+	replayCmd.Flags().Float64Var(&replaySpeed, "speed", 1, "Playback speed multiplier")
+	replayCmd.Flags().DurationVar(&replayIdleTimeLimit, "idle-time-limit", 0, "Limit pauses between output to this duration (e.g. 2s)")
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"fmt"
+
 	"github.com/rwx-cloud/rwx/internal/accesstoken"
 	"github.com/rwx-cloud/rwx/internal/errors"
 )
@@ -11,11 +13,26 @@ func requireAccessToken() error {
 		return nil
 	}
 
-	return errors.New(
-		"You're trying to use a command which requires authentication with RWX Cloud, " +
-			"but you do not have an access token configured.\n\n" +
-			"To use this command, you can authenticate with RWX Cloud via the `rwx login` command, or " +
-			"you can supply the `--access-token` option or `RWX_ACCESS_TOKEN` environment variable.\n\n" +
-			"Once you do so, go ahead and run the command again.",
+	if service.Profile != "" {
+		err := errors.WrapSentinel(fmt.Errorf(
+			"You're trying to use a command which requires authentication with RWX Cloud, "+
+				"but the %q profile does not have an access token.",
+			service.Profile,
+		), errors.ErrUnauthorized)
+		return errors.WithHint(err, fmt.Sprintf(
+			"To use this command, log in to the profile with `rwx login --profile %s`, or "+
+				"choose another profile with `--profile` or `rwx profiles use`.",
+			service.Profile,
+		))
+	}
+
+	err = errors.WrapSentinel(errors.New(
+		"You're trying to use a command which requires authentication with RWX Cloud, "+
+			"but you do not have an access token configured.",
+	), errors.ErrUnauthorized)
+	err = errors.WithHint(err,
+		"To use this command, you can authenticate with RWX Cloud via the `rwx login` command, or "+
+			"you can supply the `--access-token` option or `RWX_ACCESS_TOKEN` environment variable.",
 	)
+	return errors.WithHint(err, "Once you do so, go ahead and run the command again.")
 }
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/rwx-cloud/rwx/internal/cli"
@@ -15,24 +16,24 @@ var resolveCmd = &cobra.Command{
 		if len(args) > 0 {
 			switch args[0] {
 			case "base":
-				return resolveBase(args[1:])
+				return resolveBase(cmd.Context(), args[1:])
 			case "packages":
-				return resolvePackages(args[1:])
+				return resolvePackages(cmd.Context(), args[1:])
 			}
 		}
 
-		err := resolveBase(args)
+		err := resolveBase(cmd.Context(), args)
 		if err != nil {
 			return err
 		}
-		return resolvePackages(args)
+		return resolvePackages(cmd.Context(), args)
 	},
 }
 
 var (
 	resolveBaseCmd = &cobra.Command{
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return resolveBase(args)
+			return resolveBase(cmd.Context(), args)
 		},
 		Short: "Add a base image to RWX run configurations that do not have one",
 		Long: "Add a base image to RWX run configurations that do not have one.\n" +
@@ -42,7 +43,7 @@ var (
 
 	resolvePackagesCmd = &cobra.Command{
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return resolvePackages(args)
+			return resolvePackages(cmd.Context(), args)
 		},
 		Short: "Add the latest version to all package invocations that do not have one",
 		Long: "Add the latest version to all package invocations that do not have one.\n" +
@@ -52,9 +53,9 @@ var (
 	}
 )
 
-func resolveBase(files []string) error {
+func resolveBase(ctx context.Context, files []string) error {
 	useJson := useJsonOutput()
-	base, err := service.InsertBase(cli.InsertBaseConfig{
+	base, err := service.InsertBase(ctx, cli.InsertBaseConfig{
 		Files:        files,
 		RwxDirectory: RwxDirectory,
 		Json:         useJson,
@@ -68,9 +69,9 @@ func resolveBase(files []string) error {
 	return nil
 }
 
-func resolvePackages(files []string) error {
+func resolvePackages(ctx context.Context, files []string) error {
 	useJson := useJsonOutput()
-	_, err := service.ResolvePackages(cli.ResolvePackagesConfig{
+	_, err := service.ResolvePackages(ctx, cli.ResolvePackagesConfig{
 		Files:               files,
 		RwxDirectory:        RwxDirectory,
 		LatestVersionPicker: cli.PickLatestMajorVersion,
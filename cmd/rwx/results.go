@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 
 	"github.com/rwx-cloud/rwx/internal/cli"
@@ -34,7 +33,7 @@ var (
 				runID = args[0]
 			} else {
 				var err error
-				runID, err = service.ResolveRunIDFromGitContext(cli.ResolveRunIDConfig{
+				runID, err = service.ResolveRunIDFromGitContext(cmd.Context(), cli.ResolveRunIDConfig{
 					BranchName:     ResultsBranch,
 					RepositoryName: ResultsRepo,
 					DefinitionPath: ResultsDefinition,
@@ -45,7 +44,7 @@ var (
 				runIDFromGit = true
 			}
 
-			result, err := service.GetRunStatus(cli.GetRunStatusConfig{
+			result, err := service.GetRunStatus(cmd.Context(), cli.GetRunStatusConfig{
 				RunID:    runID,
 				Wait:     ResultsWait,
 				FailFast: ResultsFailFast,
@@ -65,11 +64,9 @@ var (
 					ResultStatus: result.ResultStatus,
 					Completed:    result.Completed,
 				}
-				resultJson, err := json.Marshal(jsonOutput)
-				if err != nil {
+				if err := printResult(jsonOutput); err != nil {
 					return err
 				}
-				fmt.Println(string(resultJson))
 			} else {
 				if runIDFromGit && ResultsBranch == "" && ResultsRepo == "" && result.Commit != "" {
 					if head := service.GitClient.GetHead(); head != "" {
@@ -87,7 +84,7 @@ var (
 					fmt.Printf("Run status: %s (in progress)\n", result.ResultStatus)
 				}
 
-				promptResult, err := service.GetRunPrompt(result.RunID)
+				promptResult, err := service.GetRunPrompt(cmd.Context(), result.RunID)
 				if err == nil {
 					fmt.Printf("\n%s", promptResult.Prompt)
 				}
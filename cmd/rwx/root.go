@@ -1,8 +1,13 @@
 package main
 
 import (
+	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/rwx-cloud/rwx/cmd/rwx/config"
 	"github.com/rwx-cloud/rwx/internal/accesstoken"
@@ -14,19 +19,32 @@ import (
 	"github.com/rwx-cloud/rwx/internal/docstoken"
 	"github.com/rwx-cloud/rwx/internal/errors"
 	"github.com/rwx-cloud/rwx/internal/git"
+	"github.com/rwx-cloud/rwx/internal/httplog"
+	"github.com/rwx-cloud/rwx/internal/output"
+	"github.com/rwx-cloud/rwx/internal/profiles"
 	"github.com/rwx-cloud/rwx/internal/retry"
 	"github.com/rwx-cloud/rwx/internal/ssh"
 	"github.com/rwx-cloud/rwx/internal/telemetry"
+	"github.com/rwx-cloud/rwx/internal/transport"
 	"github.com/rwx-cloud/rwx/internal/versions"
 	"golang.org/x/term"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
 	AccessToken string
 	Json        bool
 	Output      string
+	Template    string
+	JQ          string
+
+	caCert     string
+	clientCert string
+	clientKey  string
+	profile    string
+	verbose    bool
 
 	rwxHost            string
 	docsHost           = "www.rwx.com"
@@ -50,25 +68,92 @@ var (
 			if err != nil {
 				return errors.Wrap(err, "unable to initialize config backend")
 			}
-			accessTokenBackend = accesstoken.NewFileBackend(fileBackend)
+			settings, err := internalconfig.LoadLayered(
+				cli.FindProjectConfigFile(),
+				filepath.Join(fileBackend.PrimaryDirectory, internalconfig.UserFilename),
+				os.LookupEnv,
+			)
+			if err != nil {
+				return errors.Wrap(err, "unable to load settings")
+			}
+			if err := applySettingsToFlags(cmd, settings); err != nil {
+				return err
+			}
+			if err := outputOptions().Validate(); err != nil {
+				return err
+			}
+			git.SetRemote(settings.Get("git_remote"))
+			profileStore := profiles.NewStore(fileBackend)
+
+			activeProfile, err := resolveProfile(profileStore)
+			if err != nil {
+				return err
+			}
+
+			// The host from the environment wins over a profile's, which wins
+			// over the config files
+			rwxHost = settings.Get("host")
+			if activeProfile != "" {
+				p, _, err := profileStore.Get(activeProfile)
+				if err != nil {
+					return errors.Wrap(err, "unable to load profile")
+				}
+				if p.Host != "" && settings.Lookup("host").Source != internalconfig.SourceEnv {
+					rwxHost = p.Host
+				}
+			}
+
+			var helperBackend accesstoken.Backend
+			if credentialHelper := settings.Get("credential_helper"); credentialHelper != "" {
+				helperBackend = accesstoken.NewHelperBackend(credentialHelper, rwxHost, activeProfile)
+			}
+
+			switch {
+			case activeProfile != "":
+				accessTokenBackend = profiles.NewTokenBackend(profileStore, activeProfile, rwxHost, helperBackend)
+			case helperBackend != nil:
+				accessTokenBackend = helperBackend
+			default:
+				accessTokenBackend = accesstoken.NewFileBackend(fileBackend)
+			}
 			docsTokenBackend := docstoken.NewFileBackend(fileBackend)
 			versionsBackend := versions.NewFileBackend(fileBackend)
 			skillVersionsBackend := versions.NewSkillFileBackend(fileBackend)
 
+			httpTransport, err := transport.New(transport.Config{
+				CACertFile:     flagOrEnv(caCert, "RWX_CA_BUNDLE"),
+				ClientCertFile: flagOrEnv(clientCert, "RWX_CLIENT_CERT"),
+				ClientKeyFile:  flagOrEnv(clientKey, "RWX_CLIENT_KEY"),
+			})
+			if err != nil {
+				return errors.Wrap(err, "unable to initialize HTTP transport")
+			}
+
+			wrapTransport, err := httpTracing()
+			if err != nil {
+				return err
+			}
+
 			c, err := api.NewClient(api.Config{
 				AccessToken:          AccessToken,
 				Host:                 rwxHost,
 				AccessTokenBackend:   accessTokenBackend,
 				VersionsBackend:      versionsBackend,
 				SkillVersionsBackend: skillVersionsBackend,
+				Transport:            httpTransport,
+				WrapTransport:        wrapTransport,
 			})
 			if err != nil {
 				return errors.Wrap(err, "unable to initialize API client")
 			}
 
+			// Requests to Cloud are retried when it's unavailable, and every
+			// attempt is recorded in the API stats. Telemetry isn't retried so
+			// that it can't hold up exiting.
+			statsRT := telemetry.NewStatsRoundTripper(c.RoundTripper)
+			c.RoundTripper = retry.NewRoundTripper(statsRT)
+
 			collector := telemetry.NewCollector()
-			retryRT := retry.NewRoundTripper(c)
-			statsRT := telemetry.NewStatsRoundTripper(retryRT)
 			sender := telemetry.NewSender(collector, statsRT)
 			telem = telemetry.New(collector, sender, statsRT)
 
@@ -86,19 +171,31 @@ var (
 				return errors.Wrap(err, "unable to initialize Docker client")
 			}
 
+			keepaliveInterval, err := sshKeepaliveInterval(settings.Get("ssh_keepalive_interval"))
+			if err != nil {
+				return err
+			}
+
 			service, err = cli.NewService(cli.Config{
 				APIClient: c,
-				SSHClient: new(ssh.Client),
+				SSHClient: &ssh.Client{KeepaliveInterval: keepaliveInterval, ProxyTLSConfig: httpTransport.TLSClientConfig},
+				NewSSHClient: func(stdout, stderr io.Writer) cli.SSHClient {
+					return &ssh.Client{Stdout: stdout, Stderr: stderr, KeepaliveInterval: keepaliveInterval, ProxyTLSConfig: httpTransport.TLSClientConfig}
+				},
 				GitClient: &git.Client{
 					Binary: "git",
 					Dir:    dir,
 				},
 				DockerCLI:            dockerCli,
-				DocsClient:           docs.Client{Host: docsHost, Scheme: docsScheme},
+				DocsClient:           docs.Client{Host: docsHost, Scheme: docsScheme, HTTPClient: &http.Client{Transport: httpTransport}},
 				DocsTokenBackend:     docsTokenBackend,
 				AccessTokenBackend:   accessTokenBackend,
 				VersionsBackend:      versionsBackend,
 				SkillVersionsBackend: skillVersionsBackend,
+				Profiles:             profileStore,
+				Profile:              activeProfile,
+				Settings:             settings,
+				Output:               outputOptions(),
 				TelemetryCollector:   collector,
 				Stdin:                os.Stdin,
 				Stdout:               os.Stdout,
@@ -115,27 +212,76 @@ var (
 	}
 )
 
+// httpTracing logs requests to Cloud with --verbose or RWX_DEBUG=http, and
+// records them to a HAR file in the directory given by RWX_HTTP_RECORD.
+func httpTracing() (func(http.RoundTripper) http.RoundTripper, error) {
+	logRequests := verbose || debugEnabled("http")
+	recordDir := os.Getenv("RWX_HTTP_RECORD")
+	if !logRequests && recordDir == "" {
+		return nil, nil
+	}
+
+	var recorder *httplog.Recorder
+	if recordDir != "" {
+		var err error
+		recorder, err = httplog.NewRecorder(nil, recordDir)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to record HTTP requests")
+		}
+		fmt.Fprintf(os.Stderr, "Recording requests to RWX Cloud in %s\n", recorder.Path)
+	}
+
+	return func(rt http.RoundTripper) http.RoundTripper {
+		if logRequests {
+			rt = httplog.NewLogger(rt, os.Stderr)
+		}
+		if recorder != nil {
+			recorder.Inner = rt
+			rt = recorder
+		}
+		return rt
+	}, nil
+}
+
+// debugEnabled reports whether RWX_DEBUG, a comma-separated list such as
+// "http", includes topic or "all".
+func debugEnabled(topic string) bool {
+	for _, value := range strings.Split(os.Getenv("RWX_DEBUG"), ",") {
+		value = strings.TrimSpace(value)
+		if value == topic || value == "all" {
+			return true
+		}
+	}
+	return false
+}
+
 func addRwxDirFlag(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&RwxDirectory, "dir", "d", "", "the directory your RWX configuration files are located in, typically `.rwx`. By default, the CLI traverses up until it finds a `.rwx` directory.")
 }
 
+// outputOptions are the format chosen with --output, --template and --jq.
+// The hidden --json flag is the same as `--output json`.
+func outputOptions() output.Options {
+	format := output.Format(Output)
+	if Json {
+		format = output.FormatJSON
+	}
+	return output.Options{Format: format, Template: Template, JQ: JQ}
+}
+
+// useJsonOutput reports whether a command should produce a result for
+// printResult rather than text for people.
 func useJsonOutput() bool {
-	return Output == "json" || Json
+	return outputOptions().Structured()
 }
 
-func init() {
-	// A different host can only be set over the environment
-	mintHostEnv := os.Getenv("MINT_HOST")
-	rwxHostEnv := os.Getenv("RWX_HOST")
-
-	if mintHostEnv == "" && rwxHostEnv == "" {
-		rwxHost = "cloud.rwx.com"
-	} else if mintHostEnv != "" {
-		rwxHost = mintHostEnv
-	} else {
-		rwxHost = rwxHostEnv
-	}
+// printResult writes a command's result in the format chosen with --output,
+// --template and --jq.
+func printResult(value any) error {
+	return outputOptions().Write(os.Stdout, value)
+}
 
+func init() {
 	if docsHostEnv := os.Getenv("RWX_DOCS_HOST"); docsHostEnv != "" {
 		docsHost = docsHostEnv
 		docsScheme = "http"
@@ -144,7 +290,14 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&AccessToken, "access-token", "$RWX_ACCESS_TOKEN", "the access token for RWX")
 	rootCmd.PersistentFlags().BoolVar(&Json, "json", false, "output json data to stdout")
 	_ = rootCmd.PersistentFlags().MarkHidden("json")
-	rootCmd.PersistentFlags().StringVar(&Output, "output", "text", "output format: text or json")
+	rootCmd.PersistentFlags().StringVar(&Output, "output", "text", "output format: text, json, yaml or ndjson")
+	rootCmd.PersistentFlags().StringVar(&Template, "template", "", "format the result with a Go template, e.g. '{{.RunID}}'")
+	rootCmd.PersistentFlags().StringVar(&JQ, "jq", "", "select from the result's JSON with a jq expression, e.g. '.artifacts[].key'")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "the profile to use for authentication and host (or $RWX_PROFILE)")
+	rootCmd.PersistentFlags().BoolVar(&verbose, "verbose", false, "log requests to RWX Cloud to stderr (or $RWX_DEBUG=http)")
+	rootCmd.PersistentFlags().StringVar(&caCert, "ca-cert", "", "a PEM file of CA certificates to trust for HTTPS, e.g. for a TLS-inspecting proxy (or $RWX_CA_BUNDLE)")
+	rootCmd.PersistentFlags().StringVar(&clientCert, "client-cert", "", "a PEM client certificate to present for mutual TLS (or $RWX_CLIENT_CERT)")
+	rootCmd.PersistentFlags().StringVar(&clientKey, "client-key", "", "the PEM private key for --client-cert (or $RWX_CLIENT_KEY)")
 
 	// Define command groups for help output ordering
 	rootCmd.AddGroup(&cobra.Group{ID: "execution", Title: "Execution:"})
@@ -159,16 +312,20 @@ func init() {
 
 	// Add commands (GroupID is set in each command's definition)
 	rootCmd.AddCommand(artifactsCmd)
+	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(debugCmd)
 	rootCmd.AddCommand(dispatchCmd)
 	rootCmd.AddCommand(imageCmd)
 	rootCmd.AddCommand(lintCmd)
 	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(logoutCmd)
 	rootCmd.AddCommand(logsCmd)
 	rootCmd.AddCommand(lspCmd)
 	rootCmd.AddCommand(mcpCmd)
 	rootCmd.AddCommand(packagesCmd)
+	rootCmd.AddCommand(profilesCmd)
 	rootCmd.AddCommand(pushCmd)
+	rootCmd.AddCommand(replayCmd)
 	rootCmd.AddCommand(resolveCmd)
 	rootCmd.AddCommand(runCmd)
 	rootCmd.AddCommand(sandboxCmd)
@@ -185,3 +342,103 @@ func init() {
 		}
 	})
 }
+
+// resolveProfile returns the name of the profile to use: the --profile flag,
+// then RWX_PROFILE, then the profile chosen with `rwx profiles use`. It is
+// empty when no profile is in use.
+func resolveProfile(store *profiles.Store) (string, error) {
+	name := flagOrEnv(profile, "RWX_PROFILE")
+	if name == "" {
+		current, err := store.Current()
+		if err != nil {
+			return "", errors.Wrap(err, "unable to load profiles")
+		}
+		return current, nil
+	}
+
+	if err := profiles.ValidateName(name); err != nil {
+		return "", err
+	}
+	return name, nil
+}
+
+// mutuallyExclusiveAnnotation is the annotation cobra puts on flags passed to
+// MarkFlagsMutuallyExclusive
+const mutuallyExclusiveAnnotation = "cobra_annotation_mutually_exclusive"
+
+// applySettingsToFlags uses the configured settings as the values of flags
+// that weren't passed, and records the flags that were as the source of their
+// setting. A setting is not applied when a flag that is mutually exclusive
+// with its flag was passed.
+func applySettingsToFlags(cmd *cobra.Command, settings *internalconfig.Layered) error {
+	for _, setting := range internalconfig.Settings {
+		if setting.Flag == "" {
+			continue
+		}
+
+		flag := cmd.Flags().Lookup(setting.Flag)
+		if flag == nil {
+			continue
+		}
+		// `rwx lint` has its own --output with different formats
+		if setting.Key == "output" && flag != cmd.Root().PersistentFlags().Lookup("output") {
+			continue
+		}
+
+		if flag.Changed {
+			settings.SetFlag(setting.Key, flag.Value.String(), "--"+flag.Name)
+			continue
+		}
+
+		value := settings.Lookup(setting.Key)
+		if value.Source == internalconfig.SourceDefault || value.Value == "" {
+			continue
+		}
+		if exclusiveFlagChanged(cmd, flag) {
+			continue
+		}
+
+		if err := cmd.Flags().Set(setting.Flag, value.Value); err != nil {
+			return errors.Wrapf(err, "invalid %s from %s", setting.Key, value.Origin)
+		}
+	}
+
+	return nil
+}
+
+func exclusiveFlagChanged(cmd *cobra.Command, flag *pflag.Flag) bool {
+	for _, group := range flag.Annotations[mutuallyExclusiveAnnotation] {
+		for _, name := range strings.Split(group, " ") {
+			if other := cmd.Flags().Lookup(name); other != nil && other != flag && other.Changed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// flagOrEnv returns the value of a flag, falling back to an environment variable
+// when the flag isn't set.
+func flagOrEnv(value, env string) string {
+	if value != "" {
+		return value
+	}
+	return os.Getenv(env)
+}
+
+// sshKeepaliveInterval parses the ssh_keepalive_interval setting (e.g. 30s).
+// Zero disables keepalives, and the default is used when it is unset.
+func sshKeepaliveInterval(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid ssh_keepalive_interval %q", value)
+	}
+	if interval == 0 {
+		return -1, nil
+	}
+	return interval, nil
+}
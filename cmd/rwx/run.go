@@ -1,11 +1,9 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/rwx-cloud/rwx/internal/cli"
 	"github.com/rwx-cloud/rwx/internal/errors"
@@ -27,6 +25,7 @@ var (
 	Wait           bool
 	FailFast       bool
 	Title          string
+	Breakpoints    []string
 
 	runCmd = &cobra.Command{
 		GroupID: "execution",
@@ -69,9 +68,17 @@ var (
 				return errors.Wrap(err, "unable to parse init parameters")
 			}
 
+			breakpoints, err := ParseBreakpoints(Breakpoints)
+			if err != nil {
+				return err
+			}
+
+			// Breakpoints are only useful with a debugging session to attach
+			debug := Debug || len(breakpoints) > 0
+
 			useJson := useJsonOutput()
 
-			runResult, err := service.InitiateRun(cli.InitiateRunConfig{
+			runResult, err := service.InitiateRun(cmd.Context(), cli.InitiateRunConfig{
 				InitParameters: initParams,
 				Json:           useJson,
 				RwxDirectory:   RwxDirectory,
@@ -80,6 +87,7 @@ var (
 				TargetedTasks:  TargetedTasks,
 				Title:          Title,
 				Patchable:      true,
+				Breakpoints:    breakpoints,
 			})
 			if err != nil {
 				return err
@@ -101,12 +109,9 @@ var (
 			}
 
 			if useJson && !Wait {
-				runResultJson, err := json.Marshal(jsonOutput)
-				if err != nil {
+				if err := printResult(jsonOutput); err != nil {
 					return err
 				}
-
-				fmt.Println(string(runResultJson))
 			} else if !useJson {
 				fmt.Print(runResult.Message)
 				if !Wait {
@@ -120,8 +125,8 @@ var (
 				}
 			}
 
-			if Wait && !Debug {
-				waitResult, err := service.GetRunStatus(cli.GetRunStatusConfig{
+			if Wait && !debug {
+				waitResult, err := service.GetRunStatus(cmd.Context(), cli.GetRunStatusConfig{
 					RunID:    runResult.RunID,
 					Wait:     true,
 					FailFast: FailFast,
@@ -133,15 +138,13 @@ var (
 
 				if useJson {
 					jsonOutput.ResultStatus = waitResult.ResultStatus
-					waitResultJson, err := json.Marshal(jsonOutput)
-					if err != nil {
+					if err := printResult(jsonOutput); err != nil {
 						return err
 					}
-					fmt.Println(string(waitResultJson))
 				} else {
 					fmt.Printf("Run result status: %s\n", waitResult.ResultStatus)
 
-					promptResult, err := service.GetRunPrompt(runResult.RunID)
+					promptResult, err := service.GetRunPrompt(cmd.Context(), runResult.RunID)
 					if err == nil {
 						fmt.Printf("\n%s", promptResult.Prompt)
 					}
@@ -152,25 +155,14 @@ var (
 				}
 			}
 
-			if Debug {
+			if debug {
 				fmt.Println()
-				stopSpinner := cli.Spin("Waiting for run to hit a breakpoint...", service.StdoutIsTTY, service.Stdout)
-
-				ticker := time.NewTicker(time.Second)
-				defer ticker.Stop()
-
-				for range ticker.C {
-					stopSpinner()
-					err := service.DebugTask(cli.DebugTaskConfig{DebugKey: runResult.RunID})
-					if errors.Is(err, errors.ErrRetry) {
-						stopSpinner = cli.Spin("Waiting for run to hit a breakpoint...", service.StdoutIsTTY, service.Stdout)
-						continue
-					}
-					if errors.Is(err, errors.ErrGone) {
-						fmt.Println("Run finished without encountering a breakpoint.")
-						break
-					}
-
+				err := service.DebugTask(cmd.Context(), cli.DebugTaskConfig{DebugKey: runResult.RunID, Wait: true})
+				if errors.Is(err, errors.ErrGone) {
+					fmt.Println("Run finished without encountering a breakpoint.")
+					return nil
+				}
+				if err != nil {
 					return err
 				}
 			}
@@ -192,6 +184,7 @@ func init() {
 	addRwxDirFlag(runCmd)
 	runCmd.Flags().BoolVar(&Open, "open", false, "open the run in a browser")
 	runCmd.Flags().BoolVar(&Debug, "debug", false, "start a remote debugging session once a breakpoint is hit")
+	runCmd.Flags().StringArrayVar(&Breakpoints, "breakpoint", []string{}, "add a breakpoint to a task in the form task-key[:before|after] and start a remote debugging session once it is hit. Can be specified multiple times. The file on disk is not changed")
 	runCmd.Flags().BoolVar(&Wait, "wait", false, "poll for the run to complete and report the result status")
 	runCmd.Flags().BoolVar(&FailFast, "fail-fast", false, "stop waiting when failures are available (only has an effect when used with --wait)")
 	runCmd.Flags().StringVar(&Title, "title", "", "the title the UI will display for the run")
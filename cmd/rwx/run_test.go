@@ -26,3 +26,23 @@ func TestParseInitParameters(t *testing.T) {
 		require.EqualError(t, err, "unable to parse \"a\"")
 	})
 }
+
+func TestParseBreakpoints(t *testing.T) {
+	t.Run("should default to breaking before the task", func(t *testing.T) {
+		parsed, err := rwx.ParseBreakpoints([]string{"lint", "test:after", "build:before"})
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"lint": "before", "test": "after", "build": "before"}, parsed)
+	})
+
+	t.Run("should error on an unknown position", func(t *testing.T) {
+		parsed, err := rwx.ParseBreakpoints([]string{"lint:during"})
+		require.Nil(t, parsed)
+		require.EqualError(t, err, `unable to parse breakpoint "lint:during": position must be "before" or "after"`)
+	})
+
+	t.Run("should error on a missing task key", func(t *testing.T) {
+		parsed, err := rwx.ParseBreakpoints([]string{":after"})
+		require.Nil(t, parsed)
+		require.Error(t, err)
+	})
+}
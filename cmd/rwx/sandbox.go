@@ -1,13 +1,14 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/manifoldco/promptui"
 	"github.com/rwx-cloud/rwx/internal/cli"
+	"github.com/rwx-cloud/rwx/internal/errors"
 	"github.com/skratchdot/open-golang/open"
 	"github.com/spf13/cobra"
 )
@@ -42,7 +43,7 @@ var sandboxStartCmd = &cobra.Command{
 
 		// Check for existing active sandbox (skip if --id is provided)
 		if sandboxRunID == "" {
-			existing, err := service.CheckExistingSandbox(configFile)
+			existing, err := service.CheckExistingSandbox(cmd.Context(), configFile, sandboxName)
 			if err != nil {
 				return err
 			}
@@ -76,12 +77,11 @@ var sandboxStartCmd = &cobra.Command{
 							RunID:      existing.RunID,
 							RunURL:     existing.RunURL,
 							ConfigFile: existing.ConfigFile,
+							Name:       sandboxName,
 						}
-						jsonOutput, err := json.Marshal(result)
-						if err != nil {
+						if err := printResult(result); err != nil {
 							return err
 						}
-						fmt.Println(string(jsonOutput))
 					} else {
 						fmt.Fprintf(os.Stdout, "Using existing sandbox: %s\n", existing.RunID)
 					}
@@ -89,7 +89,7 @@ var sandboxStartCmd = &cobra.Command{
 				}
 
 				// User chose to reset - stop existing and continue to start new
-				_, err = service.StopSandbox(cli.StopSandboxConfig{
+				_, err = service.StopSandbox(cmd.Context(), cli.StopSandboxConfig{
 					RunID: existing.RunID,
 					Json:  useJson,
 				})
@@ -99,8 +99,9 @@ var sandboxStartCmd = &cobra.Command{
 			}
 		}
 
-		result, err := service.StartSandbox(cli.StartSandboxConfig{
+		result, err := service.StartSandbox(cmd.Context(), cli.StartSandboxConfig{
 			ConfigFile:     configFile,
+			Name:           sandboxName,
 			RunID:          sandboxRunID,
 			RwxDirectory:   sandboxRwxDir,
 			Json:           useJson,
@@ -120,11 +121,9 @@ var sandboxStartCmd = &cobra.Command{
 		}
 
 		if useJson {
-			jsonOutput, err := json.Marshal(result)
-			if err != nil {
+			if err := printResult(result); err != nil {
 				return err
 			}
-			fmt.Println(string(jsonOutput))
 		}
 
 		return nil
@@ -152,8 +151,61 @@ FILE SYNCING
   automatically pulled back to the local working directory via git patch.
   This happens regardless of the command's exit code.
 
+  If local files changed while the command ran, the sandbox's changes are
+  three-way merged into them. Overlapping edits are left as conflict markers
+  (<<<<<<< local / >>>>>>> sandbox) to resolve by hand. Files that cannot be
+  merged, such as binary files or renames, fall back to .rej files.
+
   Note: Git LFS files cannot be synced and will generate a warning.
 
+ENVIRONMENT
+  Use --env KEY=value (repeatable) and --env-file <file> to set environment
+  variables for the command, and --workdir to run it in a different directory
+  (relative to the sandbox's repository root). Values from --env take
+  precedence over --env-file.
+
+INTERRUPTS AND TIMEOUTS
+  Ctrl-C (SIGINT) and SIGTERM are forwarded to the remote command. Changes
+  are still pulled back and the sandbox is reverted and unlocked afterwards.
+  Use --timeout to terminate the command if it runs for too long.
+
+DEFINITION CHANGES
+  When the sandbox configuration, or a file it embeds from the .rwx directory
+  (e.g. call: ${{ run.dir }}/setup.yml), has changed since the sandbox was
+  started, exec warns that the sandbox is out of date. Use --auto-reset to
+  stop it and start a new sandbox from the current definition instead. Set
+  RWX_SANDBOX_AUTO_RESET=true to make this the default.
+
+NAMED SANDBOXES
+  By default there is one sandbox per branch and config file. Use --name to
+  keep several side by side, e.g. one for a test run and one for a dev
+  server. The same --name selects the sandbox in start, exec, stop, reset,
+  jobs, attach and kill.
+
+MULTIPLE SANDBOXES
+  Use --all to run the command in every active sandbox for the current
+  branch, or repeat --id and --name to pick several sandboxes. The command
+  runs in all of them at once, with each line of output prefixed by the
+  sandbox's name (or run ID). Every sandbox is synced, locked and reverted
+  on its own; changes are pulled back one sandbox at a time.
+
+  A summary is printed at the end. The exit code is 0 when the command
+  succeeded everywhere, and otherwise the highest exit code of any sandbox.
+
+DETACHED JOBS
+  Use --detach to start the command in the background and return a job ID
+  immediately. The job runs in its own copy of the sandbox's working tree,
+  including the synced changes, so the sandbox is free for other commands
+  while it runs. Changes made by a detached job are not pulled back to the
+  local working directory.
+
+  Use 'rwx sandbox jobs' to list jobs, 'rwx sandbox attach <job>' to stream
+  a job's output, and 'rwx sandbox kill <job>' to signal it.
+
+RECORDING
+  Use --record <file> to save the command's output as an asciicast v2
+  recording. Play it back with 'rwx replay <file>' or any asciinema player.
+
 CONFIG FILE
   The sandbox configuration (default: .rwx/sandbox.yml) defines:
     - Base image and dependencies
@@ -192,6 +244,14 @@ CONFIG FILE
 			return fmt.Errorf("No command specified. Usage: rwx sandbox exec [config-file] -- <command>")
 		}
 
+		if sandboxDetach && sandboxTimeout > 0 {
+			return fmt.Errorf("--timeout cannot be used with --detach")
+		}
+
+		if sandboxDetach && sandboxRecord != "" {
+			return fmt.Errorf("--record cannot be used with --detach")
+		}
+
 		useJson := useJsonOutput()
 
 		initParams, err := ParseInitParameters(sandboxInitParams)
@@ -199,25 +259,68 @@ CONFIG FILE
 			return fmt.Errorf("unable to parse init parameters: %w", err)
 		}
 
-		result, err := service.ExecSandbox(cli.ExecSandboxConfig{
+		execCfg := cli.ExecSandboxConfig{
 			ConfigFile:     configFile,
 			Command:        command,
-			RunID:          sandboxRunID,
 			RwxDirectory:   sandboxRwxDir,
 			Json:           useJson,
 			Sync:           !sandboxNoSync,
+			AutoReset:      sandboxAutoReset,
+			Detach:         sandboxDetach,
+			Timeout:        sandboxTimeout,
+			Env:            sandboxEnv,
+			EnvFile:        sandboxEnvFile,
+			WorkDir:        sandboxWorkDir,
 			InitParameters: initParams,
-		})
+			Record:         sandboxRecord,
+		}
+
+		if sandboxExecAll || len(sandboxExecRunIDs)+len(sandboxExecNames) > 1 {
+			if sandboxOpen {
+				return fmt.Errorf("--open cannot be used when running in multiple sandboxes")
+			}
+			if sandboxRecord != "" {
+				return fmt.Errorf("--record cannot be used when running in multiple sandboxes")
+			}
+
+			result, err := service.ExecSandboxes(cmd.Context(), cli.ExecSandboxesConfig{
+				ExecSandboxConfig: execCfg,
+				All:               sandboxExecAll,
+				RunIDs:            sandboxExecRunIDs,
+				Names:             sandboxExecNames,
+			})
+			if err != nil {
+				return err
+			}
+
+			if useJson {
+				if err := printResult(result); err != nil {
+					return err
+				}
+			}
+
+			if result.ExitCode != 0 {
+				return &cli.ExitCodeError{Code: result.ExitCode}
+			}
+			return nil
+		}
+
+		if len(sandboxExecRunIDs) > 0 {
+			execCfg.RunID = sandboxExecRunIDs[0]
+		}
+		if len(sandboxExecNames) > 0 {
+			execCfg.Name = sandboxExecNames[0]
+		}
+
+		result, err := service.ExecSandbox(cmd.Context(), execCfg)
 		if err != nil {
 			return err
 		}
 
 		if useJson {
-			jsonOutput, err := json.Marshal(result)
-			if err != nil {
+			if err := printResult(result); err != nil {
 				return err
 			}
-			fmt.Println(string(jsonOutput))
 		}
 
 		if sandboxOpen {
@@ -226,6 +329,10 @@ CONFIG FILE
 			}
 		}
 
+		if result.TimedOut {
+			timeoutErr := errors.WrapSentinel(fmt.Errorf("command timed out after %s", sandboxTimeout), errors.ErrTimeout)
+			return errors.WithID(timeoutErr, "run_id", result.RunID)
+		}
 		if result.ExitCode != 0 {
 			return &cli.ExitCodeError{Code: result.ExitCode}
 		}
@@ -233,6 +340,124 @@ CONFIG FILE
 	},
 }
 
+var sandboxJobsCmd = &cobra.Command{
+	Use:   "jobs [config-file]",
+	Short: "List detached jobs in a sandbox",
+	Args:  cobra.MaximumNArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return requireAccessToken()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var configFile string
+		if len(args) > 0 {
+			configFile = cli.AbsConfigFile(args[0])
+		}
+
+		useJson := useJsonOutput()
+		result, err := service.ListSandboxJobs(cmd.Context(), cli.ListSandboxJobsConfig{
+			SandboxJobTarget: cli.SandboxJobTarget{
+				ConfigFile: configFile,
+				Name:       sandboxName,
+				RunID:      sandboxRunID,
+				Json:       useJson,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		if useJson {
+			if err := printResult(result); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
+
+var sandboxAttachCmd = &cobra.Command{
+	Use:   "attach <job> [config-file]",
+	Short: "Stream the output of a detached job",
+	Long: `Stream the output of a detached job until it finishes.
+
+The output is replayed from the beginning. Once the job finishes, the CLI
+exits with the job's exit code.`,
+	Args: cobra.RangeArgs(1, 2),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return requireAccessToken()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var configFile string
+		if len(args) > 1 {
+			configFile = cli.AbsConfigFile(args[1])
+		}
+
+		useJson := useJsonOutput()
+		result, err := service.AttachSandboxJob(cmd.Context(), cli.AttachSandboxJobConfig{
+			SandboxJobTarget: cli.SandboxJobTarget{
+				ConfigFile: configFile,
+				Name:       sandboxName,
+				RunID:      sandboxRunID,
+				Json:       useJson,
+			},
+			JobID: args[0],
+		})
+		if err != nil {
+			return err
+		}
+
+		if useJson {
+			if err := printResult(result); err != nil {
+				return err
+			}
+		}
+
+		if result.ExitCode != 0 {
+			return &cli.ExitCodeError{Code: result.ExitCode}
+		}
+		return nil
+	},
+}
+
+var sandboxKillCmd = &cobra.Command{
+	Use:   "kill <job> [config-file]",
+	Short: "Send a signal to a detached job",
+	Args:  cobra.RangeArgs(1, 2),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return requireAccessToken()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var configFile string
+		if len(args) > 1 {
+			configFile = cli.AbsConfigFile(args[1])
+		}
+
+		useJson := useJsonOutput()
+		result, err := service.KillSandboxJob(cmd.Context(), cli.KillSandboxJobConfig{
+			SandboxJobTarget: cli.SandboxJobTarget{
+				ConfigFile: configFile,
+				Name:       sandboxName,
+				RunID:      sandboxRunID,
+				Json:       useJson,
+			},
+			JobID:  args[0],
+			Signal: sandboxSignal,
+		})
+		if err != nil {
+			return err
+		}
+
+		if useJson {
+			if err := printResult(result); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
+
 var sandboxListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List sandbox sessions with status",
@@ -242,7 +467,7 @@ var sandboxListCmd = &cobra.Command{
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		useJson := useJsonOutput()
-		result, err := service.ListSandboxes(cli.ListSandboxesConfig{
+		result, err := service.ListSandboxes(cmd.Context(), cli.ListSandboxesConfig{
 			Json: useJson,
 		})
 		if err != nil {
@@ -250,11 +475,41 @@ var sandboxListCmd = &cobra.Command{
 		}
 
 		if useJson {
-			jsonOutput, err := json.Marshal(result)
-			if err != nil {
+			if err := printResult(result); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	},
+}
+
+var sandboxGCCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Prune ended sandbox sessions and repair sandbox storage",
+	Long: `Check every stored sandbox session against RWX and clean up local state.
+
+Sessions whose runs have ended are removed, scoped tokens that are no longer
+accepted are cleared, and a corrupt or partially written sandboxes.json is
+restored from its backup. Use --dry-run to see the changes without making them.`,
+	Args: cobra.NoArgs,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return requireAccessToken()
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		useJson := useJsonOutput()
+		result, err := service.GCSandboxes(cmd.Context(), cli.GCSandboxesConfig{
+			DryRun: sandboxGCDryRun,
+			Json:   useJson,
+		})
+		if err != nil {
+			return err
+		}
+
+		if useJson {
+			if err := printResult(result); err != nil {
 				return err
 			}
-			fmt.Println(string(jsonOutput))
 		}
 
 		return nil
@@ -270,8 +525,9 @@ var sandboxStopCmd = &cobra.Command{
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		useJson := useJsonOutput()
-		result, err := service.StopSandbox(cli.StopSandboxConfig{
+		result, err := service.StopSandbox(cmd.Context(), cli.StopSandboxConfig{
 			RunID: sandboxRunID,
+			Name:  sandboxName,
 			All:   sandboxStopAll,
 			Json:  useJson,
 		})
@@ -280,11 +536,9 @@ var sandboxStopCmd = &cobra.Command{
 		}
 
 		if useJson {
-			jsonOutput, err := json.Marshal(result)
-			if err != nil {
+			if err := printResult(result); err != nil {
 				return err
 			}
-			fmt.Println(string(jsonOutput))
 		}
 
 		return nil
@@ -307,7 +561,7 @@ var sandboxInitCmd = &cobra.Command{
 		}
 
 		useJson := useJsonOutput()
-		result, err := service.GetSandboxInitTemplate(cli.GetSandboxInitTemplateConfig{
+		result, err := service.GetSandboxInitTemplate(cmd.Context(), cli.GetSandboxInitTemplateConfig{
 			Json: useJson,
 		})
 		if err != nil {
@@ -352,8 +606,9 @@ var sandboxResetCmd = &cobra.Command{
 			return fmt.Errorf("unable to parse init parameters: %w", err)
 		}
 
-		result, err := service.ResetSandbox(cli.ResetSandboxConfig{
+		result, err := service.ResetSandbox(cmd.Context(), cli.ResetSandboxConfig{
 			ConfigFile:     configFile,
+			Name:           sandboxName,
 			RwxDirectory:   sandboxRwxDir,
 			Json:           useJson,
 			Wait:           sandboxWait,
@@ -364,11 +619,9 @@ var sandboxResetCmd = &cobra.Command{
 		}
 
 		if useJson {
-			jsonOutput, err := json.Marshal(result)
-			if err != nil {
+			if err := printResult(result); err != nil {
 				return err
 			}
-			fmt.Println(string(jsonOutput))
 		}
 
 		if sandboxOpen {
@@ -383,12 +636,25 @@ var sandboxResetCmd = &cobra.Command{
 
 var (
 	sandboxRunID      string
+	sandboxName       string
 	sandboxStopAll    bool
+	sandboxGCDryRun   bool
+	sandboxExecAll    bool
+	sandboxExecRunIDs []string
+	sandboxExecNames  []string
 	sandboxRwxDir     string
 	sandboxOpen       bool
 	sandboxWait       bool
 	sandboxNoSync     bool
+	sandboxAutoReset  bool
+	sandboxDetach     bool
+	sandboxTimeout    time.Duration
+	sandboxEnv        []string
+	sandboxEnvFile    string
+	sandboxWorkDir    string
+	sandboxSignal     string
 	sandboxInitParams []string
+	sandboxRecord     string
 )
 
 func init() {
@@ -398,27 +664,59 @@ func init() {
 	sandboxCmd.AddCommand(sandboxListCmd)
 	sandboxCmd.AddCommand(sandboxStopCmd)
 	sandboxCmd.AddCommand(sandboxResetCmd)
+	sandboxCmd.AddCommand(sandboxJobsCmd)
+	sandboxCmd.AddCommand(sandboxAttachCmd)
+	sandboxCmd.AddCommand(sandboxKillCmd)
+	sandboxCmd.AddCommand(sandboxGCCmd)
 
 	// start flags
 	sandboxStartCmd.Flags().StringVarP(&sandboxRwxDir, "dir", "d", "", "RWX directory")
 	sandboxStartCmd.Flags().StringVar(&sandboxRunID, "id", "", "Use specific run ID")
+	sandboxStartCmd.Flags().StringVar(&sandboxName, "name", "", "Name the sandbox to keep several on the same branch")
 	sandboxStartCmd.Flags().BoolVar(&sandboxOpen, "open", false, "Open the run in a browser")
 	sandboxStartCmd.Flags().BoolVar(&sandboxWait, "wait", false, "Wait for sandbox to be ready")
 	sandboxStartCmd.Flags().StringArrayVar(&sandboxInitParams, "init", []string{}, "initialization parameters for the sandbox run, available in the `init` context. Can be specified multiple times")
 
 	// exec flags
 	sandboxExecCmd.Flags().StringVarP(&sandboxRwxDir, "dir", "d", "", "RWX directory")
-	sandboxExecCmd.Flags().StringVar(&sandboxRunID, "id", "", "Use specific run ID")
+	sandboxExecCmd.Flags().StringArrayVar(&sandboxExecRunIDs, "id", []string{}, "Use specific run ID. Can be specified multiple times to run in several sandboxes")
+	sandboxExecCmd.Flags().StringArrayVar(&sandboxExecNames, "name", []string{}, "Use the sandbox with this name. Can be specified multiple times to run in several sandboxes")
+	sandboxExecCmd.Flags().BoolVar(&sandboxExecAll, "all", false, "Run the command in every active sandbox for the current branch")
 	sandboxExecCmd.Flags().BoolVar(&sandboxOpen, "open", false, "Open the run in a browser")
 	sandboxExecCmd.Flags().BoolVar(&sandboxNoSync, "no-sync", false, "Skip syncing local changes before execution")
+	sandboxExecCmd.Flags().BoolVar(&sandboxAutoReset, "auto-reset", false, "Reset the sandbox when its definition has changed since it was started (default from the sandbox_auto_reset setting)")
+	sandboxExecCmd.Flags().StringArrayVarP(&sandboxEnv, "env", "e", []string{}, "set an environment variable for the command in the form KEY=value. Can be specified multiple times")
+	sandboxExecCmd.Flags().StringVar(&sandboxEnvFile, "env-file", "", "read environment variables for the command from a dotenv file")
+	sandboxExecCmd.Flags().StringVarP(&sandboxWorkDir, "workdir", "w", "", "run the command in this directory, relative to the sandbox's repository root")
+	sandboxExecCmd.Flags().DurationVar(&sandboxTimeout, "timeout", 0, "Terminate the command if it runs longer than this duration (e.g. 10m)")
+	sandboxExecCmd.Flags().BoolVar(&sandboxDetach, "detach", false, "Start the command in the background and print its job ID")
+	sandboxExecCmd.Flags().StringVar(&sandboxRecord, "record", "", "Record the command's output to this file in asciicast format. Play it back with 'rwx replay'")
 	sandboxExecCmd.Flags().StringArrayVar(&sandboxInitParams, "init", []string{}, "initialization parameters for the sandbox run, available in the `init` context. Can be specified multiple times")
 
 	// stop flags
 	sandboxStopCmd.Flags().StringVar(&sandboxRunID, "id", "", "Stop specific sandbox by run ID")
+	sandboxStopCmd.Flags().StringVar(&sandboxName, "name", "", "Stop only the sandbox with this name")
 	sandboxStopCmd.Flags().BoolVar(&sandboxStopAll, "all", false, "Stop all sandboxes")
 
+	// gc flags
+	sandboxGCCmd.Flags().BoolVar(&sandboxGCDryRun, "dry-run", false, "Show what would be cleaned up without changing anything")
+
+	// jobs flags
+	sandboxJobsCmd.Flags().StringVar(&sandboxRunID, "id", "", "Use specific run ID")
+	sandboxJobsCmd.Flags().StringVar(&sandboxName, "name", "", "Use the sandbox with this name")
+
+	// attach flags
+	sandboxAttachCmd.Flags().StringVar(&sandboxRunID, "id", "", "Use specific run ID")
+	sandboxAttachCmd.Flags().StringVar(&sandboxName, "name", "", "Use the sandbox with this name")
+
+	// kill flags
+	sandboxKillCmd.Flags().StringVar(&sandboxRunID, "id", "", "Use specific run ID")
+	sandboxKillCmd.Flags().StringVar(&sandboxName, "name", "", "Use the sandbox with this name")
+	sandboxKillCmd.Flags().StringVarP(&sandboxSignal, "signal", "s", "TERM", "Signal to send to the job")
+
 	// reset flags
 	sandboxResetCmd.Flags().StringVarP(&sandboxRwxDir, "dir", "d", "", "RWX directory")
+	sandboxResetCmd.Flags().StringVar(&sandboxName, "name", "", "Reset the sandbox with this name")
 	sandboxResetCmd.Flags().BoolVar(&sandboxOpen, "open", false, "Open the run in a browser")
 	sandboxResetCmd.Flags().BoolVar(&sandboxWait, "wait", false, "Wait for sandbox to be ready")
 	sandboxResetCmd.Flags().StringArrayVar(&sandboxInitParams, "init", []string{}, "initialization parameters for the sandbox run, available in the `init` context. Can be specified multiple times")
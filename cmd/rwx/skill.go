@@ -1,7 +1,6 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -42,9 +41,7 @@ func init() {
 }
 
 func outputSkillStatusJSON(result *skill.DetectResult) error {
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(result.Installations)
+	return printResult(result.Installations)
 }
 
 func outputSkillStatusText(result *skill.DetectResult) {
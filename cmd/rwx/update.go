@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"github.com/rwx-cloud/rwx/internal/cli"
 	"github.com/spf13/cobra"
 )
@@ -13,17 +14,17 @@ var updateCmd = &cobra.Command{
 		if len(args) > 0 {
 			switch args[0] {
 			case "base":
-				return updateBase(args[1:])
+				return updateBase(cmd.Context(), args[1:])
 			case "packages":
-				return updatePackages(args[1:])
+				return updatePackages(cmd.Context(), args[1:])
 			}
 		}
 
-		err := updateBase(args)
+		err := updateBase(cmd.Context(), args)
 		if err != nil {
 			return err
 		}
-		return updatePackages(args)
+		return updatePackages(cmd.Context(), args)
 	},
 }
 
@@ -32,7 +33,7 @@ var (
 
 	updateBaseCmd = &cobra.Command{
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return updateBase(args)
+			return updateBase(cmd.Context(), args)
 		},
 		Short: "Update base images in RWX run configurations",
 		Long: "Update base images in RWX run configurations.\n" +
@@ -43,7 +44,7 @@ var (
 
 	updatePackagesCmd = &cobra.Command{
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return updatePackages(args)
+			return updatePackages(cmd.Context(), args)
 		},
 		Short: "Update all packages to their latest (minor) version",
 		Long: "Update all packages to their latest (minor) version.\n" +
@@ -52,9 +53,9 @@ var (
 	}
 )
 
-func updateBase(files []string) error {
+func updateBase(ctx context.Context, files []string) error {
 	useJson := useJsonOutput()
-	_, err := service.InsertBase(cli.InsertBaseConfig{
+	_, err := service.InsertBase(ctx, cli.InsertBaseConfig{
 		Files:        files,
 		RwxDirectory: RwxDirectory,
 		Json:         useJson,
@@ -62,14 +63,14 @@ func updateBase(files []string) error {
 	return err
 }
 
-func updatePackages(files []string) error {
+func updatePackages(ctx context.Context, files []string) error {
 	replacementVersionPicker := cli.PickLatestMinorVersion
 	if AllowMajorVersionChange {
 		replacementVersionPicker = cli.PickLatestMajorVersion
 	}
 
 	useJson := useJsonOutput()
-	_, err := service.UpdatePackages(cli.UpdatePackagesConfig{
+	_, err := service.UpdatePackages(ctx, cli.UpdatePackagesConfig{
 		Files:                    files,
 		RwxDirectory:             RwxDirectory,
 		ReplacementVersionPicker: replacementVersionPicker,
@@ -24,7 +24,7 @@ var (
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			useJson := useJsonOutput()
-			_, err := service.CreateVault(cli.CreateVaultConfig{
+			_, err := service.CreateVault(cmd.Context(), cli.CreateVaultConfig{
 				Name:                  createVaultName,
 				Unlocked:              createVaultUnlocked,
 				RepositoryPermissions: createVaultRepoPerms,
@@ -59,7 +59,7 @@ var (
 			}
 
 			useJson := useJsonOutput()
-			_, err := service.SetSecretsInVault(cli.SetSecretsInVaultConfig{
+			_, err := service.SetSecretsInVault(cmd.Context(), cli.SetSecretsInVaultConfig{
 				Vault:   secretsSetVault,
 				File:    secretsSetFile,
 				Secrets: secrets,
@@ -83,7 +83,7 @@ var (
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			useJson := useJsonOutput()
-			_, err := service.DeleteSecret(cli.DeleteSecretConfig{
+			_, err := service.DeleteSecret(cmd.Context(), cli.DeleteSecretConfig{
 				SecretName: args[0],
 				Vault:      secretsDeleteVault,
 				Json:       useJson,
@@ -118,7 +118,7 @@ var (
 			}
 
 			useJson := useJsonOutput()
-			_, err := service.SetVars(cli.SetVarsConfig{
+			_, err := service.SetVars(cmd.Context(), cli.SetVarsConfig{
 				Vault: varsSetVault,
 				File:  varsSetFile,
 				Vars:  vars,
@@ -141,7 +141,7 @@ var (
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			useJson := useJsonOutput()
-			_, err := service.ShowVar(cli.ShowVarConfig{
+			_, err := service.ShowVar(cmd.Context(), cli.ShowVarConfig{
 				VarName: args[0],
 				Vault:   varsShowVault,
 				Json:    useJson,
@@ -164,7 +164,7 @@ var (
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			useJson := useJsonOutput()
-			_, err := service.DeleteVar(cli.DeleteVarConfig{
+			_, err := service.DeleteVar(cmd.Context(), cli.DeleteVarConfig{
 				VarName: args[0],
 				Vault:   varsDeleteVault,
 				Json:    useJson,
@@ -196,7 +196,7 @@ var (
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			useJson := useJsonOutput()
-			_, err := service.CreateVaultOidcToken(cli.CreateVaultOidcTokenConfig{
+			_, err := service.CreateVaultOidcToken(cmd.Context(), cli.CreateVaultOidcTokenConfig{
 				Vault:    oidcTokenCreateVault,
 				Name:     oidcTokenCreateName,
 				Audience: oidcTokenCreateAudience,
@@ -227,7 +227,7 @@ var (
 			}
 
 			useJson := useJsonOutput()
-			_, err := service.SetSecretsInVault(cli.SetSecretsInVaultConfig{
+			_, err := service.SetSecretsInVault(cmd.Context(), cli.SetSecretsInVaultConfig{
 				Vault:   setSecretsVault,
 				File:    setSecretsFile,
 				Secrets: secrets,
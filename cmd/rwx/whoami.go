@@ -13,7 +13,7 @@ var whoamiCmd = &cobra.Command{
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		useJson := useJsonOutput()
-		_, err := service.Whoami(cli.WhoamiConfig{Json: useJson})
+		_, err := service.Whoami(cmd.Context(), cli.WhoamiConfig{Json: useJson})
 		return err
 	},
 	Short: "Outputs details about the access token in use",
@@ -0,0 +1,112 @@
+package accesstoken
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/rwx-cloud/rwx/internal/errors"
+)
+
+// HelperBackend keeps access tokens in an external credential helper instead
+// of a file. Like git's credential helpers, the helper command is run through
+// the shell with `get`, `store` or `erase` appended, and reads `key=value`
+// lines from stdin: `host`, `profile` when one is in use, and `token` when
+// storing. For `get`, it writes `token=<token>` to stdout, or nothing when it
+// has no token.
+type HelperBackend struct {
+	command    string
+	attributes map[string]string
+}
+
+func NewHelperBackend(command, host, profile string) *HelperBackend {
+	attributes := map[string]string{"host": host}
+	if profile != "" {
+		attributes["profile"] = profile
+	}
+
+	return &HelperBackend{command: command, attributes: attributes}
+}
+
+func (h *HelperBackend) Get() (string, error) {
+	output, err := h.run("get", nil)
+	if err != nil {
+		return "", err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		if token, ok := strings.CutPrefix(scanner.Text(), "token="); ok {
+			return strings.TrimSpace(token), nil
+		}
+	}
+
+	return "", nil
+}
+
+// Set stores token with the helper, or erases the stored token when token is
+// empty.
+func (h *HelperBackend) Set(token string) error {
+	if token == "" {
+		_, err := h.run("erase", nil)
+		return err
+	}
+
+	_, err := h.run("store", map[string]string{"token": token})
+	return err
+}
+
+func (h *HelperBackend) run(action string, extra map[string]string) ([]byte, error) {
+	var input strings.Builder
+	for _, key := range sortedKeys(h.attributes, extra) {
+		value, ok := extra[key]
+		if !ok {
+			value = h.attributes[key]
+		}
+		if strings.ContainsAny(value, "\n\x00") {
+			return nil, errors.Errorf("credential helper attribute %q contains a newline", key)
+		}
+		fmt.Fprintf(&input, "%s=%s\n", key, value)
+	}
+	input.WriteString("\n")
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", h.command+" "+action)
+	} else {
+		cmd = exec.Command("sh", "-c", h.command+" "+action)
+	}
+	cmd.Stdin = strings.NewReader(input.String())
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message != "" {
+			return nil, errors.Wrapf(err, "credential helper %q failed to %s the access token: %s", h.command, action, message)
+		}
+		return nil, errors.Wrapf(err, "credential helper %q failed to %s the access token", h.command, action)
+	}
+
+	return output, nil
+}
+
+func sortedKeys(maps ...map[string]string) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, m := range maps {
+		for key := range m {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
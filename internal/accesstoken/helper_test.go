@@ -0,0 +1,84 @@
+package accesstoken_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/rwx-cloud/rwx/internal/accesstoken"
+	"github.com/stretchr/testify/require"
+)
+
+// stubHelper writes a credential helper that keeps its token in a file and
+// logs what it was asked to do.
+func stubHelper(t *testing.T) (string, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the stub credential helper is a shell script")
+	}
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "helper")
+	require.NoError(t, os.WriteFile(script, []byte(`#!/bin/sh
+dir=$(dirname "$0")
+input=$(cat)
+printf '%s\n%s\n' "$1" "$input" >> "$dir/log"
+case "$1" in
+  get)
+    if [ -f "$dir/token" ]; then printf 'token=%s\n' "$(cat "$dir/token")"; fi ;;
+  store)
+    printf '%s\n' "$input" | sed -n 's/^token=//p' > "$dir/token" ;;
+  erase)
+    rm -f "$dir/token" ;;
+esac
+`), 0o755))
+
+	return script, dir
+}
+
+func TestHelperBackend(t *testing.T) {
+	t.Run("stores, gets and erases the token with the helper", func(t *testing.T) {
+		script, dir := stubHelper(t)
+		backend := accesstoken.NewHelperBackend(script, "cloud.rwx.com", "")
+
+		token, err := backend.Get()
+		require.NoError(t, err)
+		require.Equal(t, "", token)
+
+		require.NoError(t, backend.Set("some-token"))
+		token, err = backend.Get()
+		require.NoError(t, err)
+		require.Equal(t, "some-token", token)
+
+		require.NoError(t, backend.Set(""))
+		token, err = backend.Get()
+		require.NoError(t, err)
+		require.Equal(t, "", token)
+
+		log, err := os.ReadFile(filepath.Join(dir, "log"))
+		require.NoError(t, err)
+		require.Contains(t, string(log), "store\nhost=cloud.rwx.com\ntoken=some-token\n")
+		require.Contains(t, string(log), "erase\nhost=cloud.rwx.com\n")
+	})
+
+	t.Run("passes the profile to the helper", func(t *testing.T) {
+		script, dir := stubHelper(t)
+		backend := accesstoken.NewHelperBackend(script, "cloud.rwx.com", "work")
+
+		_, err := backend.Get()
+		require.NoError(t, err)
+
+		log, err := os.ReadFile(filepath.Join(dir, "log"))
+		require.NoError(t, err)
+		require.Equal(t, "get\nhost=cloud.rwx.com\nprofile=work\n", string(log))
+	})
+
+	t.Run("reports the helper's error output", func(t *testing.T) {
+		backend := accesstoken.NewHelperBackend("echo 'vault is sealed' >&2; exit 1; :", "cloud.rwx.com", "")
+
+		_, err := backend.Get()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "failed to get the access token: vault is sealed")
+	})
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -19,13 +20,14 @@ import (
 
 var ErrNotFound = errors.New("not found")
 
-// httpClient uses a transport with a reduced idle connection timeout to avoid
-// reusing connections that the load balancer has already closed (default ALB
-// idle timeout is 60s; Go's default IdleConnTimeout is 90s).
-var httpClient = newHTTPClient()
-
-func newHTTPClient() *http.Client {
-	transport := http.DefaultTransport.(*http.Transport).Clone()
+// newHTTPClient returns a client whose transport has a reduced idle connection
+// timeout to avoid reusing connections that the load balancer has already
+// closed (default ALB idle timeout is 60s; Go's default IdleConnTimeout is 90s).
+func newHTTPClient(base *http.Transport) *http.Client {
+	if base == nil {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+	transport := base.Clone()
 	transport.IdleConnTimeout = 50 * time.Second
 	return &http.Client{Transport: transport}
 }
@@ -39,6 +41,10 @@ func (rtf roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
 // Client is an API Client for Mint
 type Client struct {
 	http.RoundTripper
+
+	// downloads is used for requests to URLs outside of Cloud, such as logs
+	// and artifacts in storage
+	downloads *http.Client
 }
 
 func NewClient(cfg Config) (Client, error) {
@@ -46,6 +52,15 @@ func NewClient(cfg Config) (Client, error) {
 		return Client{}, errors.Wrap(err, "validation failed")
 	}
 
+	httpClient := newHTTPClient(cfg.Transport)
+	if cfg.WrapTransport != nil {
+		httpClient.Transport = cfg.WrapTransport(httpClient.Transport)
+	}
+	downloads := http.DefaultClient
+	if cfg.Transport != nil {
+		downloads = &http.Client{Transport: cfg.Transport}
+	}
+
 	roundTrip := func(req *http.Request) (*http.Response, error) {
 		if req.URL.Scheme == "" {
 			req.URL.Scheme = "https"
@@ -55,27 +70,38 @@ func NewClient(cfg Config) (Client, error) {
 		}
 		req.Header.Set("User-Agent", fmt.Sprintf("rwx-cli/%s", config.Version))
 
-		token, err := accesstoken.Get(cfg.AccessTokenBackend, cfg.AccessToken)
-		if err != nil {
-			return nil, errors.Wrap(err, "unable to retrieve access token")
-		}
-		if token != "" {
-			req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		// An Authorization header set on the request, such as a token scoped
+		// to a sandbox, wins over the default access token
+		if req.Header.Get("Authorization") == "" {
+			token, err := accesstoken.Get(cfg.AccessTokenBackend, cfg.AccessToken)
+			if err != nil {
+				return nil, errors.Wrap(err, "unable to retrieve access token")
+			}
+			if token != "" {
+				req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+			}
 		}
 
 		return httpClient.Do(req)
 	}
 
 	roundTripper := versions.NewRoundTripper(roundTripFunc(roundTrip), cfg.VersionsBackend, cfg.SkillVersionsBackend)
-	return Client{roundTripper}, nil
+	return Client{RoundTripper: roundTripper, downloads: downloads}, nil
 }
 
 func NewClientWithRoundTrip(rt func(*http.Request) (*http.Response, error)) Client {
-	return Client{roundTripFunc(rt)}
+	return Client{RoundTripper: roundTripFunc(rt)}
 }
 
-func (c Client) GetSkillLatestVersion() (string, error) {
-	req, err := http.NewRequest(http.MethodGet, "/api/skill/latest", nil)
+func (c Client) downloadClient() *http.Client {
+	if c.downloads == nil {
+		return http.DefaultClient
+	}
+	return c.downloads
+}
+
+func (c Client) GetSkillLatestVersion(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "/api/skill/latest", nil)
 	if err != nil {
 		return "", errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -87,7 +113,7 @@ func (c Client) GetSkillLatestVersion() (string, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return "", errors.New(fmt.Sprintf("Unable to call RWX API - %s", resp.Status))
+		return "", responseError(resp, fmt.Sprintf("Unable to call RWX API - %s", resp.Status))
 	}
 
 	var result struct {
@@ -100,7 +126,7 @@ func (c Client) GetSkillLatestVersion() (string, error) {
 	return result.Version, nil
 }
 
-func (c Client) GetDebugConnectionInfo(debugKey string) (DebugConnectionInfo, error) {
+func (c Client) GetDebugConnectionInfo(ctx context.Context, debugKey string) (DebugConnectionInfo, error) {
 	connectionInfo := DebugConnectionInfo{}
 
 	if debugKey == "" {
@@ -108,7 +134,7 @@ func (c Client) GetDebugConnectionInfo(debugKey string) (DebugConnectionInfo, er
 	}
 
 	endpoint := fmt.Sprintf("/mint/api/debug_connection_info?debug_key=%s", url.QueryEscape(debugKey))
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return connectionInfo, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -137,7 +163,7 @@ func (c Client) GetDebugConnectionInfo(debugKey string) (DebugConnectionInfo, er
 		}
 		return connectionInfo, errors.ErrGone
 	default:
-		return connectionInfo, errors.New(fmt.Sprintf("Unable to call RWX API - %s", resp.Status))
+		return connectionInfo, responseError(resp, fmt.Sprintf("Unable to call RWX API - %s", resp.Status))
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&connectionInfo); err != nil {
@@ -147,7 +173,7 @@ func (c Client) GetDebugConnectionInfo(debugKey string) (DebugConnectionInfo, er
 	return connectionInfo, nil
 }
 
-func (c Client) GetSandboxConnectionInfo(runID, scopedToken string) (SandboxConnectionInfo, error) {
+func (c Client) GetSandboxConnectionInfo(ctx context.Context, runID, scopedToken string) (SandboxConnectionInfo, error) {
 	connectionInfo := SandboxConnectionInfo{}
 
 	if runID == "" {
@@ -155,7 +181,7 @@ func (c Client) GetSandboxConnectionInfo(runID, scopedToken string) (SandboxConn
 	}
 
 	endpoint := fmt.Sprintf("/mint/api/sandbox_connection_info?sandbox_key=%s", url.QueryEscape(runID))
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return connectionInfo, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -181,6 +207,8 @@ func (c Client) GetSandboxConnectionInfo(runID, scopedToken string) (SandboxConn
 			return connectionInfo, errors.New(connectionError.Error)
 		}
 		return connectionInfo, errors.ErrBadRequest
+	case 401:
+		return connectionInfo, errors.ErrUnauthorized
 	case 404:
 		connectionError := SandboxConnectionInfoError{}
 		if err := json.NewDecoder(resp.Body).Decode(&connectionError); err == nil && connectionError.Error != "" {
@@ -194,7 +222,7 @@ func (c Client) GetSandboxConnectionInfo(runID, scopedToken string) (SandboxConn
 		}
 		return connectionInfo, errors.ErrGone
 	default:
-		return connectionInfo, errors.New(fmt.Sprintf("Unable to call RWX API - %s", resp.Status))
+		return connectionInfo, responseError(resp, fmt.Sprintf("Unable to call RWX API - %s", resp.Status))
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&connectionInfo); err != nil {
@@ -204,7 +232,7 @@ func (c Client) GetSandboxConnectionInfo(runID, scopedToken string) (SandboxConn
 	return connectionInfo, nil
 }
 
-func (c Client) CreateSandboxToken(cfg CreateSandboxTokenConfig) (*CreateSandboxTokenResult, error) {
+func (c Client) CreateSandboxToken(ctx context.Context, cfg CreateSandboxTokenConfig) (*CreateSandboxTokenResult, error) {
 	endpoint := "/mint/api/sandbox_tokens"
 
 	encodedBody, err := json.Marshal(cfg)
@@ -212,7 +240,7 @@ func (c Client) CreateSandboxToken(cfg CreateSandboxTokenConfig) (*CreateSandbox
 		return nil, errors.Wrap(err, "unable to encode as JSON")
 	}
 
-	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -230,7 +258,7 @@ func (c Client) CreateSandboxToken(cfg CreateSandboxTokenConfig) (*CreateSandbox
 		if msg == "" {
 			msg = fmt.Sprintf("Unable to call RWX API - %s", resp.Status)
 		}
-		return nil, errors.New(msg)
+		return nil, responseError(resp, msg)
 	}
 
 	result := CreateSandboxTokenResult{}
@@ -242,7 +270,7 @@ func (c Client) CreateSandboxToken(cfg CreateSandboxTokenConfig) (*CreateSandbox
 }
 
 // InitiateRun sends a request to Mint for starting a new run
-func (c Client) InitiateRun(cfg InitiateRunConfig) (*InitiateRunResult, error) {
+func (c Client) InitiateRun(ctx context.Context, cfg InitiateRunConfig) (*InitiateRunResult, error) {
 	endpoint := "/mint/api/runs"
 
 	if err := cfg.Validate(); err != nil {
@@ -256,7 +284,7 @@ func (c Client) InitiateRun(cfg InitiateRunConfig) (*InitiateRunResult, error) {
 		return nil, errors.Wrap(err, "unable to encode as JSON")
 	}
 
-	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -275,7 +303,7 @@ func (c Client) InitiateRun(cfg InitiateRunConfig) (*InitiateRunResult, error) {
 			msg = fmt.Sprintf("Unable to call RWX API - %s", resp.Status)
 		}
 
-		return nil, errors.New(msg)
+		return nil, responseError(resp, msg)
 	}
 
 	respBody := struct {
@@ -314,7 +342,7 @@ func (c Client) InitiateRun(cfg InitiateRunConfig) (*InitiateRunResult, error) {
 	}
 }
 
-func (c Client) InitiateDispatch(cfg InitiateDispatchConfig) (*InitiateDispatchResult, error) {
+func (c Client) InitiateDispatch(ctx context.Context, cfg InitiateDispatchConfig) (*InitiateDispatchResult, error) {
 	endpoint := "/mint/api/runs/dispatches"
 
 	if err := cfg.Validate(); err != nil {
@@ -326,7 +354,7 @@ func (c Client) InitiateDispatch(cfg InitiateDispatchConfig) (*InitiateDispatchR
 		return nil, errors.Wrap(err, "unable to encode as JSON")
 	}
 
-	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -345,10 +373,10 @@ func (c Client) InitiateDispatch(cfg InitiateDispatchConfig) (*InitiateDispatchR
 		}{}
 
 		if err := json.NewDecoder(resp.Body).Decode(&errorStruct); err != nil {
-			return nil, errors.New(fmt.Sprintf("Unable to call RWX API - %s", resp.Status))
+			return nil, responseError(resp, fmt.Sprintf("Unable to call RWX API - %s", resp.Status))
 		}
 
-		return nil, errors.New(errorStruct.Error)
+		return nil, responseError(resp, errorStruct.Error)
 	}
 
 	respBody := struct {
@@ -364,10 +392,10 @@ func (c Client) InitiateDispatch(cfg InitiateDispatchConfig) (*InitiateDispatchR
 	}, nil
 }
 
-func (c Client) GetDispatch(cfg GetDispatchConfig) (*GetDispatchResult, error) {
+func (c Client) GetDispatch(ctx context.Context, cfg GetDispatchConfig) (*GetDispatchResult, error) {
 	endpoint := fmt.Sprintf("/mint/api/runs/dispatches/%s", cfg.DispatchId)
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, bytes.NewBuffer(make([]byte, 0)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, bytes.NewBuffer(make([]byte, 0)))
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -381,7 +409,7 @@ func (c Client) GetDispatch(cfg GetDispatchConfig) (*GetDispatchResult, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return nil, errors.New(fmt.Sprintf("Unable to call RWX API - %s", resp.Status))
+		return nil, responseError(resp, fmt.Sprintf("Unable to call RWX API - %s", resp.Status))
 	}
 
 	respBody := struct {
@@ -402,7 +430,7 @@ func (c Client) GetDispatch(cfg GetDispatchConfig) (*GetDispatchResult, error) {
 }
 
 // ObtainAuthCode requests a new one-time-use code to login on a device
-func (c Client) ObtainAuthCode(cfg ObtainAuthCodeConfig) (*ObtainAuthCodeResult, error) {
+func (c Client) ObtainAuthCode(ctx context.Context, cfg ObtainAuthCodeConfig) (*ObtainAuthCodeResult, error) {
 	endpoint := "/api/auth/codes"
 
 	if err := cfg.Validate(); err != nil {
@@ -414,7 +442,7 @@ func (c Client) ObtainAuthCode(cfg ObtainAuthCodeConfig) (*ObtainAuthCodeResult,
 		return nil, errors.Wrap(err, "unable to encode as JSON")
 	}
 
-	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -428,7 +456,7 @@ func (c Client) ObtainAuthCode(cfg ObtainAuthCodeConfig) (*ObtainAuthCodeResult,
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 201 {
-		return nil, errors.New(fmt.Sprintf("Unable to call RWX API - %s", resp.Status))
+		return nil, responseError(resp, fmt.Sprintf("Unable to call RWX API - %s", resp.Status))
 	}
 
 	respBody := ObtainAuthCodeResult{}
@@ -440,8 +468,8 @@ func (c Client) ObtainAuthCode(cfg ObtainAuthCodeConfig) (*ObtainAuthCodeResult,
 }
 
 // AcquireToken consumes the one-time-use code once authorized
-func (c Client) AcquireToken(tokenUrl string) (*AcquireTokenResult, error) {
-	req, err := http.NewRequest(http.MethodGet, tokenUrl, bytes.NewBuffer(make([]byte, 0)))
+func (c Client) AcquireToken(ctx context.Context, tokenUrl string) (*AcquireTokenResult, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tokenUrl, bytes.NewBuffer(make([]byte, 0)))
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -466,10 +494,10 @@ func (c Client) AcquireToken(tokenUrl string) (*AcquireTokenResult, error) {
 }
 
 // Whoami provides details about the authenticated token
-func (c Client) Whoami() (*WhoamiResult, error) {
+func (c Client) Whoami(ctx context.Context) (*WhoamiResult, error) {
 	endpoint := "/api/auth/whoami"
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, bytes.NewBuffer([]byte{}))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, bytes.NewBuffer([]byte{}))
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -483,7 +511,7 @@ func (c Client) Whoami() (*WhoamiResult, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 200 {
-		return nil, errors.New(fmt.Sprintf("Unable to call RWX API - %s", resp.Status))
+		return nil, responseError(resp, fmt.Sprintf("Unable to call RWX API - %s", resp.Status))
 	}
 
 	respBody := WhoamiResult{}
@@ -494,10 +522,38 @@ func (c Client) Whoami() (*WhoamiResult, error) {
 	return &respBody, nil
 }
 
-func (c Client) CreateDocsToken() (*DocsTokenResult, error) {
+// RevokeToken revokes the access token the request is made with. A token that
+// is already invalid is reported as errors.ErrUnauthorized. A 404 is reported
+// as a failure, since it means the request didn't reach the endpoint, not that
+// the token is gone.
+func (c Client) RevokeToken(ctx context.Context) error {
+	endpoint := "/api/auth/token"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return errors.Wrap(err, "unable to create new HTTP request")
+	}
+
+	resp, err := c.RoundTrip(req)
+	if err != nil {
+		return errors.Wrap(err, "HTTP request failed")
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent:
+		return nil
+	case http.StatusUnauthorized:
+		return errors.ErrUnauthorized
+	default:
+		return responseError(resp, fmt.Sprintf("Unable to call RWX API - %s", resp.Status))
+	}
+}
+
+func (c Client) CreateDocsToken(ctx context.Context) (*DocsTokenResult, error) {
 	endpoint := "/api/auth/docs_token"
 
-	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer([]byte{}))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer([]byte{}))
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -511,7 +567,7 @@ func (c Client) CreateDocsToken() (*DocsTokenResult, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != 201 && resp.StatusCode != 200 {
-		return nil, errors.New(fmt.Sprintf("Unable to call RWX API - %s", resp.Status))
+		return nil, responseError(resp, fmt.Sprintf("Unable to call RWX API - %s", resp.Status))
 	}
 
 	respBody := DocsTokenResult{}
@@ -522,7 +578,7 @@ func (c Client) CreateDocsToken() (*DocsTokenResult, error) {
 	return &respBody, nil
 }
 
-func (c Client) SetSecretsInVault(cfg SetSecretsInVaultConfig) (*SetSecretsInVaultResult, error) {
+func (c Client) SetSecretsInVault(ctx context.Context, cfg SetSecretsInVaultConfig) (*SetSecretsInVaultResult, error) {
 	endpoint := "/mint/api/vaults/secrets"
 
 	encodedBody, err := json.Marshal(cfg)
@@ -530,7 +586,7 @@ func (c Client) SetSecretsInVault(cfg SetSecretsInVaultConfig) (*SetSecretsInVau
 		return nil, errors.Wrap(err, "unable to encode as JSON")
 	}
 
-	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -549,7 +605,7 @@ func (c Client) SetSecretsInVault(cfg SetSecretsInVaultConfig) (*SetSecretsInVau
 			msg = fmt.Sprintf("Unable to call RWX API - %s", resp.Status)
 		}
 
-		return nil, errors.New(msg)
+		return nil, responseError(resp, msg)
 	}
 
 	respBody := SetSecretsInVaultResult{}
@@ -560,7 +616,7 @@ func (c Client) SetSecretsInVault(cfg SetSecretsInVaultConfig) (*SetSecretsInVau
 	return &respBody, nil
 }
 
-func (c Client) CreateVault(cfg CreateVaultConfig) (*CreateVaultResult, error) {
+func (c Client) CreateVault(ctx context.Context, cfg CreateVaultConfig) (*CreateVaultResult, error) {
 	endpoint := "/mint/api/vaults"
 
 	encodedBody, err := json.Marshal(cfg)
@@ -568,7 +624,7 @@ func (c Client) CreateVault(cfg CreateVaultConfig) (*CreateVaultResult, error) {
 		return nil, errors.Wrap(err, "unable to encode as JSON")
 	}
 
-	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -587,19 +643,19 @@ func (c Client) CreateVault(cfg CreateVaultConfig) (*CreateVaultResult, error) {
 			msg = fmt.Sprintf("Unable to call RWX API - %s", resp.Status)
 		}
 
-		return nil, errors.New(msg)
+		return nil, responseError(resp, msg)
 	}
 
 	return &CreateVaultResult{}, nil
 }
 
-func (c Client) DeleteSecret(cfg DeleteSecretConfig) (*DeleteSecretResult, error) {
+func (c Client) DeleteSecret(ctx context.Context, cfg DeleteSecretConfig) (*DeleteSecretResult, error) {
 	endpoint := fmt.Sprintf("/mint/api/vaults/secrets/%s?vault_name=%s",
 		url.PathEscape(cfg.SecretName),
 		url.QueryEscape(cfg.VaultName),
 	)
 
-	req, err := http.NewRequest(http.MethodDelete, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -616,13 +672,13 @@ func (c Client) DeleteSecret(cfg DeleteSecretConfig) (*DeleteSecretResult, error
 			msg = fmt.Sprintf("Unable to call RWX API - %s", resp.Status)
 		}
 
-		return nil, errors.New(msg)
+		return nil, responseError(resp, msg)
 	}
 
 	return &DeleteSecretResult{}, nil
 }
 
-func (c Client) SetVar(cfg SetVarConfig) (*SetVarResult, error) {
+func (c Client) SetVar(ctx context.Context, cfg SetVarConfig) (*SetVarResult, error) {
 	endpoint := "/mint/api/vaults/vars"
 
 	encodedBody, err := json.Marshal(cfg)
@@ -630,7 +686,7 @@ func (c Client) SetVar(cfg SetVarConfig) (*SetVarResult, error) {
 		return nil, errors.Wrap(err, "unable to encode as JSON")
 	}
 
-	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -649,19 +705,19 @@ func (c Client) SetVar(cfg SetVarConfig) (*SetVarResult, error) {
 			msg = fmt.Sprintf("Unable to call RWX API - %s", resp.Status)
 		}
 
-		return nil, errors.New(msg)
+		return nil, responseError(resp, msg)
 	}
 
 	return &SetVarResult{}, nil
 }
 
-func (c Client) ShowVar(cfg ShowVarConfig) (*ShowVarResult, error) {
+func (c Client) ShowVar(ctx context.Context, cfg ShowVarConfig) (*ShowVarResult, error) {
 	endpoint := fmt.Sprintf("/mint/api/vaults/vars/%s?vault_name=%s",
 		url.PathEscape(cfg.VarName),
 		url.QueryEscape(cfg.VaultName),
 	)
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -678,7 +734,7 @@ func (c Client) ShowVar(cfg ShowVarConfig) (*ShowVarResult, error) {
 			msg = fmt.Sprintf("Unable to call RWX API - %s", resp.Status)
 		}
 
-		return nil, errors.New(msg)
+		return nil, responseError(resp, msg)
 	}
 
 	respBody := ShowVarResult{}
@@ -689,13 +745,13 @@ func (c Client) ShowVar(cfg ShowVarConfig) (*ShowVarResult, error) {
 	return &respBody, nil
 }
 
-func (c Client) DeleteVar(cfg DeleteVarConfig) (*DeleteVarResult, error) {
+func (c Client) DeleteVar(ctx context.Context, cfg DeleteVarConfig) (*DeleteVarResult, error) {
 	endpoint := fmt.Sprintf("/mint/api/vaults/vars/%s?vault_name=%s",
 		url.PathEscape(cfg.VarName),
 		url.QueryEscape(cfg.VaultName),
 	)
 
-	req, err := http.NewRequest(http.MethodDelete, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -712,13 +768,13 @@ func (c Client) DeleteVar(cfg DeleteVarConfig) (*DeleteVarResult, error) {
 			msg = fmt.Sprintf("Unable to call RWX API - %s", resp.Status)
 		}
 
-		return nil, errors.New(msg)
+		return nil, responseError(resp, msg)
 	}
 
 	return &DeleteVarResult{}, nil
 }
 
-func (c Client) CreateVaultOidcToken(cfg CreateVaultOidcTokenConfig) (*CreateVaultOidcTokenResult, error) {
+func (c Client) CreateVaultOidcToken(ctx context.Context, cfg CreateVaultOidcTokenConfig) (*CreateVaultOidcTokenResult, error) {
 	endpoint := "/mint/api/vaults/oidc_tokens"
 
 	encodedBody, err := json.Marshal(cfg)
@@ -726,7 +782,7 @@ func (c Client) CreateVaultOidcToken(cfg CreateVaultOidcTokenConfig) (*CreateVau
 		return nil, errors.Wrap(err, "unable to encode as JSON")
 	}
 
-	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -745,7 +801,7 @@ func (c Client) CreateVaultOidcToken(cfg CreateVaultOidcTokenConfig) (*CreateVau
 			msg = fmt.Sprintf("Unable to call RWX API - %s", resp.Status)
 		}
 
-		return nil, errors.New(msg)
+		return nil, responseError(resp, msg)
 	}
 
 	result := CreateVaultOidcTokenResult{}
@@ -785,10 +841,10 @@ func extractOidcTokenErrorMessage(reader io.Reader) string {
 	return ""
 }
 
-func (c Client) GetPackageVersions() (*PackageVersionsResult, error) {
+func (c Client) GetPackageVersions(ctx context.Context) (*PackageVersionsResult, error) {
 	endpoint := "/mint/api/leaves"
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, bytes.NewBuffer([]byte{}))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, bytes.NewBuffer([]byte{}))
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -804,7 +860,7 @@ func (c Client) GetPackageVersions() (*PackageVersionsResult, error) {
 		if msg == "" {
 			msg = fmt.Sprintf("Unable to call RWX API - %s", resp.Status)
 		}
-		return nil, errors.New(msg)
+		return nil, responseError(resp, msg)
 	}
 
 	respBody := PackageVersionsResult{}
@@ -815,10 +871,10 @@ func (c Client) GetPackageVersions() (*PackageVersionsResult, error) {
 	return &respBody, nil
 }
 
-func (c Client) GetPackageDocumentation(packageName string) (*PackageDocumentationResult, error) {
+func (c Client) GetPackageDocumentation(ctx context.Context, packageName string) (*PackageDocumentationResult, error) {
 	endpoint := fmt.Sprintf("/mint/api/leaves/%s/documentation", packageName)
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -838,7 +894,7 @@ func (c Client) GetPackageDocumentation(packageName string) (*PackageDocumentati
 		if msg == "" {
 			msg = fmt.Sprintf("Unable to call RWX API - %s", resp.Status)
 		}
-		return nil, errors.New(msg)
+		return nil, responseError(resp, msg)
 	}
 
 	respBody := PackageDocumentationResult{}
@@ -849,11 +905,11 @@ func (c Client) GetPackageDocumentation(packageName string) (*PackageDocumentati
 	return &respBody, nil
 }
 
-func (c Client) GetDefaultBase() (DefaultBaseResult, error) {
+func (c Client) GetDefaultBase(ctx context.Context) (DefaultBaseResult, error) {
 	endpoint := "/mint/api/base/default"
 	result := DefaultBaseResult{}
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return result, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -873,7 +929,7 @@ func (c Client) GetDefaultBase() (DefaultBaseResult, error) {
 	return result, nil
 }
 
-func (c Client) StartImagePush(cfg StartImagePushConfig) (StartImagePushResult, error) {
+func (c Client) StartImagePush(ctx context.Context, cfg StartImagePushConfig) (StartImagePushResult, error) {
 	endpoint := "/mint/api/images/pushes"
 	result := StartImagePushResult{}
 
@@ -882,7 +938,7 @@ func (c Client) StartImagePush(cfg StartImagePushConfig) (StartImagePushResult,
 		return result, errors.Wrap(err, "unable to encode as JSON")
 	}
 
-	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
 	if err != nil {
 		return result, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -902,11 +958,11 @@ func (c Client) StartImagePush(cfg StartImagePushConfig) (StartImagePushResult,
 	return result, nil
 }
 
-func (c Client) ImagePushStatus(pushID string) (ImagePushStatusResult, error) {
+func (c Client) ImagePushStatus(ctx context.Context, pushID string) (ImagePushStatusResult, error) {
 	endpoint := "/mint/api/images/pushes/" + url.PathEscape(pushID)
 	result := ImagePushStatusResult{}
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return result, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -926,11 +982,11 @@ func (c Client) ImagePushStatus(pushID string) (ImagePushStatusResult, error) {
 	return result, nil
 }
 
-func (c Client) TaskKeyStatus(cfg TaskKeyStatusConfig) (TaskStatusResult, error) {
+func (c Client) TaskKeyStatus(ctx context.Context, cfg TaskKeyStatusConfig) (TaskStatusResult, error) {
 	endpoint := fmt.Sprintf("/mint/api/runs/%s/task_status?task_key=%s", url.PathEscape(cfg.RunID), url.PathEscape(cfg.TaskKey))
 	result := TaskStatusResult{}
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return result, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -943,18 +999,18 @@ func (c Client) TaskKeyStatus(cfg TaskKeyStatusConfig) (TaskStatusResult, error)
 	}
 	defer resp.Body.Close()
 
-	if err = decodeResponseJSON(resp, &result); err != nil {
+	if err = decodeTaskKeyResponseJSON(resp, cfg.TaskKey, &result); err != nil {
 		return result, err
 	}
 
 	return result, nil
 }
 
-func (c Client) TaskIDStatus(cfg TaskIDStatusConfig) (TaskStatusResult, error) {
+func (c Client) TaskIDStatus(ctx context.Context, cfg TaskIDStatusConfig) (TaskStatusResult, error) {
 	endpoint := fmt.Sprintf("/mint/api/tasks/%s/status", url.PathEscape(cfg.TaskID))
 	result := TaskStatusResult{}
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return result, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -974,7 +1030,7 @@ func (c Client) TaskIDStatus(cfg TaskIDStatusConfig) (TaskStatusResult, error) {
 	return result, nil
 }
 
-func (c Client) RunStatus(cfg RunStatusConfig) (RunStatusResult, error) {
+func (c Client) RunStatus(ctx context.Context, cfg RunStatusConfig) (RunStatusResult, error) {
 	var endpoint string
 	failFast := fmt.Sprintf("%t", cfg.FailFast)
 	if cfg.RunID != "" {
@@ -991,7 +1047,7 @@ func (c Client) RunStatus(cfg RunStatusConfig) (RunStatusResult, error) {
 	}
 	result := RunStatusResult{}
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return result, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -1011,10 +1067,10 @@ func (c Client) RunStatus(cfg RunStatusConfig) (RunStatusResult, error) {
 	return result, nil
 }
 
-func (c Client) GetRunPrompt(runID string) (string, error) {
+func (c Client) GetRunPrompt(ctx context.Context, runID string) (string, error) {
 	endpoint := fmt.Sprintf("/mint/api/runs/%s/prompt", url.PathEscape(runID))
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return "", errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -1034,7 +1090,7 @@ func (c Client) GetRunPrompt(runID string) (string, error) {
 		if resp.StatusCode == http.StatusNotFound {
 			return "", errors.Wrap(ErrNotFound, errMsg)
 		}
-		return "", errors.New(errMsg)
+		return "", responseError(resp, errMsg)
 	}
 
 	body, err := io.ReadAll(resp.Body)
@@ -1045,13 +1101,13 @@ func (c Client) GetRunPrompt(runID string) (string, error) {
 	return string(body), nil
 }
 
-func (c Client) GetLogDownloadRequest(taskId string) (LogDownloadRequestResult, error) {
+func (c Client) GetLogDownloadRequest(ctx context.Context, taskId string) (LogDownloadRequestResult, error) {
 	params := url.Values{}
 	params.Set("id", taskId)
 	endpoint := "/mint/api/log_download?" + params.Encode()
 	result := LogDownloadRequestResult{}
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return result, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -1071,14 +1127,14 @@ func (c Client) GetLogDownloadRequest(taskId string) (LogDownloadRequestResult,
 	return result, nil
 }
 
-func (c Client) GetLogDownloadRequestByTaskKey(runID, taskKey string) (LogDownloadRequestResult, error) {
+func (c Client) GetLogDownloadRequestByTaskKey(ctx context.Context, runID, taskKey string) (LogDownloadRequestResult, error) {
 	params := url.Values{}
 	params.Set("run_id", runID)
 	params.Set("task_key", taskKey)
 	endpoint := "/mint/api/log_download?" + params.Encode()
 	result := LogDownloadRequestResult{}
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return result, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -1098,7 +1154,7 @@ func (c Client) GetLogDownloadRequestByTaskKey(runID, taskKey string) (LogDownlo
 	return result, nil
 }
 
-func (c Client) DownloadLogs(request LogDownloadRequestResult, maxRetryDurationSeconds ...int) ([]byte, error) {
+func (c Client) DownloadLogs(ctx context.Context, request LogDownloadRequestResult, maxRetryDurationSeconds ...int) ([]byte, error) {
 	maxRetryDuration := 30 * time.Second
 	if len(maxRetryDurationSeconds) > 0 && maxRetryDurationSeconds[0] > 0 {
 		maxRetryDuration = time.Duration(maxRetryDurationSeconds[0]) * time.Second
@@ -1124,23 +1180,25 @@ func (c Client) DownloadLogs(request LogDownloadRequestResult, maxRetryDurationS
 		formData.Set("contents", request.Contents)
 		encodedBody := formData.Encode()
 
-		req, err = http.NewRequest(http.MethodPost, request.URL, strings.NewReader(encodedBody))
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, request.URL, strings.NewReader(encodedBody))
 		if err != nil {
 			return nil, errors.Wrap(err, "unable to create new HTTP request")
 		}
 		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 		req.Header.Set("Accept", "application/octet-stream")
 
-		// Use http.DefaultClient directly since the logs will come from a task server URL rather than Cloud
-		resp, err := http.DefaultClient.Do(req)
+		// Bypass the Cloud round tripper since the logs will come from a task server URL rather than Cloud
+		resp, err := c.downloadClient().Do(req)
 		if err != nil {
 			lastErr = errors.Wrap(err, "HTTP request failed")
 
-			if time.Since(startTime) >= maxRetryDuration {
+			if ctx.Err() != nil || time.Since(startTime) >= maxRetryDuration {
 				return nil, errors.Wrapf(lastErr, "failed after %d attempts over %v", attempt, time.Since(startTime).Round(time.Second))
 			}
 
-			time.Sleep(backoff)
+			if err := sleep(ctx, backoff); err != nil {
+				return nil, err
+			}
 			backoff *= 2
 			if backoff > 5*time.Second {
 				backoff = 5 * time.Second
@@ -1180,7 +1238,9 @@ func (c Client) DownloadLogs(request LogDownloadRequestResult, maxRetryDurationS
 			return nil, errors.Wrapf(lastErr, "failed after %d attempts over %v", attempt, time.Since(startTime).Round(time.Second))
 		}
 
-		time.Sleep(backoff)
+		if err := sleep(ctx, backoff); err != nil {
+			return nil, err
+		}
 		backoff *= 2
 		if backoff > 5*time.Second {
 			backoff = 5 * time.Second
@@ -1188,12 +1248,12 @@ func (c Client) DownloadLogs(request LogDownloadRequestResult, maxRetryDurationS
 	}
 }
 
-func (c Client) GetAllArtifactDownloadRequests(taskId string) ([]ArtifactDownloadRequestResult, error) {
+func (c Client) GetAllArtifactDownloadRequests(ctx context.Context, taskId string) ([]ArtifactDownloadRequestResult, error) {
 	params := url.Values{}
 	params.Set("task_id", taskId)
 	endpoint := "/mint/api/artifact_downloads?" + params.Encode()
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -1214,7 +1274,7 @@ func (c Client) GetAllArtifactDownloadRequests(taskId string) ([]ArtifactDownloa
 		if resp.StatusCode == http.StatusNotFound {
 			return nil, errors.Wrap(ErrNotFound, errMsg)
 		}
-		return nil, errors.New(errMsg)
+		return nil, responseError(resp, errMsg)
 	}
 
 	var results []ArtifactDownloadRequestResult
@@ -1225,13 +1285,13 @@ func (c Client) GetAllArtifactDownloadRequests(taskId string) ([]ArtifactDownloa
 	return results, nil
 }
 
-func (c Client) GetAllArtifactDownloadRequestsByTaskKey(runID, taskKey string) ([]ArtifactDownloadRequestResult, error) {
+func (c Client) GetAllArtifactDownloadRequestsByTaskKey(ctx context.Context, runID, taskKey string) ([]ArtifactDownloadRequestResult, error) {
 	params := url.Values{}
 	params.Set("run_id", runID)
 	params.Set("task_key", taskKey)
 	endpoint := "/mint/api/artifact_downloads?" + params.Encode()
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -1256,7 +1316,7 @@ func (c Client) GetAllArtifactDownloadRequestsByTaskKey(runID, taskKey string) (
 		if resp.StatusCode == http.StatusNotFound {
 			return nil, errors.Wrap(ErrNotFound, errMsg)
 		}
-		return nil, errors.New(errMsg)
+		return nil, responseError(resp, errMsg)
 	}
 
 	var results []ArtifactDownloadRequestResult
@@ -1267,14 +1327,14 @@ func (c Client) GetAllArtifactDownloadRequestsByTaskKey(runID, taskKey string) (
 	return results, nil
 }
 
-func (c Client) GetArtifactDownloadRequest(taskId, artifactKey string) (ArtifactDownloadRequestResult, error) {
+func (c Client) GetArtifactDownloadRequest(ctx context.Context, taskId, artifactKey string) (ArtifactDownloadRequestResult, error) {
 	params := url.Values{}
 	params.Set("task_id", taskId)
 	params.Set("key", artifactKey)
 	endpoint := fmt.Sprintf("/mint/api/artifact_download?%s", params.Encode())
 	result := ArtifactDownloadRequestResult{}
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return result, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -1294,7 +1354,7 @@ func (c Client) GetArtifactDownloadRequest(taskId, artifactKey string) (Artifact
 	return result, nil
 }
 
-func (c Client) GetArtifactDownloadRequestByTaskKey(runID, taskKey, artifactKey string) (ArtifactDownloadRequestResult, error) {
+func (c Client) GetArtifactDownloadRequestByTaskKey(ctx context.Context, runID, taskKey, artifactKey string) (ArtifactDownloadRequestResult, error) {
 	params := url.Values{}
 	params.Set("run_id", runID)
 	params.Set("task_key", taskKey)
@@ -1302,7 +1362,7 @@ func (c Client) GetArtifactDownloadRequestByTaskKey(runID, taskKey, artifactKey
 	endpoint := fmt.Sprintf("/mint/api/artifact_download?%s", params.Encode())
 	result := ArtifactDownloadRequestResult{}
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return result, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -1322,15 +1382,15 @@ func (c Client) GetArtifactDownloadRequestByTaskKey(runID, taskKey, artifactKey
 	return result, nil
 }
 
-func (c Client) DownloadArtifact(request ArtifactDownloadRequestResult) ([]byte, error) {
-	req, err := http.NewRequest(http.MethodGet, request.URL, nil)
+func (c Client) DownloadArtifact(ctx context.Context, request ArtifactDownloadRequestResult) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, request.URL, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
 	req.Header.Set("Accept", "application/octet-stream")
 
-	// Use http.DefaultClient directly since the artifact will come from storage (S3, etc.)
-	resp, err := http.DefaultClient.Do(req)
+	// Bypass the Cloud round tripper since the artifact will come from storage (S3, etc.)
+	resp, err := c.downloadClient().Do(req)
 	if err != nil {
 		return nil, errors.Wrap(err, "HTTP request failed")
 	}
@@ -1352,13 +1412,13 @@ func (c Client) DownloadArtifact(request ArtifactDownloadRequestResult) ([]byte,
 	return nil, errors.New(errMsg)
 }
 
-func (c Client) CancelRun(runID, scopedToken string) error {
+func (c Client) CancelRun(ctx context.Context, runID, scopedToken string) error {
 	if runID == "" {
 		return errors.New("missing runID")
 	}
 
 	endpoint := fmt.Sprintf("/mint/api/runs/%s/cancel", url.PathEscape(runID))
-	req, err := http.NewRequest(http.MethodPost, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, nil)
 	if err != nil {
 		return errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -1378,16 +1438,16 @@ func (c Client) CancelRun(runID, scopedToken string) error {
 		if msg == "" {
 			msg = fmt.Sprintf("Unable to call RWX API - %s", resp.Status)
 		}
-		return errors.New(msg)
+		return responseError(resp, msg)
 	}
 
 	return nil
 }
 
-func (c Client) ListSandboxRuns() (*ListSandboxRunsResult, error) {
+func (c Client) ListSandboxRuns(ctx context.Context) (*ListSandboxRunsResult, error) {
 	endpoint := "/mint/api/runs?result_status=sandboxed&execution_status=in_progress&my_runs=true"
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -1407,11 +1467,11 @@ func (c Client) ListSandboxRuns() (*ListSandboxRunsResult, error) {
 	return &result, nil
 }
 
-func (c Client) GetSandboxInitTemplate() (SandboxInitTemplateResult, error) {
+func (c Client) GetSandboxInitTemplate(ctx context.Context) (SandboxInitTemplateResult, error) {
 	endpoint := "/mint/api/sandbox_init_template"
 	result := SandboxInitTemplateResult{}
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		return result, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -1431,6 +1491,29 @@ func (c Client) GetSandboxInitTemplate() (SandboxInitTemplateResult, error) {
 	return result, nil
 }
 
+// sleep waits for d, returning early with the context's error when it's done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// responseError is the error for a failed response from the API with message
+// msg. A 401 means the access token isn't valid, so it's reported as
+// errors.ErrUnauthorized.
+func responseError(resp *http.Response, msg string) error {
+	if resp.StatusCode == http.StatusUnauthorized {
+		return errors.WrapSentinel(errors.New(msg), errors.ErrUnauthorized)
+	}
+	return errors.New(msg)
+}
+
 func decodeResponseJSON(resp *http.Response, result any) error {
 	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 		if result == nil {
@@ -1452,7 +1535,7 @@ func decodeResponseJSON(resp *http.Response, result any) error {
 		return errors.Wrap(ErrNotFound, errMsg)
 	}
 
-	return errors.New(errMsg)
+	return responseError(resp, errMsg)
 }
 
 type ErrorMessage struct {
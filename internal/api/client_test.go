@@ -2,6 +2,7 @@ package api_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -10,7 +11,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/rwx-cloud/rwx/internal/accesstoken"
 	"github.com/rwx-cloud/rwx/internal/api"
+	"github.com/rwx-cloud/rwx/internal/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -49,7 +52,7 @@ func TestAPIClient_InitiateRun(t *testing.T) {
 			UseCache:         false,
 		}
 
-		result, err := c.InitiateRun(initRunConfig)
+		result, err := c.InitiateRun(t.Context(), initRunConfig)
 		require.NoError(t, err)
 		require.Equal(t, "123", result.RunID)
 	})
@@ -88,7 +91,7 @@ func TestAPIClient_InitiateRun(t *testing.T) {
 			UseCache:         false,
 		}
 
-		result, err := c.InitiateRun(initRunConfig)
+		result, err := c.InitiateRun(t.Context(), initRunConfig)
 		require.NoError(t, err)
 		require.Equal(t, "123", result.RunID)
 	})
@@ -122,7 +125,7 @@ func TestAPIClient_ObtainAuthCode(t *testing.T) {
 			},
 		}
 
-		_, err := c.ObtainAuthCode(obtainAuthCodeConfig)
+		_, err := c.ObtainAuthCode(t.Context(), obtainAuthCodeConfig)
 		require.NoError(t, err)
 	})
 }
@@ -151,7 +154,7 @@ func TestAPIClient_AcquireToken(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		_, err := c.AcquireToken("https://cloud.rwx.com/api/auth/codes/some-uuid/token")
+		_, err := c.AcquireToken(t.Context(), "https://cloud.rwx.com/api/auth/codes/some-uuid/token")
 		require.NoError(t, err)
 	})
 }
@@ -181,9 +184,123 @@ func TestAPIClient_Whoami(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		_, err := c.Whoami()
+		_, err := c.Whoami(t.Context())
 		require.NoError(t, err)
 	})
+	t.Run("makes the request with the context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+
+		roundTrip := func(req *http.Request) (*http.Response, error) {
+			return nil, req.Context().Err()
+		}
+
+		c := api.NewClientWithRoundTrip(roundTrip)
+
+		_, err := c.Whoami(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestAPIClient_Authorization(t *testing.T) {
+	newClient := func(t *testing.T, authorization *string) api.Client {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			*authorization = r.Header.Get("Authorization")
+			w.WriteHeader(http.StatusOK)
+		}))
+		t.Cleanup(server.Close)
+
+		serverURL, err := url.Parse(server.URL)
+		require.NoError(t, err)
+
+		c, err := api.NewClient(api.Config{
+			Host:               serverURL.Host,
+			AccessToken:        "default-token",
+			AccessTokenBackend: accesstoken.NewMemoryBackend(),
+			Transport:          server.Client().Transport.(*http.Transport),
+		})
+		require.NoError(t, err)
+		return c
+	}
+
+	t.Run("sends the default access token", func(t *testing.T) {
+		var authorization string
+		c := newClient(t, &authorization)
+
+		require.NoError(t, c.CancelRun(t.Context(), "run-123", ""))
+		require.Equal(t, "Bearer default-token", authorization)
+	})
+
+	t.Run("keeps an Authorization header that is already set", func(t *testing.T) {
+		var authorization string
+		c := newClient(t, &authorization)
+
+		require.NoError(t, c.CancelRun(t.Context(), "run-123", "scoped-token"))
+		require.Equal(t, "Bearer scoped-token", authorization)
+	})
+}
+
+func TestAPIClient_RevokeToken(t *testing.T) {
+	newClient := func(t *testing.T, handler http.HandlerFunc) api.Client {
+		server := httptest.NewTLSServer(handler)
+		t.Cleanup(server.Close)
+
+		serverURL, err := url.Parse(server.URL)
+		require.NoError(t, err)
+
+		c, err := api.NewClient(api.Config{
+			Host:               serverURL.Host,
+			AccessToken:        "token-to-revoke",
+			AccessTokenBackend: accesstoken.NewMemoryBackend(),
+			Transport:          server.Client().Transport.(*http.Transport),
+		})
+		require.NoError(t, err)
+		return c
+	}
+
+	t.Run("revokes the token the request is made with", func(t *testing.T) {
+		revoked := false
+		c := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodDelete, r.Method)
+			require.Equal(t, "/api/auth/token", r.URL.Path)
+			require.Equal(t, "Bearer token-to-revoke", r.Header.Get("Authorization"))
+			revoked = true
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		require.NoError(t, c.RevokeToken(t.Context()))
+		require.True(t, revoked)
+	})
+
+	t.Run("reports a token that is already invalid as unauthorized", func(t *testing.T) {
+		c := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		})
+
+		require.ErrorIs(t, c.RevokeToken(t.Context()), errors.ErrUnauthorized)
+	})
+
+	t.Run("errors when the endpoint isn't found", func(t *testing.T) {
+		c := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		})
+
+		err := c.RevokeToken(t.Context())
+		require.Error(t, err)
+		require.NotErrorIs(t, err, errors.ErrUnauthorized)
+		require.Contains(t, err.Error(), "404")
+	})
+
+	t.Run("errors on other failures", func(t *testing.T) {
+		c := newClient(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		err := c.RevokeToken(t.Context())
+		require.Error(t, err)
+		require.NotErrorIs(t, err, errors.ErrUnauthorized)
+		require.Contains(t, err.Error(), "500")
+	})
 }
 
 func TestAPIClient_SetSecretsInVault(t *testing.T) {
@@ -205,7 +322,7 @@ func TestAPIClient_SetSecretsInVault(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		_, err := c.SetSecretsInVault(body)
+		_, err := c.SetSecretsInVault(t.Context(), body)
 		require.NoError(t, err)
 	})
 }
@@ -238,7 +355,7 @@ func TestAPIClient_InitiateDispatch(t *testing.T) {
 			Title:       "Test Dispatch",
 		}
 
-		result, err := c.InitiateDispatch(dispatchConfig)
+		result, err := c.InitiateDispatch(t.Context(), dispatchConfig)
 		require.NoError(t, err)
 		require.Equal(t, "dispatch-123", result.DispatchId)
 	})
@@ -273,7 +390,7 @@ func TestAPIClient_GetDispatch(t *testing.T) {
 			DispatchId: "dispatch-123",
 		}
 
-		result, err := c.GetDispatch(dispatchConfig)
+		result, err := c.GetDispatch(t.Context(), dispatchConfig)
 		require.NoError(t, err)
 		require.Equal(t, "ready", result.Status)
 		require.Len(t, result.Runs, 1)
@@ -298,7 +415,7 @@ func TestAPIClient_GetDefaultBase(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		result, err := c.GetDefaultBase()
+		result, err := c.GetDefaultBase(t.Context())
 		require.NoError(t, err)
 		require.Equal(t, "ubuntu:24.04", result.Image)
 		require.Equal(t, "rwx/base 1.0.0", result.Config)
@@ -344,7 +461,7 @@ func TestAPIClient_StartImagePush(t *testing.T) {
 			},
 		}
 
-		result, err := c.StartImagePush(pushConfig)
+		result, err := c.StartImagePush(t.Context(), pushConfig)
 		require.NoError(t, err)
 		require.Equal(t, "push-123", result.PushID)
 	})
@@ -366,7 +483,7 @@ func TestAPIClient_ImagePushStatus(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		result, err := c.ImagePushStatus("abc123")
+		result, err := c.ImagePushStatus(t.Context(), "abc123")
 		require.NoError(t, err)
 		require.Equal(t, "in_progress", result.Status)
 	})
@@ -388,7 +505,7 @@ func TestAPIClient_TaskIDStatus(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		result, err := c.TaskIDStatus(api.TaskIDStatusConfig{TaskID: "abc123"})
+		result, err := c.TaskIDStatus(t.Context(), api.TaskIDStatusConfig{TaskID: "abc123"})
 		require.NoError(t, err)
 		require.True(t, result.Polling.Completed)
 	})
@@ -420,7 +537,7 @@ func TestAPIClient_GetLogDownloadRequest(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		result, err := c.GetLogDownloadRequest("task-123")
+		result, err := c.GetLogDownloadRequest(t.Context(), "task-123")
 		require.NoError(t, err)
 		require.Equal(t, "https://example.com/logs/download", result.URL)
 		require.Equal(t, "jwt-token-123", result.Token)
@@ -455,7 +572,7 @@ func TestAPIClient_GetLogDownloadRequest(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		result, err := c.GetLogDownloadRequest("task-123")
+		result, err := c.GetLogDownloadRequest(t.Context(), "task-123")
 		require.NoError(t, err)
 		require.Equal(t, "https://example.com/logs/download", result.URL)
 		require.Equal(t, "jwt-token-123", result.Token)
@@ -476,7 +593,7 @@ func TestAPIClient_GetLogDownloadRequest(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		_, err := c.GetLogDownloadRequest("task-999")
+		_, err := c.GetLogDownloadRequest(t.Context(), "task-999")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "not found")
 	})
@@ -507,7 +624,7 @@ func TestAPIClient_DownloadLogs(t *testing.T) {
 			return http.DefaultClient.Do(req)
 		})
 
-		result, err := c.DownloadLogs(api.LogDownloadRequestResult{
+		result, err := c.DownloadLogs(t.Context(), api.LogDownloadRequestResult{
 			URL:      server.URL,
 			Token:    "jwt-token-123",
 			Filename: "task-123-logs.zip",
@@ -532,7 +649,7 @@ func TestAPIClient_DownloadLogs(t *testing.T) {
 			return http.DefaultClient.Do(req)
 		})
 
-		_, err := c.DownloadLogs(api.LogDownloadRequestResult{
+		_, err := c.DownloadLogs(t.Context(), api.LogDownloadRequestResult{
 			URL:      server.URL,
 			Token:    "token",
 			Filename: "logs.log",
@@ -554,7 +671,7 @@ func TestAPIClient_DownloadLogs(t *testing.T) {
 
 		startTime := time.Now()
 		// Use 2 seconds for faster test execution
-		_, err := c.DownloadLogs(api.LogDownloadRequestResult{
+		_, err := c.DownloadLogs(t.Context(), api.LogDownloadRequestResult{
 			URL:      serverURL,
 			Token:    "token",
 			Filename: "logs.log",
@@ -591,7 +708,7 @@ func TestAPIClient_DownloadLogs(t *testing.T) {
 		})
 
 		// Use 5 seconds for faster test execution
-		result, err := c.DownloadLogs(api.LogDownloadRequestResult{
+		result, err := c.DownloadLogs(t.Context(), api.LogDownloadRequestResult{
 			URL:      server.URL,
 			Token:    "token",
 			Filename: "logs.log",
@@ -616,7 +733,7 @@ func TestAPIClient_DownloadLogs(t *testing.T) {
 			return http.DefaultClient.Do(req)
 		})
 
-		_, err := c.DownloadLogs(api.LogDownloadRequestResult{
+		_, err := c.DownloadLogs(t.Context(), api.LogDownloadRequestResult{
 			URL:      server.URL,
 			Token:    "token",
 			Filename: "logs.log",
@@ -659,7 +776,7 @@ func TestAPIClient_GetArtifactDownloadRequest(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		result, err := c.GetArtifactDownloadRequest("task-123", "my-artifact")
+		result, err := c.GetArtifactDownloadRequest(t.Context(), "task-123", "my-artifact")
 		require.NoError(t, err)
 		require.Equal(t, "https://s3.example.com/artifacts/abc123", result.URL)
 		require.Equal(t, "task-123-my-artifact.tar", result.Filename)
@@ -698,7 +815,7 @@ func TestAPIClient_GetArtifactDownloadRequest(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		result, err := c.GetArtifactDownloadRequest("task-456", "my-dir")
+		result, err := c.GetArtifactDownloadRequest(t.Context(), "task-456", "my-dir")
 		require.NoError(t, err)
 		require.Equal(t, "directory", result.Kind)
 	})
@@ -717,7 +834,7 @@ func TestAPIClient_GetArtifactDownloadRequest(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		_, err := c.GetArtifactDownloadRequest("task-999", "missing")
+		_, err := c.GetArtifactDownloadRequest(t.Context(), "task-999", "missing")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "not found")
 	})
@@ -748,7 +865,7 @@ func TestAPIClient_GetArtifactDownloadRequest(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		_, err := c.GetArtifactDownloadRequest("task-123", "my-artifact-v1.2.3")
+		_, err := c.GetArtifactDownloadRequest(t.Context(), "task-123", "my-artifact-v1.2.3")
 		require.NoError(t, err)
 	})
 }
@@ -785,7 +902,7 @@ func TestAPIClient_GetArtifactDownloadRequestByTaskKey(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		result, err := c.GetArtifactDownloadRequestByTaskKey("run-123", "build", "my-artifact")
+		result, err := c.GetArtifactDownloadRequestByTaskKey(t.Context(), "run-123", "build", "my-artifact")
 		require.NoError(t, err)
 		require.Equal(t, "https://s3.example.com/artifacts/abc123", result.URL)
 		require.Equal(t, "file", result.Kind)
@@ -832,7 +949,7 @@ func TestAPIClient_GetAllArtifactDownloadRequests(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		results, err := c.GetAllArtifactDownloadRequests("task-123")
+		results, err := c.GetAllArtifactDownloadRequests(t.Context(), "task-123")
 		require.NoError(t, err)
 		require.Len(t, results, 2)
 		require.Equal(t, "artifact-a", results[0].Key)
@@ -852,7 +969,7 @@ func TestAPIClient_GetAllArtifactDownloadRequests(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		_, err := c.GetAllArtifactDownloadRequests("task-999")
+		_, err := c.GetAllArtifactDownloadRequests(t.Context(), "task-999")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "not found")
 	})
@@ -870,7 +987,7 @@ func TestAPIClient_GetAllArtifactDownloadRequests(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		results, err := c.GetAllArtifactDownloadRequests("task-123")
+		results, err := c.GetAllArtifactDownloadRequests(t.Context(), "task-123")
 		require.NoError(t, err)
 		require.Empty(t, results)
 	})
@@ -903,7 +1020,7 @@ func TestAPIClient_RunStatus(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		result, err := c.RunStatus(api.RunStatusConfig{RunID: "run-123", FailFast: true})
+		result, err := c.RunStatus(t.Context(), api.RunStatusConfig{RunID: "run-123", FailFast: true})
 		require.NoError(t, err)
 		require.NotNil(t, result.Status)
 		require.Equal(t, "", result.Status.Result)
@@ -934,7 +1051,7 @@ func TestAPIClient_RunStatus(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		_, err := c.RunStatus(api.RunStatusConfig{RunID: "run-123"})
+		_, err := c.RunStatus(t.Context(), api.RunStatusConfig{RunID: "run-123"})
 		require.NoError(t, err)
 	})
 
@@ -962,7 +1079,7 @@ func TestAPIClient_RunStatus(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		result, err := c.RunStatus(api.RunStatusConfig{RunID: "run-456"})
+		result, err := c.RunStatus(t.Context(), api.RunStatusConfig{RunID: "run-456"})
 		require.NoError(t, err)
 		require.NotNil(t, result.Status)
 		require.Equal(t, "succeeded", result.Status.Result)
@@ -991,7 +1108,7 @@ func TestAPIClient_RunStatus(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		result, err := c.RunStatus(api.RunStatusConfig{RunID: "nonexistent"})
+		result, err := c.RunStatus(t.Context(), api.RunStatusConfig{RunID: "nonexistent"})
 		require.NoError(t, err)
 		require.Nil(t, result.Status)
 		require.True(t, result.Polling.Completed)
@@ -1029,7 +1146,7 @@ func TestAPIClient_GetSandboxConnectionInfo(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		result, err := c.GetSandboxConnectionInfo("run-123", "")
+		result, err := c.GetSandboxConnectionInfo(t.Context(), "run-123", "")
 		require.NoError(t, err)
 		require.True(t, result.Sandboxable)
 		require.Equal(t, "192.168.1.1:22", result.Address)
@@ -1059,7 +1176,7 @@ func TestAPIClient_GetSandboxConnectionInfo(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		result, err := c.GetSandboxConnectionInfo("run-456", "")
+		result, err := c.GetSandboxConnectionInfo(t.Context(), "run-456", "")
 		require.NoError(t, err)
 		require.False(t, result.Sandboxable)
 		require.NotNil(t, result.Polling.BackoffMs)
@@ -1077,7 +1194,7 @@ func TestAPIClient_GetSandboxConnectionInfo(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		_, err := c.GetSandboxConnectionInfo("nonexistent", "")
+		_, err := c.GetSandboxConnectionInfo(t.Context(), "nonexistent", "")
 		require.Error(t, err)
 	})
 
@@ -1092,17 +1209,32 @@ func TestAPIClient_GetSandboxConnectionInfo(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		_, err := c.GetSandboxConnectionInfo("ended-run", "")
+		_, err := c.GetSandboxConnectionInfo(t.Context(), "ended-run", "")
 		require.Error(t, err)
 	})
 
+	t.Run("returns ErrUnauthorized on 401", func(t *testing.T) {
+		roundTrip := func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				Status:     "401 Unauthorized",
+				StatusCode: 401,
+				Body:       io.NopCloser(bytes.NewReader([]byte(`{"error": "Token expired"}`))),
+			}, nil
+		}
+
+		c := api.NewClientWithRoundTrip(roundTrip)
+
+		_, err := c.GetSandboxConnectionInfo(t.Context(), "run-123", "expired-token")
+		require.ErrorIs(t, err, errors.ErrUnauthorized)
+	})
+
 	t.Run("returns error when runID is empty", func(t *testing.T) {
 		c := api.NewClientWithRoundTrip(func(req *http.Request) (*http.Response, error) {
 			t.Fatal("should not make request")
 			return nil, nil
 		})
 
-		_, err := c.GetSandboxConnectionInfo("", "")
+		_, err := c.GetSandboxConnectionInfo(t.Context(), "", "")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "missing runID")
 	})
@@ -1131,7 +1263,7 @@ func TestAPIClient_GetSandboxConnectionInfo(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		result, err := c.GetSandboxConnectionInfo("run-123", "scoped-token-abc")
+		result, err := c.GetSandboxConnectionInfo(t.Context(), "run-123", "scoped-token-abc")
 		require.NoError(t, err)
 		require.True(t, result.Sandboxable)
 	})
@@ -1170,7 +1302,7 @@ func TestAPIClient_CreateSandboxToken(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		result, err := c.CreateSandboxToken(api.CreateSandboxTokenConfig{
+		result, err := c.CreateSandboxToken(t.Context(), api.CreateSandboxTokenConfig{
 			RunID: "run-123",
 		})
 		require.NoError(t, err)
@@ -1190,7 +1322,7 @@ func TestAPIClient_CreateSandboxToken(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		_, err := c.CreateSandboxToken(api.CreateSandboxTokenConfig{
+		_, err := c.CreateSandboxToken(t.Context(), api.CreateSandboxTokenConfig{
 			RunID: "run-123",
 		})
 		require.Error(t, err)
@@ -1213,7 +1345,7 @@ func TestAPIClient_GetRunPrompt(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		result, err := c.GetRunPrompt("run-123")
+		result, err := c.GetRunPrompt(t.Context(), "run-123")
 		require.NoError(t, err)
 		require.NotEmpty(t, result)
 	})
@@ -1229,7 +1361,7 @@ func TestAPIClient_GetRunPrompt(t *testing.T) {
 
 		c := api.NewClientWithRoundTrip(roundTrip)
 
-		_, err := c.GetRunPrompt("nonexistent")
+		_, err := c.GetRunPrompt(t.Context(), "nonexistent")
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "not found")
 	})
@@ -1253,7 +1385,7 @@ func TestAPIClient_DownloadArtifact(t *testing.T) {
 			return http.DefaultClient.Do(req)
 		})
 
-		result, err := c.DownloadArtifact(api.ArtifactDownloadRequestResult{
+		result, err := c.DownloadArtifact(t.Context(), api.ArtifactDownloadRequestResult{
 			URL:      server.URL,
 			Filename: "artifact.tar",
 			Kind:     "file",
@@ -1276,7 +1408,7 @@ func TestAPIClient_DownloadArtifact(t *testing.T) {
 			return http.DefaultClient.Do(req)
 		})
 
-		_, err := c.DownloadArtifact(api.ArtifactDownloadRequestResult{
+		_, err := c.DownloadArtifact(t.Context(), api.ArtifactDownloadRequestResult{
 			URL:      server.URL,
 			Filename: "artifact.tar",
 			Kind:     "file",
@@ -1299,7 +1431,7 @@ func TestAPIClient_DownloadArtifact(t *testing.T) {
 			return http.DefaultClient.Do(req)
 		})
 
-		_, err := c.DownloadArtifact(api.ArtifactDownloadRequestResult{
+		_, err := c.DownloadArtifact(t.Context(), api.ArtifactDownloadRequestResult{
 			URL:      server.URL,
 			Filename: "artifact.tar",
 			Kind:     "file",
@@ -1321,7 +1453,7 @@ func TestAPIClient_DownloadArtifact(t *testing.T) {
 			return http.DefaultClient.Do(req)
 		})
 
-		_, err := c.DownloadArtifact(api.ArtifactDownloadRequestResult{
+		_, err := c.DownloadArtifact(t.Context(), api.ArtifactDownloadRequestResult{
 			URL:      server.URL,
 			Filename: "artifact.tar",
 			Kind:     "file",
@@ -1350,7 +1482,7 @@ func TestAPIClient_DownloadArtifact(t *testing.T) {
 			return http.DefaultClient.Do(req)
 		})
 
-		result, err := c.DownloadArtifact(api.ArtifactDownloadRequestResult{
+		result, err := c.DownloadArtifact(t.Context(), api.ArtifactDownloadRequestResult{
 			URL:      server.URL,
 			Filename: "large-artifact.tar",
 			Kind:     "directory",
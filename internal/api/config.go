@@ -2,6 +2,7 @@ package api
 
 import (
 	"encoding/json"
+	"net/http"
 
 	"github.com/rwx-cloud/rwx/internal/accesstoken"
 	"github.com/rwx-cloud/rwx/internal/errors"
@@ -14,6 +15,12 @@ type Config struct {
 	AccessTokenBackend   accesstoken.Backend
 	VersionsBackend      versions.Backend
 	SkillVersionsBackend versions.Backend
+	// Transport is used for every request when set, so that proxy and TLS
+	// settings apply to downloads as well as to Cloud
+	Transport *http.Transport
+	// WrapTransport wraps the transport for requests to Cloud, such as to
+	// trace or record them
+	WrapTransport func(http.RoundTripper) http.RoundTripper
 }
 
 func (c Config) Validate() error {
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -18,11 +19,11 @@ type McpGetRunTestFailuresRequest struct {
 	RunUrls []string `json:"run_urls"`
 }
 
-func (c Client) McpGetRunTestFailures(cfg McpGetRunTestFailuresRequest) (*McpTextResult, error) {
-	return c.makeMcpTextRequest("/mint/api/mcp/get_run_test_failures", cfg)
+func (c Client) McpGetRunTestFailures(ctx context.Context, cfg McpGetRunTestFailuresRequest) (*McpTextResult, error) {
+	return c.makeMcpTextRequest(ctx, "/mint/api/mcp/get_run_test_failures", cfg)
 }
 
-func (c Client) makeMcpTextRequest(endpoint string, body any) (*McpTextResult, error) {
+func (c Client) makeMcpTextRequest(ctx context.Context, endpoint string, body any) (*McpTextResult, error) {
 	result := &McpTextResult{}
 
 	encodedBody, err := json.Marshal(body)
@@ -30,7 +31,7 @@ func (c Client) makeMcpTextRequest(endpoint string, body any) (*McpTextResult, e
 		return result, errors.Wrap(err, "unable to encode as JSON")
 	}
 
-	req, err := http.NewRequest(http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(encodedBody))
 	if err != nil {
 		return result, errors.Wrap(err, "unable to create new HTTP request")
 	}
@@ -40,7 +40,7 @@ func TestClient_McpGetRunTestFailures(t *testing.T) {
 		}
 
 		client := api.NewClientWithRoundTrip(roundTrip)
-		result, err := client.McpGetRunTestFailures(api.McpGetRunTestFailuresRequest{
+		result, err := client.McpGetRunTestFailures(t.Context(), api.McpGetRunTestFailuresRequest{
 			RunUrls: []string{"https://cloud.rwx.com/mint/org/runs/123", "https://cloud.rwx.com/mint/org/runs/456"},
 		})
 
@@ -59,7 +59,7 @@ func TestClient_McpGetRunTestFailures(t *testing.T) {
 		}
 
 		client := api.NewClientWithRoundTrip(roundTrip)
-		_, err := client.McpGetRunTestFailures(api.McpGetRunTestFailuresRequest{
+		_, err := client.McpGetRunTestFailures(t.Context(), api.McpGetRunTestFailuresRequest{
 			RunUrls: []string{"https://cloud.rwx.com/mint/org/runs/nonexistent"},
 		})
 
@@ -84,7 +84,7 @@ func TestClient_McpGetRunTestFailures(t *testing.T) {
 		}
 
 		client := api.NewClientWithRoundTrip(roundTrip)
-		_, err := client.McpGetRunTestFailures(api.McpGetRunTestFailuresRequest{
+		_, err := client.McpGetRunTestFailures(t.Context(), api.McpGetRunTestFailuresRequest{
 			RunUrls: []string{"https://cloud.rwx.com/mint/org/runs/123"},
 		})
 
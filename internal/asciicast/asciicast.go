@@ -0,0 +1,271 @@
+// Package asciicast records and plays back terminal sessions in the asciicast
+// v2 format used by asciinema (https://docs.asciinema.org/manual/asciicast/v2/).
+//
+// A recording is a JSON header line followed by one JSON array per event:
+//
+//	{"version": 2, "width": 80, "height": 24, "timestamp": 1504467315}
+//	[0.248848, "o", "hello\r\n"]
+//	[1.001376, "r", "100x50"]
+package asciicast
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rwx-cloud/rwx/internal/errors"
+)
+
+const Version = 2
+
+const (
+	EventOutput = "o"
+	EventInput  = "i"
+	EventResize = "r"
+)
+
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+type Event struct {
+	// Time is the number of seconds since the start of the recording
+	Time float64
+	Type string
+	Data string
+}
+
+func (e Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]any{e.Time, e.Type, e.Data})
+}
+
+func (e *Event) UnmarshalJSON(data []byte) error {
+	var fields []json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	if len(fields) != 3 {
+		return fmt.Errorf("expected 3 fields in event, got %d", len(fields))
+	}
+	if err := json.Unmarshal(fields[0], &e.Time); err != nil {
+		return errors.Wrap(err, "invalid event time")
+	}
+	if err := json.Unmarshal(fields[1], &e.Type); err != nil {
+		return errors.Wrap(err, "invalid event type")
+	}
+	if err := json.Unmarshal(fields[2], &e.Data); err != nil {
+		return errors.Wrap(err, "invalid event data")
+	}
+	return nil
+}
+
+// Recorder writes a recording as it happens. Every write is recorded as an
+// output event, so a Recorder can be used wherever terminal output is
+// written. It is safe for concurrent use.
+type Recorder struct {
+	w       io.Writer
+	start   time.Time
+	lock    sync.Mutex
+	pending []byte
+	err     error
+}
+
+// NewRecorder writes the header to w and returns a Recorder that appends
+// events to it. The header's Version and Timestamp are filled in.
+func NewRecorder(w io.Writer, header Header) (*Recorder, error) {
+	start := time.Now()
+	header.Version = Version
+	header.Timestamp = start.Unix()
+
+	line, err := json.Marshal(header)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to encode recording header")
+	}
+	if _, err := w.Write(append(line, '\n')); err != nil {
+		return nil, errors.Wrap(err, "unable to write recording header")
+	}
+
+	return &Recorder{w: w, start: start}, nil
+}
+
+// Create creates the file at path and starts a recording in it. Closing the
+// Recorder closes the file.
+func Create(path string, header Header) (*Recorder, error) {
+	fd, err := os.Create(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to create recording %q", path)
+	}
+
+	recorder, err := NewRecorder(fd, header)
+	if err != nil {
+		fd.Close()
+		return nil, err
+	}
+	return recorder, nil
+}
+
+// Write records p as output. Bytes that end in the middle of a UTF-8
+// sequence are held back until the rest of the sequence is written, since
+// event data must be valid UTF-8.
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	data := append(r.pending, p...)
+	complete := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				complete = i
+			}
+			break
+		}
+	}
+
+	r.pending = append([]byte(nil), data[complete:]...)
+	if complete > 0 {
+		r.writeEvent(EventOutput, string(data[:complete]))
+	}
+
+	// Recording must never interrupt the session being recorded, so errors
+	// are only reported by Close
+	return len(p), nil
+}
+
+// Resize records a change of the terminal size.
+func (r *Recorder) Resize(width, height int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.writeEvent(EventResize, fmt.Sprintf("%dx%d", width, height))
+}
+
+func (r *Recorder) writeEvent(eventType, data string) {
+	if r.err != nil {
+		return
+	}
+
+	elapsed := time.Since(r.start).Seconds()
+	line, err := json.Marshal(Event{Time: float64(int64(elapsed*1e6)) / 1e6, Type: eventType, Data: data})
+	if err != nil {
+		r.err = err
+		return
+	}
+	if _, err := r.w.Write(append(line, '\n')); err != nil {
+		r.err = err
+	}
+}
+
+// Close flushes any held back output and closes the underlying writer if it
+// is an io.Closer. It returns the first error encountered while recording.
+func (r *Recorder) Close() error {
+	r.lock.Lock()
+	if len(r.pending) > 0 {
+		r.writeEvent(EventOutput, string(r.pending))
+		r.pending = nil
+	}
+	err := r.err
+	r.lock.Unlock()
+
+	if closer, ok := r.w.(io.Closer); ok {
+		if closeErr := closer.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return errors.Wrap(err, "unable to write recording")
+	}
+	return nil
+}
+
+// Read parses a recording.
+func Read(r io.Reader) (Header, []Event, error) {
+	var header Header
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return header, nil, errors.Wrap(err, "unable to read recording")
+		}
+		return header, nil, errors.New("recording is empty")
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return header, nil, errors.Wrap(err, "invalid recording header")
+	}
+	if header.Version != Version {
+		return header, nil, fmt.Errorf("unsupported asciicast version %d, only version %d is supported", header.Version, Version)
+	}
+
+	var events []Event
+	for line := 2; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return header, nil, errors.Wrapf(err, "invalid event on line %d", line)
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return header, nil, errors.Wrap(err, "unable to read recording")
+	}
+
+	return header, events, nil
+}
+
+type PlayOptions struct {
+	// Speed multiplies the playback speed. Values <= 0 play at normal speed.
+	Speed float64
+	// IdleTimeLimit caps the pause between events. Zero means no limit.
+	IdleTimeLimit time.Duration
+	// Sleep waits between events. It defaults to time.Sleep.
+	Sleep func(time.Duration)
+}
+
+// Play writes the output events to w with their original timing. Input and
+// resize events are skipped, since a terminal can't be resized from within.
+func Play(w io.Writer, events []Event, opts PlayOptions) error {
+	speed := opts.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	sleep := opts.Sleep
+	if sleep == nil {
+		sleep = time.Sleep
+	}
+
+	previous := 0.0
+	for _, event := range events {
+		if event.Type != EventOutput {
+			continue
+		}
+
+		delay := time.Duration((event.Time - previous) * float64(time.Second))
+		previous = event.Time
+		if opts.IdleTimeLimit > 0 && delay > opts.IdleTimeLimit {
+			delay = opts.IdleTimeLimit
+		}
+		if delay > 0 {
+			sleep(time.Duration(float64(delay) / speed))
+		}
+
+		if _, err := io.WriteString(w, event.Data); err != nil {
+			return errors.Wrap(err, "unable to write recording output")
+		}
+	}
+
+	return nil
+}
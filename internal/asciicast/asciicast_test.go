@@ -0,0 +1,130 @@
+package asciicast
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	t.Run("writes a header and output events", func(t *testing.T) {
+		var buf bytes.Buffer
+		recorder, err := NewRecorder(&buf, Header{Width: 100, Height: 40, Title: "rwx debug"})
+		require.NoError(t, err)
+
+		_, _ = recorder.Write([]byte("hello\r\n"))
+		recorder.Resize(120, 50)
+		_, _ = recorder.Write([]byte("world"))
+		require.NoError(t, recorder.Close())
+
+		header, events, err := Read(&buf)
+		require.NoError(t, err)
+		require.Equal(t, 2, header.Version)
+		require.Equal(t, 100, header.Width)
+		require.Equal(t, 40, header.Height)
+		require.Equal(t, "rwx debug", header.Title)
+		require.NotZero(t, header.Timestamp)
+
+		require.Len(t, events, 3)
+		require.Equal(t, Event{Time: events[0].Time, Type: EventOutput, Data: "hello\r\n"}, events[0])
+		require.Equal(t, Event{Time: events[1].Time, Type: EventResize, Data: "120x50"}, events[1])
+		require.Equal(t, Event{Time: events[2].Time, Type: EventOutput, Data: "world"}, events[2])
+		require.LessOrEqual(t, events[0].Time, events[1].Time)
+		require.LessOrEqual(t, events[1].Time, events[2].Time)
+	})
+
+	t.Run("holds back incomplete UTF-8 sequences", func(t *testing.T) {
+		var buf bytes.Buffer
+		recorder, err := NewRecorder(&buf, Header{Width: 80, Height: 24})
+		require.NoError(t, err)
+
+		check := []byte("✓")
+		_, _ = recorder.Write(append([]byte("ok "), check[:1]...))
+		_, _ = recorder.Write(check[1:])
+		require.NoError(t, recorder.Close())
+
+		_, events, err := Read(&buf)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		require.Equal(t, "ok ", events[0].Data)
+		require.Equal(t, "✓", events[1].Data)
+	})
+
+	t.Run("encodes events as arrays", func(t *testing.T) {
+		var buf bytes.Buffer
+		recorder, err := NewRecorder(&buf, Header{Width: 80, Height: 24})
+		require.NoError(t, err)
+		_, _ = recorder.Write([]byte("hi"))
+		require.NoError(t, recorder.Close())
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+		require.Contains(t, lines[0], `"version":2`)
+		require.Regexp(t, `^\[[0-9.e-]+,"o","hi"\]$`, lines[1])
+	})
+}
+
+func TestRead(t *testing.T) {
+	t.Run("parses an asciinema recording", func(t *testing.T) {
+		recording := `{"version": 2, "width": 80, "height": 24, "timestamp": 1504467315, "env": {"TERM": "xterm-256color"}}
+[0.248848, "o", "\u001b[1;31mHello \u001b[32mWorld!\u001b[0m\n"]
+[1.001376, "o", "I am \rThis is on the next line."]
+`
+		header, events, err := Read(strings.NewReader(recording))
+		require.NoError(t, err)
+		require.Equal(t, "xterm-256color", header.Env["TERM"])
+		require.Len(t, events, 2)
+		require.Equal(t, 1.001376, events[1].Time)
+		require.Equal(t, "\x1b[1;31mHello \x1b[32mWorld!\x1b[0m\n", events[0].Data)
+	})
+
+	t.Run("rejects other versions", func(t *testing.T) {
+		_, _, err := Read(strings.NewReader(`{"version": 1, "width": 80, "height": 24}`))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unsupported asciicast version 1")
+	})
+
+	t.Run("rejects empty recordings", func(t *testing.T) {
+		_, _, err := Read(strings.NewReader(""))
+		require.Error(t, err)
+	})
+
+	t.Run("reports malformed events", func(t *testing.T) {
+		_, _, err := Read(strings.NewReader("{\"version\": 2, \"width\": 80, \"height\": 24}\n[0.1, \"o\"]\n"))
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "line 2")
+	})
+}
+
+func TestPlay(t *testing.T) {
+	events := []Event{
+		{Time: 0.5, Type: EventOutput, Data: "a"},
+		{Time: 0.6, Type: EventResize, Data: "100x50"},
+		{Time: 1.5, Type: EventOutput, Data: "b"},
+		{Time: 11.5, Type: EventOutput, Data: "c"},
+	}
+
+	t.Run("writes output with the original timing", func(t *testing.T) {
+		var out bytes.Buffer
+		var sleeps []time.Duration
+		err := Play(&out, events, PlayOptions{Sleep: func(d time.Duration) { sleeps = append(sleeps, d) }})
+		require.NoError(t, err)
+		require.Equal(t, "abc", out.String())
+		require.Equal(t, []time.Duration{500 * time.Millisecond, time.Second, 10 * time.Second}, sleeps)
+	})
+
+	t.Run("applies speed and idle time limit", func(t *testing.T) {
+		var out bytes.Buffer
+		var sleeps []time.Duration
+		err := Play(&out, events, PlayOptions{
+			Speed:         2,
+			IdleTimeLimit: 2 * time.Second,
+			Sleep:         func(d time.Duration) { sleeps = append(sleeps, d) },
+		})
+		require.NoError(t, err)
+		require.Equal(t, []time.Duration{250 * time.Millisecond, 500 * time.Millisecond, time.Second}, sleeps)
+	})
+}
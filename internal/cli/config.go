@@ -4,10 +4,13 @@ import (
 	"io"
 
 	"github.com/rwx-cloud/rwx/internal/accesstoken"
+	"github.com/rwx-cloud/rwx/internal/config"
 	"github.com/rwx-cloud/rwx/internal/docker"
 	"github.com/rwx-cloud/rwx/internal/docs"
 	"github.com/rwx-cloud/rwx/internal/docstoken"
 	"github.com/rwx-cloud/rwx/internal/errors"
+	"github.com/rwx-cloud/rwx/internal/output"
+	"github.com/rwx-cloud/rwx/internal/profiles"
 	"github.com/rwx-cloud/rwx/internal/telemetry"
 	"github.com/rwx-cloud/rwx/internal/versions"
 )
@@ -15,6 +18,7 @@ import (
 type Config struct {
 	APIClient            APIClient
 	SSHClient            SSHClient
+	NewSSHClient         func(stdout, stderr io.Writer) SSHClient
 	GitClient            GitClient
 	DockerCLI            docker.Client
 	DocsClient           docs.Client
@@ -22,6 +26,10 @@ type Config struct {
 	AccessTokenBackend   accesstoken.Backend
 	VersionsBackend      versions.Backend
 	SkillVersionsBackend versions.Backend
+	Profiles             *profiles.Store
+	Profile              string
+	Settings             *config.Layered
+	Output               output.Options
 	TelemetryCollector   *telemetry.Collector
 	Stdin                io.Reader
 	Stdout               io.Writer
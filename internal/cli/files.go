@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/rwx-cloud/rwx/internal/api"
+	"github.com/rwx-cloud/rwx/internal/config"
 	"github.com/rwx-cloud/rwx/internal/errors"
 	"github.com/rwx-cloud/rwx/internal/fs"
 )
@@ -42,6 +43,16 @@ func FindDefaultSandboxConfigFile() string {
 	return fallback
 }
 
+// FindProjectConfigFile returns the path of cli.yml in the closest .rwx
+// directory, or "" when there is no .rwx directory.
+func FindProjectConfigFile() string {
+	rwxDir, err := findRwxDirectoryPath("")
+	if err != nil || rwxDir == "" {
+		return ""
+	}
+	return filepath.Join(rwxDir, config.ProjectFilename)
+}
+
 func RwxDirectoryEntries(dir string) ([]RwxDirectoryEntry, error) {
 	return rwxDirectoryEntries(dir)
 }
@@ -229,6 +240,11 @@ func readRwxDirectoryEntries(paths []string, relativeTo string) ([]RwxDirectoryE
 					return filepath.SkipDir
 				}
 			}
+			// The project's CLI settings aren't a run definition, and aren't
+			// needed by runs
+			if entry.IsFile() && isProjectConfigFile(subpath, relativeTo) {
+				return nil
+			}
 
 			totalSize += entrySize
 			if strings.Contains(entry.Path, ".patches") {
@@ -412,6 +428,20 @@ func isJSON(content []byte) bool {
 	return len(content) > 0 && content[0] == '{' && json.Unmarshal(content, &jsonContent) == nil
 }
 
+// isProjectConfigFile reports whether path is the project settings file at the
+// top of rwxDir, or of any .rwx or .mint directory.
+func isProjectConfigFile(path, rwxDir string) bool {
+	if filepath.Base(path) != config.ProjectFilename {
+		return false
+	}
+
+	dir := filepath.Dir(path)
+	if rwxDir != "" && dir == filepath.Clean(rwxDir) {
+		return true
+	}
+	return filepath.Base(dir) == ".rwx" || filepath.Base(dir) == ".mint"
+}
+
 func isYAMLFile(entry RwxDirectoryEntry) bool {
 	return entry.IsFile() && (strings.HasSuffix(entry.OriginalPath, ".yml") || strings.HasSuffix(entry.OriginalPath, ".yaml"))
 }
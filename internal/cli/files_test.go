@@ -123,6 +123,37 @@ func TestReadRwxDirectoryEntries_SkipsSandboxes(t *testing.T) {
 	})
 }
 
+func TestGetFileOrDirectoryYAMLEntries_SkipsProjectConfig(t *testing.T) {
+	t.Run("does not treat cli.yml as a run definition", func(t *testing.T) {
+		t.Chdir(t.TempDir())
+
+		rwxDir := ".rwx"
+		require.NoError(t, os.MkdirAll(filepath.Join(rwxDir, "nested"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(rwxDir, "ci.yml"), []byte("tasks: []\n"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(rwxDir, "cli.yml"), []byte("vault: team\n"), 0644))
+		// Only the settings file at the top of the directory is skipped
+		require.NoError(t, os.WriteFile(filepath.Join(rwxDir, "nested", "cli.yml"), []byte("tasks: []\n"), 0644))
+
+		// As linted when no files are given
+		entries, err := cli.GetFileOrDirectoryYAMLEntries(nil, rwxDir)
+		require.NoError(t, err)
+		paths := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			paths = append(paths, entry.Path)
+		}
+		require.Equal(t, []string{"ci.yml", "nested/cli.yml"}, paths)
+
+		// As linted when the directory is given
+		entries, err = cli.GetFileOrDirectoryYAMLEntries([]string{rwxDir}, "")
+		require.NoError(t, err)
+		paths = paths[:0]
+		for _, entry := range entries {
+			paths = append(paths, entry.Path)
+		}
+		require.Equal(t, []string{".rwx/ci.yml", ".rwx/nested/cli.yml"}, paths)
+	})
+}
+
 func TestFindRunDefinitionFile(t *testing.T) {
 	t.Run("when file exists in pwd", func(t *testing.T) {
 		t.Chdir(t.TempDir())
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"io"
 	"os/exec"
 
@@ -12,44 +13,45 @@ import (
 )
 
 type APIClient interface {
-	GetSkillLatestVersion() (string, error)
-	GetDebugConnectionInfo(debugKey string) (api.DebugConnectionInfo, error)
-	GetSandboxConnectionInfo(runID, scopedToken string) (api.SandboxConnectionInfo, error)
-	CreateSandboxToken(api.CreateSandboxTokenConfig) (*api.CreateSandboxTokenResult, error)
-	GetDispatch(api.GetDispatchConfig) (*api.GetDispatchResult, error)
-	InitiateRun(api.InitiateRunConfig) (*api.InitiateRunResult, error)
-	InitiateDispatch(api.InitiateDispatchConfig) (*api.InitiateDispatchResult, error)
-	ObtainAuthCode(api.ObtainAuthCodeConfig) (*api.ObtainAuthCodeResult, error)
-	AcquireToken(tokenUrl string) (*api.AcquireTokenResult, error)
-	Whoami() (*api.WhoamiResult, error)
-	CreateDocsToken() (*api.DocsTokenResult, error)
-	SetSecretsInVault(api.SetSecretsInVaultConfig) (*api.SetSecretsInVaultResult, error)
-	CreateVault(api.CreateVaultConfig) (*api.CreateVaultResult, error)
-	CreateVaultOidcToken(api.CreateVaultOidcTokenConfig) (*api.CreateVaultOidcTokenResult, error)
-	DeleteSecret(api.DeleteSecretConfig) (*api.DeleteSecretResult, error)
-	SetVar(api.SetVarConfig) (*api.SetVarResult, error)
-	ShowVar(api.ShowVarConfig) (*api.ShowVarResult, error)
-	DeleteVar(api.DeleteVarConfig) (*api.DeleteVarResult, error)
-	GetPackageVersions() (*api.PackageVersionsResult, error)
-	GetPackageDocumentation(packageName string) (*api.PackageDocumentationResult, error)
-	GetDefaultBase() (api.DefaultBaseResult, error)
-	StartImagePush(cfg api.StartImagePushConfig) (api.StartImagePushResult, error)
-	ImagePushStatus(pushID string) (api.ImagePushStatusResult, error)
-	TaskKeyStatus(api.TaskKeyStatusConfig) (api.TaskStatusResult, error)
-	TaskIDStatus(api.TaskIDStatusConfig) (api.TaskStatusResult, error)
-	RunStatus(api.RunStatusConfig) (api.RunStatusResult, error)
-	GetLogDownloadRequest(taskId string) (api.LogDownloadRequestResult, error)
-	GetLogDownloadRequestByTaskKey(runID, taskKey string) (api.LogDownloadRequestResult, error)
-	DownloadLogs(api.LogDownloadRequestResult, ...int) ([]byte, error)
-	GetAllArtifactDownloadRequests(taskId string) ([]api.ArtifactDownloadRequestResult, error)
-	GetAllArtifactDownloadRequestsByTaskKey(runID, taskKey string) ([]api.ArtifactDownloadRequestResult, error)
-	GetArtifactDownloadRequest(taskId, artifactKey string) (api.ArtifactDownloadRequestResult, error)
-	GetArtifactDownloadRequestByTaskKey(runID, taskKey, artifactKey string) (api.ArtifactDownloadRequestResult, error)
-	DownloadArtifact(api.ArtifactDownloadRequestResult) ([]byte, error)
-	GetRunPrompt(runID string) (string, error)
-	GetSandboxInitTemplate() (api.SandboxInitTemplateResult, error)
-	ListSandboxRuns() (*api.ListSandboxRunsResult, error)
-	CancelRun(runID, scopedToken string) error
+	GetSkillLatestVersion(ctx context.Context) (string, error)
+	GetDebugConnectionInfo(ctx context.Context, debugKey string) (api.DebugConnectionInfo, error)
+	GetSandboxConnectionInfo(ctx context.Context, runID, scopedToken string) (api.SandboxConnectionInfo, error)
+	CreateSandboxToken(context.Context, api.CreateSandboxTokenConfig) (*api.CreateSandboxTokenResult, error)
+	GetDispatch(context.Context, api.GetDispatchConfig) (*api.GetDispatchResult, error)
+	InitiateRun(context.Context, api.InitiateRunConfig) (*api.InitiateRunResult, error)
+	InitiateDispatch(context.Context, api.InitiateDispatchConfig) (*api.InitiateDispatchResult, error)
+	ObtainAuthCode(context.Context, api.ObtainAuthCodeConfig) (*api.ObtainAuthCodeResult, error)
+	AcquireToken(ctx context.Context, tokenUrl string) (*api.AcquireTokenResult, error)
+	Whoami(ctx context.Context) (*api.WhoamiResult, error)
+	CreateDocsToken(ctx context.Context) (*api.DocsTokenResult, error)
+	RevokeToken(ctx context.Context) error
+	SetSecretsInVault(context.Context, api.SetSecretsInVaultConfig) (*api.SetSecretsInVaultResult, error)
+	CreateVault(context.Context, api.CreateVaultConfig) (*api.CreateVaultResult, error)
+	CreateVaultOidcToken(context.Context, api.CreateVaultOidcTokenConfig) (*api.CreateVaultOidcTokenResult, error)
+	DeleteSecret(context.Context, api.DeleteSecretConfig) (*api.DeleteSecretResult, error)
+	SetVar(context.Context, api.SetVarConfig) (*api.SetVarResult, error)
+	ShowVar(context.Context, api.ShowVarConfig) (*api.ShowVarResult, error)
+	DeleteVar(context.Context, api.DeleteVarConfig) (*api.DeleteVarResult, error)
+	GetPackageVersions(ctx context.Context) (*api.PackageVersionsResult, error)
+	GetPackageDocumentation(ctx context.Context, packageName string) (*api.PackageDocumentationResult, error)
+	GetDefaultBase(ctx context.Context) (api.DefaultBaseResult, error)
+	StartImagePush(ctx context.Context, cfg api.StartImagePushConfig) (api.StartImagePushResult, error)
+	ImagePushStatus(ctx context.Context, pushID string) (api.ImagePushStatusResult, error)
+	TaskKeyStatus(context.Context, api.TaskKeyStatusConfig) (api.TaskStatusResult, error)
+	TaskIDStatus(context.Context, api.TaskIDStatusConfig) (api.TaskStatusResult, error)
+	RunStatus(context.Context, api.RunStatusConfig) (api.RunStatusResult, error)
+	GetLogDownloadRequest(ctx context.Context, taskId string) (api.LogDownloadRequestResult, error)
+	GetLogDownloadRequestByTaskKey(ctx context.Context, runID, taskKey string) (api.LogDownloadRequestResult, error)
+	DownloadLogs(context.Context, api.LogDownloadRequestResult, ...int) ([]byte, error)
+	GetAllArtifactDownloadRequests(ctx context.Context, taskId string) ([]api.ArtifactDownloadRequestResult, error)
+	GetAllArtifactDownloadRequestsByTaskKey(ctx context.Context, runID, taskKey string) ([]api.ArtifactDownloadRequestResult, error)
+	GetArtifactDownloadRequest(ctx context.Context, taskId, artifactKey string) (api.ArtifactDownloadRequestResult, error)
+	GetArtifactDownloadRequestByTaskKey(ctx context.Context, runID, taskKey, artifactKey string) (api.ArtifactDownloadRequestResult, error)
+	DownloadArtifact(context.Context, api.ArtifactDownloadRequestResult) ([]byte, error)
+	GetRunPrompt(ctx context.Context, runID string) (string, error)
+	GetSandboxInitTemplate(ctx context.Context) (api.SandboxInitTemplateResult, error)
+	ListSandboxRuns(ctx context.Context) (*api.ListSandboxRunsResult, error)
+	CancelRun(ctx context.Context, runID, scopedToken string) error
 }
 
 var _ APIClient = api.Client{}
@@ -57,8 +59,9 @@ var _ APIClient = api.Client{}
 type SSHClient interface {
 	Close() error
 	Connect(addr string, cfg gossh.ClientConfig) error
-	InteractiveSession() error
+	InteractiveSession(recorder ssh.TerminalRecorder) error
 	ExecuteCommand(command string) (int, error)
+	ExecuteCommandWithSignals(command string, signals <-chan gossh.Signal) (int, error)
 	ExecuteCommandWithStdin(command string, stdin io.Reader) (int, error)
 	ExecuteCommandWithOutput(command string) (int, string, error)
 	ExecuteCommandWithStdinAndCombinedOutput(command string, stdin io.Reader) (int, string, error)
@@ -75,6 +78,7 @@ type GitClient interface {
 	GeneratePatch(pathspec []string) ([]byte, *git.LFSChangedFilesMetadata, error)
 	ApplyPatch(patch []byte) *exec.Cmd
 	ApplyPatchReject(patch []byte) *exec.Cmd
+	MergeFile(current, base, other string) (int, error)
 	IsInstalled() bool
 	IsInsideWorkTree() bool
 }
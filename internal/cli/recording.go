@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"os"
+	"time"
+
+	"github.com/rwx-cloud/rwx/internal/asciicast"
+	"github.com/rwx-cloud/rwx/internal/errors"
+	"github.com/rwx-cloud/rwx/internal/ssh"
+	"golang.org/x/term"
+)
+
+type ReplayConfig struct {
+	File          string
+	Speed         float64
+	IdleTimeLimit time.Duration
+}
+
+// startRecording creates an asciicast recording at path. The recording is
+// sized to the terminal when stdout is one, and to 80x24 otherwise.
+func (s Service) startRecording(path, title string) (*asciicast.Recorder, error) {
+	width, height := 80, 24
+	if s.StdoutIsTTY {
+		if w, h, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+			width, height = w, h
+		}
+	}
+
+	env := make(map[string]string)
+	for _, name := range []string{"SHELL", "TERM"} {
+		if value := os.Getenv(name); value != "" {
+			env[name] = value
+		}
+	}
+
+	return asciicast.Create(path, asciicast.Header{
+		Width:  width,
+		Height: height,
+		Title:  title,
+		Env:    env,
+	})
+}
+
+// terminalRecorder avoids passing a nil *asciicast.Recorder as a non-nil
+// ssh.TerminalRecorder.
+func terminalRecorder(recorder *asciicast.Recorder) ssh.TerminalRecorder {
+	if recorder == nil {
+		return nil
+	}
+	return recorder
+}
+
+// Replay plays a recording made with --record back to stdout.
+func (s Service) Replay(cfg ReplayConfig) error {
+	fd, err := os.Open(cfg.File)
+	if err != nil {
+		return errors.Wrapf(err, "unable to open recording %q", cfg.File)
+	}
+	defer fd.Close()
+
+	_, events, err := asciicast.Read(fd)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read recording %q", cfg.File)
+	}
+
+	if err := asciicast.Play(s.Stdout, events, asciicast.PlayOptions{
+		Speed:         cfg.Speed,
+		IdleTimeLimit: cfg.IdleTimeLimit,
+	}); err != nil {
+		return err
+	}
+
+	s.recordTelemetry("replay", map[string]any{
+		"event_count": len(events),
+	})
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package cli_test
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rwx-cloud/rwx/internal/api"
+	"github.com/rwx-cloud/rwx/internal/asciicast"
+	"github.com/rwx-cloud/rwx/internal/cli"
+	"github.com/rwx-cloud/rwx/internal/mocks"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestService_ExecSandbox_Record(t *testing.T) {
+	t.Run("records the command's output", func(t *testing.T) {
+		setup := setupTest(t)
+		recordingPath := filepath.Join(setup.tmp, "exec.cast")
+
+		setup.mockAPI.MockGetSandboxConnectionInfo = func(id, token string) (api.SandboxConnectionInfo, error) {
+			return api.SandboxConnectionInfo{
+				Sandboxable:    true,
+				Address:        "192.168.1.1:22",
+				PrivateUserKey: sandboxPrivateTestKey,
+				PublicHostKey:  sandboxPublicTestKey,
+			}, nil
+		}
+		setup.service.NewSSHClient = func(stdout, stderr io.Writer) cli.SSHClient {
+			return &mocks.SSH{
+				MockConnect: func(addr string, _ ssh.ClientConfig) error {
+					return nil
+				},
+				MockExecuteCommand: func(cmd string) (int, error) {
+					if cmd == "echo hello" {
+						fmt.Fprint(stdout, "hello\n")
+						fmt.Fprint(stderr, "warning\n")
+					}
+					return 0, nil
+				},
+			}
+		}
+
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			Command: []string{"echo", "hello"},
+			RunID:   "run-123",
+			Json:    true,
+			Record:  recordingPath,
+		})
+		require.NoError(t, err)
+		require.Contains(t, setup.mockStdout.String(), "hello\n")
+
+		fd, err := os.Open(recordingPath)
+		require.NoError(t, err)
+		defer fd.Close()
+
+		header, events, err := asciicast.Read(fd)
+		require.NoError(t, err)
+		require.Equal(t, "rwx sandbox exec -- echo hello", header.Title)
+		require.Equal(t, 80, header.Width)
+
+		var output string
+		for _, event := range events {
+			output += event.Data
+		}
+		require.Contains(t, output, "hello\n")
+		require.Contains(t, output, "warning\n")
+	})
+}
+
+func TestService_Replay(t *testing.T) {
+	t.Run("plays the recorded output", func(t *testing.T) {
+		setup := setupTest(t)
+		recordingPath := filepath.Join(setup.tmp, "session.cast")
+		recording := `{"version": 2, "width": 80, "height": 24}
+[0.001, "o", "hello "]
+[0.002, "r", "100x50"]
+[0.003, "o", "world\r\n"]
+`
+		require.NoError(t, os.WriteFile(recordingPath, []byte(recording), 0o644))
+
+		err := setup.service.Replay(cli.ReplayConfig{File: recordingPath, Speed: 10})
+		require.NoError(t, err)
+		require.Equal(t, "hello world\r\n", setup.mockStdout.String())
+
+		event := findEvent(setup.drainEvents(), "replay")
+		require.NotNil(t, event)
+	})
+
+	t.Run("returns an error for a missing recording", func(t *testing.T) {
+		setup := setupTest(t)
+
+		err := setup.service.Replay(cli.ReplayConfig{File: filepath.Join(setup.tmp, "missing.cast")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unable to open recording")
+	})
+
+	t.Run("returns an error for an invalid recording", func(t *testing.T) {
+		setup := setupTest(t)
+		recordingPath := filepath.Join(setup.tmp, "session.cast")
+		require.NoError(t, os.WriteFile(recordingPath, []byte("not a recording\n"), 0o644))
+
+		err := setup.service.Replay(cli.ReplayConfig{File: recordingPath})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unable to read recording")
+	})
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"al.essio.dev/pkg/shellescape"
+	"github.com/rwx-cloud/rwx/internal/dotenv"
+	"github.com/rwx-cloud/rwx/internal/errors"
+)
+
+var sandboxEnvKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ParseSandboxEnv merges the variables in envFile (a dotenv file) with the
+// `KEY=value` pairs in env. Pairs given in env take precedence over the file.
+func ParseSandboxEnv(env []string, envFile string) (map[string]string, error) {
+	vars := make(map[string]string)
+
+	if envFile != "" {
+		fd, err := os.Open(envFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error while opening %q", envFile)
+		}
+		defer fd.Close()
+
+		fileContent, err := io.ReadAll(fd)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error while reading %q", envFile)
+		}
+
+		if err := dotenv.ParseBytes(fileContent, vars); err != nil {
+			return nil, errors.Wrapf(err, "error while parsing %q", envFile)
+		}
+	}
+
+	for _, pair := range env {
+		key, value, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("Invalid environment variable '%s'. Environment variables must be specified in the form 'KEY=value'.", pair)
+		}
+		vars[key] = value
+	}
+
+	for key := range vars {
+		if !sandboxEnvKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("Invalid environment variable name '%s'.", key)
+		}
+	}
+
+	return vars, nil
+}
+
+// BuildSandboxCommand renders command as a single remote shell command line.
+// Every argument, environment assignment and the working directory is quoted
+// individually, so nothing is re-interpreted by the remote shell. Variables
+// are passed through env(1) rather than shell assignments.
+func BuildSandboxCommand(command []string, env map[string]string, workdir string) string {
+	var parts []string
+
+	if workdir != "" {
+		parts = append(parts, "cd", "--", shellescape.Quote(workdir), "&&")
+	}
+
+	if len(env) > 0 {
+		keys := make([]string, 0, len(env))
+		for key := range env {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		parts = append(parts, "env", "--")
+		for _, key := range keys {
+			parts = append(parts, shellescape.Quote(key+"="+env[key]))
+		}
+	}
+
+	parts = append(parts, shellescape.QuoteCommand(command))
+	return strings.Join(parts, " ")
+}
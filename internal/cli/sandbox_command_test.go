@@ -0,0 +1,72 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rwx-cloud/rwx/internal/cli"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSandboxEnv(t *testing.T) {
+	t.Run("parses KEY=value pairs", func(t *testing.T) {
+		env, err := cli.ParseSandboxEnv([]string{"FOO=bar", "URL=postgres://u:p@h/db?x=1"}, "")
+
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"FOO": "bar", "URL": "postgres://u:p@h/db?x=1"}, env)
+	})
+
+	t.Run("reads an env file and lets pairs override it", func(t *testing.T) {
+		envFile := filepath.Join(t.TempDir(), ".env")
+		require.NoError(t, os.WriteFile(envFile, []byte("# comment\nFOO=from-file\nexport BAR=\"two words\"\n"), 0o644))
+
+		env, err := cli.ParseSandboxEnv([]string{"FOO=from-flag"}, envFile)
+
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"FOO": "from-flag", "BAR": "two words"}, env)
+	})
+
+	t.Run("errors on pairs without =", func(t *testing.T) {
+		_, err := cli.ParseSandboxEnv([]string{"FOO"}, "")
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Invalid environment variable 'FOO'")
+	})
+
+	t.Run("errors on invalid names", func(t *testing.T) {
+		_, err := cli.ParseSandboxEnv([]string{"FOO BAR=baz"}, "")
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Invalid environment variable name")
+	})
+
+	t.Run("errors when the env file does not exist", func(t *testing.T) {
+		_, err := cli.ParseSandboxEnv(nil, filepath.Join(t.TempDir(), "missing.env"))
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "error while opening")
+	})
+}
+
+func TestBuildSandboxCommand(t *testing.T) {
+	t.Run("quotes the command only when no env or workdir is given", func(t *testing.T) {
+		require.Equal(t, "bash -c 'cat README.md'", cli.BuildSandboxCommand([]string{"bash", "-c", "cat README.md"}, nil, ""))
+	})
+
+	t.Run("passes variables through env with each assignment quoted", func(t *testing.T) {
+		command := cli.BuildSandboxCommand(
+			[]string{"npm", "test"},
+			map[string]string{"B": "it's $HOME", "A": "1"},
+			"",
+		)
+
+		require.Equal(t, `env -- A=1 'B=it'"'"'s $HOME' npm test`, command)
+	})
+
+	t.Run("changes into the quoted working directory first", func(t *testing.T) {
+		command := cli.BuildSandboxCommand([]string{"make"}, map[string]string{"X": "y"}, "sub dir")
+
+		require.Equal(t, "cd -- 'sub dir' && env -- X=y make", command)
+	})
+}
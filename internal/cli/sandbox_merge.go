@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"al.essio.dev/pkg/shellescape"
+	"github.com/rwx-cloud/rwx/internal/errors"
+)
+
+// sandboxFilePatch is the portion of a git patch that touches a single file.
+type sandboxFilePatch struct {
+	Path    string
+	OldPath string
+	Patch   string
+	Added   bool
+	Deleted bool
+	Binary  bool
+}
+
+// sandboxMergeResult describes how the sandbox's changes were brought into
+// the local working directory when the patch did not apply cleanly.
+type sandboxMergeResult struct {
+	// Conflicted lists files that were merged with conflict markers.
+	Conflicted []string
+	// Unmerged holds the per-file patches that could not be three-way merged
+	// (binary files, renames, deletions of locally-changed files...).
+	Unmerged []sandboxFilePatch
+}
+
+// splitPatchByFile splits a git patch into one patch per file.
+func splitPatchByFile(patch string) []sandboxFilePatch {
+	var files []sandboxFilePatch
+	var current *sandboxFilePatch
+	var body strings.Builder
+
+	flush := func() {
+		if current != nil {
+			current.Patch = body.String()
+			files = append(files, *current)
+		}
+		body.Reset()
+	}
+
+	for _, line := range strings.SplitAfter(patch, "\n") {
+		trimmed := strings.TrimRight(line, "\n")
+		if strings.HasPrefix(trimmed, "diff --git") {
+			flush()
+			current = &sandboxFilePatch{}
+			// Format: diff --git a/path b/path
+			parts := strings.Split(trimmed, " ")
+			if len(parts) >= 4 {
+				current.OldPath = strings.TrimPrefix(parts[2], "a/")
+				current.Path = strings.TrimPrefix(parts[3], "b/")
+			}
+		} else if current != nil {
+			switch {
+			case strings.HasPrefix(trimmed, "new file mode"):
+				current.Added = true
+			case strings.HasPrefix(trimmed, "deleted file mode"):
+				current.Deleted = true
+			case strings.HasPrefix(trimmed, "Binary files"), strings.HasPrefix(trimmed, "GIT binary patch"):
+				current.Binary = true
+			}
+		}
+		if current != nil {
+			body.WriteString(line)
+		}
+	}
+	flush()
+
+	return files
+}
+
+// mergeSandboxPatch brings the sandbox's changes into cwd one file at a time.
+// Files whose changes apply cleanly are applied as-is. The rest are merged
+// three ways: the sandbox's version of the file at refs/rwx-sync (the state
+// it was synced to) is the base, the local file is ours and the sandbox's
+// working tree file is theirs. Overlapping changes are left as conflict
+// markers. It assumes the SSH connection is already established.
+//
+// Files are changed as they are merged, so on error the result is still
+// returned: it has the files merged so far, and every file that wasn't
+// processed yet is in Unmerged.
+func (s Service) mergeSandboxPatch(cwd string, patch string) (*sandboxMergeResult, error) {
+	files := splitPatchByFile(patch)
+	result := &sandboxMergeResult{}
+
+	tmpDir, err := os.MkdirTemp("", "rwx-sandbox-merge")
+	if err != nil {
+		result.Unmerged = files
+		return result, errors.Wrap(err, "unable to create temporary directory for merge")
+	}
+	defer os.RemoveAll(tmpDir)
+
+	_, _ = s.SSHClient.ExecuteCommand("__rwx_sandbox_sync_start__")
+	defer func() {
+		_, _ = s.SSHClient.ExecuteCommand("__rwx_sandbox_sync_end__")
+	}()
+
+	for i, file := range files {
+		if err := s.GitClient.ApplyPatch([]byte(file.Patch)).Run(); err == nil {
+			continue
+		}
+
+		localPath := filepath.Join(cwd, file.Path)
+		if file.Binary || file.Deleted || file.OldPath != file.Path {
+			result.Unmerged = append(result.Unmerged, file)
+			continue
+		}
+		if _, err := os.Stat(localPath); err != nil {
+			result.Unmerged = append(result.Unmerged, file)
+			continue
+		}
+
+		// A file added in the sandbox that also exists locally merges against
+		// an empty base.
+		base := ""
+		if !file.Added {
+			exitCode, output, err := s.SSHClient.ExecuteCommandWithOutput("/usr/bin/git show " + shellescape.Quote("refs/rwx-sync:"+file.Path))
+			if err != nil || exitCode != 0 {
+				result.Unmerged = append(result.Unmerged, file)
+				continue
+			}
+			base = output
+		}
+
+		exitCode, theirs, err := s.SSHClient.ExecuteCommandWithOutput("cat -- " + shellescape.Quote(file.Path))
+		if err != nil || exitCode != 0 {
+			result.Unmerged = append(result.Unmerged, file)
+			continue
+		}
+
+		basePath := filepath.Join(tmpDir, fmt.Sprintf("%d.base", i))
+		theirsPath := filepath.Join(tmpDir, fmt.Sprintf("%d.sandbox", i))
+		if err := os.WriteFile(basePath, []byte(base), 0o600); err != nil {
+			result.Unmerged = append(result.Unmerged, files[i:]...)
+			return result, errors.Wrap(err, "unable to write merge base")
+		}
+		if err := os.WriteFile(theirsPath, []byte(theirs), 0o600); err != nil {
+			result.Unmerged = append(result.Unmerged, files[i:]...)
+			return result, errors.Wrap(err, "unable to write sandbox version for merge")
+		}
+
+		conflicts, err := s.GitClient.MergeFile(localPath, basePath, theirsPath)
+		if err != nil {
+			result.Unmerged = append(result.Unmerged, file)
+			continue
+		}
+		if conflicts > 0 {
+			result.Conflicted = append(result.Conflicted, file.Path)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitPatchByFile(t *testing.T) {
+	t.Run("splits a patch into per-file patches", func(t *testing.T) {
+		patch := "diff --git a/one.txt b/one.txt\nindex abc..def 100644\n--- a/one.txt\n+++ b/one.txt\n@@ -1 +1 @@\n-old\n+new\n" +
+			"diff --git a/two.txt b/two.txt\nnew file mode 100644\nindex 000..123\n--- /dev/null\n+++ b/two.txt\n@@ -0,0 +1 @@\n+added\n" +
+			"diff --git a/three.txt b/three.txt\ndeleted file mode 100644\nindex 456..000\n--- a/three.txt\n+++ /dev/null\n@@ -1 +0,0 @@\n-gone\n" +
+			"diff --git a/image.png b/image.png\nindex 789..abc 100644\nBinary files a/image.png and b/image.png differ\n"
+
+		files := splitPatchByFile(patch)
+
+		require.Len(t, files, 4)
+		require.Equal(t, "one.txt", files[0].Path)
+		require.Equal(t, "one.txt", files[0].OldPath)
+		require.Equal(t, "diff --git a/one.txt b/one.txt\nindex abc..def 100644\n--- a/one.txt\n+++ b/one.txt\n@@ -1 +1 @@\n-old\n+new\n", files[0].Patch)
+		require.False(t, files[0].Added)
+		require.True(t, files[1].Added)
+		require.True(t, files[2].Deleted)
+		require.True(t, files[3].Binary)
+	})
+
+	t.Run("returns nothing for an empty patch", func(t *testing.T) {
+		require.Empty(t, splitPatchByFile(""))
+	})
+}
@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -19,10 +21,11 @@ import (
 type CliState struct {
 	Branch     string `json:"branch"`
 	ConfigFile string `json:"configFile"`
+	Name       string `json:"name,omitempty"`
 }
 
-func EncodeCliState(branch, configFile string) string {
-	state := CliState{Branch: branch, ConfigFile: configFile}
+func EncodeCliState(branch, configFile, name string) string {
+	state := CliState{Branch: branch, ConfigFile: configFile, Name: name}
 	data, _ := json.Marshal(state)
 	return base64.StdEncoding.EncodeToString(data)
 }
@@ -40,32 +43,110 @@ func DecodeCliState(encoded string) (*CliState, error) {
 }
 
 type SandboxSession struct {
-	RunID       string     `json:"runId"`
-	ConfigFile  string     `json:"configFile"`
-	ScopedToken string     `json:"scopedToken,omitempty"`
-	RunURL      string     `json:"runUrl,omitempty"`
-	ConfigHash  string     `json:"configHash,omitempty"`
-	CreatedAt   *time.Time `json:"createdAt,omitempty"`
-	LastExecAt  *time.Time `json:"lastExecAt,omitempty"`
-	ExecCount   int        `json:"execCount,omitempty"`
-}
-
-// HashConfigFile returns a hex-encoded SHA-256 hash of the file at the given path.
-// Returns an empty string if the file cannot be read.
+	RunID          string     `json:"runId"`
+	ConfigFile     string     `json:"configFile"`
+	Name           string     `json:"name,omitempty"`
+	ScopedToken    string     `json:"scopedToken,omitempty"`
+	TokenExpiresAt *time.Time `json:"tokenExpiresAt,omitempty"`
+	RunURL         string     `json:"runUrl,omitempty"`
+	ConfigHash     string     `json:"configHash,omitempty"`
+	CreatedAt      *time.Time `json:"createdAt,omitempty"`
+	LastExecAt     *time.Time `json:"lastExecAt,omitempty"`
+	ExecCount      int        `json:"execCount,omitempty"`
+}
+
+// HashConfigFile returns a hex-encoded SHA-256 hash of the file at the given
+// path together with the files it depends on (see configFileDependencies), so
+// editing an embedded run also changes the hash. A file without dependencies
+// hashes the same as its contents alone. Returns an empty string if the file
+// cannot be read.
 func HashConfigFile(path string) string {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return ""
 	}
-	sum := sha256.Sum256(data)
-	return hex.EncodeToString(sum[:])
+
+	dependencies := configFileDependencies(path, data)
+	if len(dependencies) == 0 {
+		sum := sha256.Sum256(data)
+		return hex.EncodeToString(sum[:])
+	}
+
+	hash := sha256.New()
+	hash.Write(data)
+	for _, dependency := range dependencies {
+		fmt.Fprintf(hash, "\x00%s\x00", dependency.path)
+		hash.Write(dependency.contents)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// runDirReferencePattern matches references to files in the RWX directory,
+// such as embedded runs (call: ${{ run.dir }}/ci.yml).
+var runDirReferencePattern = regexp.MustCompile(`\$\{\{\s*run\.dir\s*\}\}/([^\s"'}]+)`)
+
+type configFileDependency struct {
+	path     string
+	contents []byte
+}
+
+// configFileDependencies returns the files a definition pulls in from the RWX
+// directory through ${{ run.dir }}, following references in YAML files
+// recursively, sorted by path. Files that don't exist are included with no
+// contents so that creating them changes the hash.
+func configFileDependencies(path string, data []byte) []configFileDependency {
+	rwxDir := configFileRwxDirectory(path)
+	seen := map[string]bool{filepath.Clean(path): true}
+	var dependencies []configFileDependency
+
+	pending := [][]byte{data}
+	for len(pending) > 0 {
+		contents := pending[0]
+		pending = pending[1:]
+
+		for _, match := range runDirReferencePattern.FindAllSubmatch(contents, -1) {
+			depPath := filepath.Join(rwxDir, string(match[1]))
+			if seen[depPath] {
+				continue
+			}
+			seen[depPath] = true
+
+			depContents, _ := os.ReadFile(depPath)
+			dependencies = append(dependencies, configFileDependency{path: depPath, contents: depContents})
+
+			if ext := filepath.Ext(depPath); ext == ".yml" || ext == ".yaml" {
+				pending = append(pending, depContents)
+			}
+		}
+	}
+
+	sort.Slice(dependencies, func(i, j int) bool {
+		return dependencies[i].path < dependencies[j].path
+	})
+	return dependencies
+}
+
+// configFileRwxDirectory returns the .rwx directory containing path, which is
+// what ${{ run.dir }} refers to, or the file's own directory when it isn't in
+// one.
+func configFileRwxDirectory(path string) string {
+	dir := filepath.Dir(filepath.Clean(path))
+	for candidate := dir; ; candidate = filepath.Dir(candidate) {
+		if filepath.Base(candidate) == ".rwx" {
+			return candidate
+		}
+		if filepath.Dir(candidate) == candidate {
+			return dir
+		}
+	}
 }
 
 // sandboxStorageVersion is bumped when the on-disk format changes and a
 // migration is needed. Version 0 (or absent) is the legacy format that
 // included cwd in session keys; version 1 uses branch:configFile keys with
-// absolute config paths.
-const sandboxStorageVersion = 1
+// absolute config paths; version 2 adds sandbox names, which are appended to
+// the key as branch:configFile#name and recorded on the session.
+const sandboxStorageVersion = 2
 
 type SandboxStorage struct {
 	Version   int                       `json:"version,omitempty"`
@@ -173,6 +254,16 @@ func LoadSandboxStorage() (*SandboxStorage, error) {
 		return nil, err
 	}
 
+	return loadSandboxStorageFile(path)
+}
+
+// sandboxStorageBackupPath is where Save keeps the previous good copy of the
+// storage file, so a corrupt or partial file can be repaired.
+func sandboxStorageBackupPath(path string) string {
+	return path + ".bak"
+}
+
+func loadSandboxStorageFile(path string) (*SandboxStorage, error) {
 	storage := &SandboxStorage{
 		Sandboxes: make(map[string]SandboxSession),
 	}
@@ -187,7 +278,7 @@ func LoadSandboxStorage() (*SandboxStorage, error) {
 	defer fd.Close()
 
 	if err := json.NewDecoder(fd).Decode(storage); err != nil {
-		return nil, errors.Wrapf(err, "unable to parse %q", path)
+		return nil, errors.Wrapf(err, "unable to parse %q; run 'rwx sandbox gc' to repair it", path)
 	}
 
 	if storage.Sandboxes == nil {
@@ -195,7 +286,7 @@ func LoadSandboxStorage() (*SandboxStorage, error) {
 	}
 
 	if storage.Version < sandboxStorageVersion {
-		storage.Sandboxes = migrateOldSessionKeys(storage.Sandboxes)
+		storage.Sandboxes = migrateOldSessionKeys(storage.Sandboxes, storage.Version)
 		storage.Version = sandboxStorageVersion
 	}
 
@@ -215,15 +306,36 @@ func (s *SandboxStorage) Save() error {
 
 	ensureSandboxesDirGitignore(dir)
 
-	fd, err := os.Create(path)
+	// Keep the last good copy around so 'rwx sandbox gc' can repair the
+	// file if it is ever corrupted.
+	if previous, err := os.ReadFile(path); err == nil && json.Valid(previous) {
+		_ = os.WriteFile(sandboxStorageBackupPath(path), previous, 0o644)
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "unable to encode %q", path)
+	}
+
+	// Write to a temporary file and rename it into place so an interrupted
+	// save never leaves a partial file behind.
+	fd, err := os.CreateTemp(dir, "sandboxes-*.json")
 	if err != nil {
 		return errors.Wrapf(err, "unable to create %q", path)
 	}
-	defer fd.Close()
+	defer os.Remove(fd.Name())
 
-	encoder := json.NewEncoder(fd)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(s); err != nil {
+	if _, err := fd.Write(append(data, '\n')); err != nil {
+		fd.Close()
+		return errors.Wrapf(err, "unable to write %q", path)
+	}
+	if err := fd.Close(); err != nil {
+		return errors.Wrapf(err, "unable to write %q", path)
+	}
+	if err := os.Chmod(fd.Name(), 0o644); err != nil {
+		return errors.Wrapf(err, "unable to write %q", path)
+	}
+	if err := os.Rename(fd.Name(), path); err != nil {
 		return errors.Wrapf(err, "unable to write %q", path)
 	}
 
@@ -237,13 +349,30 @@ func ensureSandboxesDirGitignore(dir string) {
 	}
 }
 
-func SessionKey(branch, configFile string) string {
+var sandboxNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// ValidateSandboxName returns an error if name cannot be used to label a
+// sandbox. The empty name is valid and refers to the default sandbox.
+func ValidateSandboxName(name string) error {
+	if name == "" || sandboxNamePattern.MatchString(name) {
+		return nil
+	}
+	return fmt.Errorf("Invalid sandbox name '%s'. Names must start with a letter or digit and may only contain letters, digits, '.', '_' and '-'.", name)
+}
+
+// SessionKey identifies a sandbox by branch, config file and an optional
+// name. Unnamed sandboxes use branch:configFile; named sandboxes append
+// #name so several can share a branch and config file.
+func SessionKey(branch, configFile, name string) string {
 	if branch == "" {
 		branch = "detached"
 	}
 	if configFile != "" && !filepath.IsAbs(configFile) {
 		panic(fmt.Sprintf("SessionKey called with relative configFile: %q", configFile))
 	}
+	if name != "" {
+		return fmt.Sprintf("%s:%s#%s", branch, configFile, name)
+	}
 	return fmt.Sprintf("%s:%s", branch, configFile)
 }
 
@@ -275,8 +404,8 @@ func DetachedShortSHA(branch string) string {
 	return ""
 }
 
-func (s *SandboxStorage) GetSession(branch, configFile string) (*SandboxSession, bool) {
-	key := SessionKey(branch, configFile)
+func (s *SandboxStorage) GetSession(branch, configFile, name string) (*SandboxSession, bool) {
+	key := SessionKey(branch, configFile, name)
 	session, found := s.Sandboxes[key]
 	if !found {
 		return nil, false
@@ -284,7 +413,8 @@ func (s *SandboxStorage) GetSession(branch, configFile string) (*SandboxSession,
 	return &session, true
 }
 
-// GetSessionsForBranch returns all sessions matching branch (any config file)
+// GetSessionsForBranch returns all sessions matching branch (any config file
+// or name)
 func (s *SandboxStorage) GetSessionsForBranch(branch string) []SandboxSession {
 	if branch == "" {
 		branch = "detached"
@@ -299,13 +429,14 @@ func (s *SandboxStorage) GetSessionsForBranch(branch string) []SandboxSession {
 	return sessions
 }
 
-func (s *SandboxStorage) SetSession(branch, configFile string, session SandboxSession) {
-	key := SessionKey(branch, configFile)
+func (s *SandboxStorage) SetSession(branch, configFile, name string, session SandboxSession) {
+	key := SessionKey(branch, configFile, name)
+	session.Name = name
 	s.Sandboxes[key] = session
 }
 
-func (s *SandboxStorage) DeleteSession(branch, configFile string) {
-	key := SessionKey(branch, configFile)
+func (s *SandboxStorage) DeleteSession(branch, configFile, name string) {
+	key := SessionKey(branch, configFile, name)
 	delete(s.Sandboxes, key)
 }
 
@@ -357,14 +488,14 @@ type AncestryChecker interface {
 // detached SHA is an ancestor of HEAD, the session is returned and re-keyed to
 // the current branch so subsequent lookups hit the fast path.
 // The caller must call Save() to persist the re-keyed session.
-func (s *SandboxStorage) GetSessionByAncestry(branch, configFile string, checker AncestryChecker) (*SandboxSession, bool) {
+func (s *SandboxStorage) GetSessionByAncestry(branch, configFile, name string, checker AncestryChecker) (*SandboxSession, bool) {
 	if !IsDetachedBranch(branch) || DetachedShortSHA(branch) == "" {
 		return nil, false
 	}
 
 	for key, session := range s.Sandboxes {
-		storedBranch, storedConfig := ParseSessionKey(key)
-		if storedConfig != configFile {
+		storedBranch, storedConfig, storedName := ParseSessionKey(key)
+		if storedConfig != configFile || storedName != name {
 			continue
 		}
 		if !IsDetachedBranch(storedBranch) {
@@ -376,7 +507,7 @@ func (s *SandboxStorage) GetSessionByAncestry(branch, configFile string, checker
 		}
 		if checker.IsAncestor(storedSHA, "HEAD") {
 			delete(s.Sandboxes, key)
-			newKey := SessionKey(branch, configFile)
+			newKey := SessionKey(branch, configFile, name)
 			s.Sandboxes[newKey] = session
 			return &session, true
 		}
@@ -395,12 +526,13 @@ func (s *SandboxStorage) GetSessionsForBranchByAncestry(branch string, checker A
 	type rekey struct {
 		oldKey  string
 		config  string
+		name    string
 		session SandboxSession
 	}
 	var toRekey []rekey
 
 	for key, session := range s.Sandboxes {
-		storedBranch, storedConfig := ParseSessionKey(key)
+		storedBranch, storedConfig, storedName := ParseSessionKey(key)
 		if !IsDetachedBranch(storedBranch) {
 			continue
 		}
@@ -409,14 +541,14 @@ func (s *SandboxStorage) GetSessionsForBranchByAncestry(branch string, checker A
 			continue
 		}
 		if checker.IsAncestor(storedSHA, "HEAD") {
-			toRekey = append(toRekey, rekey{oldKey: key, config: storedConfig, session: session})
+			toRekey = append(toRekey, rekey{oldKey: key, config: storedConfig, name: storedName, session: session})
 		}
 	}
 
 	var sessions []SandboxSession
 	for _, r := range toRekey {
 		delete(s.Sandboxes, r.oldKey)
-		newKey := SessionKey(branch, r.config)
+		newKey := SessionKey(branch, r.config, r.name)
 		s.Sandboxes[newKey] = r.session
 		sessions = append(sessions, r.session)
 	}
@@ -424,8 +556,18 @@ func (s *SandboxStorage) GetSessionsForBranchByAncestry(branch string, checker A
 	return sessions
 }
 
-func ParseSessionKey(key string) (branch, configFile string) {
-	// Key format: branch:configFile
+func ParseSessionKey(key string) (branch, configFile, name string) {
+	// Key format: branch:configFile[#name]
+	branch, configFile = parseUnnamedSessionKey(key)
+	if idx := strings.LastIndex(configFile, "#"); idx != -1 && sandboxNamePattern.MatchString(configFile[idx+1:]) {
+		return branch, configFile[:idx], configFile[idx+1:]
+	}
+	return branch, configFile, ""
+}
+
+// parseUnnamedSessionKey parses a version 1 key (branch:configFile), which
+// never carries a name.
+func parseUnnamedSessionKey(key string) (branch, configFile string) {
 	// ConfigFile is typically an absolute path starting with "/",
 	// so split on the first ":/" for reliable parsing.
 	if idx := strings.Index(key, ":/"); idx != -1 {
@@ -439,14 +581,25 @@ func ParseSessionKey(key string) (branch, configFile string) {
 	return key[:lastColon], key[lastColon+1:]
 }
 
-// migrateOldSessionKeys converts old-format keys (cwd:branch:configFile)
-// to the new format (branch:configFile). Old keys are detected by checking
-// whether the legacy 3-part parse yields a cwd starting with "/" and a
-// configFile ending with ".yml" or ".yaml".
-func migrateOldSessionKeys(sandboxes map[string]SandboxSession) map[string]SandboxSession {
+// migrateOldSessionKeys converts keys written by an older storage version to
+// the current format. Version 0 keys (cwd:branch:configFile) are detected by
+// checking whether the legacy 3-part parse yields a cwd starting with "/" and
+// a configFile ending with ".yml" or ".yaml". Sessions from before version 2
+// are always unnamed.
+func migrateOldSessionKeys(sandboxes map[string]SandboxSession, version int) map[string]SandboxSession {
 	migrated := make(map[string]SandboxSession, len(sandboxes))
 	for key, session := range sandboxes {
-		newKey := migrateSessionKey(key)
+		newKey := key
+		if version < 1 {
+			newKey = migrateSessionKey(key)
+		}
+		if version < 2 {
+			branch, configFile := parseUnnamedSessionKey(newKey)
+			if filepath.IsAbs(configFile) {
+				newKey = SessionKey(branch, configFile, "")
+			}
+			session.Name = ""
+		}
 		migrated[newKey] = session
 	}
 	return migrated
@@ -475,7 +628,7 @@ func migrateSessionKey(key string) string {
 		if !filepath.IsAbs(configFile) {
 			configFile = filepath.Join(cwd, configFile)
 		}
-		return SessionKey(branch, configFile)
+		return SessionKey(branch, configFile, "")
 	}
 	return key
 }
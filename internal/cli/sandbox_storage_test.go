@@ -1,6 +1,8 @@
 package cli_test
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"os"
 	"path/filepath"
 	"testing"
@@ -11,42 +13,63 @@ import (
 
 func TestSessionKey(t *testing.T) {
 	t.Run("creates key from branch and config file", func(t *testing.T) {
-		key := cli.SessionKey("main", "/home/user/project/.rwx/sandbox.yml")
+		key := cli.SessionKey("main", "/home/user/project/.rwx/sandbox.yml", "")
 		require.Equal(t, "main:/home/user/project/.rwx/sandbox.yml", key)
 	})
 
 	t.Run("uses 'detached' when branch is empty", func(t *testing.T) {
-		key := cli.SessionKey("", "/home/user/project/.rwx/sandbox.yml")
+		key := cli.SessionKey("", "/home/user/project/.rwx/sandbox.yml", "")
 		require.Equal(t, "detached:/home/user/project/.rwx/sandbox.yml", key)
 	})
 
 	t.Run("preserves detached@sha format as-is", func(t *testing.T) {
-		key := cli.SessionKey("detached@abc1234", "/home/user/project/.rwx/sandbox.yml")
+		key := cli.SessionKey("detached@abc1234", "/home/user/project/.rwx/sandbox.yml", "")
 		require.Equal(t, "detached@abc1234:/home/user/project/.rwx/sandbox.yml", key)
 	})
+
+	t.Run("appends the name for named sandboxes", func(t *testing.T) {
+		key := cli.SessionKey("main", "/home/user/project/.rwx/sandbox.yml", "backend")
+		require.Equal(t, "main:/home/user/project/.rwx/sandbox.yml#backend", key)
+	})
+}
+
+func TestValidateSandboxName(t *testing.T) {
+	t.Run("accepts empty and simple names", func(t *testing.T) {
+		require.NoError(t, cli.ValidateSandboxName(""))
+		require.NoError(t, cli.ValidateSandboxName("backend"))
+		require.NoError(t, cli.ValidateSandboxName("pg-16.2_test"))
+	})
+
+	t.Run("rejects names with separators or spaces", func(t *testing.T) {
+		for _, name := range []string{"a#b", "a:b", "a b", "-leading", "a/b"} {
+			err := cli.ValidateSandboxName(name)
+			require.Error(t, err, name)
+			require.Contains(t, err.Error(), "Invalid sandbox name")
+		}
+	})
 }
 
 func TestParseSessionKey(t *testing.T) {
 	t.Run("parses standard key with absolute config path", func(t *testing.T) {
-		branch, configFile := cli.ParseSessionKey("main:/home/user/project/.rwx/sandbox.yml")
+		branch, configFile, _ := cli.ParseSessionKey("main:/home/user/project/.rwx/sandbox.yml")
 		require.Equal(t, "main", branch)
 		require.Equal(t, "/home/user/project/.rwx/sandbox.yml", configFile)
 	})
 
 	t.Run("parses key with detached branch", func(t *testing.T) {
-		branch, configFile := cli.ParseSessionKey("detached:/home/user/project/.rwx/sandbox.yml")
+		branch, configFile, _ := cli.ParseSessionKey("detached:/home/user/project/.rwx/sandbox.yml")
 		require.Equal(t, "detached", branch)
 		require.Equal(t, "/home/user/project/.rwx/sandbox.yml", configFile)
 	})
 
 	t.Run("parses key with detached@sha branch", func(t *testing.T) {
-		branch, configFile := cli.ParseSessionKey("detached@abc1234:/home/user/project/.rwx/sandbox.yml")
+		branch, configFile, _ := cli.ParseSessionKey("detached@abc1234:/home/user/project/.rwx/sandbox.yml")
 		require.Equal(t, "detached@abc1234", branch)
 		require.Equal(t, "/home/user/project/.rwx/sandbox.yml", configFile)
 	})
 
 	t.Run("parses key with feature branch containing slashes", func(t *testing.T) {
-		branch, configFile := cli.ParseSessionKey("feature/test:/home/user/project/.rwx/sandbox.yml")
+		branch, configFile, _ := cli.ParseSessionKey("feature/test:/home/user/project/.rwx/sandbox.yml")
 		require.Equal(t, "feature/test", branch)
 		require.Equal(t, "/home/user/project/.rwx/sandbox.yml", configFile)
 	})
@@ -55,27 +78,66 @@ func TestParseSessionKey(t *testing.T) {
 		originalBranch := "feature/new-feature"
 		originalConfig := "/home/user/project/.rwx/sandbox.yml"
 
-		key := cli.SessionKey(originalBranch, originalConfig)
-		branch, configFile := cli.ParseSessionKey(key)
+		key := cli.SessionKey(originalBranch, originalConfig, "")
+		branch, configFile, _ := cli.ParseSessionKey(key)
 
 		require.Equal(t, originalBranch, branch)
 		require.Equal(t, originalConfig, configFile)
 	})
 
 	t.Run("handles key with no colons", func(t *testing.T) {
-		branch, configFile := cli.ParseSessionKey("invalid")
+		branch, configFile, _ := cli.ParseSessionKey("invalid")
 		require.Equal(t, "invalid", branch)
 		require.Equal(t, "", configFile)
 	})
 
 	t.Run("handles relative config file fallback", func(t *testing.T) {
-		branch, configFile := cli.ParseSessionKey("main:.rwx/sandbox.yml")
+		branch, configFile, _ := cli.ParseSessionKey("main:.rwx/sandbox.yml")
 		require.Equal(t, "main", branch)
 		require.Equal(t, ".rwx/sandbox.yml", configFile)
 	})
+
+	t.Run("parses the name of a named sandbox", func(t *testing.T) {
+		branch, configFile, name := cli.ParseSessionKey("feature/test:/home/user/project/.rwx/sandbox.yml#frontend")
+		require.Equal(t, "feature/test", branch)
+		require.Equal(t, "/home/user/project/.rwx/sandbox.yml", configFile)
+		require.Equal(t, "frontend", name)
+	})
+
+	t.Run("returns an empty name for unnamed sandboxes", func(t *testing.T) {
+		_, _, name := cli.ParseSessionKey("main:/home/user/project/.rwx/sandbox.yml")
+		require.Equal(t, "", name)
+	})
 }
 
 func TestSandboxStorage_SessionOperations(t *testing.T) {
+	t.Run("keeps named sandboxes separate from the unnamed one", func(t *testing.T) {
+		storage := &cli.SandboxStorage{
+			Sandboxes: make(map[string]cli.SandboxSession),
+		}
+
+		storage.SetSession("main", "/project/.rwx/sandbox.yml", "", cli.SandboxSession{RunID: "run-default"})
+		storage.SetSession("main", "/project/.rwx/sandbox.yml", "backend", cli.SandboxSession{RunID: "run-backend"})
+		storage.SetSession("main", "/project/.rwx/sandbox.yml", "frontend", cli.SandboxSession{RunID: "run-frontend"})
+
+		session, found := storage.GetSession("main", "/project/.rwx/sandbox.yml", "backend")
+		require.True(t, found)
+		require.Equal(t, "run-backend", session.RunID)
+		require.Equal(t, "backend", session.Name)
+
+		session, found = storage.GetSession("main", "/project/.rwx/sandbox.yml", "")
+		require.True(t, found)
+		require.Equal(t, "run-default", session.RunID)
+
+		require.Len(t, storage.GetSessionsForBranch("main"), 3)
+
+		storage.DeleteSession("main", "/project/.rwx/sandbox.yml", "backend")
+		_, found = storage.GetSession("main", "/project/.rwx/sandbox.yml", "backend")
+		require.False(t, found)
+		_, found = storage.GetSession("main", "/project/.rwx/sandbox.yml", "frontend")
+		require.True(t, found)
+	})
+
 	t.Run("SetSession and GetSession", func(t *testing.T) {
 		storage := &cli.SandboxStorage{
 			Sandboxes: make(map[string]cli.SandboxSession),
@@ -86,9 +148,9 @@ func TestSandboxStorage_SessionOperations(t *testing.T) {
 			ConfigFile: "/home/user/project/.rwx/sandbox.yml",
 		}
 
-		storage.SetSession("main", "/home/user/project/.rwx/sandbox.yml", session)
+		storage.SetSession("main", "/home/user/project/.rwx/sandbox.yml", "", session)
 
-		retrieved, found := storage.GetSession("main", "/home/user/project/.rwx/sandbox.yml")
+		retrieved, found := storage.GetSession("main", "/home/user/project/.rwx/sandbox.yml", "")
 		require.True(t, found)
 		require.Equal(t, "run-123", retrieved.RunID)
 		require.Equal(t, "/home/user/project/.rwx/sandbox.yml", retrieved.ConfigFile)
@@ -105,9 +167,9 @@ func TestSandboxStorage_SessionOperations(t *testing.T) {
 			ScopedToken: "scoped-token-abc",
 		}
 
-		storage.SetSession("main", "/home/user/project/.rwx/sandbox.yml", session)
+		storage.SetSession("main", "/home/user/project/.rwx/sandbox.yml", "", session)
 
-		retrieved, found := storage.GetSession("main", "/home/user/project/.rwx/sandbox.yml")
+		retrieved, found := storage.GetSession("main", "/home/user/project/.rwx/sandbox.yml", "")
 		require.True(t, found)
 		require.Equal(t, "run-123", retrieved.RunID)
 		require.Equal(t, "/home/user/project/.rwx/sandbox.yml", retrieved.ConfigFile)
@@ -119,7 +181,7 @@ func TestSandboxStorage_SessionOperations(t *testing.T) {
 			Sandboxes: make(map[string]cli.SandboxSession),
 		}
 
-		_, found := storage.GetSession("main", "/home/user/project/.rwx/sandbox.yml")
+		_, found := storage.GetSession("main", "/home/user/project/.rwx/sandbox.yml", "")
 		require.False(t, found)
 	})
 
@@ -129,11 +191,11 @@ func TestSandboxStorage_SessionOperations(t *testing.T) {
 		}
 
 		session := cli.SandboxSession{RunID: "run-123", ConfigFile: "/home/user/project/.rwx/sandbox.yml"}
-		storage.SetSession("main", "/home/user/project/.rwx/sandbox.yml", session)
+		storage.SetSession("main", "/home/user/project/.rwx/sandbox.yml", "", session)
 
-		storage.DeleteSession("main", "/home/user/project/.rwx/sandbox.yml")
+		storage.DeleteSession("main", "/home/user/project/.rwx/sandbox.yml", "")
 
-		_, found := storage.GetSession("main", "/home/user/project/.rwx/sandbox.yml")
+		_, found := storage.GetSession("main", "/home/user/project/.rwx/sandbox.yml", "")
 		require.False(t, found)
 	})
 
@@ -143,7 +205,7 @@ func TestSandboxStorage_SessionOperations(t *testing.T) {
 		}
 
 		// Should not panic
-		storage.DeleteSession("main", "/home/user/project/.rwx/sandbox.yml")
+		storage.DeleteSession("main", "/home/user/project/.rwx/sandbox.yml", "")
 		require.Empty(t, storage.Sandboxes)
 	})
 }
@@ -154,9 +216,9 @@ func TestSandboxStorage_GetSessionsForBranch(t *testing.T) {
 			Sandboxes: make(map[string]cli.SandboxSession),
 		}
 
-		storage.SetSession("main", "/project/.rwx/config1.yml", cli.SandboxSession{RunID: "run-1"})
-		storage.SetSession("main", "/project/.rwx/config2.yml", cli.SandboxSession{RunID: "run-2"})
-		storage.SetSession("develop", "/project/.rwx/config1.yml", cli.SandboxSession{RunID: "run-3"})
+		storage.SetSession("main", "/project/.rwx/config1.yml", "", cli.SandboxSession{RunID: "run-1"})
+		storage.SetSession("main", "/project/.rwx/config2.yml", "", cli.SandboxSession{RunID: "run-2"})
+		storage.SetSession("develop", "/project/.rwx/config1.yml", "", cli.SandboxSession{RunID: "run-3"})
 
 		sessions := storage.GetSessionsForBranch("main")
 		require.Len(t, sessions, 2)
@@ -173,7 +235,7 @@ func TestSandboxStorage_GetSessionsForBranch(t *testing.T) {
 			Sandboxes: make(map[string]cli.SandboxSession),
 		}
 
-		storage.SetSession("main", "/project/.rwx/config.yml", cli.SandboxSession{RunID: "run-1"})
+		storage.SetSession("main", "/project/.rwx/config.yml", "", cli.SandboxSession{RunID: "run-1"})
 
 		sessions := storage.GetSessionsForBranch("develop")
 		require.Empty(t, sessions)
@@ -184,7 +246,7 @@ func TestSandboxStorage_GetSessionsForBranch(t *testing.T) {
 			Sandboxes: make(map[string]cli.SandboxSession),
 		}
 
-		storage.SetSession("", "/project/.rwx/config.yml", cli.SandboxSession{RunID: "run-1"})
+		storage.SetSession("", "/project/.rwx/config.yml", "", cli.SandboxSession{RunID: "run-1"})
 
 		sessions := storage.GetSessionsForBranch("")
 		require.Len(t, sessions, 1)
@@ -198,7 +260,7 @@ func TestSandboxStorage_FindByRunID(t *testing.T) {
 			Sandboxes: make(map[string]cli.SandboxSession),
 		}
 
-		storage.SetSession("main", "/project/.rwx/config.yml", cli.SandboxSession{
+		storage.SetSession("main", "/project/.rwx/config.yml", "", cli.SandboxSession{
 			RunID:      "run-123",
 			ConfigFile: "/project/.rwx/config.yml",
 		})
@@ -214,7 +276,7 @@ func TestSandboxStorage_FindByRunID(t *testing.T) {
 			Sandboxes: make(map[string]cli.SandboxSession),
 		}
 
-		storage.SetSession("main", "/project/.rwx/config.yml", cli.SandboxSession{RunID: "run-123"})
+		storage.SetSession("main", "/project/.rwx/config.yml", "", cli.SandboxSession{RunID: "run-123"})
 
 		_, _, found := storage.FindByRunID("run-456")
 		require.False(t, found)
@@ -227,12 +289,12 @@ func TestSandboxStorage_DeleteSessionByRunID(t *testing.T) {
 			Sandboxes: make(map[string]cli.SandboxSession),
 		}
 
-		storage.SetSession("main", "/project/.rwx/config.yml", cli.SandboxSession{RunID: "run-123"})
+		storage.SetSession("main", "/project/.rwx/config.yml", "", cli.SandboxSession{RunID: "run-123"})
 
 		deleted := storage.DeleteSessionByRunID("run-123")
 		require.True(t, deleted)
 
-		_, found := storage.GetSession("main", "/project/.rwx/config.yml")
+		_, found := storage.GetSession("main", "/project/.rwx/config.yml", "")
 		require.False(t, found)
 	})
 
@@ -252,8 +314,8 @@ func TestSandboxStorage_AllSessions(t *testing.T) {
 			Sandboxes: make(map[string]cli.SandboxSession),
 		}
 
-		storage.SetSession("main", "/project1/.rwx/config.yml", cli.SandboxSession{RunID: "run-1"})
-		storage.SetSession("develop", "/project2/.rwx/config.yml", cli.SandboxSession{RunID: "run-2"})
+		storage.SetSession("main", "/project1/.rwx/config.yml", "", cli.SandboxSession{RunID: "run-1"})
+		storage.SetSession("develop", "/project2/.rwx/config.yml", "", cli.SandboxSession{RunID: "run-2"})
 
 		all := storage.AllSessions()
 		require.Len(t, all, 2)
@@ -306,7 +368,7 @@ func TestSandboxStorage_LoadAndSave(t *testing.T) {
 		storage := &cli.SandboxStorage{
 			Sandboxes: make(map[string]cli.SandboxSession),
 		}
-		storage.SetSession("main", "/home/user/project/.rwx/sandbox.yml", cli.SandboxSession{
+		storage.SetSession("main", "/home/user/project/.rwx/sandbox.yml", "", cli.SandboxSession{
 			RunID:      "run-123",
 			ConfigFile: "/home/user/project/.rwx/sandbox.yml",
 		})
@@ -322,7 +384,7 @@ func TestSandboxStorage_LoadAndSave(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, loaded.Sandboxes, 1)
 
-		session, found := loaded.GetSession("main", "/home/user/project/.rwx/sandbox.yml")
+		session, found := loaded.GetSession("main", "/home/user/project/.rwx/sandbox.yml", "")
 		require.True(t, found)
 		require.Equal(t, "run-123", session.RunID)
 	})
@@ -342,6 +404,29 @@ func TestSandboxStorage_LoadAndSave(t *testing.T) {
 		require.Equal(t, "*\n", string(contents))
 	})
 
+	t.Run("keeps a backup of the previous file when saving", func(t *testing.T) {
+		tmpDir := setupTestStorageDir(t)
+
+		storage := &cli.SandboxStorage{
+			Sandboxes: make(map[string]cli.SandboxSession),
+		}
+		storage.SetSession("main", "/repo/.rwx/sandbox.yml", "", cli.SandboxSession{RunID: "run-1"})
+		require.NoError(t, storage.Save())
+		storage.SetSession("main", "/repo/.rwx/sandbox.yml", "", cli.SandboxSession{RunID: "run-2"})
+		require.NoError(t, storage.Save())
+
+		backup, err := os.ReadFile(filepath.Join(tmpDir, ".rwx", "sandboxes", "sandboxes.json.bak"))
+		require.NoError(t, err)
+		require.Contains(t, string(backup), "run-1")
+		require.NotContains(t, string(backup), "run-2")
+
+		entries, err := os.ReadDir(filepath.Join(tmpDir, ".rwx", "sandboxes"))
+		require.NoError(t, err)
+		for _, entry := range entries {
+			require.NotContains(t, entry.Name(), "sandboxes-", "temporary files are cleaned up")
+		}
+	})
+
 	t.Run("creates directory structure if it does not exist", func(t *testing.T) {
 		tmpDir := setupTestStorageDir(t)
 
@@ -399,11 +484,40 @@ func TestSandboxStorage_LoadAndSave(t *testing.T) {
 		require.Len(t, storage.Sandboxes, 1)
 
 		// Should be accessible via new-format key
-		session, found := storage.GetSession("main", "/home/user/project/.rwx/sandbox.yml")
+		session, found := storage.GetSession("main", "/home/user/project/.rwx/sandbox.yml", "")
 		require.True(t, found)
 		require.Equal(t, "run-old", session.RunID)
 	})
 
+	t.Run("migrates version 1 storage to the current version", func(t *testing.T) {
+		tmpDir := setupTestStorageDir(t)
+
+		sandboxesDir := filepath.Join(tmpDir, ".rwx", "sandboxes")
+		require.NoError(t, os.MkdirAll(sandboxesDir, 0o755))
+		storagePath := filepath.Join(sandboxesDir, "sandboxes.json")
+		v1JSON := `{"version":1,"sandboxes":{"main:/home/user/project/.rwx/sandbox.yml":{"runId":"run-v1","configFile":"/home/user/project/.rwx/sandbox.yml"}}}`
+		require.NoError(t, os.WriteFile(storagePath, []byte(v1JSON), 0o644))
+
+		storage, err := cli.LoadSandboxStorage()
+		require.NoError(t, err)
+		require.Equal(t, 2, storage.Version)
+
+		session, found := storage.GetSession("main", "/home/user/project/.rwx/sandbox.yml", "")
+		require.True(t, found)
+		require.Equal(t, "run-v1", session.RunID)
+		require.Equal(t, "", session.Name)
+
+		storage.SetSession("main", "/home/user/project/.rwx/sandbox.yml", "backend", cli.SandboxSession{RunID: "run-backend"})
+		require.NoError(t, storage.Save())
+
+		reloaded, err := cli.LoadSandboxStorage()
+		require.NoError(t, err)
+		require.Len(t, reloaded.Sandboxes, 2)
+		named, found := reloaded.GetSession("main", "/home/user/project/.rwx/sandbox.yml", "backend")
+		require.True(t, found)
+		require.Equal(t, "run-backend", named.RunID)
+	})
+
 	t.Run("skips migration when version is current", func(t *testing.T) {
 		tmpDir := setupTestStorageDir(t)
 
@@ -411,14 +525,14 @@ func TestSandboxStorage_LoadAndSave(t *testing.T) {
 		require.NoError(t, os.MkdirAll(sandboxesDir, 0o755))
 		storagePath := filepath.Join(sandboxesDir, "sandboxes.json")
 		// Current version with a key that looks like it could be old-format but shouldn't be migrated
-		currentJSON := `{"version":1,"sandboxes":{"main:/home/user/project/.rwx/sandbox.yml":{"runId":"run-current","configFile":"/home/user/project/.rwx/sandbox.yml"}}}`
+		currentJSON := `{"version":2,"sandboxes":{"main:/home/user/project/.rwx/sandbox.yml":{"runId":"run-current","configFile":"/home/user/project/.rwx/sandbox.yml"}}}`
 		require.NoError(t, os.WriteFile(storagePath, []byte(currentJSON), 0o644))
 
 		storage, err := cli.LoadSandboxStorage()
 		require.NoError(t, err)
 		require.Len(t, storage.Sandboxes, 1)
 
-		session, found := storage.GetSession("main", "/home/user/project/.rwx/sandbox.yml")
+		session, found := storage.GetSession("main", "/home/user/project/.rwx/sandbox.yml", "")
 		require.True(t, found)
 		require.Equal(t, "run-current", session.RunID)
 	})
@@ -426,7 +540,7 @@ func TestSandboxStorage_LoadAndSave(t *testing.T) {
 
 func TestEncodeDecodeCliState(t *testing.T) {
 	t.Run("round-trips correctly", func(t *testing.T) {
-		encoded := cli.EncodeCliState("main", "/home/user/project/.rwx/sandbox.yml")
+		encoded := cli.EncodeCliState("main", "/home/user/project/.rwx/sandbox.yml", "")
 		state, err := cli.DecodeCliState(encoded)
 		require.NoError(t, err)
 		require.Equal(t, "main", state.Branch)
@@ -434,7 +548,7 @@ func TestEncodeDecodeCliState(t *testing.T) {
 	})
 
 	t.Run("handles empty fields", func(t *testing.T) {
-		encoded := cli.EncodeCliState("", "")
+		encoded := cli.EncodeCliState("", "", "")
 		state, err := cli.DecodeCliState(encoded)
 		require.NoError(t, err)
 		require.Equal(t, "", state.Branch)
@@ -442,7 +556,7 @@ func TestEncodeDecodeCliState(t *testing.T) {
 	})
 
 	t.Run("handles special characters", func(t *testing.T) {
-		encoded := cli.EncodeCliState("feature/test-branch", "/path/with spaces/config.yml")
+		encoded := cli.EncodeCliState("feature/test-branch", "/path/with spaces/config.yml", "")
 		state, err := cli.DecodeCliState(encoded)
 		require.NoError(t, err)
 		require.Equal(t, "feature/test-branch", state.Branch)
@@ -451,7 +565,7 @@ func TestEncodeDecodeCliState(t *testing.T) {
 
 	t.Run("decodes old format with cwd gracefully", func(t *testing.T) {
 		// Old CliState payloads include "cwd" — the field is silently ignored
-		encoded := cli.EncodeCliState("main", "/project/.rwx/sandbox.yml")
+		encoded := cli.EncodeCliState("main", "/project/.rwx/sandbox.yml", "")
 		state, err := cli.DecodeCliState(encoded)
 		require.NoError(t, err)
 		require.Equal(t, "main", state.Branch)
@@ -474,34 +588,39 @@ func TestEncodeDecodeCliState(t *testing.T) {
 
 func TestSandboxTitle(t *testing.T) {
 	t.Run("creates title from project name and branch", func(t *testing.T) {
-		title := cli.SandboxTitle("/home/user/my-project", "main", ".rwx/sandbox.yml")
+		title := cli.SandboxTitle("/home/user/my-project", "main", ".rwx/sandbox.yml", "")
 		require.Equal(t, "Sandbox: my-project (main)", title)
 	})
 
 	t.Run("uses 'detached' when branch is empty", func(t *testing.T) {
-		title := cli.SandboxTitle("/home/user/my-project", "", ".rwx/sandbox.yml")
+		title := cli.SandboxTitle("/home/user/my-project", "", ".rwx/sandbox.yml", "")
 		require.Equal(t, "Sandbox: my-project (detached)", title)
 	})
 
 	t.Run("displays detached with short SHA", func(t *testing.T) {
-		title := cli.SandboxTitle("/home/user/my-project", "detached@abc1234", ".rwx/sandbox.yml")
+		title := cli.SandboxTitle("/home/user/my-project", "detached@abc1234", ".rwx/sandbox.yml", "")
 		require.Equal(t, "Sandbox: my-project (detached abc1234)", title)
 	})
 
 	t.Run("includes non-default config file", func(t *testing.T) {
-		title := cli.SandboxTitle("/home/user/my-project", "feature/test", ".rwx/custom.yml")
+		title := cli.SandboxTitle("/home/user/my-project", "feature/test", ".rwx/custom.yml", "")
 		require.Equal(t, "Sandbox: my-project (feature/test) [.rwx/custom.yml]", title)
 	})
 
 	t.Run("excludes default config file", func(t *testing.T) {
-		title := cli.SandboxTitle("/home/user/my-project", "develop", ".rwx/sandbox.yml")
+		title := cli.SandboxTitle("/home/user/my-project", "develop", ".rwx/sandbox.yml", "")
 		require.Equal(t, "Sandbox: my-project (develop)", title)
 	})
 
 	t.Run("handles empty config file", func(t *testing.T) {
-		title := cli.SandboxTitle("/home/user/my-project", "main", "")
+		title := cli.SandboxTitle("/home/user/my-project", "main", "", "")
 		require.Equal(t, "Sandbox: my-project (main)", title)
 	})
+
+	t.Run("includes the sandbox name", func(t *testing.T) {
+		title := cli.SandboxTitle("/home/user/my-project", "main", ".rwx/sandbox.yml", "backend")
+		require.Equal(t, "Sandbox: my-project/backend (main)", title)
+	})
 }
 
 func TestIsDetachedBranch(t *testing.T) {
@@ -560,6 +679,45 @@ func TestHashConfigFile(t *testing.T) {
 	t.Run("returns empty string for nonexistent file", func(t *testing.T) {
 		require.Equal(t, "", cli.HashConfigFile("/nonexistent/file.yml"))
 	})
+
+	t.Run("hashes a file without dependencies by its contents alone", func(t *testing.T) {
+		tmpFile := filepath.Join(t.TempDir(), "sandbox.yml")
+		contents := []byte("tasks:\n  - key: test\n")
+		require.NoError(t, os.WriteFile(tmpFile, contents, 0o644))
+
+		sum := sha256.Sum256(contents)
+		require.Equal(t, hex.EncodeToString(sum[:]), cli.HashConfigFile(tmpFile))
+	})
+
+	t.Run("changes when an embedded run changes", func(t *testing.T) {
+		rwxDir := filepath.Join(t.TempDir(), ".rwx")
+		require.NoError(t, os.MkdirAll(filepath.Join(rwxDir, "sandbox"), 0o755))
+		configFile := filepath.Join(rwxDir, "sandbox", "sandbox.yml")
+		require.NoError(t, os.WriteFile(configFile, []byte("tasks:\n  - key: setup\n    call: ${{ run.dir }}/setup.yml\n"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(rwxDir, "setup.yml"), []byte("tasks:\n  - key: deps\n    call: ${{ run.dir }}/deps.yml\n"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(rwxDir, "deps.yml"), []byte("tasks:\n  - key: a\n    run: echo a\n"), 0o644))
+
+		original := cli.HashConfigFile(configFile)
+		require.NotEmpty(t, original)
+
+		// A nested embedded run is followed too
+		require.NoError(t, os.WriteFile(filepath.Join(rwxDir, "deps.yml"), []byte("tasks:\n  - key: a\n    run: echo b\n"), 0o644))
+		changed := cli.HashConfigFile(configFile)
+		require.NotEqual(t, original, changed)
+
+		require.NoError(t, os.Remove(filepath.Join(rwxDir, "deps.yml")))
+		require.NotEqual(t, changed, cli.HashConfigFile(configFile))
+	})
+
+	t.Run("does not loop on files that reference each other", func(t *testing.T) {
+		rwxDir := filepath.Join(t.TempDir(), ".rwx")
+		require.NoError(t, os.MkdirAll(rwxDir, 0o755))
+		configFile := filepath.Join(rwxDir, "a.yml")
+		require.NoError(t, os.WriteFile(configFile, []byte("call: ${{ run.dir }}/b.yml\n"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(rwxDir, "b.yml"), []byte("call: ${{ run.dir }}/a.yml\n"), 0o644))
+
+		require.NotEmpty(t, cli.HashConfigFile(configFile))
+	})
 }
 
 // mockAncestryChecker implements cli.AncestryChecker for testing.
@@ -574,81 +732,81 @@ func (m *mockAncestryChecker) IsAncestor(candidateSHA, headRef string) bool {
 func TestGetSessionByAncestry(t *testing.T) {
 	t.Run("returns nil when branch is not detached", func(t *testing.T) {
 		storage := &cli.SandboxStorage{Sandboxes: make(map[string]cli.SandboxSession)}
-		storage.SetSession("detached@abc1234", "/project/.rwx/config.yml", cli.SandboxSession{RunID: "run-1"})
+		storage.SetSession("detached@abc1234", "/project/.rwx/config.yml", "", cli.SandboxSession{RunID: "run-1"})
 
 		checker := &mockAncestryChecker{ancestors: map[string]bool{"abc1234->HEAD": true}}
-		session, found := storage.GetSessionByAncestry("main", "/project/.rwx/config.yml", checker)
+		session, found := storage.GetSessionByAncestry("main", "/project/.rwx/config.yml", "", checker)
 		require.False(t, found)
 		require.Nil(t, session)
 	})
 
 	t.Run("returns nil when branch is bare detached without SHA", func(t *testing.T) {
 		storage := &cli.SandboxStorage{Sandboxes: make(map[string]cli.SandboxSession)}
-		storage.SetSession("detached@abc1234", "/project/.rwx/config.yml", cli.SandboxSession{RunID: "run-1"})
+		storage.SetSession("detached@abc1234", "/project/.rwx/config.yml", "", cli.SandboxSession{RunID: "run-1"})
 
 		checker := &mockAncestryChecker{ancestors: map[string]bool{"abc1234->HEAD": true}}
-		session, found := storage.GetSessionByAncestry("detached", "/project/.rwx/config.yml", checker)
+		session, found := storage.GetSessionByAncestry("detached", "/project/.rwx/config.yml", "", checker)
 		require.False(t, found)
 		require.Nil(t, session)
 	})
 
 	t.Run("finds session when stored SHA is ancestor of HEAD", func(t *testing.T) {
 		storage := &cli.SandboxStorage{Sandboxes: make(map[string]cli.SandboxSession)}
-		storage.SetSession("detached@abc1234", "/project/.rwx/config.yml", cli.SandboxSession{RunID: "run-1", ConfigFile: "/project/.rwx/config.yml"})
+		storage.SetSession("detached@abc1234", "/project/.rwx/config.yml", "", cli.SandboxSession{RunID: "run-1", ConfigFile: "/project/.rwx/config.yml"})
 
 		checker := &mockAncestryChecker{ancestors: map[string]bool{"abc1234->HEAD": true}}
-		session, found := storage.GetSessionByAncestry("detached@def5678", "/project/.rwx/config.yml", checker)
+		session, found := storage.GetSessionByAncestry("detached@def5678", "/project/.rwx/config.yml", "", checker)
 		require.True(t, found)
 		require.Equal(t, "run-1", session.RunID)
 
 		// Verify key was updated
-		_, oldFound := storage.GetSession("detached@abc1234", "/project/.rwx/config.yml")
+		_, oldFound := storage.GetSession("detached@abc1234", "/project/.rwx/config.yml", "")
 		require.False(t, oldFound)
-		newSession, newFound := storage.GetSession("detached@def5678", "/project/.rwx/config.yml")
+		newSession, newFound := storage.GetSession("detached@def5678", "/project/.rwx/config.yml", "")
 		require.True(t, newFound)
 		require.Equal(t, "run-1", newSession.RunID)
 	})
 
 	t.Run("does not match when stored SHA is not an ancestor", func(t *testing.T) {
 		storage := &cli.SandboxStorage{Sandboxes: make(map[string]cli.SandboxSession)}
-		storage.SetSession("detached@abc1234", "/project/.rwx/config.yml", cli.SandboxSession{RunID: "run-1"})
+		storage.SetSession("detached@abc1234", "/project/.rwx/config.yml", "", cli.SandboxSession{RunID: "run-1"})
 
 		checker := &mockAncestryChecker{ancestors: map[string]bool{}}
-		session, found := storage.GetSessionByAncestry("detached@def5678", "/project/.rwx/config.yml", checker)
+		session, found := storage.GetSessionByAncestry("detached@def5678", "/project/.rwx/config.yml", "", checker)
 		require.False(t, found)
 		require.Nil(t, session)
 
 		// Verify original key is untouched
-		_, stillThere := storage.GetSession("detached@abc1234", "/project/.rwx/config.yml")
+		_, stillThere := storage.GetSession("detached@abc1234", "/project/.rwx/config.yml", "")
 		require.True(t, stillThere)
 	})
 
 	t.Run("does not match sessions with different config file", func(t *testing.T) {
 		storage := &cli.SandboxStorage{Sandboxes: make(map[string]cli.SandboxSession)}
-		storage.SetSession("detached@abc1234", "/project/.rwx/other.yml", cli.SandboxSession{RunID: "run-1"})
+		storage.SetSession("detached@abc1234", "/project/.rwx/other.yml", "", cli.SandboxSession{RunID: "run-1"})
 
 		checker := &mockAncestryChecker{ancestors: map[string]bool{"abc1234->HEAD": true}}
-		session, found := storage.GetSessionByAncestry("detached@def5678", "/project/.rwx/config.yml", checker)
+		session, found := storage.GetSessionByAncestry("detached@def5678", "/project/.rwx/config.yml", "", checker)
 		require.False(t, found)
 		require.Nil(t, session)
 	})
 
 	t.Run("does not match sessions stored under a named branch", func(t *testing.T) {
 		storage := &cli.SandboxStorage{Sandboxes: make(map[string]cli.SandboxSession)}
-		storage.SetSession("main", "/project/.rwx/config.yml", cli.SandboxSession{RunID: "run-1"})
+		storage.SetSession("main", "/project/.rwx/config.yml", "", cli.SandboxSession{RunID: "run-1"})
 
 		checker := &mockAncestryChecker{ancestors: map[string]bool{}}
-		session, found := storage.GetSessionByAncestry("detached@def5678", "/project/.rwx/config.yml", checker)
+		session, found := storage.GetSessionByAncestry("detached@def5678", "/project/.rwx/config.yml", "", checker)
 		require.False(t, found)
 		require.Nil(t, session)
 	})
 
 	t.Run("does not match bare detached stored sessions", func(t *testing.T) {
 		storage := &cli.SandboxStorage{Sandboxes: make(map[string]cli.SandboxSession)}
-		storage.SetSession("detached", "/project/.rwx/config.yml", cli.SandboxSession{RunID: "run-1"})
+		storage.SetSession("detached", "/project/.rwx/config.yml", "", cli.SandboxSession{RunID: "run-1"})
 
 		checker := &mockAncestryChecker{ancestors: map[string]bool{}}
-		session, found := storage.GetSessionByAncestry("detached@def5678", "/project/.rwx/config.yml", checker)
+		session, found := storage.GetSessionByAncestry("detached@def5678", "/project/.rwx/config.yml", "", checker)
 		require.False(t, found)
 		require.Nil(t, session)
 	})
@@ -657,7 +815,7 @@ func TestGetSessionByAncestry(t *testing.T) {
 func TestGetSessionsForBranchByAncestry(t *testing.T) {
 	t.Run("returns nil when branch is not detached", func(t *testing.T) {
 		storage := &cli.SandboxStorage{Sandboxes: make(map[string]cli.SandboxSession)}
-		storage.SetSession("detached@abc1234", "/project/.rwx/config.yml", cli.SandboxSession{RunID: "run-1"})
+		storage.SetSession("detached@abc1234", "/project/.rwx/config.yml", "", cli.SandboxSession{RunID: "run-1"})
 
 		checker := &mockAncestryChecker{ancestors: map[string]bool{"abc1234->HEAD": true}}
 		sessions := storage.GetSessionsForBranchByAncestry("main", checker)
@@ -666,8 +824,8 @@ func TestGetSessionsForBranchByAncestry(t *testing.T) {
 
 	t.Run("returns matching sessions across multiple configs", func(t *testing.T) {
 		storage := &cli.SandboxStorage{Sandboxes: make(map[string]cli.SandboxSession)}
-		storage.SetSession("detached@abc1234", "/project/.rwx/config1.yml", cli.SandboxSession{RunID: "run-1"})
-		storage.SetSession("detached@abc1234", "/project/.rwx/config2.yml", cli.SandboxSession{RunID: "run-2"})
+		storage.SetSession("detached@abc1234", "/project/.rwx/config1.yml", "", cli.SandboxSession{RunID: "run-1"})
+		storage.SetSession("detached@abc1234", "/project/.rwx/config2.yml", "", cli.SandboxSession{RunID: "run-2"})
 
 		checker := &mockAncestryChecker{ancestors: map[string]bool{"abc1234->HEAD": true}}
 		sessions := storage.GetSessionsForBranchByAncestry("detached@def5678", checker)
@@ -677,16 +835,16 @@ func TestGetSessionsForBranchByAncestry(t *testing.T) {
 		require.ElementsMatch(t, []string{"run-1", "run-2"}, runIDs)
 
 		// Verify keys were updated
-		_, oldFound := storage.GetSession("detached@abc1234", "/project/.rwx/config1.yml")
+		_, oldFound := storage.GetSession("detached@abc1234", "/project/.rwx/config1.yml", "")
 		require.False(t, oldFound)
-		_, newFound := storage.GetSession("detached@def5678", "/project/.rwx/config1.yml")
+		_, newFound := storage.GetSession("detached@def5678", "/project/.rwx/config1.yml", "")
 		require.True(t, newFound)
 	})
 
 	t.Run("does not return non-ancestor detached sessions", func(t *testing.T) {
 		storage := &cli.SandboxStorage{Sandboxes: make(map[string]cli.SandboxSession)}
-		storage.SetSession("detached@abc1234", "/project/.rwx/config.yml", cli.SandboxSession{RunID: "run-1"})
-		storage.SetSession("detached@unrelated", "/project/.rwx/config.yml", cli.SandboxSession{RunID: "run-2"})
+		storage.SetSession("detached@abc1234", "/project/.rwx/config.yml", "", cli.SandboxSession{RunID: "run-1"})
+		storage.SetSession("detached@unrelated", "/project/.rwx/config.yml", "", cli.SandboxSession{RunID: "run-2"})
 
 		checker := &mockAncestryChecker{ancestors: map[string]bool{"abc1234->HEAD": true}}
 		sessions := storage.GetSessionsForBranchByAncestry("detached@def5678", checker)
@@ -696,7 +854,7 @@ func TestGetSessionsForBranchByAncestry(t *testing.T) {
 
 	t.Run("returns empty when no matches", func(t *testing.T) {
 		storage := &cli.SandboxStorage{Sandboxes: make(map[string]cli.SandboxSession)}
-		storage.SetSession("detached@abc1234", "/project/.rwx/config.yml", cli.SandboxSession{RunID: "run-1"})
+		storage.SetSession("detached@abc1234", "/project/.rwx/config.yml", "", cli.SandboxSession{RunID: "run-1"})
 
 		checker := &mockAncestryChecker{ancestors: map[string]bool{}}
 		sessions := storage.GetSessionsForBranchByAncestry("detached@def5678", checker)
@@ -710,8 +868,8 @@ func TestGetSessionsForBranch_DetachedSHA(t *testing.T) {
 			Sandboxes: make(map[string]cli.SandboxSession),
 		}
 
-		storage.SetSession("detached@abc1234", "/project/.rwx/config.yml", cli.SandboxSession{RunID: "run-1"})
-		storage.SetSession("detached@def5678", "/project/.rwx/config.yml", cli.SandboxSession{RunID: "run-2"})
+		storage.SetSession("detached@abc1234", "/project/.rwx/config.yml", "", cli.SandboxSession{RunID: "run-1"})
+		storage.SetSession("detached@def5678", "/project/.rwx/config.yml", "", cli.SandboxSession{RunID: "run-2"})
 
 		sessions1 := storage.GetSessionsForBranch("detached@abc1234")
 		require.Len(t, sessions1, 1)
@@ -0,0 +1,138 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rwx-cloud/rwx/internal/api"
+	"github.com/rwx-cloud/rwx/internal/errors"
+)
+
+// sandboxTokenRefreshWindow is how long before its expiry a scoped token is
+// replaced, so a token never expires partway through an exec.
+const sandboxTokenRefreshWindow = 5 * time.Minute
+
+// ScopedTokenExpiring reports whether the session's scoped token expires
+// within the refresh window of now. Tokens without a known expiry are
+// assumed to be valid.
+func (s SandboxSession) ScopedTokenExpiring(now time.Time) bool {
+	if s.ScopedToken == "" || s.TokenExpiresAt == nil {
+		return false
+	}
+	return !now.Add(sandboxTokenRefreshWindow).Before(*s.TokenExpiresAt)
+}
+
+// UpdateScopedToken replaces the scoped token of the session for runID. It
+// returns false when no session has that run ID.
+func (s *SandboxStorage) UpdateScopedToken(runID, token string, expiresAt *time.Time) bool {
+	for key, session := range s.Sandboxes {
+		if session.RunID == runID {
+			session.ScopedToken = token
+			session.TokenExpiresAt = expiresAt
+			s.Sandboxes[key] = session
+			return true
+		}
+	}
+	return false
+}
+
+// ClearScopedTokens removes the scoped token from every session and returns
+// how many sessions had one.
+func (s *SandboxStorage) ClearScopedTokens() int {
+	cleared := 0
+	for key, session := range s.Sandboxes {
+		if session.ScopedToken == "" {
+			continue
+		}
+		session.ScopedToken = ""
+		session.TokenExpiresAt = nil
+		s.Sandboxes[key] = session
+		cleared++
+	}
+	return cleared
+}
+
+// createSandboxToken requests a new scoped token for runID and returns it
+// with its expiry, which is nil when the API doesn't report one.
+func (s Service) createSandboxToken(ctx context.Context, runID string) (string, *time.Time, error) {
+	result, err := s.APIClient.CreateSandboxToken(ctx, api.CreateSandboxTokenConfig{
+		RunID: runID,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	var expiresAt *time.Time
+	if parsed, err := time.Parse(time.RFC3339, result.ExpiresAt); err == nil {
+		parsed = parsed.UTC()
+		expiresAt = &parsed
+	}
+
+	return result.Token, expiresAt, nil
+}
+
+// refreshSessionToken replaces the session's scoped token with a new one. On
+// failure the session is left unchanged.
+func (s Service) refreshSessionToken(ctx context.Context, session *SandboxSession) error {
+	token, expiresAt, err := s.createSandboxToken(ctx, session.RunID)
+	if err != nil {
+		return errors.Wrapf(err, "unable to refresh scoped token for %s", session.RunID)
+	}
+
+	session.ScopedToken = token
+	session.TokenExpiresAt = expiresAt
+	return nil
+}
+
+// sandboxConnectionInfo gets the connection info for a stored session. The
+// session's scoped token is refreshed first when it is about to expire, and
+// again if the API rejects it, in which case the request is retried. The
+// returned bool reports whether the session's token changed and needs to be
+// saved.
+func (s Service) sandboxConnectionInfo(ctx context.Context, session *SandboxSession) (api.SandboxConnectionInfo, bool, error) {
+	refreshed := false
+	if session.ScopedTokenExpiring(time.Now()) {
+		if err := s.refreshSessionToken(ctx, session); err != nil {
+			fmt.Fprintf(s.Stderr, "Warning: %v\n", err)
+		} else {
+			refreshed = true
+		}
+	}
+
+	connInfo, err := s.APIClient.GetSandboxConnectionInfo(ctx, session.RunID, session.ScopedToken)
+	if !errors.Is(err, errors.ErrUnauthorized) || session.ScopedToken == "" {
+		return connInfo, refreshed, err
+	}
+
+	if refreshErr := s.refreshSessionToken(ctx, session); refreshErr != nil {
+		return connInfo, refreshed, err
+	}
+
+	connInfo, err = s.APIClient.GetSandboxConnectionInfo(ctx, session.RunID, session.ScopedToken)
+	return connInfo, true, err
+}
+
+// saveSessionToken persists a refreshed scoped token. It must not be called
+// while the sandbox storage lock is held.
+func (s Service) saveSessionToken(session SandboxSession) {
+	lockFile, err := LockSandboxStorage()
+	if err != nil {
+		fmt.Fprintf(s.Stderr, "Warning: Unable to lock sandbox storage: %v\n", err)
+		return
+	}
+	defer UnlockSandboxStorage(lockFile)
+
+	storage, err := LoadSandboxStorage()
+	if err != nil {
+		fmt.Fprintf(s.Stderr, "Warning: Unable to load sandbox sessions: %v\n", err)
+		return
+	}
+
+	if !storage.UpdateScopedToken(session.RunID, session.ScopedToken, session.TokenExpiresAt) {
+		return
+	}
+	if err := storage.Save(); err != nil {
+		fmt.Fprintf(s.Stderr, "Warning: Unable to save sandbox session: %v\n", err)
+	}
+}
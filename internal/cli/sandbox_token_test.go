@@ -0,0 +1,195 @@
+package cli_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rwx-cloud/rwx/internal/api"
+	"github.com/rwx-cloud/rwx/internal/cli"
+	"github.com/rwx-cloud/rwx/internal/errors"
+	"github.com/rwx-cloud/rwx/internal/git"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSandboxSession_ScopedTokenExpiring(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		t := now.Add(d)
+		return &t
+	}
+
+	t.Run("is false without a token", func(t *testing.T) {
+		session := cli.SandboxSession{TokenExpiresAt: at(-time.Hour)}
+		require.False(t, session.ScopedTokenExpiring(now))
+	})
+
+	t.Run("is false when the expiry is unknown", func(t *testing.T) {
+		session := cli.SandboxSession{ScopedToken: "token"}
+		require.False(t, session.ScopedTokenExpiring(now))
+	})
+
+	t.Run("is false when the token is valid for a while", func(t *testing.T) {
+		session := cli.SandboxSession{ScopedToken: "token", TokenExpiresAt: at(time.Hour)}
+		require.False(t, session.ScopedTokenExpiring(now))
+	})
+
+	t.Run("is true when the token expires soon", func(t *testing.T) {
+		session := cli.SandboxSession{ScopedToken: "token", TokenExpiresAt: at(time.Minute)}
+		require.True(t, session.ScopedTokenExpiring(now))
+	})
+
+	t.Run("is true when the token has expired", func(t *testing.T) {
+		session := cli.SandboxSession{ScopedToken: "token", TokenExpiresAt: at(-time.Minute)}
+		require.True(t, session.ScopedTokenExpiring(now))
+	})
+}
+
+// tokenRefreshSetup makes the API accept only the "fresh-token" scoped token
+// (and the user's own token) and hand out fresh-token on refresh. It returns
+// the tokens connection info was requested with.
+func tokenRefreshSetup(t *testing.T, setup *testSetup) *[]string {
+	t.Helper()
+
+	var tokens []string
+	expiresAt := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+	setup.mockAPI.MockCreateSandboxToken = func(cfg api.CreateSandboxTokenConfig) (*api.CreateSandboxTokenResult, error) {
+		return &api.CreateSandboxTokenResult{Token: "fresh-token", ExpiresAt: expiresAt, RunID: cfg.RunID}, nil
+	}
+	setup.mockAPI.MockGetSandboxConnectionInfo = func(id, token string) (api.SandboxConnectionInfo, error) {
+		tokens = append(tokens, token)
+		if token != "" && token != "fresh-token" {
+			return api.SandboxConnectionInfo{}, errors.ErrUnauthorized
+		}
+		return api.SandboxConnectionInfo{
+			Sandboxable:    true,
+			Address:        "192.168.1.1:22",
+			PrivateUserKey: sandboxPrivateTestKey,
+			PublicHostKey:  sandboxPublicTestKey,
+		}, nil
+	}
+	setup.mockSSH.MockConnect = func(addr string, _ ssh.ClientConfig) error {
+		return nil
+	}
+	setup.mockSSH.MockExecuteCommand = func(cmd string) (int, error) {
+		return 0, nil
+	}
+	setup.mockGit.MockGeneratePatch = func(pathspec []string) ([]byte, *git.LFSChangedFilesMetadata, error) {
+		return nil, nil, nil
+	}
+
+	return &tokens
+}
+
+func TestService_SandboxTokenRefresh(t *testing.T) {
+	t.Run("refreshes a token that is about to expire before using it", func(t *testing.T) {
+		setup := setupTest(t)
+		configFile := setup.absConfig(".rwx/sandbox.yml")
+		soon := time.Now().Add(time.Minute).UTC()
+		seedSandboxStorageMulti(t, setup.tmp, map[string]cli.SandboxSession{
+			"detached:" + configFile: {RunID: "run-123", ConfigFile: configFile, ScopedToken: "old-token", TokenExpiresAt: &soon},
+		})
+		tokens := tokenRefreshSetup(t, setup)
+
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			Command: []string{"echo", "hello"},
+			RunID:   "run-123",
+			Json:    true,
+		})
+		require.NoError(t, err)
+		require.NotContains(t, *tokens, "old-token")
+
+		storage, err := cli.LoadSandboxStorage()
+		require.NoError(t, err)
+		session, _, found := storage.FindByRunID("run-123")
+		require.True(t, found)
+		require.Equal(t, "fresh-token", session.ScopedToken)
+		require.NotNil(t, session.TokenExpiresAt)
+		require.True(t, session.TokenExpiresAt.After(time.Now().Add(30*time.Minute)))
+	})
+
+	t.Run("gets a new token and retries when the token is rejected", func(t *testing.T) {
+		setup := setupTest(t)
+		configFile := setup.absConfig(".rwx/sandbox.yml")
+		seedSandboxStorageMulti(t, setup.tmp, map[string]cli.SandboxSession{
+			"detached:" + configFile: {RunID: "run-123", ConfigFile: configFile, ScopedToken: "revoked-token"},
+		})
+		tokens := tokenRefreshSetup(t, setup)
+
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			ConfigFile: configFile,
+			Command:    []string{"echo", "hello"},
+			Json:       true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "revoked-token", (*tokens)[0])
+		require.Equal(t, "fresh-token", (*tokens)[len(*tokens)-1])
+
+		storage, err := cli.LoadSandboxStorage()
+		require.NoError(t, err)
+		session, found := storage.GetSession("detached", configFile, "")
+		require.True(t, found, "the session is kept rather than treated as expired")
+		require.Equal(t, "fresh-token", session.ScopedToken)
+	})
+
+	t.Run("gets a new token when it is rejected while waiting for the sandbox", func(t *testing.T) {
+		setup := setupTest(t)
+		configFile := setup.absConfig(".rwx/sandbox.yml")
+		seedSandboxStorageMulti(t, setup.tmp, map[string]cli.SandboxSession{
+			"detached:" + configFile: {RunID: "run-123", ConfigFile: configFile, ScopedToken: "revoked-token"},
+		})
+		tokenRefreshSetup(t, setup)
+
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			Command: []string{"echo", "hello"},
+			RunID:   "run-123",
+			Json:    true,
+		})
+		require.NoError(t, err)
+
+		storage, err := cli.LoadSandboxStorage()
+		require.NoError(t, err)
+		session, _, found := storage.FindByRunID("run-123")
+		require.True(t, found)
+		require.Equal(t, "fresh-token", session.ScopedToken)
+	})
+
+	t.Run("returns the original error when the token can't be refreshed", func(t *testing.T) {
+		setup := setupTest(t)
+		configFile := setup.absConfig(".rwx/sandbox.yml")
+		seedSandboxStorageMulti(t, setup.tmp, map[string]cli.SandboxSession{
+			"detached:" + configFile: {RunID: "run-123", ConfigFile: configFile, ScopedToken: "revoked-token"},
+		})
+		tokenRefreshSetup(t, setup)
+		setup.mockAPI.MockCreateSandboxToken = func(cfg api.CreateSandboxTokenConfig) (*api.CreateSandboxTokenResult, error) {
+			return nil, errors.New("run has ended")
+		}
+
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			Command: []string{"echo", "hello"},
+			RunID:   "run-123",
+			Json:    true,
+		})
+		require.ErrorIs(t, err, errors.ErrUnauthorized)
+	})
+
+	t.Run("records the token expiry when starting a sandbox", func(t *testing.T) {
+		setup := setupTest(t)
+		tokenRefreshSetup(t, setup)
+
+		_, err := setup.service.StartSandbox(t.Context(), cli.StartSandboxConfig{
+			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
+			RunID:      "run-reattach",
+			Json:       true,
+		})
+		require.NoError(t, err)
+
+		storage, err := cli.LoadSandboxStorage()
+		require.NoError(t, err)
+		session, _, found := storage.FindByRunID("run-reattach")
+		require.True(t, found)
+		require.Equal(t, "fresh-token", session.ScopedToken)
+		require.NotNil(t, session.TokenExpiresAt)
+	})
+}
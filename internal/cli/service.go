@@ -2,10 +2,13 @@ package cli
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
 
 	semver "github.com/Masterminds/semver/v3"
 	"github.com/rwx-cloud/rwx/internal/api"
@@ -41,6 +44,10 @@ var hasOutputSkillMessage atomic.Bool
 // Service holds the main business logic of the CLI.
 type Service struct {
 	Config
+
+	// sandboxSyncLock is shared by the concurrent execs of ExecSandboxes so
+	// that pulling one sandbox's changes never races with pushing to another.
+	sandboxSyncLock *sync.RWMutex
 }
 
 func NewService(cfg Config) (Service, error) {
@@ -48,7 +55,7 @@ func NewService(cfg Config) (Service, error) {
 		return Service{}, errors.Wrap(err, "validation failed")
 	}
 
-	svc := Service{cfg}
+	svc := Service{Config: cfg}
 	svc.outputLatestVersionMessage()
 	svc.outputOutdatedSkillMessage()
 	return svc, nil
@@ -152,6 +159,20 @@ func (s Service) recordTelemetry(event string, props map[string]any) {
 	s.TelemetryCollector.Record(event, props)
 }
 
+// sleep pauses polling for d, returning the context's error instead if it's
+// cancelled first, such as by Ctrl-C.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 // confirmDestruction prompts the user to confirm a destructive action.
 // If yes is true, confirmation is skipped. In non-TTY environments without
 // yes, an error is returned instructing the user to pass --yes.
@@ -187,7 +208,7 @@ type ResolveRunIDConfig struct {
 // ResolveRunIDFromGitContext resolves the latest run ID by looking up the
 // current git branch and repository name via the API. Fields set on the
 // optional config override values that would otherwise be inferred from git.
-func (s Service) ResolveRunIDFromGitContext(overrides ...ResolveRunIDConfig) (string, error) {
+func (s Service) ResolveRunIDFromGitContext(ctx context.Context, overrides ...ResolveRunIDConfig) (string, error) {
 	var cfg ResolveRunIDConfig
 	if len(overrides) > 0 {
 		cfg = overrides[0]
@@ -206,7 +227,7 @@ func (s Service) ResolveRunIDFromGitContext(overrides ...ResolveRunIDConfig) (st
 		return "", errors.New("unable to determine the current branch and repository from git; please provide a run ID")
 	}
 
-	result, err := s.APIClient.RunStatus(api.RunStatusConfig{
+	result, err := s.APIClient.RunStatus(ctx, api.RunStatusConfig{
 		BranchName:     branchName,
 		RepositoryName: repositoryName,
 		DefinitionPath: cfg.DefinitionPath,
@@ -3,7 +3,7 @@ package cli
 import (
 	"archive/tar"
 	"bytes"
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -52,7 +52,7 @@ type DownloadArtifactResult struct {
 	OutputFiles []string
 }
 
-func (s Service) DownloadArtifact(cfg DownloadArtifactConfig) (_ *DownloadArtifactResult, dlErr error) {
+func (s Service) DownloadArtifact(ctx context.Context, cfg DownloadArtifactConfig) (_ *DownloadArtifactResult, dlErr error) {
 	start := time.Now()
 	var totalBytes int64
 	defer func() {
@@ -71,16 +71,16 @@ func (s Service) DownloadArtifact(cfg DownloadArtifactConfig) (_ *DownloadArtifa
 
 	var artifactDownloadRequest api.ArtifactDownloadRequestResult
 	if cfg.TaskKey != "" {
-		artifactDownloadRequest, err = s.APIClient.GetArtifactDownloadRequestByTaskKey(cfg.RunID, cfg.TaskKey, cfg.ArtifactKey)
+		artifactDownloadRequest, err = s.APIClient.GetArtifactDownloadRequestByTaskKey(ctx, cfg.RunID, cfg.TaskKey, cfg.ArtifactKey)
 	} else {
-		artifactDownloadRequest, err = s.APIClient.GetArtifactDownloadRequest(cfg.TaskID, cfg.ArtifactKey)
+		artifactDownloadRequest, err = s.APIClient.GetArtifactDownloadRequest(ctx, cfg.TaskID, cfg.ArtifactKey)
 	}
 	if err != nil {
 		if errors.Is(err, api.ErrNotFound) {
 			if cfg.TaskKey != "" {
-				return nil, errors.WrapSentinel(errors.New(fmt.Sprintf("Artifact %s for task key '%s' not found", cfg.ArtifactKey, cfg.TaskKey)), api.ErrNotFound)
+				return nil, withTaskIDs(errors.WrapSentinel(errors.New(fmt.Sprintf("Artifact %s for task key '%s' not found", cfg.ArtifactKey, cfg.TaskKey)), api.ErrNotFound), cfg.RunID, cfg.TaskID, cfg.TaskKey)
 			}
-			return nil, errors.WrapSentinel(errors.New(fmt.Sprintf("Artifact %s for task %s not found", cfg.ArtifactKey, cfg.TaskID)), api.ErrNotFound)
+			return nil, withTaskIDs(errors.WrapSentinel(errors.New(fmt.Sprintf("Artifact %s for task %s not found", cfg.ArtifactKey, cfg.TaskID)), api.ErrNotFound), cfg.RunID, cfg.TaskID, cfg.TaskKey)
 		}
 		return nil, errors.Wrap(err, "unable to fetch artifact download request")
 	}
@@ -93,7 +93,7 @@ func (s Service) DownloadArtifact(cfg DownloadArtifactConfig) (_ *DownloadArtifa
 		s.Stderr,
 	)
 
-	artifactBytes, err := s.APIClient.DownloadArtifact(artifactDownloadRequest)
+	artifactBytes, err := s.APIClient.DownloadArtifact(ctx, artifactDownloadRequest)
 	stopSpinner()
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to download artifact")
@@ -185,7 +185,7 @@ func (s Service) DownloadArtifact(cfg DownloadArtifactConfig) (_ *DownloadArtifa
 	result := &DownloadArtifactResult{OutputFiles: outputFiles}
 
 	if cfg.Json {
-		if err := json.NewEncoder(s.Stdout).Encode(result); err != nil {
+		if err := s.Output.Write(s.Stdout, result); err != nil {
 			return nil, errors.Wrap(err, "unable to encode JSON output")
 		}
 	} else {
@@ -230,7 +230,7 @@ type ListArtifactsResult struct {
 	Artifacts []ArtifactInfo
 }
 
-func (s Service) ListArtifacts(cfg ListArtifactsConfig) (*ListArtifactsResult, error) {
+func (s Service) ListArtifacts(ctx context.Context, cfg ListArtifactsConfig) (*ListArtifactsResult, error) {
 	err := cfg.Validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "validation failed")
@@ -238,16 +238,16 @@ func (s Service) ListArtifacts(cfg ListArtifactsConfig) (*ListArtifactsResult, e
 
 	var artifactDownloadRequests []api.ArtifactDownloadRequestResult
 	if cfg.TaskKey != "" {
-		artifactDownloadRequests, err = s.APIClient.GetAllArtifactDownloadRequestsByTaskKey(cfg.RunID, cfg.TaskKey)
+		artifactDownloadRequests, err = s.APIClient.GetAllArtifactDownloadRequestsByTaskKey(ctx, cfg.RunID, cfg.TaskKey)
 	} else {
-		artifactDownloadRequests, err = s.APIClient.GetAllArtifactDownloadRequests(cfg.TaskID)
+		artifactDownloadRequests, err = s.APIClient.GetAllArtifactDownloadRequests(ctx, cfg.TaskID)
 	}
 	if err != nil {
 		if errors.Is(err, api.ErrNotFound) {
 			if cfg.TaskKey != "" {
-				return nil, errors.WrapSentinel(errors.New(fmt.Sprintf("Artifacts for task key '%s' not found", cfg.TaskKey)), api.ErrNotFound)
+				return nil, withTaskIDs(errors.WrapSentinel(errors.New(fmt.Sprintf("Artifacts for task key '%s' not found", cfg.TaskKey)), api.ErrNotFound), cfg.RunID, cfg.TaskID, cfg.TaskKey)
 			}
-			return nil, errors.WrapSentinel(errors.New(fmt.Sprintf("Artifacts for task %s not found", cfg.TaskID)), api.ErrNotFound)
+			return nil, withTaskIDs(errors.WrapSentinel(errors.New(fmt.Sprintf("Artifacts for task %s not found", cfg.TaskID)), api.ErrNotFound), cfg.RunID, cfg.TaskID, cfg.TaskKey)
 		}
 		return nil, errors.Wrap(err, "unable to fetch artifacts")
 	}
@@ -264,7 +264,7 @@ func (s Service) ListArtifacts(cfg ListArtifactsConfig) (*ListArtifactsResult, e
 	result := &ListArtifactsResult{Artifacts: artifacts}
 
 	if cfg.Json {
-		if err := json.NewEncoder(s.Stdout).Encode(result); err != nil {
+		if err := s.Output.Write(s.Stdout, result); err != nil {
 			return nil, errors.Wrap(err, "unable to encode JSON output")
 		}
 	} else {
@@ -340,7 +340,7 @@ type DownloadAllArtifactsResult struct {
 	OutputFiles []string
 }
 
-func (s Service) DownloadAllArtifacts(cfg DownloadAllArtifactsConfig) (_ *DownloadAllArtifactsResult, dlErr error) {
+func (s Service) DownloadAllArtifacts(ctx context.Context, cfg DownloadAllArtifactsConfig) (_ *DownloadAllArtifactsResult, dlErr error) {
 	start := time.Now()
 	var artifactCount int
 	var totalBytes int64
@@ -360,16 +360,16 @@ func (s Service) DownloadAllArtifacts(cfg DownloadAllArtifactsConfig) (_ *Downlo
 
 	var artifactDownloadRequests []api.ArtifactDownloadRequestResult
 	if cfg.TaskKey != "" {
-		artifactDownloadRequests, err = s.APIClient.GetAllArtifactDownloadRequestsByTaskKey(cfg.RunID, cfg.TaskKey)
+		artifactDownloadRequests, err = s.APIClient.GetAllArtifactDownloadRequestsByTaskKey(ctx, cfg.RunID, cfg.TaskKey)
 	} else {
-		artifactDownloadRequests, err = s.APIClient.GetAllArtifactDownloadRequests(cfg.TaskID)
+		artifactDownloadRequests, err = s.APIClient.GetAllArtifactDownloadRequests(ctx, cfg.TaskID)
 	}
 	if err != nil {
 		if errors.Is(err, api.ErrNotFound) {
 			if cfg.TaskKey != "" {
-				return nil, errors.WrapSentinel(errors.New(fmt.Sprintf("Artifacts for task key '%s' not found", cfg.TaskKey)), api.ErrNotFound)
+				return nil, withTaskIDs(errors.WrapSentinel(errors.New(fmt.Sprintf("Artifacts for task key '%s' not found", cfg.TaskKey)), api.ErrNotFound), cfg.RunID, cfg.TaskID, cfg.TaskKey)
 			}
-			return nil, errors.WrapSentinel(errors.New(fmt.Sprintf("Artifacts for task %s not found", cfg.TaskID)), api.ErrNotFound)
+			return nil, withTaskIDs(errors.WrapSentinel(errors.New(fmt.Sprintf("Artifacts for task %s not found", cfg.TaskID)), api.ErrNotFound), cfg.RunID, cfg.TaskID, cfg.TaskKey)
 		}
 		return nil, errors.Wrap(err, "unable to fetch artifact download requests")
 	}
@@ -385,7 +385,7 @@ func (s Service) DownloadAllArtifacts(cfg DownloadAllArtifactsConfig) (_ *Downlo
 		}
 		result := &DownloadAllArtifactsResult{}
 		if cfg.Json {
-			if err := json.NewEncoder(s.Stdout).Encode(result); err != nil {
+			if err := s.Output.Write(s.Stdout, result); err != nil {
 				return nil, errors.Wrap(err, "unable to encode JSON output")
 			}
 		}
@@ -410,7 +410,7 @@ func (s Service) DownloadAllArtifacts(cfg DownloadAllArtifactsConfig) (_ *Downlo
 		wg.Add(1)
 		go func(idx int, r api.ArtifactDownloadRequestResult) {
 			defer wg.Done()
-			artifactBytes, err := s.APIClient.DownloadArtifact(r)
+			artifactBytes, err := s.APIClient.DownloadArtifact(ctx, r)
 			results[idx] = downloadResult{index: idx, bytes: artifactBytes, err: err}
 		}(i, req)
 	}
@@ -486,7 +486,7 @@ func (s Service) DownloadAllArtifacts(cfg DownloadAllArtifactsConfig) (_ *Downlo
 	result := &DownloadAllArtifactsResult{OutputFiles: allOutputFiles}
 
 	if cfg.Json {
-		if err := json.NewEncoder(s.Stdout).Encode(result); err != nil {
+		if err := s.Output.Write(s.Stdout, result); err != nil {
 			return nil, errors.Wrap(err, "unable to encode JSON output")
 		}
 	} else {
@@ -23,7 +23,7 @@ func TestService_DownloadArtifact(t *testing.T) {
 			return api.ArtifactDownloadRequestResult{}, api.ErrNotFound
 		}
 
-		_, err := s.service.DownloadArtifact(cli.DownloadArtifactConfig{
+		_, err := s.service.DownloadArtifact(t.Context(), cli.DownloadArtifactConfig{
 			TaskID:      "task-123",
 			ArtifactKey: "my-artifact",
 			OutputDir:   s.tmp,
@@ -40,7 +40,7 @@ func TestService_DownloadArtifact(t *testing.T) {
 			return api.ArtifactDownloadRequestResult{}, errors.New("network error")
 		}
 
-		_, err := s.service.DownloadArtifact(cli.DownloadArtifactConfig{
+		_, err := s.service.DownloadArtifact(t.Context(), cli.DownloadArtifactConfig{
 			TaskID:      "task-123",
 			ArtifactKey: "my-artifact",
 			OutputDir:   s.tmp,
@@ -69,7 +69,7 @@ func TestService_DownloadArtifact(t *testing.T) {
 			return nil, errors.New("download failed")
 		}
 
-		_, err := s.service.DownloadArtifact(cli.DownloadArtifactConfig{
+		_, err := s.service.DownloadArtifact(t.Context(), cli.DownloadArtifactConfig{
 			TaskID:      "task-123",
 			ArtifactKey: "my-artifact",
 			OutputDir:   s.tmp,
@@ -84,7 +84,7 @@ func TestService_DownloadArtifact(t *testing.T) {
 	t.Run("when validation fails - missing task ID", func(t *testing.T) {
 		s := setupTest(t)
 
-		_, err := s.service.DownloadArtifact(cli.DownloadArtifactConfig{
+		_, err := s.service.DownloadArtifact(t.Context(), cli.DownloadArtifactConfig{
 			TaskID:      "",
 			ArtifactKey: "my-artifact",
 			OutputDir:   s.tmp,
@@ -98,7 +98,7 @@ func TestService_DownloadArtifact(t *testing.T) {
 	t.Run("when validation fails - missing artifact key", func(t *testing.T) {
 		s := setupTest(t)
 
-		_, err := s.service.DownloadArtifact(cli.DownloadArtifactConfig{
+		_, err := s.service.DownloadArtifact(t.Context(), cli.DownloadArtifactConfig{
 			TaskID:      "task-123",
 			ArtifactKey: "",
 			OutputDir:   s.tmp,
@@ -112,7 +112,7 @@ func TestService_DownloadArtifact(t *testing.T) {
 	t.Run("when validation fails - both output-dir and output-file set", func(t *testing.T) {
 		s := setupTest(t)
 
-		_, err := s.service.DownloadArtifact(cli.DownloadArtifactConfig{
+		_, err := s.service.DownloadArtifact(t.Context(), cli.DownloadArtifactConfig{
 			TaskID:      "task-123",
 			ArtifactKey: "my-artifact",
 			OutputDir:   s.tmp,
@@ -147,7 +147,7 @@ func TestService_DownloadArtifact(t *testing.T) {
 			return tarBytes, nil
 		}
 
-		_, err := s.service.DownloadArtifact(cli.DownloadArtifactConfig{
+		_, err := s.service.DownloadArtifact(t.Context(), cli.DownloadArtifactConfig{
 			TaskID:      "task-123",
 			ArtifactKey: "my-file",
 			OutputDir:   s.tmp,
@@ -190,7 +190,7 @@ func TestService_DownloadArtifact(t *testing.T) {
 			return tarBytes, nil
 		}
 
-		_, err := s.service.DownloadArtifact(cli.DownloadArtifactConfig{
+		_, err := s.service.DownloadArtifact(t.Context(), cli.DownloadArtifactConfig{
 			TaskID:      "task-456",
 			ArtifactKey: "my-dir",
 			OutputDir:   s.tmp,
@@ -232,7 +232,7 @@ func TestService_DownloadArtifact(t *testing.T) {
 			return tarBytes, nil
 		}
 
-		_, err := s.service.DownloadArtifact(cli.DownloadArtifactConfig{
+		_, err := s.service.DownloadArtifact(t.Context(), cli.DownloadArtifactConfig{
 			TaskID:      "task-789",
 			ArtifactKey: "my-dir",
 			OutputDir:   s.tmp,
@@ -278,7 +278,7 @@ func TestService_DownloadArtifact(t *testing.T) {
 			return tarBytes, nil
 		}
 
-		_, err := s.service.DownloadArtifact(cli.DownloadArtifactConfig{
+		_, err := s.service.DownloadArtifact(t.Context(), cli.DownloadArtifactConfig{
 			TaskID:      "task-999",
 			ArtifactKey: "my-file",
 			OutputFile:  customOutputFile,
@@ -316,7 +316,7 @@ func TestService_DownloadArtifact(t *testing.T) {
 			return tarBytes, nil
 		}
 
-		_, err := s.service.DownloadArtifact(cli.DownloadArtifactConfig{
+		_, err := s.service.DownloadArtifact(t.Context(), cli.DownloadArtifactConfig{
 			TaskID:      "task-111",
 			ArtifactKey: "result",
 			OutputDir:   s.tmp,
@@ -351,7 +351,7 @@ func TestService_DownloadArtifact(t *testing.T) {
 			return tarBytes, nil
 		}
 
-		_, err := s.service.DownloadArtifact(cli.DownloadArtifactConfig{
+		_, err := s.service.DownloadArtifact(t.Context(), cli.DownloadArtifactConfig{
 			TaskID:      "task-222",
 			ArtifactKey: "my-dir",
 			OutputDir:   s.tmp,
@@ -411,7 +411,7 @@ func TestService_DownloadArtifact(t *testing.T) {
 			return buf.Bytes(), nil
 		}
 
-		_, err = s.service.DownloadArtifact(cli.DownloadArtifactConfig{
+		_, err = s.service.DownloadArtifact(t.Context(), cli.DownloadArtifactConfig{
 			TaskID:      "task-444",
 			ArtifactKey: "dotslash",
 			OutputDir:   s.tmp,
@@ -447,7 +447,7 @@ func TestService_DownloadArtifact(t *testing.T) {
 			return tarBytes, nil
 		}
 
-		_, err := s.service.DownloadArtifact(cli.DownloadArtifactConfig{
+		_, err := s.service.DownloadArtifact(t.Context(), cli.DownloadArtifactConfig{
 			TaskID:      "task-999",
 			ArtifactKey: "evil",
 			OutputDir:   s.tmp,
@@ -472,7 +472,7 @@ func TestService_ListArtifacts(t *testing.T) {
 	t.Run("when validation fails - missing task ID", func(t *testing.T) {
 		s := setupTest(t)
 
-		_, err := s.service.ListArtifacts(cli.ListArtifactsConfig{
+		_, err := s.service.ListArtifacts(t.Context(), cli.ListArtifactsConfig{
 			TaskID: "",
 		})
 
@@ -488,7 +488,7 @@ func TestService_ListArtifacts(t *testing.T) {
 			return nil, api.ErrNotFound
 		}
 
-		_, err := s.service.ListArtifacts(cli.ListArtifactsConfig{
+		_, err := s.service.ListArtifacts(t.Context(), cli.ListArtifactsConfig{
 			TaskID: "task-999",
 		})
 
@@ -503,7 +503,7 @@ func TestService_ListArtifacts(t *testing.T) {
 			return nil, errors.New("network error")
 		}
 
-		_, err := s.service.ListArtifacts(cli.ListArtifactsConfig{
+		_, err := s.service.ListArtifacts(t.Context(), cli.ListArtifactsConfig{
 			TaskID: "task-123",
 		})
 
@@ -518,7 +518,7 @@ func TestService_ListArtifacts(t *testing.T) {
 			return []api.ArtifactDownloadRequestResult{}, nil
 		}
 
-		result, err := s.service.ListArtifacts(cli.ListArtifactsConfig{
+		result, err := s.service.ListArtifacts(t.Context(), cli.ListArtifactsConfig{
 			TaskID: "task-123",
 		})
 
@@ -538,7 +538,7 @@ func TestService_ListArtifacts(t *testing.T) {
 			}, nil
 		}
 
-		result, err := s.service.ListArtifacts(cli.ListArtifactsConfig{
+		result, err := s.service.ListArtifacts(t.Context(), cli.ListArtifactsConfig{
 			TaskID: "task-123",
 		})
 
@@ -568,7 +568,7 @@ func TestService_ListArtifacts(t *testing.T) {
 			}, nil
 		}
 
-		result, err := s.service.ListArtifacts(cli.ListArtifactsConfig{
+		result, err := s.service.ListArtifacts(t.Context(), cli.ListArtifactsConfig{
 			TaskID: "task-123",
 			Json:   true,
 		})
@@ -591,7 +591,7 @@ func TestService_ListArtifacts(t *testing.T) {
 			return []api.ArtifactDownloadRequestResult{}, nil
 		}
 
-		_, err := s.service.ListArtifacts(cli.ListArtifactsConfig{
+		_, err := s.service.ListArtifacts(t.Context(), cli.ListArtifactsConfig{
 			TaskID: "task-123",
 			Json:   true,
 		})
@@ -612,7 +612,7 @@ func TestService_DownloadAllArtifacts(t *testing.T) {
 			return []api.ArtifactDownloadRequestResult{}, nil
 		}
 
-		result, err := s.service.DownloadAllArtifacts(cli.DownloadAllArtifactsConfig{
+		result, err := s.service.DownloadAllArtifacts(t.Context(), cli.DownloadAllArtifactsConfig{
 			TaskID:    "task-123",
 			OutputDir: s.tmp,
 		})
@@ -629,7 +629,7 @@ func TestService_DownloadAllArtifacts(t *testing.T) {
 			return nil, api.ErrNotFound
 		}
 
-		_, err := s.service.DownloadAllArtifacts(cli.DownloadAllArtifactsConfig{
+		_, err := s.service.DownloadAllArtifacts(t.Context(), cli.DownloadAllArtifactsConfig{
 			TaskID:    "task-999",
 			OutputDir: s.tmp,
 		})
@@ -645,7 +645,7 @@ func TestService_DownloadAllArtifacts(t *testing.T) {
 			return nil, errors.New("network error")
 		}
 
-		_, err := s.service.DownloadAllArtifacts(cli.DownloadAllArtifactsConfig{
+		_, err := s.service.DownloadAllArtifacts(t.Context(), cli.DownloadAllArtifactsConfig{
 			TaskID:    "task-123",
 			OutputDir: s.tmp,
 		})
@@ -658,7 +658,7 @@ func TestService_DownloadAllArtifacts(t *testing.T) {
 	t.Run("when validation fails - missing task ID", func(t *testing.T) {
 		s := setupTest(t)
 
-		_, err := s.service.DownloadAllArtifacts(cli.DownloadAllArtifactsConfig{
+		_, err := s.service.DownloadAllArtifacts(t.Context(), cli.DownloadAllArtifactsConfig{
 			TaskID:    "",
 			OutputDir: s.tmp,
 		})
@@ -692,7 +692,7 @@ func TestService_DownloadAllArtifacts(t *testing.T) {
 			return tar2, nil
 		}
 
-		result, err := s.service.DownloadAllArtifacts(cli.DownloadAllArtifactsConfig{
+		result, err := s.service.DownloadAllArtifacts(t.Context(), cli.DownloadAllArtifactsConfig{
 			TaskID:    "task-123",
 			OutputDir: s.tmp,
 		})
@@ -729,7 +729,7 @@ func TestService_DownloadAllArtifacts(t *testing.T) {
 			return tarBytes, nil
 		}
 
-		result, err := s.service.DownloadAllArtifacts(cli.DownloadAllArtifactsConfig{
+		result, err := s.service.DownloadAllArtifacts(t.Context(), cli.DownloadAllArtifactsConfig{
 			TaskID:      "task-123",
 			OutputDir:   s.tmp,
 			AutoExtract: false,
@@ -763,7 +763,7 @@ func TestService_DownloadAllArtifacts(t *testing.T) {
 			return tarBytes, nil
 		}
 
-		result, err := s.service.DownloadAllArtifacts(cli.DownloadAllArtifactsConfig{
+		result, err := s.service.DownloadAllArtifacts(t.Context(), cli.DownloadAllArtifactsConfig{
 			TaskID:      "task-123",
 			OutputDir:   s.tmp,
 			AutoExtract: true,
@@ -801,7 +801,7 @@ func TestService_DownloadAllArtifacts(t *testing.T) {
 			return nil, errors.New("download failed")
 		}
 
-		_, err := s.service.DownloadAllArtifacts(cli.DownloadAllArtifactsConfig{
+		_, err := s.service.DownloadAllArtifacts(t.Context(), cli.DownloadAllArtifactsConfig{
 			TaskID:    "task-123",
 			OutputDir: s.tmp,
 		})
@@ -827,7 +827,7 @@ func TestService_DownloadAllArtifacts(t *testing.T) {
 			return tar1, nil
 		}
 
-		_, err := s.service.DownloadAllArtifacts(cli.DownloadAllArtifactsConfig{
+		_, err := s.service.DownloadAllArtifacts(t.Context(), cli.DownloadAllArtifactsConfig{
 			TaskID:    "task-123",
 			OutputDir: s.tmp,
 			Json:      true,
@@ -860,7 +860,7 @@ func TestService_DownloadAllArtifacts(t *testing.T) {
 		customDir := filepath.Join(s.tmp, "custom-output")
 		require.NoError(t, os.MkdirAll(customDir, 0755))
 
-		result, err := s.service.DownloadAllArtifacts(cli.DownloadAllArtifactsConfig{
+		result, err := s.service.DownloadAllArtifacts(t.Context(), cli.DownloadAllArtifactsConfig{
 			TaskID:                 "task-123",
 			OutputDir:              customDir,
 			OutputDirExplicitlySet: true,
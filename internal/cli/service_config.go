@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/rwx-cloud/rwx/internal/config"
+	"github.com/rwx-cloud/rwx/internal/errors"
+)
+
+type ListSettingsConfig struct {
+	Json bool
+}
+
+type GetSettingConfig struct {
+	Key  string
+	Json bool
+}
+
+type SetSettingConfig struct {
+	Key   string
+	Value string
+	// Project writes to the project's .rwx/cli.yml instead of the user's
+	// config file
+	Project bool
+}
+
+func (c SetSettingConfig) Validate() error {
+	if err := config.ValidateValue(c.Key, c.Value); err != nil {
+		return err
+	}
+
+	setting, _ := config.LookupSetting(c.Key)
+	if c.Project && setting.UserOnly {
+		return errors.Errorf("%s can only be set in the user config file", c.Key)
+	}
+
+	return nil
+}
+
+func (s Service) requireSettings() error {
+	if s.Settings == nil {
+		return errors.New("settings are not available")
+	}
+
+	return nil
+}
+
+// ListSettings prints every setting with its value and where it came from.
+func (s Service) ListSettings(cfg ListSettingsConfig) ([]config.Value, error) {
+	if err := s.requireSettings(); err != nil {
+		return nil, err
+	}
+
+	values := s.Settings.Values()
+
+	if cfg.Json {
+		if err := s.Output.Write(s.Stdout, values); err != nil {
+			return nil, errors.Wrap(err, "unable to encode the settings")
+		}
+		return values, nil
+	}
+
+	fmt.Fprintf(s.Stdout, "%-24s %-20s %s\n", "KEY", "VALUE", "SOURCE")
+	for _, value := range values {
+		fmt.Fprintf(s.Stdout, "%-24s %-20s %s\n", value.Key, value.Value, describeSource(value))
+	}
+
+	return values, nil
+}
+
+func describeSource(value config.Value) string {
+	if value.Origin == "" {
+		return string(value.Source)
+	}
+	return fmt.Sprintf("%s (%s)", value.Source, value.Origin)
+}
+
+// GetSetting prints the value of one setting.
+func (s Service) GetSetting(cfg GetSettingConfig) (config.Value, error) {
+	if err := s.requireSettings(); err != nil {
+		return config.Value{}, err
+	}
+
+	if _, ok := config.LookupSetting(cfg.Key); !ok {
+		return config.Value{}, errors.Errorf("unknown setting %q", cfg.Key)
+	}
+
+	value := s.Settings.Lookup(cfg.Key)
+
+	if cfg.Json {
+		if err := s.Output.Write(s.Stdout, value); err != nil {
+			return value, errors.Wrap(err, "unable to encode the setting")
+		}
+	} else {
+		fmt.Fprintln(s.Stdout, value.Value)
+	}
+
+	return value, nil
+}
+
+// SetSetting writes a setting to the user's config file, or to the project's
+// when cfg.Project is set. Comments and other settings in the file are kept.
+func (s Service) SetSetting(cfg SetSettingConfig) error {
+	if err := s.requireSettings(); err != nil {
+		return err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return errors.Wrap(err, "validation failed")
+	}
+
+	path := s.Settings.UserFile
+	if cfg.Project {
+		path = s.Settings.ProjectFile
+		if path == "" {
+			return errors.New("no .rwx directory found for a project config file")
+		}
+	}
+
+	if err := writeSetting(path, cfg.Key, settingYAMLValue(cfg.Key, cfg.Value)); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(s.Stdout, "Set %s to %q in %s\n", cfg.Key, cfg.Value, path)
+	return nil
+}
+
+// settingYAMLValue keeps boolean settings as YAML booleans.
+func settingYAMLValue(key, value string) any {
+	setting, _ := config.LookupSetting(key)
+	if setting.Default == "true" || setting.Default == "false" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return value
+}
+
+func writeSetting(path, key string, value any) error {
+	contents, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return errors.Wrapf(err, "unable to read %q", path)
+	}
+
+	existing, err := config.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if len(existing) == 0 {
+		// The file is missing, empty, or only has comments, so append to it
+		encoded, err := yaml.Marshal(map[string]any{key: value})
+		if err != nil {
+			return errors.Wrap(err, "unable to encode the setting")
+		}
+
+		prefix := string(contents)
+		if prefix != "" && !strings.HasSuffix(prefix, "\n") {
+			prefix += "\n"
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return errors.Wrapf(err, "unable to create the directory for %q", path)
+		}
+		if err := os.WriteFile(path, []byte(prefix+string(encoded)), 0o644); err != nil {
+			return errors.Wrapf(err, "unable to write %q", path)
+		}
+		return nil
+	}
+
+	doc, err := ParseYAMLDoc(string(contents))
+	if err != nil {
+		return errors.Wrapf(err, "unable to parse %q", path)
+	}
+
+	if err := doc.SetAtPath("$."+key, value); err != nil {
+		return errors.Wrapf(err, "unable to set %s in %q", key, path)
+	}
+
+	if err := doc.WriteFile(path); err != nil {
+		return errors.Wrapf(err, "unable to write %q", path)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rwx-cloud/rwx/internal/cli"
+	"github.com/rwx-cloud/rwx/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func setupSettings(t *testing.T, s *testSetup, userContents string) string {
+	userFile := filepath.Join(s.tmp, "user", config.UserFilename)
+	if userContents != "" {
+		require.NoError(t, os.MkdirAll(filepath.Dir(userFile), 0o755))
+		require.NoError(t, os.WriteFile(userFile, []byte(userContents), 0o644))
+	}
+
+	settings, err := config.LoadLayered(s.absConfig(".rwx/cli.yml"), userFile, func(string) (string, bool) { return "", false })
+	require.NoError(t, err)
+	s.config.Settings = settings
+
+	s.service, err = cli.NewService(s.config)
+	require.NoError(t, err)
+	return userFile
+}
+
+func TestService_ListSettings(t *testing.T) {
+	t.Run("shows where each value came from", func(t *testing.T) {
+		s := setupTest(t)
+		userFile := setupSettings(t, s, "vault: team\n")
+
+		values, err := s.service.ListSettings(cli.ListSettingsConfig{})
+		require.NoError(t, err)
+		require.Len(t, values, len(config.Settings))
+		require.Contains(t, s.mockStdout.String(), "user ("+userFile+")")
+		require.Contains(t, s.mockStdout.String(), "team")
+	})
+}
+
+func TestService_GetSetting(t *testing.T) {
+	t.Run("prints the value", func(t *testing.T) {
+		s := setupTest(t)
+		setupSettings(t, s, "vault: team\n")
+
+		value, err := s.service.GetSetting(cli.GetSettingConfig{Key: "vault"})
+		require.NoError(t, err)
+		require.Equal(t, config.SourceUser, value.Source)
+		require.Equal(t, "team\n", s.mockStdout.String())
+	})
+
+	t.Run("errors on unknown settings", func(t *testing.T) {
+		s := setupTest(t)
+		setupSettings(t, s, "")
+
+		_, err := s.service.GetSetting(cli.GetSettingConfig{Key: "nope"})
+		require.ErrorContains(t, err, `unknown setting "nope"`)
+	})
+}
+
+func TestService_SetSetting(t *testing.T) {
+	t.Run("creates the user file", func(t *testing.T) {
+		s := setupTest(t)
+		userFile := setupSettings(t, s, "")
+
+		err := s.service.SetSetting(cli.SetSettingConfig{Key: "no_cache", Value: "true"})
+		require.NoError(t, err)
+
+		contents, err := os.ReadFile(userFile)
+		require.NoError(t, err)
+		require.Equal(t, "no_cache: true\n", string(contents))
+	})
+
+	t.Run("keeps comments and other settings", func(t *testing.T) {
+		s := setupTest(t)
+		userFile := setupSettings(t, s, "# team defaults\nvault: team\noutput: text\n")
+
+		err := s.service.SetSetting(cli.SetSettingConfig{Key: "output", Value: "json"})
+		require.NoError(t, err)
+
+		values, err := config.ReadFile(userFile)
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"vault": "team", "output": "json"}, values)
+
+		contents, err := os.ReadFile(userFile)
+		require.NoError(t, err)
+		require.Contains(t, string(contents), "# team defaults")
+	})
+
+	t.Run("writes to the project file", func(t *testing.T) {
+		s := setupTest(t)
+		setupSettings(t, s, "")
+
+		err := s.service.SetSetting(cli.SetSettingConfig{Key: "vault", Value: "team", Project: true})
+		require.NoError(t, err)
+
+		values, err := config.ReadFile(s.absConfig(".rwx/cli.yml"))
+		require.NoError(t, err)
+		require.Equal(t, map[string]string{"vault": "team"}, values)
+	})
+
+	t.Run("does not write user-only settings to the project file", func(t *testing.T) {
+		s := setupTest(t)
+		setupSettings(t, s, "")
+
+		err := s.service.SetSetting(cli.SetSettingConfig{Key: "host", Value: "cloud.example.com", Project: true})
+		require.ErrorContains(t, err, "host can only be set in the user config file")
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		s := setupTest(t)
+		setupSettings(t, s, "")
+
+		err := s.service.SetSetting(cli.SetSettingConfig{Key: "output", Value: "xml"})
+		require.ErrorContains(t, err, `"xml" is not text, json, yaml or ndjson`)
+	})
+}
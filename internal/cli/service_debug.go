@@ -1,9 +1,17 @@
 package cli
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"al.essio.dev/pkg/shellescape"
+	"github.com/rwx-cloud/rwx/internal/api"
+	"github.com/rwx-cloud/rwx/internal/asciicast"
 	"github.com/rwx-cloud/rwx/internal/errors"
 
 	"golang.org/x/crypto/ssh"
@@ -11,32 +19,76 @@ import (
 
 type DebugTaskConfig struct {
 	DebugKey string
+	// RunID and TaskKey select a task by its key instead of a debug key
+	RunID   string
+	TaskKey string
+	// Wait polls until the task hits a breakpoint instead of failing with
+	// ErrRetry when it isn't debuggable yet
+	Wait bool
+	// PollInterval is how often to check for a breakpoint while waiting. It
+	// defaults to one second.
+	PollInterval time.Duration
+	// Record is a file to record the session to in asciicast v2 format
+	Record string
+	// Command runs non-interactively instead of starting a shell. A non-zero
+	// exit code is returned as an *ExitCodeError.
+	Command []string
+	// Pull copies this path from the task to OutputDir instead of starting a
+	// shell
+	Pull      string
+	OutputDir string
 }
 
 func (c DebugTaskConfig) Validate() error {
+	if c.TaskKey != "" {
+		if c.RunID == "" {
+			return errors.New("run ID must be provided when using task key")
+		}
+		return c.validateMode()
+	}
+
 	if c.DebugKey == "" {
 		return errors.New("you must specify a run ID, a task ID, or an RWX Cloud URL")
 	}
 
+	return c.validateMode()
+}
+
+func (c DebugTaskConfig) validateMode() error {
+	if len(c.Command) > 0 && c.Pull != "" {
+		return errors.New("a command and a path to pull cannot be used together")
+	}
+
+	if c.Record != "" && !c.interactive() {
+		return errors.New("only interactive sessions can be recorded")
+	}
+
 	return nil
 }
 
+func (c DebugTaskConfig) interactive() bool {
+	return len(c.Command) == 0 && c.Pull == ""
+}
+
+func (c DebugTaskConfig) description() string {
+	if c.TaskKey != "" {
+		return c.TaskKey
+	}
+	return c.DebugKey
+}
+
 // DebugTask will connect to a running task over SSH. Key exchange is facilitated over the Cloud API.
-func (s Service) DebugTask(cfg DebugTaskConfig) error {
+func (s Service) DebugTask(ctx context.Context, cfg DebugTaskConfig) error {
 	err := cfg.Validate()
 	if err != nil {
 		return errors.Wrap(err, "validation failed")
 	}
 
-	connectionInfo, err := s.APIClient.GetDebugConnectionInfo(cfg.DebugKey)
+	connectionInfo, err := s.waitForDebugConnectionInfo(ctx, cfg)
 	if err != nil {
 		return err
 	}
 
-	if !connectionInfo.Debuggable {
-		return errors.Wrap(errors.ErrRetry, "The task or run is not in a debuggable state")
-	}
-
 	privateUserKey, err := ssh.ParsePrivateKey([]byte(connectionInfo.PrivateUserKey))
 	if err != nil {
 		return errors.Wrap(err, "unable to parse key material retrieved from Cloud API")
@@ -57,6 +109,11 @@ func (s Service) DebugTask(cfg DebugTaskConfig) error {
 		Auth:            []ssh.AuthMethod{ssh.PublicKeys(privateUserKey)},
 		HostKeyCallback: ssh.FixedHostKey(publicHostKey),
 		BannerCallback: func(message string) error {
+			// Keep stdout for the output of a command or a pull
+			if !cfg.interactive() {
+				fmt.Fprintln(s.Stderr, message)
+				return nil
+			}
 			fmt.Println(message)
 			return nil
 		},
@@ -76,8 +133,28 @@ func (s Service) DebugTask(cfg DebugTaskConfig) error {
 	})
 	defer s.SSHClient.Close()
 
+	if len(cfg.Command) > 0 {
+		return s.execDebugCommand(cfg.Command)
+	}
+	if cfg.Pull != "" {
+		return s.pullDebugPath(cfg.Pull, cfg.OutputDir)
+	}
+
+	var recorder *asciicast.Recorder
+	if cfg.Record != "" {
+		recorder, err = s.startRecording(cfg.Record, "rwx debug "+cfg.description())
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err := recorder.Close(); err != nil {
+				fmt.Fprintf(s.Stderr, "Warning: %v\n", err)
+			}
+		}()
+	}
+
 	cmdStart := time.Now()
-	if err := s.SSHClient.InteractiveSession(); err != nil {
+	if err := s.SSHClient.InteractiveSession(terminalRecorder(recorder)); err != nil {
 		exitCode := -1
 		var exitErr *ssh.ExitError
 		if errors.As(err, &exitErr) {
@@ -107,3 +184,154 @@ func (s Service) DebugTask(cfg DebugTaskConfig) error {
 
 	return nil
 }
+
+// waitForDebugConnectionInfo returns the connection info for a debuggable
+// task. Unless cfg.Wait is set, a task that isn't debuggable yet is an
+// ErrRetry; otherwise it is polled until it hits a breakpoint. A run or task
+// that finishes first is an ErrGone.
+func (s Service) waitForDebugConnectionInfo(ctx context.Context, cfg DebugTaskConfig) (api.DebugConnectionInfo, error) {
+	pollInterval := cfg.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = time.Second
+	}
+
+	var stopSpinner func()
+	defer func() {
+		if stopSpinner != nil {
+			stopSpinner()
+		}
+	}()
+
+	for {
+		connectionInfo, err := s.getDebugConnectionInfo(ctx, cfg)
+		if err == nil {
+			return connectionInfo, nil
+		}
+		if !cfg.Wait || !errors.Is(err, errors.ErrRetry) {
+			return connectionInfo, err
+		}
+
+		if stopSpinner == nil {
+			stopSpinner = Spin(fmt.Sprintf("Waiting for %s to hit a breakpoint...", cfg.description()), s.StdoutIsTTY, s.Stdout)
+		}
+		if err := sleep(ctx, pollInterval); err != nil {
+			return api.DebugConnectionInfo{}, err
+		}
+	}
+}
+
+func (s Service) getDebugConnectionInfo(ctx context.Context, cfg DebugTaskConfig) (api.DebugConnectionInfo, error) {
+	debugKey := cfg.DebugKey
+	if cfg.TaskKey != "" {
+		taskID, err := s.resolveDebugTaskKey(ctx, cfg.RunID, cfg.TaskKey)
+		if err != nil {
+			return api.DebugConnectionInfo{}, err
+		}
+		debugKey = taskID
+	}
+
+	connectionInfo, err := s.APIClient.GetDebugConnectionInfo(ctx, debugKey)
+	if err != nil {
+		return connectionInfo, err
+	}
+
+	if !connectionInfo.Debuggable {
+		return connectionInfo, errors.Wrap(errors.ErrRetry, "The task or run is not in a debuggable state")
+	}
+
+	return connectionInfo, nil
+}
+
+// resolveDebugTaskKey finds the ID of the task with the given key in a run.
+// It returns an empty ID while the task is still queued, and ErrGone once it
+// has finished.
+func (s Service) resolveDebugTaskKey(ctx context.Context, runID, taskKey string) (string, error) {
+	status, err := s.APIClient.TaskKeyStatus(ctx, api.TaskKeyStatusConfig{RunID: runID, TaskKey: taskKey})
+	if err != nil {
+		if errors.Is(err, api.ErrNotFound) {
+			return "", withTaskIDs(errors.WrapSentinel(fmt.Errorf("Task with key '%s' not found", taskKey), api.ErrNotFound), runID, "", taskKey)
+		}
+		return "", err
+	}
+
+	if status.Polling.Completed {
+		return "", errors.Wrap(errors.ErrGone, fmt.Sprintf("Task '%s' finished without hitting a breakpoint", taskKey))
+	}
+
+	if status.TaskID == "" {
+		return "", errors.Wrap(errors.ErrRetry, fmt.Sprintf("Task '%s' hasn't started yet", taskKey))
+	}
+
+	return status.TaskID, nil
+}
+
+// execDebugCommand runs command on the task and writes its output to stdout.
+func (s Service) execDebugCommand(command []string) error {
+	cmdStart := time.Now()
+	exitCode, output, err := s.SSHClient.ExecuteCommandWithOutput(BuildSandboxCommand(command, nil, ""))
+	s.recordTelemetry("ssh.command", map[string]any{
+		"duration_ms": time.Since(cmdStart).Milliseconds(),
+		"exit_code":   exitCode,
+		"interactive": false,
+	})
+	if err != nil {
+		return errors.WrapSentinel(fmt.Errorf("unable to run command on remote host: %w", err), errors.ErrSSH)
+	}
+
+	fmt.Fprint(s.Stdout, output)
+
+	if exitCode != 0 {
+		return &ExitCodeError{Code: exitCode}
+	}
+	return nil
+}
+
+// pullDebugPath copies a file or directory from the task into outputDir. It
+// is archived with tar on the task, so it keeps its name and permissions.
+func (s Service) pullDebugPath(remotePath, outputDir string) error {
+	if outputDir == "" {
+		outputDir = "."
+	}
+
+	remotePath = strings.TrimSuffix(remotePath, "/")
+	if remotePath == "" {
+		remotePath = "/"
+	}
+	parent, name := path.Split(remotePath)
+	if parent == "" {
+		parent = "."
+	}
+	if name == "" {
+		parent, name = "/", "."
+	}
+
+	command := fmt.Sprintf("tar -cf - -C %s -- %s", shellescape.Quote(parent), shellescape.Quote(name))
+	exitCode, archive, err := s.SSHClient.ExecuteCommandWithOutput(command)
+	if err != nil {
+		return errors.WrapSentinel(fmt.Errorf("unable to pull %q from remote host: %w", remotePath, err), errors.ErrSSH)
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("unable to pull %q from remote host: tar exited with code %d", remotePath, exitCode)
+	}
+
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		return errors.Wrapf(err, "unable to create directory %q", outputDir)
+	}
+
+	files, err := extractTar([]byte(archive), outputDir)
+	if err != nil {
+		return errors.Wrapf(err, "unable to extract %q", remotePath)
+	}
+
+	s.recordTelemetry("debug.pull", map[string]any{
+		"file_count": len(files),
+		"bytes":      len(archive),
+	})
+
+	if len(files) == 1 {
+		fmt.Fprintf(s.Stdout, "Pulled %s\n", files[0])
+	} else {
+		fmt.Fprintf(s.Stdout, "Pulled %d files to %s\n", len(files), filepath.Join(outputDir, name))
+	}
+	return nil
+}
@@ -1,13 +1,20 @@
 package cli_test
 
 import (
+	"archive/tar"
+	"bytes"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/rwx-cloud/rwx/internal/api"
+	"github.com/rwx-cloud/rwx/internal/asciicast"
 	"github.com/rwx-cloud/rwx/internal/cli"
 	internalErrors "github.com/rwx-cloud/rwx/internal/errors"
+	internalssh "github.com/rwx-cloud/rwx/internal/ssh"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/ssh"
 )
@@ -54,12 +61,12 @@ AAAEC6442PQKevgYgeT0SIu9zwlnEMl6MF59ZgM+i0ByMv4eLJPqG3xnZcEQmktHj/GY2i
 			return nil
 		}
 
-		s.mockSSH.MockInteractiveSession = func() error {
+		s.mockSSH.MockInteractiveSession = func(_ internalssh.TerminalRecorder) error {
 			interactiveSSHSessionStarted = true
 			return nil
 		}
 
-		err := s.service.DebugTask(debugConfig)
+		err := s.service.DebugTask(t.Context(), debugConfig)
 		require.NoError(t, err)
 
 		require.True(t, fetchedConnectionInfo)
@@ -69,6 +76,66 @@ AAAEC6442PQKevgYgeT0SIu9zwlnEMl6MF59ZgM+i0ByMv4eLJPqG3xnZcEQmktHj/GY2i
 		require.True(t, interactiveSSHSessionStarted)
 	})
 
+	t.Run("records the session when a recording file is given", func(t *testing.T) {
+		s := setupTest(t)
+		recordingPath := filepath.Join(s.tmp, "debug.cast")
+
+		s.mockAPI.MockGetDebugConnectionInfo = func(runId string) (api.DebugConnectionInfo, error) {
+			return api.DebugConnectionInfo{
+				Debuggable:     true,
+				PrivateUserKey: privateTestKey,
+				PublicHostKey:  publicTestKey,
+				Address:        "agent.example.org:1234",
+			}, nil
+		}
+		s.mockSSH.MockConnect = func(addr string, _ ssh.ClientConfig) error {
+			return nil
+		}
+		s.mockSSH.MockInteractiveSession = func(recorder internalssh.TerminalRecorder) error {
+			require.NotNil(t, recorder)
+			_, _ = recorder.Write([]byte("$ ls\r\n"))
+			recorder.Resize(120, 40)
+			return nil
+		}
+
+		err := s.service.DebugTask(t.Context(), cli.DebugTaskConfig{DebugKey: "run-123", Record: recordingPath})
+		require.NoError(t, err)
+
+		fd, err := os.Open(recordingPath)
+		require.NoError(t, err)
+		defer fd.Close()
+
+		header, events, err := asciicast.Read(fd)
+		require.NoError(t, err)
+		require.Equal(t, "rwx debug run-123", header.Title)
+		require.Len(t, events, 2)
+		require.Equal(t, "$ ls\r\n", events[0].Data)
+		require.Equal(t, asciicast.EventResize, events[1].Type)
+	})
+
+	t.Run("doesn't record without a recording file", func(t *testing.T) {
+		s := setupTest(t)
+
+		s.mockAPI.MockGetDebugConnectionInfo = func(runId string) (api.DebugConnectionInfo, error) {
+			return api.DebugConnectionInfo{
+				Debuggable:     true,
+				PrivateUserKey: privateTestKey,
+				PublicHostKey:  publicTestKey,
+				Address:        "agent.example.org:1234",
+			}, nil
+		}
+		s.mockSSH.MockConnect = func(addr string, _ ssh.ClientConfig) error {
+			return nil
+		}
+		s.mockSSH.MockInteractiveSession = func(recorder internalssh.TerminalRecorder) error {
+			require.Nil(t, recorder)
+			return nil
+		}
+
+		err := s.service.DebugTask(t.Context(), cli.DebugTaskConfig{DebugKey: "run-123"})
+		require.NoError(t, err)
+	})
+
 	t.Run("when the task isn't debuggable yet", func(t *testing.T) {
 		s := setupTest(t)
 
@@ -82,8 +149,232 @@ AAAEC6442PQKevgYgeT0SIu9zwlnEMl6MF59ZgM+i0ByMv4eLJPqG3xnZcEQmktHj/GY2i
 			return api.DebugConnectionInfo{Debuggable: false}, nil
 		}
 
-		err := s.service.DebugTask(debugConfig)
+		err := s.service.DebugTask(t.Context(), debugConfig)
 
 		require.True(t, errors.Is(err, internalErrors.ErrRetry))
 	})
+	debuggableInfo := api.DebugConnectionInfo{
+		Debuggable:     true,
+		PrivateUserKey: privateTestKey,
+		PublicHostKey:  publicTestKey,
+		Address:        "agent.example.org:1234",
+	}
+
+	t.Run("resolves the task by key within a run", func(t *testing.T) {
+		s := setupTest(t)
+
+		s.mockAPI.MockTaskKeyStatus = func(cfg api.TaskKeyStatusConfig) (api.TaskStatusResult, error) {
+			require.Equal(t, "run-123", cfg.RunID)
+			require.Equal(t, "ci.checks.lint", cfg.TaskKey)
+			return api.TaskStatusResult{TaskID: "task-456"}, nil
+		}
+		s.mockAPI.MockGetDebugConnectionInfo = func(debugKey string) (api.DebugConnectionInfo, error) {
+			require.Equal(t, "task-456", debugKey)
+			return debuggableInfo, nil
+		}
+		s.mockSSH.MockConnect = func(addr string, _ ssh.ClientConfig) error {
+			return nil
+		}
+		sessionStarted := false
+		s.mockSSH.MockInteractiveSession = func(_ internalssh.TerminalRecorder) error {
+			sessionStarted = true
+			return nil
+		}
+
+		err := s.service.DebugTask(t.Context(), cli.DebugTaskConfig{RunID: "run-123", TaskKey: "ci.checks.lint"})
+		require.NoError(t, err)
+		require.True(t, sessionStarted)
+	})
+
+	t.Run("requires a run ID with a task key", func(t *testing.T) {
+		s := setupTest(t)
+
+		err := s.service.DebugTask(t.Context(), cli.DebugTaskConfig{TaskKey: "ci.checks.lint"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "run ID must be provided")
+	})
+
+	t.Run("reports a task key that doesn't exist", func(t *testing.T) {
+		s := setupTest(t)
+
+		s.mockAPI.MockTaskKeyStatus = func(cfg api.TaskKeyStatusConfig) (api.TaskStatusResult, error) {
+			return api.TaskStatusResult{}, errors.Wrap(api.ErrNotFound, "not found")
+		}
+
+		err := s.service.DebugTask(t.Context(), cli.DebugTaskConfig{RunID: "run-123", TaskKey: "missing", Wait: true})
+		require.ErrorIs(t, err, api.ErrNotFound)
+		require.Contains(t, err.Error(), "Task with key 'missing' not found")
+	})
+
+	t.Run("fails with ErrRetry for a queued task without --wait", func(t *testing.T) {
+		s := setupTest(t)
+
+		s.mockAPI.MockTaskKeyStatus = func(cfg api.TaskKeyStatusConfig) (api.TaskStatusResult, error) {
+			return api.TaskStatusResult{}, nil
+		}
+
+		err := s.service.DebugTask(t.Context(), cli.DebugTaskConfig{RunID: "run-123", TaskKey: "ci.checks.lint"})
+		require.ErrorIs(t, err, internalErrors.ErrRetry)
+		require.Contains(t, err.Error(), "hasn't started yet")
+	})
+
+	t.Run("waits for a queued task to hit a breakpoint", func(t *testing.T) {
+		s := setupTest(t)
+
+		statusCalls := 0
+		s.mockAPI.MockTaskKeyStatus = func(cfg api.TaskKeyStatusConfig) (api.TaskStatusResult, error) {
+			statusCalls++
+			if statusCalls == 1 {
+				return api.TaskStatusResult{}, nil
+			}
+			return api.TaskStatusResult{TaskID: "task-456"}, nil
+		}
+		infoCalls := 0
+		s.mockAPI.MockGetDebugConnectionInfo = func(debugKey string) (api.DebugConnectionInfo, error) {
+			infoCalls++
+			if infoCalls == 1 {
+				return api.DebugConnectionInfo{Debuggable: false}, nil
+			}
+			return debuggableInfo, nil
+		}
+		s.mockSSH.MockConnect = func(addr string, _ ssh.ClientConfig) error {
+			return nil
+		}
+		sessionStarted := false
+		s.mockSSH.MockInteractiveSession = func(_ internalssh.TerminalRecorder) error {
+			sessionStarted = true
+			return nil
+		}
+
+		err := s.service.DebugTask(t.Context(), cli.DebugTaskConfig{
+			RunID:        "run-123",
+			TaskKey:      "ci.checks.lint",
+			Wait:         true,
+			PollInterval: time.Millisecond,
+		})
+		require.NoError(t, err)
+		require.True(t, sessionStarted)
+		require.Equal(t, 3, statusCalls)
+		require.Equal(t, 2, infoCalls)
+	})
+
+	t.Run("stops waiting when the task finishes without a breakpoint", func(t *testing.T) {
+		s := setupTest(t)
+
+		s.mockAPI.MockTaskKeyStatus = func(cfg api.TaskKeyStatusConfig) (api.TaskStatusResult, error) {
+			return api.TaskStatusResult{TaskID: "task-456", Polling: api.PollingResult{Completed: true}}, nil
+		}
+
+		err := s.service.DebugTask(t.Context(), cli.DebugTaskConfig{RunID: "run-123", TaskKey: "ci.checks.lint", Wait: true})
+		require.ErrorIs(t, err, internalErrors.ErrGone)
+	})
+
+	t.Run("stops waiting when the run finishes without a breakpoint", func(t *testing.T) {
+		s := setupTest(t)
+
+		calls := 0
+		s.mockAPI.MockGetDebugConnectionInfo = func(debugKey string) (api.DebugConnectionInfo, error) {
+			calls++
+			if calls == 1 {
+				return api.DebugConnectionInfo{Debuggable: false}, nil
+			}
+			return api.DebugConnectionInfo{}, internalErrors.ErrGone
+		}
+
+		err := s.service.DebugTask(t.Context(), cli.DebugTaskConfig{DebugKey: "run-123", Wait: true, PollInterval: time.Millisecond})
+		require.ErrorIs(t, err, internalErrors.ErrGone)
+		require.Equal(t, 2, calls)
+	})
+	connectDebuggable := func(t *testing.T, s *testSetup) {
+		s.mockAPI.MockGetDebugConnectionInfo = func(debugKey string) (api.DebugConnectionInfo, error) {
+			return debuggableInfo, nil
+		}
+		s.mockSSH.MockConnect = func(addr string, _ ssh.ClientConfig) error {
+			return nil
+		}
+		s.mockSSH.MockInteractiveSession = func(_ internalssh.TerminalRecorder) error {
+			t.Fatal("should not start an interactive session")
+			return nil
+		}
+	}
+
+	t.Run("runs a command without a shell", func(t *testing.T) {
+		s := setupTest(t)
+		connectDebuggable(t, s)
+
+		var ranCommand string
+		s.mockSSH.MockExecuteCommandWithOutput = func(command string) (int, string, error) {
+			ranCommand = command
+			return 0, "hello world\n", nil
+		}
+
+		err := s.service.DebugTask(t.Context(), cli.DebugTaskConfig{DebugKey: "run-123", Command: []string{"echo", "hello world"}})
+		require.NoError(t, err)
+		require.Equal(t, "echo 'hello world'", ranCommand)
+		require.Equal(t, "hello world\n", s.mockStdout.String())
+	})
+
+	t.Run("returns the command's exit code", func(t *testing.T) {
+		s := setupTest(t)
+		connectDebuggable(t, s)
+
+		s.mockSSH.MockExecuteCommandWithOutput = func(command string) (int, string, error) {
+			return 3, "", nil
+		}
+
+		err := s.service.DebugTask(t.Context(), cli.DebugTaskConfig{DebugKey: "run-123", Command: []string{"false"}})
+		var exitErr *cli.ExitCodeError
+		require.ErrorAs(t, err, &exitErr)
+		require.Equal(t, 3, exitErr.Code)
+	})
+
+	t.Run("pulls a directory from the task", func(t *testing.T) {
+		s := setupTest(t)
+		connectDebuggable(t, s)
+
+		var archive bytes.Buffer
+		tw := tar.NewWriter(&archive)
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: "logs/", Typeflag: tar.TypeDir, Mode: 0o755}))
+		require.NoError(t, tw.WriteHeader(&tar.Header{Name: "logs/test.log", Typeflag: tar.TypeReg, Mode: 0o644, Size: 5}))
+		_, err := tw.Write([]byte("hello"))
+		require.NoError(t, err)
+		require.NoError(t, tw.Close())
+
+		var ranCommand string
+		s.mockSSH.MockExecuteCommandWithOutput = func(command string) (int, string, error) {
+			ranCommand = command
+			return 0, archive.String(), nil
+		}
+
+		outputDir := filepath.Join(s.tmp, "pulled")
+		err = s.service.DebugTask(t.Context(), cli.DebugTaskConfig{DebugKey: "run-123", Pull: "/tmp/work/logs/", OutputDir: outputDir})
+		require.NoError(t, err)
+		require.Equal(t, "tar -cf - -C /tmp/work/ -- logs", ranCommand)
+
+		contents, err := os.ReadFile(filepath.Join(outputDir, "logs", "test.log"))
+		require.NoError(t, err)
+		require.Equal(t, "hello", string(contents))
+		require.Contains(t, s.mockStdout.String(), "Pulled")
+	})
+
+	t.Run("reports a path that can't be pulled", func(t *testing.T) {
+		s := setupTest(t)
+		connectDebuggable(t, s)
+
+		s.mockSSH.MockExecuteCommandWithOutput = func(command string) (int, string, error) {
+			return 2, "", nil
+		}
+
+		err := s.service.DebugTask(t.Context(), cli.DebugTaskConfig{DebugKey: "run-123", Pull: "missing", OutputDir: s.tmp})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `unable to pull "missing"`)
+	})
+
+	t.Run("only records interactive sessions", func(t *testing.T) {
+		s := setupTest(t)
+
+		err := s.service.DebugTask(t.Context(), cli.DebugTaskConfig{DebugKey: "run-123", Command: []string{"ls"}, Record: filepath.Join(s.tmp, "x.cast")})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "only interactive sessions can be recorded")
+	})
 }
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"github.com/rwx-cloud/rwx/internal/api"
 	"github.com/rwx-cloud/rwx/internal/errors"
 )
@@ -30,13 +31,13 @@ type GetDispatchRun struct {
 	RunUrl string
 }
 
-func (s Service) InitiateDispatch(cfg InitiateDispatchConfig) (*api.InitiateDispatchResult, error) {
+func (s Service) InitiateDispatch(ctx context.Context, cfg InitiateDispatchConfig) (*api.InitiateDispatchResult, error) {
 	err := cfg.Validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "validation failed")
 	}
 
-	dispatchResult, err := s.APIClient.InitiateDispatch(api.InitiateDispatchConfig{
+	dispatchResult, err := s.APIClient.InitiateDispatch(ctx, api.InitiateDispatchConfig{
 		DispatchKey: cfg.DispatchKey,
 		Params:      cfg.Params,
 		Ref:         cfg.Ref,
@@ -49,8 +50,8 @@ func (s Service) InitiateDispatch(cfg InitiateDispatchConfig) (*api.InitiateDisp
 	return dispatchResult, nil
 }
 
-func (s Service) GetDispatch(cfg GetDispatchConfig) ([]GetDispatchRun, error) {
-	dispatchResult, err := s.APIClient.GetDispatch(api.GetDispatchConfig{
+func (s Service) GetDispatch(ctx context.Context, cfg GetDispatchConfig) ([]GetDispatchRun, error) {
+	dispatchResult, err := s.APIClient.GetDispatch(ctx, api.GetDispatchConfig{
 		DispatchId: cfg.DispatchId,
 	})
 	if err != nil {
@@ -43,7 +43,7 @@ func TestService_InitiatingDispatch(t *testing.T) {
 			}, nil
 		}
 
-		dispatchResult, err := s.service.InitiateDispatch(dispatchConfig)
+		dispatchResult, err := s.service.InitiateDispatch(t.Context(), dispatchConfig)
 		require.NoError(t, err)
 		require.Equal(t, originalParams, receivedParams)
 		require.Equal(t, "12345", dispatchResult.DispatchId)
@@ -56,7 +56,7 @@ func TestService_InitiatingDispatch(t *testing.T) {
 			DispatchKey: "",
 		}
 
-		_, err := s.service.InitiateDispatch(dispatchConfig)
+		_, err := s.service.InitiateDispatch(t.Context(), dispatchConfig)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "a dispatch key must be provided")
 	})
@@ -74,7 +74,7 @@ func TestService_GettingDispatch(t *testing.T) {
 			return &api.GetDispatchResult{Status: "not_ready"}, nil
 		}
 
-		_, err := s.service.GetDispatch(dispatchConfig)
+		_, err := s.service.GetDispatch(t.Context(), dispatchConfig)
 		require.Error(t, err)
 		require.True(t, errors.Is(err, internalErrors.ErrRetry))
 	})
@@ -90,7 +90,7 @@ func TestService_GettingDispatch(t *testing.T) {
 			return &api.GetDispatchResult{Status: "error", Error: "dispatch failed"}, nil
 		}
 
-		_, err := s.service.GetDispatch(dispatchConfig)
+		_, err := s.service.GetDispatch(t.Context(), dispatchConfig)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "dispatch failed")
 	})
@@ -111,7 +111,7 @@ func TestService_GettingDispatch(t *testing.T) {
 			}, nil
 		}
 
-		runs, err := s.service.GetDispatch(dispatchConfig)
+		runs, err := s.service.GetDispatch(t.Context(), dispatchConfig)
 		require.NoError(t, err)
 		require.Equal(t, "runid", runs[0].RunID)
 		require.Equal(t, "runurl", runs[0].RunUrl)
@@ -128,7 +128,7 @@ func TestService_GettingDispatch(t *testing.T) {
 			return &api.GetDispatchResult{Status: "ready", Runs: []api.GetDispatchRun{}}, nil
 		}
 
-		_, err := s.service.GetDispatch(dispatchConfig)
+		_, err := s.service.GetDispatch(t.Context(), dispatchConfig)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "No runs were created as a result of this dispatch")
 	})
@@ -2,6 +2,7 @@ package cli
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -32,14 +33,14 @@ type DocsPullResult struct {
 	Body string `json:"Body"`
 }
 
-func (s Service) docsClientWithToken() docs.Client {
+func (s Service) docsClientWithToken(ctx context.Context) docs.Client {
 	client := s.DocsClient
-	client.DocsToken = s.resolveDocsToken()
+	client.DocsToken = s.resolveDocsToken(ctx)
 	return client
 }
 
-func (s Service) DocsSearch(cfg DocsSearchConfig) (*DocsSearchResult, error) {
-	docsClient := s.docsClientWithToken()
+func (s Service) DocsSearch(ctx context.Context, cfg DocsSearchConfig) (*DocsSearchResult, error) {
+	docsClient := s.docsClientWithToken(ctx)
 	resp, err := docsClient.Search(cfg.Query, cfg.Limit)
 	if err != nil {
 		return nil, err
@@ -143,8 +144,8 @@ func (s Service) DocsSearch(cfg DocsSearchConfig) (*DocsSearchResult, error) {
 	}, nil
 }
 
-func (s Service) DocsPull(cfg DocsPullConfig) (*DocsPullResult, error) {
-	docsClient := s.docsClientWithToken()
+func (s Service) DocsPull(ctx context.Context, cfg DocsPullConfig) (*DocsPullResult, error) {
+	docsClient := s.docsClientWithToken(ctx)
 	body, err := docsClient.FetchArticle(cfg.URL)
 	if err != nil {
 		return nil, err
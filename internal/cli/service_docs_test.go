@@ -45,7 +45,7 @@ func TestService_DocsSearch(t *testing.T) {
 			})
 		}))
 
-		result, err := s.service.DocsSearch(cli.DocsSearchConfig{
+		result, err := s.service.DocsSearch(t.Context(), cli.DocsSearchConfig{
 			Query: "nothing",
 			Limit: 5,
 		})
@@ -72,7 +72,7 @@ func TestService_DocsSearch(t *testing.T) {
 			})
 		}))
 
-		result, err := s.service.DocsSearch(cli.DocsSearchConfig{
+		result, err := s.service.DocsSearch(t.Context(), cli.DocsSearchConfig{
 			Query: "flaky tests",
 			Limit: 5,
 		})
@@ -108,7 +108,7 @@ func TestService_DocsSearch(t *testing.T) {
 			}
 		}))
 
-		result, err := s.service.DocsSearch(cli.DocsSearchConfig{
+		result, err := s.service.DocsSearch(t.Context(), cli.DocsSearchConfig{
 			Query: "flaky tests",
 			Limit: 5,
 		})
@@ -134,7 +134,7 @@ func TestService_DocsSearch(t *testing.T) {
 			})
 		}))
 
-		result, err := s.service.DocsSearch(cli.DocsSearchConfig{
+		result, err := s.service.DocsSearch(t.Context(), cli.DocsSearchConfig{
 			Query: "flaky tests",
 			Limit: 5,
 			Json:  true,
@@ -169,7 +169,7 @@ func TestService_DocsSearch(t *testing.T) {
 			})
 		}))
 
-		result, err := s.service.DocsSearch(cli.DocsSearchConfig{
+		result, err := s.service.DocsSearch(t.Context(), cli.DocsSearchConfig{
 			Query:       "caching",
 			Limit:       5,
 			StdoutIsTTY: false,
@@ -210,7 +210,7 @@ func TestService_DocsSearch(t *testing.T) {
 			})
 		}))
 
-		result, err := s.service.DocsSearch(cli.DocsSearchConfig{
+		result, err := s.service.DocsSearch(t.Context(), cli.DocsSearchConfig{
 			Query:       "caching",
 			Limit:       5,
 			Json:        true,
@@ -246,7 +246,7 @@ func TestService_DocsSearch(t *testing.T) {
 			})
 		}))
 
-		result, err := s.service.DocsSearch(cli.DocsSearchConfig{
+		result, err := s.service.DocsSearch(t.Context(), cli.DocsSearchConfig{
 			Query:       "caching",
 			Limit:       5,
 			Json:        true,
@@ -291,7 +291,7 @@ func TestService_DocsSearch(t *testing.T) {
 
 		s.mockStdin.WriteString("2\n")
 
-		result, err := s.service.DocsSearch(cli.DocsSearchConfig{
+		result, err := s.service.DocsSearch(t.Context(), cli.DocsSearchConfig{
 			Query:       "caching",
 			Limit:       5,
 			StdoutIsTTY: true,
@@ -313,7 +313,7 @@ func TestService_DocsSearch(t *testing.T) {
 			fmt.Fprintln(w, "internal server error")
 		}))
 
-		result, err := s.service.DocsSearch(cli.DocsSearchConfig{
+		result, err := s.service.DocsSearch(t.Context(), cli.DocsSearchConfig{
 			Query: "test",
 			Limit: 5,
 		})
@@ -334,7 +334,7 @@ func TestService_DocsSearch(t *testing.T) {
 			})
 		}))
 
-		_, err := s.service.DocsSearch(cli.DocsSearchConfig{
+		_, err := s.service.DocsSearch(t.Context(), cli.DocsSearchConfig{
 			Query: "test",
 			Limit: 3,
 		})
@@ -352,7 +352,7 @@ func TestService_DocsPull(t *testing.T) {
 			fmt.Fprint(w, "# Getting Started\n\nWelcome to Captain.")
 		}))
 
-		result, err := s.service.DocsPull(cli.DocsPullConfig{
+		result, err := s.service.DocsPull(t.Context(), cli.DocsPullConfig{
 			URL: "/captain/getting-started",
 		})
 
@@ -367,7 +367,7 @@ func TestService_DocsPull(t *testing.T) {
 			fmt.Fprint(w, "# Getting Started\n\nWelcome to Captain.")
 		}))
 
-		result, err := s.service.DocsPull(cli.DocsPullConfig{
+		result, err := s.service.DocsPull(t.Context(), cli.DocsPullConfig{
 			URL:  "/captain/getting-started",
 			Json: true,
 		})
@@ -390,7 +390,7 @@ func TestService_DocsPull(t *testing.T) {
 		addr := s.config.DocsClient.Host
 		fullURL := fmt.Sprintf("http://%s/docs/rwx/guides/ci", addr)
 
-		result, err := s.service.DocsPull(cli.DocsPullConfig{
+		result, err := s.service.DocsPull(t.Context(), cli.DocsPullConfig{
 			URL: fullURL,
 		})
 
@@ -406,7 +406,7 @@ func TestService_DocsPull(t *testing.T) {
 			fmt.Fprintln(w, "not found")
 		}))
 
-		result, err := s.service.DocsPull(cli.DocsPullConfig{
+		result, err := s.service.DocsPull(t.Context(), cli.DocsPullConfig{
 			URL: "/nonexistent",
 		})
 
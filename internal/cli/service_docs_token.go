@@ -1,8 +1,11 @@
 package cli
 
-import "github.com/rwx-cloud/rwx/internal/docstoken"
+import (
+	"context"
+	"github.com/rwx-cloud/rwx/internal/docstoken"
+)
 
-func (s Service) resolveDocsToken() string {
+func (s Service) resolveDocsToken(ctx context.Context) string {
 	if s.DocsTokenBackend == nil || s.AccessTokenBackend == nil {
 		return ""
 	}
@@ -17,7 +20,7 @@ func (s Service) resolveDocsToken() string {
 		return cached.Token
 	}
 
-	result, err := s.APIClient.CreateDocsToken()
+	result, err := s.APIClient.CreateDocsToken(ctx)
 	if err != nil {
 		return ""
 	}
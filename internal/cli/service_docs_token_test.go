@@ -40,7 +40,7 @@ func TestResolveDocsToken(t *testing.T) {
 		s.service, err = cli.NewService(s.config)
 		require.NoError(t, err)
 
-		_, err = s.service.DocsPull(cli.DocsPullConfig{URL: "/docs/test", Json: true})
+		_, err = s.service.DocsPull(t.Context(), cli.DocsPullConfig{URL: "/docs/test", Json: true})
 		require.NoError(t, err)
 	})
 
@@ -63,7 +63,7 @@ func TestResolveDocsToken(t *testing.T) {
 		s.service, err = cli.NewService(s.config)
 		require.NoError(t, err)
 
-		_, err = s.service.DocsPull(cli.DocsPullConfig{URL: "/docs/test", Json: true})
+		_, err = s.service.DocsPull(t.Context(), cli.DocsPullConfig{URL: "/docs/test", Json: true})
 		require.NoError(t, err)
 	})
 
@@ -91,7 +91,7 @@ func TestResolveDocsToken(t *testing.T) {
 		s.service, err = cli.NewService(s.config)
 		require.NoError(t, err)
 
-		_, err = s.service.DocsPull(cli.DocsPullConfig{URL: "/docs/test", Json: true})
+		_, err = s.service.DocsPull(t.Context(), cli.DocsPullConfig{URL: "/docs/test", Json: true})
 		require.NoError(t, err)
 	})
 
@@ -123,7 +123,7 @@ func TestResolveDocsToken(t *testing.T) {
 		s.service, err = cli.NewService(s.config)
 		require.NoError(t, err)
 
-		_, err = s.service.DocsPull(cli.DocsPullConfig{URL: "/docs/test", Json: true})
+		_, err = s.service.DocsPull(t.Context(), cli.DocsPullConfig{URL: "/docs/test", Json: true})
 		require.NoError(t, err)
 
 		// Verify it was cached with the new auth token
@@ -155,7 +155,7 @@ func TestResolveDocsToken(t *testing.T) {
 		s.service, err = cli.NewService(s.config)
 		require.NoError(t, err)
 
-		_, err = s.service.DocsPull(cli.DocsPullConfig{URL: "/docs/test", Json: true})
+		_, err = s.service.DocsPull(t.Context(), cli.DocsPullConfig{URL: "/docs/test", Json: true})
 		require.NoError(t, err)
 	})
 
@@ -182,7 +182,7 @@ func TestResolveDocsToken(t *testing.T) {
 		s.service, err = cli.NewService(s.config)
 		require.NoError(t, err)
 
-		_, err = s.service.DocsPull(cli.DocsPullConfig{URL: "/docs/test", Json: true})
+		_, err = s.service.DocsPull(t.Context(), cli.DocsPullConfig{URL: "/docs/test", Json: true})
 		require.NoError(t, err)
 
 		// Verify it was cached
@@ -2,7 +2,6 @@ package cli
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
@@ -44,7 +43,7 @@ type ImageBuildResult struct {
 	Push     *ImagePushResult `json:",omitempty"`
 }
 
-func (s Service) ImageBuild(config ImageBuildConfig) (buildResult *ImageBuildResult, buildErr error) {
+func (s Service) ImageBuild(ctx context.Context, config ImageBuildConfig) (buildResult *ImageBuildResult, buildErr error) {
 	start := time.Now()
 	defer func() {
 		s.recordTelemetry("image.build", map[string]any{
@@ -57,7 +56,7 @@ func (s Service) ImageBuild(config ImageBuildConfig) (buildResult *ImageBuildRes
 		return nil, err
 	}
 
-	runResult, err := s.InitiateRun(InitiateRunConfig{
+	runResult, err := s.InitiateRun(ctx, InitiateRunConfig{
 		InitParameters: config.InitParameters,
 		RwxDirectory:   config.RwxDirectory,
 		MintFilePath:   config.MintFilePath,
@@ -91,25 +90,43 @@ func (s Service) ImageBuild(config ImageBuildConfig) (buildResult *ImageBuildRes
 		)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+	// --timeout covers waiting for the build and pulling and tagging the image;
+	// pushing it is bounded by ImagePush itself
+	buildCtx, cancel := context.WithTimeout(ctx, config.Timeout)
 	defer cancel()
 
+	// doneErr reports why polling stopped once buildCtx is done: the command was
+	// interrupted, or the build didn't complete in time
+	doneErr := func() error {
+		if errors.Is(buildCtx.Err(), context.Canceled) {
+			return buildCtx.Err()
+		}
+		return errors.WrapSentinel(
+			fmt.Errorf("timeout waiting for build to complete after %s\n\nThe build may still be running. Check the status at: %s", config.Timeout, runResult.RunURL),
+			errors.ErrTimeout,
+		)
+	}
+
 	var taskID string
 	succeeded := false
 	for !succeeded {
 		select {
-		case <-ctx.Done():
+		case <-buildCtx.Done():
 			stopSpinner()
-			return nil, fmt.Errorf("timeout waiting for build to complete after %s\n\nThe build may still be running. Check the status at: %s", config.Timeout, runResult.RunURL)
+			return nil, doneErr()
 		default:
 		}
 
-		statusResult, err := s.APIClient.TaskKeyStatus(api.TaskKeyStatusConfig{
+		statusResult, err := s.APIClient.TaskKeyStatus(buildCtx, api.TaskKeyStatusConfig{
 			RunID:   runResult.RunID,
 			TaskKey: config.TargetTaskKey,
 		})
 		if err != nil {
 			stopSpinner()
+			// The request fails when buildCtx is done while it's in flight
+			if buildCtx.Err() != nil {
+				return nil, doneErr()
+			}
 			return nil, fmt.Errorf("failed to get build status: %w", err)
 		}
 
@@ -130,13 +147,14 @@ func (s Service) ImageBuild(config ImageBuildConfig) (buildResult *ImageBuildRes
 				stopSpinner()
 				return nil, fmt.Errorf("build failed")
 			}
-			time.Sleep(time.Duration(*statusResult.Polling.BackoffMs) * time.Millisecond)
+			// A cancelled or timed out sleep is reported at the top of the loop
+			_ = sleep(buildCtx, time.Duration(*statusResult.Polling.BackoffMs)*time.Millisecond)
 		}
 	}
 
 	result.TaskID = taskID
 
-	whoamiResult, err := s.APIClient.Whoami()
+	whoamiResult, err := s.APIClient.Whoami(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get organization info: %w\nTry running `rwx login` again", err)
 	}
@@ -149,7 +167,7 @@ func (s Service) ImageBuild(config ImageBuildConfig) (buildResult *ImageBuildRes
 		if !config.OutputJSON {
 			fmt.Fprintf(s.Stdout, "Image available at: %s\n", imageRef)
 		} else {
-			if err := json.NewEncoder(s.Stdout).Encode(result); err != nil {
+			if err := s.Output.Write(s.Stdout, result); err != nil {
 				return nil, fmt.Errorf("unable to encode output: %w", err)
 			}
 		}
@@ -166,7 +184,7 @@ func (s Service) ImageBuild(config ImageBuildConfig) (buildResult *ImageBuildRes
 		ServerAddress: registry,
 	}
 
-	if err := s.DockerCLI.Pull(ctx, imageRef, authConfig, config.OutputJSON); err != nil {
+	if err := s.DockerCLI.Pull(buildCtx, imageRef, authConfig, config.OutputJSON); err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			return nil, fmt.Errorf("timeout while pulling image after %s\n\nThe image may still be available at: %s", config.Timeout, imageRef)
 		}
@@ -182,7 +200,7 @@ func (s Service) ImageBuild(config ImageBuildConfig) (buildResult *ImageBuildRes
 			fmt.Fprintf(s.Stdout, "Tagging image as: %s\n", tag)
 		}
 
-		if err := s.DockerCLI.Tag(ctx, imageRef, tag); err != nil {
+		if err := s.DockerCLI.Tag(buildCtx, imageRef, tag); err != nil {
 			if errors.Is(err, context.DeadlineExceeded) {
 				return nil, fmt.Errorf("timeout while tagging image after %s", config.Timeout)
 			}
@@ -213,7 +231,7 @@ func (s Service) ImageBuild(config ImageBuildConfig) (buildResult *ImageBuildRes
 			return nil, err
 		}
 
-		pushResult, err := s.ImagePush(pushConfig)
+		pushResult, err := s.ImagePush(ctx, pushConfig)
 		if err != nil {
 			return nil, err
 		}
@@ -221,7 +239,7 @@ func (s Service) ImageBuild(config ImageBuildConfig) (buildResult *ImageBuildRes
 	}
 
 	if config.OutputJSON {
-		if err := json.NewEncoder(s.Stdout).Encode(result); err != nil {
+		if err := s.Output.Write(s.Stdout, result); err != nil {
 			return nil, fmt.Errorf("unable to encode output: %w", err)
 		}
 	}
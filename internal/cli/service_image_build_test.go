@@ -10,6 +10,7 @@ import (
 	"github.com/docker/cli/cli/config/types"
 	"github.com/rwx-cloud/rwx/internal/api"
 	"github.com/rwx-cloud/rwx/internal/cli"
+	"github.com/rwx-cloud/rwx/internal/errors"
 	"github.com/stretchr/testify/require"
 )
 
@@ -101,7 +102,7 @@ func TestService_ImageBuild(t *testing.T) {
 			OpenURL:        func(string) error { return nil },
 		}
 
-		_, err := s.service.ImageBuild(cfg)
+		_, err := s.service.ImageBuild(t.Context(), cfg)
 
 		require.NoError(t, err)
 		require.Contains(t, s.mockStdout.String(), "Building image for build-task")
@@ -162,7 +163,7 @@ func TestService_ImageBuild(t *testing.T) {
 			OpenURL:        func(string) error { return nil },
 		}
 
-		_, err := s.service.ImageBuild(cfg)
+		_, err := s.service.ImageBuild(t.Context(), cfg)
 
 		require.NoError(t, err)
 		require.ElementsMatch(t, []string{
@@ -189,7 +190,7 @@ func TestService_ImageBuild(t *testing.T) {
 			OpenURL:        func(string) error { return nil },
 		}
 
-		_, err := s.service.ImageBuild(cfg)
+		_, err := s.service.ImageBuild(t.Context(), cfg)
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "failed to initiate run")
@@ -218,13 +219,77 @@ func TestService_ImageBuild(t *testing.T) {
 			OpenURL:        func(string) error { return nil },
 		}
 
-		_, err := s.service.ImageBuild(cfg)
+		_, err := s.service.ImageBuild(t.Context(), cfg)
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "failed to get build status")
 		require.Contains(t, err.Error(), "failed to get task status")
 	})
 
+	t.Run("times out when the deadline passes while getting the build status", func(t *testing.T) {
+		s := setupTest(t)
+		setupImageBuildTest(t, s)
+
+		s.mockAPI.MockInitiateRun = func(cfg api.InitiateRunConfig) (*api.InitiateRunResult, error) {
+			return &api.InitiateRunResult{
+				RunID:  "run-123",
+				RunURL: "https://cloud.rwx.com/runs/run-123",
+			}, nil
+		}
+
+		s.mockAPI.MockTaskKeyStatus = func(cfg api.TaskKeyStatusConfig) (api.TaskStatusResult, error) {
+			time.Sleep(50 * time.Millisecond)
+			return api.TaskStatusResult{}, context.DeadlineExceeded
+		}
+
+		cfg := cli.ImageBuildConfig{
+			InitParameters: map[string]string{},
+			MintFilePath:   "test.yml",
+			TargetTaskKey:  "build-task",
+			Timeout:        10 * time.Millisecond,
+			OpenURL:        func(string) error { return nil },
+		}
+
+		_, err := s.service.ImageBuild(t.Context(), cfg)
+
+		require.ErrorIs(t, err, errors.ErrTimeout)
+		require.Contains(t, err.Error(), "timeout waiting for build to complete")
+		require.Contains(t, err.Error(), "https://cloud.rwx.com/runs/run-123")
+	})
+
+	t.Run("stops when cancelled while getting the build status", func(t *testing.T) {
+		s := setupTest(t)
+		setupImageBuildTest(t, s)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		s.mockAPI.MockInitiateRun = func(cfg api.InitiateRunConfig) (*api.InitiateRunResult, error) {
+			return &api.InitiateRunResult{
+				RunID:  "run-123",
+				RunURL: "https://cloud.rwx.com/runs/run-123",
+			}, nil
+		}
+
+		s.mockAPI.MockTaskKeyStatus = func(cfg api.TaskKeyStatusConfig) (api.TaskStatusResult, error) {
+			cancel()
+			return api.TaskStatusResult{}, context.Canceled
+		}
+
+		cfg := cli.ImageBuildConfig{
+			InitParameters: map[string]string{},
+			MintFilePath:   "test.yml",
+			TargetTaskKey:  "build-task",
+			Timeout:        1 * time.Second,
+			OpenURL:        func(string) error { return nil },
+		}
+
+		_, err := s.service.ImageBuild(ctx, cfg)
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.NotErrorIs(t, err, errors.ErrTimeout)
+	})
+
 	t.Run("fails when build fails", func(t *testing.T) {
 		s := setupTest(t)
 		setupImageBuildTest(t, s)
@@ -253,7 +318,7 @@ func TestService_ImageBuild(t *testing.T) {
 			OpenURL:        func(string) error { return nil },
 		}
 
-		_, err := s.service.ImageBuild(cfg)
+		_, err := s.service.ImageBuild(t.Context(), cfg)
 
 		require.Error(t, err)
 		require.Equal(t, "build failed", err.Error())
@@ -287,7 +352,7 @@ func TestService_ImageBuild(t *testing.T) {
 			OpenURL:        func(string) error { return nil },
 		}
 
-		_, err := s.service.ImageBuild(cfg)
+		_, err := s.service.ImageBuild(t.Context(), cfg)
 
 		require.Error(t, err)
 		require.Equal(t, "build failed", err.Error())
@@ -326,7 +391,7 @@ func TestService_ImageBuild(t *testing.T) {
 			OpenURL:        func(string) error { return nil },
 		}
 
-		_, err := s.service.ImageBuild(cfg)
+		_, err := s.service.ImageBuild(t.Context(), cfg)
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "failed to get organization info: unauthorized")
@@ -374,7 +439,7 @@ func TestService_ImageBuild(t *testing.T) {
 			OpenURL:        func(string) error { return nil },
 		}
 
-		_, err := s.service.ImageBuild(cfg)
+		_, err := s.service.ImageBuild(t.Context(), cfg)
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "failed to pull image")
@@ -427,7 +492,7 @@ func TestService_ImageBuild(t *testing.T) {
 			OpenURL:        func(string) error { return nil },
 		}
 
-		_, err := s.service.ImageBuild(cfg)
+		_, err := s.service.ImageBuild(t.Context(), cfg)
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "failed to tag image as latest")
@@ -461,7 +526,7 @@ func TestService_ImageBuild(t *testing.T) {
 			OpenURL:        func(string) error { return nil },
 		}
 
-		_, err := s.service.ImageBuild(cfg)
+		_, err := s.service.ImageBuild(t.Context(), cfg)
 
 		require.Error(t, err)
 		require.Equal(t, "build failed", err.Error())
@@ -495,7 +560,7 @@ func TestService_ImageBuild(t *testing.T) {
 			OpenURL:        func(string) error { return nil },
 		}
 
-		_, err := s.service.ImageBuild(cfg)
+		_, err := s.service.ImageBuild(t.Context(), cfg)
 
 		require.Error(t, err)
 		require.Equal(t, "build failed", err.Error())
@@ -530,7 +595,7 @@ func TestService_ImageBuild(t *testing.T) {
 			OpenURL:        func(string) error { return nil },
 		}
 
-		_, err := s.service.ImageBuild(cfg)
+		_, err := s.service.ImageBuild(t.Context(), cfg)
 
 		require.Error(t, err)
 		require.Equal(t, "build failed", err.Error())
@@ -583,7 +648,7 @@ func TestService_ImageBuild(t *testing.T) {
 			OpenURL:        func(string) error { return nil },
 		}
 
-		_, err := s.service.ImageBuild(cfg)
+		_, err := s.service.ImageBuild(t.Context(), cfg)
 
 		require.NoError(t, err)
 		require.False(t, pullCalled, "Pull should not be called when NoPull is true")
@@ -665,7 +730,7 @@ func TestService_ImageBuild(t *testing.T) {
 			OpenURL:          func(string) error { return nil },
 		}
 
-		_, err := s.service.ImageBuild(cfg)
+		_, err := s.service.ImageBuild(t.Context(), cfg)
 
 		require.NoError(t, err)
 		require.Contains(t, s.mockStdout.String(), "Build succeeded!")
@@ -720,7 +785,7 @@ func TestService_ImageBuild(t *testing.T) {
 			OpenURL:          func(string) error { return nil },
 		}
 
-		_, err := s.service.ImageBuild(cfg)
+		_, err := s.service.ImageBuild(t.Context(), cfg)
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "no credentials available")
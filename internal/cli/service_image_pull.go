@@ -2,7 +2,6 @@ package cli
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"time"
 
@@ -29,7 +28,7 @@ type ImagePullResult struct {
 	Tags     []string `json:",omitempty"`
 }
 
-func (s Service) ImagePull(config ImagePullConfig) (pullResult *ImagePullResult, pullErr error) {
+func (s Service) ImagePull(ctx context.Context, config ImagePullConfig) (pullResult *ImagePullResult, pullErr error) {
 	start := time.Now()
 	defer func() {
 		s.recordTelemetry("image.pull", map[string]any{
@@ -42,7 +41,7 @@ func (s Service) ImagePull(config ImagePullConfig) (pullResult *ImagePullResult,
 		return nil, err
 	}
 
-	whoamiResult, err := s.APIClient.Whoami()
+	whoamiResult, err := s.APIClient.Whoami(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get organization info: %w\nTry running `rwx login` again", err)
 	}
@@ -54,7 +53,7 @@ func (s Service) ImagePull(config ImagePullConfig) (pullResult *ImagePullResult,
 		fmt.Fprintf(s.Stdout, "Pulling image: %s\n", imageRef)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.Timeout)
+	ctx, cancel := context.WithTimeout(ctx, config.Timeout)
 	defer cancel()
 
 	authConfig := cliTypes.AuthConfig{
@@ -93,7 +92,7 @@ func (s Service) ImagePull(config ImagePullConfig) (pullResult *ImagePullResult,
 	}
 
 	if config.OutputJSON {
-		if err := json.NewEncoder(s.Stdout).Encode(result); err != nil {
+		if err := s.Output.Write(s.Stdout, result); err != nil {
 			return nil, fmt.Errorf("unable to encode output: %w", err)
 		}
 	}
@@ -38,7 +38,7 @@ func TestService_ImagePull(t *testing.T) {
 			Timeout: 1 * time.Second,
 		}
 
-		result, err := s.service.ImagePull(cfg)
+		result, err := s.service.ImagePull(t.Context(), cfg)
 
 		require.NoError(t, err)
 		require.Equal(t, "cloud.rwx.com/my-org:task-456", result.ImageRef)
@@ -76,7 +76,7 @@ func TestService_ImagePull(t *testing.T) {
 			Timeout: 1 * time.Second,
 		}
 
-		result, err := s.service.ImagePull(cfg)
+		result, err := s.service.ImagePull(t.Context(), cfg)
 
 		require.NoError(t, err)
 		require.Equal(t, "cloud.rwx.com/my-org:task-456", result.ImageRef)
@@ -97,7 +97,7 @@ func TestService_ImagePull(t *testing.T) {
 			Timeout: 1 * time.Second,
 		}
 
-		result, err := s.service.ImagePull(cfg)
+		result, err := s.service.ImagePull(t.Context(), cfg)
 
 		require.Nil(t, result)
 		require.Error(t, err)
@@ -116,7 +116,7 @@ func TestService_ImagePull(t *testing.T) {
 			Timeout: 1 * time.Second,
 		}
 
-		result, err := s.service.ImagePull(cfg)
+		result, err := s.service.ImagePull(t.Context(), cfg)
 
 		require.Nil(t, result)
 		require.Error(t, err)
@@ -144,7 +144,7 @@ func TestService_ImagePull(t *testing.T) {
 			Timeout: 1 * time.Second,
 		}
 
-		result, err := s.service.ImagePull(cfg)
+		result, err := s.service.ImagePull(t.Context(), cfg)
 
 		require.Nil(t, result)
 		require.Error(t, err)
@@ -172,7 +172,7 @@ func TestService_ImagePull(t *testing.T) {
 			Timeout: 1 * time.Second,
 		}
 
-		result, err := s.service.ImagePull(cfg)
+		result, err := s.service.ImagePull(t.Context(), cfg)
 
 		require.Nil(t, result)
 		require.Error(t, err)
@@ -205,7 +205,7 @@ func TestService_ImagePull(t *testing.T) {
 			Timeout: 1 * time.Second,
 		}
 
-		result, err := s.service.ImagePull(cfg)
+		result, err := s.service.ImagePull(t.Context(), cfg)
 
 		require.Nil(t, result)
 		require.Error(t, err)
@@ -237,7 +237,7 @@ func TestService_ImagePull(t *testing.T) {
 			Timeout: 1 * time.Second,
 		}
 
-		result, err := s.service.ImagePull(cfg)
+		result, err := s.service.ImagePull(t.Context(), cfg)
 
 		require.Nil(t, result)
 		require.Error(t, err)
@@ -274,7 +274,7 @@ func TestService_ImagePull(t *testing.T) {
 			OutputJSON: true,
 		}
 
-		result, err := s.service.ImagePull(cfg)
+		result, err := s.service.ImagePull(t.Context(), cfg)
 
 		require.NoError(t, err)
 		require.Equal(t, "cloud.rwx.com/my-org:task-456", result.ImageRef)
@@ -314,7 +314,7 @@ func TestService_ImagePull(t *testing.T) {
 			OutputJSON: true,
 		}
 
-		result, err := s.service.ImagePull(cfg)
+		result, err := s.service.ImagePull(t.Context(), cfg)
 
 		require.NoError(t, err)
 		require.Equal(t, "cloud.rwx.com/my-org:task-456", result.ImageRef)
@@ -2,7 +2,6 @@ package cli
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"time"
@@ -64,7 +63,7 @@ func NewImagePushConfig(taskID string, references []string, compression string,
 	}, nil
 }
 
-func (s Service) ImagePush(config ImagePushConfig) (pushResult *ImagePushResult, pushErr error) {
+func (s Service) ImagePush(ctx context.Context, config ImagePushConfig) (pushResult *ImagePushResult, pushErr error) {
 	start := time.Now()
 	defer func() {
 		s.recordTelemetry("image.push", map[string]any{
@@ -148,7 +147,7 @@ func (s Service) ImagePush(config ImagePushConfig) (pushResult *ImagePushResult,
 		)
 	}
 
-	taskStatusTimeout, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	taskStatusTimeout, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
 
 	succeeded := false
@@ -160,7 +159,7 @@ func (s Service) ImagePush(config ImagePushConfig) (pushResult *ImagePushResult,
 		default:
 		}
 
-		result, err := s.APIClient.TaskIDStatus(api.TaskIDStatusConfig{TaskID: config.TaskID})
+		result, err := s.APIClient.TaskIDStatus(ctx, api.TaskIDStatusConfig{TaskID: config.TaskID})
 		if err != nil {
 			stopStartSpinner()
 			return nil, fmt.Errorf("failed to get task status: %w", err)
@@ -174,11 +173,14 @@ func (s Service) ImagePush(config ImagePushConfig) (pushResult *ImagePushResult,
 				return nil, fmt.Errorf("task failed")
 			}
 		} else {
-			time.Sleep(time.Duration(*result.Polling.BackoffMs) * time.Millisecond)
+			if err := sleep(ctx, time.Duration(*result.Polling.BackoffMs)*time.Millisecond); err != nil {
+				stopStartSpinner()
+				return nil, err
+			}
 		}
 	}
 
-	result, err := s.APIClient.StartImagePush(request)
+	result, err := s.APIClient.StartImagePush(ctx, request)
 	stopStartSpinner()
 	if err != nil {
 		return nil, err
@@ -197,7 +199,7 @@ func (s Service) ImagePush(config ImagePushConfig) (pushResult *ImagePushResult,
 	if !config.Wait {
 		output := &ImagePushResult{PushID: result.PushID, RunURL: result.RunURL}
 		if config.JSON {
-			if err := json.NewEncoder(s.Stdout).Encode(output); err != nil {
+			if err := s.Output.Write(s.Stdout, output); err != nil {
 				return nil, fmt.Errorf("unable to encode output: %w", err)
 			}
 		} else {
@@ -225,8 +227,15 @@ func (s Service) ImagePush(config ImagePushConfig) (pushResult *ImagePushResult,
 	defer ticker.Stop()
 	var finalPushResult api.ImagePushStatusResult
 statusloop:
-	for range ticker.C {
-		pushStatus, err := s.APIClient.ImagePushStatus(result.PushID)
+	for {
+		select {
+		case <-ctx.Done():
+			stopWaitingSpinner()
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		pushStatus, err := s.APIClient.ImagePushStatus(ctx, result.PushID)
 		if err != nil {
 			stopWaitingSpinner()
 			return nil, fmt.Errorf("unable to get image push status: %w", err)
@@ -250,7 +259,7 @@ statusloop:
 	switch finalPushResult.Status {
 	case "succeeded":
 		if config.JSON {
-			if err := json.NewEncoder(s.Stdout).Encode(output); err != nil {
+			if err := s.Output.Write(s.Stdout, output); err != nil {
 				return nil, fmt.Errorf("unable to encode output: %w", err)
 			}
 		} else {
@@ -259,7 +268,7 @@ statusloop:
 		return output, nil
 	case "failed":
 		if config.JSON {
-			if err := json.NewEncoder(s.Stdout).Encode(output); err != nil {
+			if err := s.Output.Write(s.Stdout, output); err != nil {
 				return nil, fmt.Errorf("unable to encode output: %w", err)
 			}
 		}
@@ -34,7 +34,7 @@ func TestService_ImagePush(t *testing.T) {
 			OpenURL:     func(url string) error { return nil },
 		}
 
-		result, err := s.service.ImagePush(cfg)
+		result, err := s.service.ImagePush(t.Context(), cfg)
 
 		require.Nil(t, result)
 		require.Error(t, err)
@@ -57,7 +57,7 @@ func TestService_ImagePush(t *testing.T) {
 			OpenURL:     func(url string) error { return nil },
 		}
 
-		result, err := s.service.ImagePush(cfg)
+		result, err := s.service.ImagePush(t.Context(), cfg)
 
 		require.Nil(t, result)
 		require.Error(t, err)
@@ -82,7 +82,7 @@ func TestService_ImagePush(t *testing.T) {
 			OpenURL:     func(url string) error { return nil },
 		}
 
-		result, err := s.service.ImagePush(cfg)
+		result, err := s.service.ImagePush(t.Context(), cfg)
 
 		require.Nil(t, result)
 		require.Error(t, err)
@@ -113,7 +113,7 @@ func TestService_ImagePush(t *testing.T) {
 			OpenURL:     func(url string) error { return nil },
 		}
 
-		result, err := s.service.ImagePush(cfg)
+		result, err := s.service.ImagePush(t.Context(), cfg)
 
 		require.Nil(t, result)
 		require.Error(t, err)
@@ -160,7 +160,7 @@ func TestService_ImagePush(t *testing.T) {
 			},
 		}
 
-		result, err := s.service.ImagePush(cfg)
+		result, err := s.service.ImagePush(t.Context(), cfg)
 
 		require.Nil(t, result)
 		require.Error(t, err)
@@ -205,7 +205,7 @@ func TestService_ImagePush(t *testing.T) {
 			},
 		}
 
-		result, err := s.service.ImagePush(cfg)
+		result, err := s.service.ImagePush(t.Context(), cfg)
 
 		require.Nil(t, result)
 		require.Error(t, err)
@@ -257,7 +257,7 @@ func TestService_ImagePush(t *testing.T) {
 			},
 		}
 
-		result, err := s.service.ImagePush(cfg)
+		result, err := s.service.ImagePush(t.Context(), cfg)
 
 		require.Nil(t, result)
 		require.Error(t, err)
@@ -312,7 +312,7 @@ func TestService_ImagePush(t *testing.T) {
 			},
 		}
 
-		result, err := s.service.ImagePush(cfg)
+		result, err := s.service.ImagePush(t.Context(), cfg)
 
 		require.Error(t, err)
 		require.Equal(t, "image push failed, inspect the run at \"some-run-url\" to see why", err.Error())
@@ -370,7 +370,7 @@ func TestService_ImagePush(t *testing.T) {
 			},
 		}
 
-		result, err := s.service.ImagePush(cfg)
+		result, err := s.service.ImagePush(t.Context(), cfg)
 
 		require.NoError(t, err)
 		require.NotNil(t, result)
@@ -432,7 +432,7 @@ func TestService_ImagePush(t *testing.T) {
 			},
 		}
 
-		result, err := s.service.ImagePush(cfg)
+		result, err := s.service.ImagePush(t.Context(), cfg)
 
 		require.NoError(t, err)
 		require.NotNil(t, result)
@@ -487,7 +487,7 @@ func TestService_ImagePush(t *testing.T) {
 			},
 		}
 
-		result, err := s.service.ImagePush(cfg)
+		result, err := s.service.ImagePush(t.Context(), cfg)
 
 		require.Nil(t, result)
 		require.ErrorContains(t, err, "RWX_PUSH_PASSWORD must be set if RWX_PUSH_USERNAME is set")
@@ -532,7 +532,7 @@ func TestService_ImagePush(t *testing.T) {
 			},
 		}
 
-		result, err := s.service.ImagePush(cfg)
+		result, err := s.service.ImagePush(t.Context(), cfg)
 
 		require.Nil(t, result)
 		require.ErrorContains(t, err, "RWX_PUSH_USERNAME must be set if RWX_PUSH_PASSWORD is set")
@@ -1,7 +1,7 @@
 package cli
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"strings"
 
@@ -41,7 +41,7 @@ func (r InsertDefaultBaseResult) HasChanges() bool {
 	return len(r.ErroredRunFiles) > 0 || len(r.UpdatedRunFiles) > 0
 }
 
-func (s Service) InsertBase(cfg InsertBaseConfig) (InsertDefaultBaseResult, error) {
+func (s Service) InsertBase(ctx context.Context, cfg InsertBaseConfig) (InsertDefaultBaseResult, error) {
 	err := cfg.Validate()
 	if err != nil {
 		return InsertDefaultBaseResult{}, errors.Wrap(err, "validation failed")
@@ -61,7 +61,7 @@ func (s Service) InsertBase(cfg InsertBaseConfig) (InsertDefaultBaseResult, erro
 		return InsertDefaultBaseResult{}, fmt.Errorf("no files provided, and no yaml files found in directory %s", rwxDirectoryPath)
 	}
 
-	result, err := s.insertOrUpdateBase(yamlFiles, true)
+	result, err := s.insertOrUpdateBase(ctx, yamlFiles, true)
 	if err != nil {
 		return InsertDefaultBaseResult{}, err
 	}
@@ -82,7 +82,7 @@ func (s Service) InsertBase(cfg InsertBaseConfig) (InsertDefaultBaseResult, erro
 			UpdatedBases: updatedBases,
 			ErroredBases: erroredBases,
 		}
-		if err := json.NewEncoder(s.Stdout).Encode(output); err != nil {
+		if err := s.Output.Write(s.Stdout, output); err != nil {
 			return InsertDefaultBaseResult{}, errors.Wrap(err, "unable to encode JSON output")
 		}
 	} else {
@@ -113,11 +113,11 @@ func (s Service) InsertBase(cfg InsertBaseConfig) (InsertDefaultBaseResult, erro
 	return result, nil
 }
 
-func (s Service) insertDefaultBaseIfMissing(mintFiles []RwxDirectoryEntry) (InsertDefaultBaseResult, error) {
-	return s.insertOrUpdateBase(mintFiles, false)
+func (s Service) insertDefaultBaseIfMissing(ctx context.Context, mintFiles []RwxDirectoryEntry) (InsertDefaultBaseResult, error) {
+	return s.insertOrUpdateBase(ctx, mintFiles, false)
 }
 
-func (s Service) insertOrUpdateBase(mintFiles []RwxDirectoryEntry, updateDeprecated bool) (InsertDefaultBaseResult, error) {
+func (s Service) insertOrUpdateBase(ctx context.Context, mintFiles []RwxDirectoryEntry, updateDeprecated bool) (InsertDefaultBaseResult, error) {
 	runFilesToInsert, err := s.getFilesForBaseInsert(mintFiles)
 	if err != nil {
 		return InsertDefaultBaseResult{}, err
@@ -135,7 +135,7 @@ func (s Service) insertOrUpdateBase(mintFiles []RwxDirectoryEntry, updateDepreca
 		return InsertDefaultBaseResult{}, nil
 	}
 
-	defaultBaseSpec, err := s.getDefaultBaseSpec()
+	defaultBaseSpec, err := s.getDefaultBaseSpec(ctx)
 	if err != nil {
 		return InsertDefaultBaseResult{}, errors.Wrap(err, "unable to get default base spec")
 	}
@@ -200,8 +200,8 @@ func (s Service) getFilesForBaseInsert(entries []RwxDirectoryEntry) ([]BaseLayer
 	return runFiles, nil
 }
 
-func (s Service) getDefaultBaseSpec() (BaseSpec, error) {
-	result, err := s.APIClient.GetDefaultBase()
+func (s Service) getDefaultBaseSpec(ctx context.Context) (BaseSpec, error) {
+	result, err := s.APIClient.GetDefaultBase(ctx)
 
 	if err != nil {
 		return BaseSpec{}, errors.Wrap(err, "unable to get default base")
@@ -69,7 +69,7 @@ func TestService_InsertBase(t *testing.T) {
 		err := os.WriteFile(filepath.Join(bl.mintDir, "bar.json"), []byte("some json"), 0o644)
 		require.NoError(t, err)
 
-		_, err = bl.s.service.InsertBase(cli.InsertBaseConfig{})
+		_, err = bl.s.service.InsertBase(t.Context(), cli.InsertBaseConfig{})
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), fmt.Sprintf("no files provided, and no yaml files found in directory %s", bl.mintDir))
@@ -86,7 +86,7 @@ func TestService_InsertBase(t *testing.T) {
 }`), 0o644)
 		require.NoError(t, err)
 
-		_, err = bl.s.service.InsertBase(cli.InsertBaseConfig{})
+		_, err = bl.s.service.InsertBase(t.Context(), cli.InsertBaseConfig{})
 
 		require.NoError(t, err)
 		require.Equal(t, "", bl.s.mockStderr.String())
@@ -114,7 +114,7 @@ not-my-key:
 		require.NoError(t, err)
 
 		t.Run("adds base to file", func(t *testing.T) {
-			_, err = bl.s.service.InsertBase(cli.InsertBaseConfig{})
+			_, err = bl.s.service.InsertBase(t.Context(), cli.InsertBaseConfig{})
 			require.NoError(t, err)
 
 			var contents []byte
@@ -162,7 +162,7 @@ tasks:
 			err = os.WriteFile(filepath.Join(bl.mintDir, "qux.yaml"), []byte(originalQuxContents), 0o644)
 			require.NoError(t, err)
 
-			_, err = bl.s.service.InsertBase(cli.InsertBaseConfig{
+			_, err = bl.s.service.InsertBase(t.Context(), cli.InsertBaseConfig{
 				Files: []string{"../.mint/bar.yaml"},
 			})
 			require.NoError(t, err)
@@ -191,7 +191,7 @@ tasks:
 		})
 
 		t.Run("errors when given a file that does not exist", func(t *testing.T) {
-			_, err := bl.s.service.InsertBase(cli.InsertBaseConfig{
+			_, err := bl.s.service.InsertBase(t.Context(), cli.InsertBaseConfig{
 				Files: []string{"does-not-exist.yaml"},
 			})
 			require.Error(t, err)
@@ -216,7 +216,7 @@ tasks:
 `), 0o644)
 		require.NoError(t, err)
 
-		_, err = bl.s.service.InsertBase(cli.InsertBaseConfig{})
+		_, err = bl.s.service.InsertBase(t.Context(), cli.InsertBaseConfig{})
 		require.NoError(t, err)
 
 		contents, err := os.ReadFile(filepath.Join(bl.mintDir, "ci.yaml"))
@@ -251,7 +251,7 @@ tasks:
 `), 0o644)
 		require.NoError(t, err)
 
-		_, err = bl.s.service.InsertBase(cli.InsertBaseConfig{})
+		_, err = bl.s.service.InsertBase(t.Context(), cli.InsertBaseConfig{})
 		require.NoError(t, err)
 
 		contents, err := os.ReadFile(filepath.Join(bl.mintDir, "ci.yaml"))
@@ -278,7 +278,7 @@ tasks:
 `), 0o644)
 		require.NoError(t, err)
 
-		_, err = bl.s.service.InsertBase(cli.InsertBaseConfig{})
+		_, err = bl.s.service.InsertBase(t.Context(), cli.InsertBaseConfig{})
 		require.NoError(t, err)
 
 		contents, err := os.ReadFile(filepath.Join(bl.mintDir, "ci.yaml"))
@@ -304,7 +304,7 @@ tasks:
 `), 0o644)
 		require.NoError(t, err)
 
-		_, err = bl.s.service.InsertBase(cli.InsertBaseConfig{})
+		_, err = bl.s.service.InsertBase(t.Context(), cli.InsertBaseConfig{})
 		require.NoError(t, err)
 
 		contents, err := os.ReadFile(filepath.Join(bl.mintDir, "ci.yaml"))
@@ -342,7 +342,7 @@ tasks:
 		require.NoError(t, err)
 
 		t.Run("updates all files", func(t *testing.T) {
-			_, err = bl.s.service.InsertBase(cli.InsertBaseConfig{})
+			_, err = bl.s.service.InsertBase(t.Context(), cli.InsertBaseConfig{})
 			require.NoError(t, err)
 
 			var contents []byte
@@ -404,7 +404,7 @@ tasks:
 				return errors.New("API request failed")
 			}
 
-			_, err = bl.s.service.InsertBase(cli.InsertBaseConfig{})
+			_, err = bl.s.service.InsertBase(t.Context(), cli.InsertBaseConfig{})
 			require.Error(t, err)
 			require.Contains(t, err.Error(), "API request failed")
 
@@ -434,7 +434,7 @@ tasks:
 		require.NoError(t, err)
 
 		t.Run("does not add base to file", func(t *testing.T) {
-			_, err = bl.s.service.InsertBase(cli.InsertBaseConfig{})
+			_, err = bl.s.service.InsertBase(t.Context(), cli.InsertBaseConfig{})
 			require.NoError(t, err)
 
 			var contents []byte
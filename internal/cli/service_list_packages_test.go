@@ -32,7 +32,7 @@ func TestService_ListPackages(t *testing.T) {
 			}, nil
 		}
 
-		result, err := s.service.ListPackages(cli.ListPackagesConfig{Json: false})
+		result, err := s.service.ListPackages(t.Context(), cli.ListPackagesConfig{Json: false})
 		require.NoError(t, err)
 		require.Len(t, result.Packages, 2)
 		require.Equal(t, "nodejs/install", result.Packages[0].Name)
@@ -74,7 +74,7 @@ func TestService_ListPackages(t *testing.T) {
 			}, nil
 		}
 
-		result, err := s.service.ListPackages(cli.ListPackagesConfig{Json: false})
+		result, err := s.service.ListPackages(t.Context(), cli.ListPackagesConfig{Json: false})
 		require.NoError(t, err)
 		require.Len(t, result.Packages, 2)
 		require.Equal(t, "git/clone", result.Packages[0].Name)
@@ -97,7 +97,7 @@ func TestService_ListPackages(t *testing.T) {
 			}, nil
 		}
 
-		result, err := s.service.ListPackages(cli.ListPackagesConfig{Json: false})
+		result, err := s.service.ListPackages(t.Context(), cli.ListPackagesConfig{Json: false})
 		require.NoError(t, err)
 		require.Equal(t, longDesc, result.Packages[0].Description)
 
@@ -127,7 +127,7 @@ func TestService_ListPackages(t *testing.T) {
 			}, nil
 		}
 
-		_, err := s.service.ListPackages(cli.ListPackagesConfig{Json: false})
+		_, err := s.service.ListPackages(t.Context(), cli.ListPackagesConfig{Json: false})
 		require.NoError(t, err)
 
 		output := s.mockStdout.String()
@@ -152,7 +152,7 @@ func TestService_ListPackages(t *testing.T) {
 			}, nil
 		}
 
-		_, err := s.service.ListPackages(cli.ListPackagesConfig{Json: true})
+		_, err := s.service.ListPackages(t.Context(), cli.ListPackagesConfig{Json: true})
 		require.NoError(t, err)
 
 		var output cli.ListPackagesResult
@@ -174,7 +174,7 @@ func TestService_ListPackages(t *testing.T) {
 			}, nil
 		}
 
-		result, err := s.service.ListPackages(cli.ListPackagesConfig{Json: false})
+		result, err := s.service.ListPackages(t.Context(), cli.ListPackagesConfig{Json: false})
 		require.NoError(t, err)
 		require.Empty(t, result.Packages)
 		require.Contains(t, s.mockStdout.String(), "No packages found.")
@@ -187,7 +187,7 @@ func TestService_ListPackages(t *testing.T) {
 			return nil, errors.New("network error")
 		}
 
-		_, err := s.service.ListPackages(cli.ListPackagesConfig{Json: false})
+		_, err := s.service.ListPackages(t.Context(), cli.ListPackagesConfig{Json: false})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "network error")
 	})
@@ -212,7 +212,7 @@ func TestService_ShowPackage(t *testing.T) {
 			}, nil
 		}
 
-		result, err := s.service.ShowPackage(cli.ShowPackageConfig{PackageName: "git/clone"})
+		result, err := s.service.ShowPackage(t.Context(), cli.ShowPackageConfig{PackageName: "git/clone"})
 		require.NoError(t, err)
 		require.Equal(t, "git/clone", result.Name)
 		require.Equal(t, "1.2.0", result.Version)
@@ -245,7 +245,7 @@ func TestService_ShowPackage(t *testing.T) {
 			}, nil
 		}
 
-		_, err := s.service.ShowPackage(cli.ShowPackageConfig{PackageName: "git/clone"})
+		_, err := s.service.ShowPackage(t.Context(), cli.ShowPackageConfig{PackageName: "git/clone"})
 		require.NoError(t, err)
 
 		output := s.mockStdout.String()
@@ -274,7 +274,7 @@ func TestService_ShowPackage(t *testing.T) {
 			}, nil
 		}
 
-		_, err := s.service.ShowPackage(cli.ShowPackageConfig{PackageName: "git/clone"})
+		_, err := s.service.ShowPackage(t.Context(), cli.ShowPackageConfig{PackageName: "git/clone"})
 		require.NoError(t, err)
 
 		output := s.mockStdout.String()
@@ -297,7 +297,7 @@ func TestService_ShowPackage(t *testing.T) {
 			}, nil
 		}
 
-		_, err := s.service.ShowPackage(cli.ShowPackageConfig{PackageName: "git/clone", NoReadme: true})
+		_, err := s.service.ShowPackage(t.Context(), cli.ShowPackageConfig{PackageName: "git/clone", NoReadme: true})
 		require.NoError(t, err)
 
 		output := s.mockStdout.String()
@@ -312,7 +312,7 @@ func TestService_ShowPackage(t *testing.T) {
 			return nil, api.ErrNotFound
 		}
 
-		_, err := s.service.ShowPackage(cli.ShowPackageConfig{PackageName: "nonexistent/pkg"})
+		_, err := s.service.ShowPackage(t.Context(), cli.ShowPackageConfig{PackageName: "nonexistent/pkg"})
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "unable to fetch documentation for package")
 	})
@@ -334,7 +334,7 @@ func TestService_ShowPackage(t *testing.T) {
 			}, nil
 		}
 
-		_, err := s.service.ShowPackage(cli.ShowPackageConfig{PackageName: "git/clone", Json: true})
+		_, err := s.service.ShowPackage(t.Context(), cli.ShowPackageConfig{PackageName: "git/clone", Json: true})
 		require.NoError(t, err)
 
 		var output cli.ShowPackageResult
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 type LoginConfig struct {
 	DeviceName         string
 	AccessTokenBackend accesstoken.Backend
+	Profile            string
 	OpenUrl            func(url string) error
 	PollInterval       time.Duration
 }
@@ -24,7 +26,7 @@ func (c LoginConfig) Validate() error {
 	return nil
 }
 
-func (s Service) Login(cfg LoginConfig) (loginErr error) {
+func (s Service) Login(ctx context.Context, cfg LoginConfig) (loginErr error) {
 	start := time.Now()
 	defer func() {
 		s.recordTelemetry("auth.login", map[string]any{
@@ -38,7 +40,7 @@ func (s Service) Login(cfg LoginConfig) (loginErr error) {
 		return errors.Wrap(err, "validation failed")
 	}
 
-	authCodeResult, err := s.APIClient.ObtainAuthCode(api.ObtainAuthCodeConfig{
+	authCodeResult, err := s.APIClient.ObtainAuthCode(ctx, api.ObtainAuthCodeConfig{
 		Code: api.ObtainAuthCodeCode{
 			DeviceName: cfg.DeviceName,
 		},
@@ -66,7 +68,7 @@ func (s Service) Login(cfg LoginConfig) (loginErr error) {
 	}
 
 	for {
-		tokenResult, err := s.APIClient.AcquireToken(authCodeResult.TokenUrl)
+		tokenResult, err := s.APIClient.AcquireToken(ctx, authCodeResult.TokenUrl)
 		if err != nil {
 			stop()
 			return errors.Wrap(err, "unable to acquire the token")
@@ -84,15 +86,24 @@ func (s Service) Login(cfg LoginConfig) (loginErr error) {
 			if tokenResult.Token == "" {
 				return errors.New("The code has been authorized, but there is no token. You can try again, but this is likely an issue with RWX Cloud. Please reach out at support@rwx.com.")
 			} else {
-				if err := accesstoken.Set(cfg.AccessTokenBackend, tokenResult.Token); err == nil {
-					fmt.Fprint(s.Stdout, "Authorized!\n")
-					return nil
-				} else {
+				if err := accesstoken.Set(cfg.AccessTokenBackend, tokenResult.Token); err != nil {
 					return fmt.Errorf("An error occurred while storing the token: %w", err)
 				}
+
+				fmt.Fprint(s.Stdout, "Authorized!\n")
+				if cfg.Profile != "" {
+					if err := s.recordProfileOrganization(ctx, cfg.Profile); err != nil {
+						return err
+					}
+					fmt.Fprintf(s.Stdout, "Saved to profile %q.\n", cfg.Profile)
+				}
+				return nil
 			}
 		case "pending":
-			time.Sleep(cfg.PollInterval)
+			if err := sleep(ctx, cfg.PollInterval); err != nil {
+				stop()
+				return err
+			}
 		default:
 			stop()
 			return errors.New("The code is in an unexpected state. You can try again, but this is likely an issue with RWX Cloud. Please reach out at support@rwx.com.")
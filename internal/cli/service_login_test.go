@@ -26,7 +26,7 @@ func TestService_LoggingIn(t *testing.T) {
 			return nil, errors.New("error in obtain auth code")
 		}
 
-		err := s.service.Login(cli.LoginConfig{
+		err := s.service.Login(t.Context(), cli.LoginConfig{
 			DeviceName:         "some-device",
 			AccessTokenBackend: tokenBackend,
 			OpenUrl: func(url string) error {
@@ -66,7 +66,7 @@ func TestService_LoggingIn(t *testing.T) {
 			}
 
 			t.Run("does not error", func(t *testing.T) {
-				err := s.service.Login(cli.LoginConfig{
+				err := s.service.Login(t.Context(), cli.LoginConfig{
 					DeviceName:         "some-device",
 					AccessTokenBackend: tokenBackend,
 					OpenUrl: func(url string) error {
@@ -79,7 +79,7 @@ func TestService_LoggingIn(t *testing.T) {
 			})
 
 			t.Run("stores the token", func(t *testing.T) {
-				err := s.service.Login(cli.LoginConfig{
+				err := s.service.Login(t.Context(), cli.LoginConfig{
 					DeviceName:         "some-device",
 					AccessTokenBackend: tokenBackend,
 					OpenUrl: func(url string) error {
@@ -95,7 +95,7 @@ func TestService_LoggingIn(t *testing.T) {
 			})
 
 			t.Run("indicates success and help in case the browser does not open", func(t *testing.T) {
-				err := s.service.Login(cli.LoginConfig{
+				err := s.service.Login(t.Context(), cli.LoginConfig{
 					DeviceName:         "some-device",
 					AccessTokenBackend: tokenBackend,
 					OpenUrl: func(url string) error {
@@ -110,7 +110,7 @@ func TestService_LoggingIn(t *testing.T) {
 			})
 
 			t.Run("attempts to open the authorization URL, but doesn't care if it fails", func(t *testing.T) {
-				err := s.service.Login(cli.LoginConfig{
+				err := s.service.Login(t.Context(), cli.LoginConfig{
 					DeviceName:         "some-device",
 					AccessTokenBackend: tokenBackend,
 					OpenUrl: func(url string) error {
@@ -151,7 +151,7 @@ func TestService_LoggingIn(t *testing.T) {
 			}
 
 			t.Run("errors", func(t *testing.T) {
-				err := s.service.Login(cli.LoginConfig{
+				err := s.service.Login(t.Context(), cli.LoginConfig{
 					DeviceName:         "some-device",
 					AccessTokenBackend: tokenBackend,
 					OpenUrl: func(url string) error {
@@ -165,7 +165,7 @@ func TestService_LoggingIn(t *testing.T) {
 			})
 
 			t.Run("does not store the token", func(t *testing.T) {
-				err := s.service.Login(cli.LoginConfig{
+				err := s.service.Login(t.Context(), cli.LoginConfig{
 					DeviceName:         "some-device",
 					AccessTokenBackend: tokenBackend,
 					OpenUrl: func(url string) error {
@@ -181,7 +181,7 @@ func TestService_LoggingIn(t *testing.T) {
 			})
 
 			t.Run("does not indicate success, but still helps in case the browser does not open", func(t *testing.T) {
-				err := s.service.Login(cli.LoginConfig{
+				err := s.service.Login(t.Context(), cli.LoginConfig{
 					DeviceName:         "some-device",
 					AccessTokenBackend: tokenBackend,
 					OpenUrl: func(url string) error {
@@ -222,7 +222,7 @@ func TestService_LoggingIn(t *testing.T) {
 			}
 
 			t.Run("errors", func(t *testing.T) {
-				err := s.service.Login(cli.LoginConfig{
+				err := s.service.Login(t.Context(), cli.LoginConfig{
 					DeviceName:         "some-device",
 					AccessTokenBackend: tokenBackend,
 					OpenUrl: func(url string) error {
@@ -236,7 +236,7 @@ func TestService_LoggingIn(t *testing.T) {
 			})
 
 			t.Run("does not store the token", func(t *testing.T) {
-				err := s.service.Login(cli.LoginConfig{
+				err := s.service.Login(t.Context(), cli.LoginConfig{
 					DeviceName:         "some-device",
 					AccessTokenBackend: tokenBackend,
 					OpenUrl: func(url string) error {
@@ -252,7 +252,7 @@ func TestService_LoggingIn(t *testing.T) {
 			})
 
 			t.Run("does not indicate success, but still helps in case the browser does not open", func(t *testing.T) {
-				err := s.service.Login(cli.LoginConfig{
+				err := s.service.Login(t.Context(), cli.LoginConfig{
 					DeviceName:         "some-device",
 					AccessTokenBackend: tokenBackend,
 					OpenUrl: func(url string) error {
@@ -293,7 +293,7 @@ func TestService_LoggingIn(t *testing.T) {
 			}
 
 			t.Run("errors", func(t *testing.T) {
-				err := s.service.Login(cli.LoginConfig{
+				err := s.service.Login(t.Context(), cli.LoginConfig{
 					DeviceName:         "some-device",
 					AccessTokenBackend: tokenBackend,
 					OpenUrl: func(url string) error {
@@ -307,7 +307,7 @@ func TestService_LoggingIn(t *testing.T) {
 			})
 
 			t.Run("does not store the token", func(t *testing.T) {
-				err := s.service.Login(cli.LoginConfig{
+				err := s.service.Login(t.Context(), cli.LoginConfig{
 					DeviceName:         "some-device",
 					AccessTokenBackend: tokenBackend,
 					OpenUrl: func(url string) error {
@@ -323,7 +323,7 @@ func TestService_LoggingIn(t *testing.T) {
 			})
 
 			t.Run("does not indicate success, but still helps in case the browser does not open", func(t *testing.T) {
-				err := s.service.Login(cli.LoginConfig{
+				err := s.service.Login(t.Context(), cli.LoginConfig{
 					DeviceName:         "some-device",
 					AccessTokenBackend: tokenBackend,
 					OpenUrl: func(url string) error {
@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/rwx-cloud/rwx/internal/accesstoken"
+	"github.com/rwx-cloud/rwx/internal/docstoken"
+	"github.com/rwx-cloud/rwx/internal/errors"
+	"github.com/rwx-cloud/rwx/internal/fs"
+)
+
+type LogoutConfig struct {
+	AccessTokenBackend accesstoken.Backend
+}
+
+func (c LogoutConfig) Validate() error {
+	if c.AccessTokenBackend == nil {
+		return errors.New("missing access token backend")
+	}
+
+	return nil
+}
+
+// Logout revokes the stored access token with RWX Cloud and removes it from
+// this device, along with the cached docs token and the scoped tokens of
+// sandboxes in the current project. The local tokens are removed even when
+// the access token can't be revoked, in which case an error is returned.
+func (s Service) Logout(ctx context.Context, cfg LogoutConfig) (logoutErr error) {
+	start := time.Now()
+	revoked := false
+	defer func() {
+		s.recordTelemetry("auth.logout", map[string]any{
+			"success":     logoutErr == nil,
+			"revoked":     revoked,
+			"duration_ms": time.Since(start).Milliseconds(),
+		})
+	}()
+
+	err := cfg.Validate()
+	if err != nil {
+		return errors.Wrap(err, "validation failed")
+	}
+
+	token, err := accesstoken.Get(cfg.AccessTokenBackend, "")
+	if err != nil {
+		return errors.Wrap(err, "unable to read the stored access token")
+	}
+
+	var revokeErr error
+	if token != "" {
+		revokeErr = s.APIClient.RevokeToken(ctx)
+		switch {
+		case revokeErr == nil:
+			revoked = true
+		case errors.Is(revokeErr, errors.ErrUnauthorized):
+			// The token was already revoked or expired, so there is nothing left to do on the server
+			revokeErr = nil
+		}
+
+		if err := accesstoken.Set(cfg.AccessTokenBackend, ""); err != nil {
+			return errors.Wrap(err, "unable to remove the stored access token")
+		}
+	}
+
+	if s.DocsTokenBackend != nil {
+		if err := s.DocsTokenBackend.Set(docstoken.DocsToken{}); err != nil {
+			return errors.Wrap(err, "unable to remove the cached docs token")
+		}
+	}
+
+	cleared, err := s.clearSandboxScopedTokens()
+	if err != nil {
+		return err
+	}
+
+	if revokeErr != nil {
+		return errors.Wrap(revokeErr, "the access token was removed from this device, but it could not be revoked. Revoke it in RWX Cloud to finish logging out")
+	}
+
+	if token == "" {
+		fmt.Fprintln(s.Stdout, "You are not logged in.")
+	} else {
+		fmt.Fprintln(s.Stdout, "Logged out.")
+	}
+	if cleared > 0 {
+		fmt.Fprintf(s.Stdout, "Removed the scoped tokens of %d sandbox(es).\n", cleared)
+	}
+
+	return nil
+}
+
+// clearSandboxScopedTokens removes the scoped tokens from the sandbox storage
+// of the current project, without creating the storage when there is none.
+func (s Service) clearSandboxScopedTokens() (int, error) {
+	rwxDir, err := findRwxDirectoryPath("")
+	if err != nil || rwxDir == "" {
+		return 0, nil
+	}
+
+	exists, err := fs.Exists(filepath.Join(rwxDir, "sandboxes", "sandboxes.json"))
+	if err != nil || !exists {
+		return 0, nil
+	}
+
+	lock, err := s.lockSandboxStorageWithInfo(false)
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to lock sandbox storage")
+	}
+	defer UnlockSandboxStorage(lock)
+
+	storage, err := LoadSandboxStorage()
+	if err != nil {
+		return 0, errors.Wrap(err, "unable to load sandbox storage")
+	}
+
+	cleared := storage.ClearScopedTokens()
+	if cleared == 0 {
+		return 0, nil
+	}
+
+	if err := storage.Save(); err != nil {
+		return 0, errors.Wrap(err, "unable to save sandbox storage")
+	}
+	return cleared, nil
+}
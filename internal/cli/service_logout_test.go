@@ -0,0 +1,143 @@
+package cli_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rwx-cloud/rwx/internal/accesstoken"
+	"github.com/rwx-cloud/rwx/internal/cli"
+	"github.com/rwx-cloud/rwx/internal/docstoken"
+	"github.com/rwx-cloud/rwx/internal/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Logout(t *testing.T) {
+	setupLogout := func(t *testing.T) (*testSetup, accesstoken.Backend, docstoken.Backend) {
+		s := setupTest(t)
+
+		tokenBackend := accesstoken.NewMemoryBackend()
+		require.NoError(t, tokenBackend.Set("stored-token"))
+
+		docsTokenBackend := docstoken.NewMemoryBackend()
+		require.NoError(t, docsTokenBackend.Set(docstoken.DocsToken{Token: "docs-token", AuthToken: "stored-token"}))
+		s.config.DocsTokenBackend = docsTokenBackend
+
+		var err error
+		s.service, err = cli.NewService(s.config)
+		require.NoError(t, err)
+		return s, tokenBackend, docsTokenBackend
+	}
+
+	t.Run("revokes and removes the stored tokens", func(t *testing.T) {
+		s, tokenBackend, docsTokenBackend := setupLogout(t)
+
+		revoked := false
+		s.mockAPI.MockRevokeToken = func() error {
+			revoked = true
+			return nil
+		}
+
+		err := s.service.Logout(t.Context(), cli.LogoutConfig{AccessTokenBackend: tokenBackend})
+		require.NoError(t, err)
+		require.True(t, revoked)
+
+		token, err := tokenBackend.Get()
+		require.NoError(t, err)
+		require.Equal(t, "", token)
+
+		docsToken, err := docsTokenBackend.Get()
+		require.NoError(t, err)
+		require.Equal(t, docstoken.DocsToken{}, docsToken)
+
+		require.Contains(t, s.mockStdout.String(), "Logged out.")
+
+		event := findEvent(s.drainEvents(), "auth.logout")
+		require.NotNil(t, event)
+		require.Equal(t, true, event.Props["revoked"])
+	})
+
+	t.Run("removes a token that is already invalid", func(t *testing.T) {
+		s, tokenBackend, _ := setupLogout(t)
+
+		s.mockAPI.MockRevokeToken = func() error {
+			return errors.ErrUnauthorized
+		}
+
+		err := s.service.Logout(t.Context(), cli.LogoutConfig{AccessTokenBackend: tokenBackend})
+		require.NoError(t, err)
+
+		token, err := tokenBackend.Get()
+		require.NoError(t, err)
+		require.Equal(t, "", token)
+	})
+
+	t.Run("removes the token but errors when it cannot be revoked", func(t *testing.T) {
+		s, tokenBackend, _ := setupLogout(t)
+
+		s.mockAPI.MockRevokeToken = func() error {
+			return errors.New("connection refused")
+		}
+
+		err := s.service.Logout(t.Context(), cli.LogoutConfig{AccessTokenBackend: tokenBackend})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "could not be revoked")
+		require.Contains(t, err.Error(), "connection refused")
+
+		token, err := tokenBackend.Get()
+		require.NoError(t, err)
+		require.Equal(t, "", token)
+	})
+
+	t.Run("removes the token but errors when the revoke endpoint isn't found", func(t *testing.T) {
+		s, tokenBackend, _ := setupLogout(t)
+
+		s.mockAPI.MockRevokeToken = func() error {
+			return errors.New("Unable to call RWX API - 404 Not Found")
+		}
+
+		err := s.service.Logout(t.Context(), cli.LogoutConfig{AccessTokenBackend: tokenBackend})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Revoke it in RWX Cloud to finish logging out")
+		require.Contains(t, err.Error(), "404 Not Found")
+		require.NotContains(t, s.mockStdout.String(), "Logged out.")
+
+		token, err := tokenBackend.Get()
+		require.NoError(t, err)
+		require.Equal(t, "", token)
+	})
+
+	t.Run("does not call the API when not logged in", func(t *testing.T) {
+		s := setupTest(t)
+
+		err := s.service.Logout(t.Context(), cli.LogoutConfig{AccessTokenBackend: accesstoken.NewMemoryBackend()})
+		require.NoError(t, err)
+		require.Contains(t, s.mockStdout.String(), "You are not logged in.")
+	})
+
+	t.Run("clears the scoped tokens of sandboxes", func(t *testing.T) {
+		s, tokenBackend, _ := setupLogout(t)
+		s.mockAPI.MockRevokeToken = func() error { return nil }
+
+		expiresAt := time.Now().Add(time.Hour)
+		storage := &cli.SandboxStorage{Sandboxes: map[string]cli.SandboxSession{}}
+		storage.SetSession("main", s.absConfig(".rwx/sandbox.yml"), "", cli.SandboxSession{
+			RunID:          "run-123",
+			ConfigFile:     s.absConfig(".rwx/sandbox.yml"),
+			ScopedToken:    "scoped-token",
+			TokenExpiresAt: &expiresAt,
+		})
+		require.NoError(t, storage.Save())
+
+		err := s.service.Logout(t.Context(), cli.LogoutConfig{AccessTokenBackend: tokenBackend})
+		require.NoError(t, err)
+
+		loaded, err := cli.LoadSandboxStorage()
+		require.NoError(t, err)
+		session, found := loaded.GetSession("main", s.absConfig(".rwx/sandbox.yml"), "")
+		require.True(t, found)
+		require.Equal(t, "run-123", session.RunID)
+		require.Equal(t, "", session.ScopedToken)
+		require.Nil(t, session.TokenExpiresAt)
+		require.Contains(t, s.mockStdout.String(), "Removed the scoped tokens of 1 sandbox(es).")
+	})
+}
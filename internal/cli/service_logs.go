@@ -2,7 +2,7 @@ package cli
 
 import (
 	"archive/zip"
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -45,7 +45,7 @@ type DownloadLogsResult struct {
 	OutputFiles []string
 }
 
-func (s Service) DownloadLogs(cfg DownloadLogsConfig) (_ *DownloadLogsResult, dlErr error) {
+func (s Service) DownloadLogs(ctx context.Context, cfg DownloadLogsConfig) (_ *DownloadLogsResult, dlErr error) {
 	start := time.Now()
 	defer func() {
 		s.recordTelemetry("logs.download", map[string]any{
@@ -61,16 +61,16 @@ func (s Service) DownloadLogs(cfg DownloadLogsConfig) (_ *DownloadLogsResult, dl
 
 	var logDownloadRequest api.LogDownloadRequestResult
 	if cfg.TaskKey != "" {
-		logDownloadRequest, err = s.APIClient.GetLogDownloadRequestByTaskKey(cfg.RunID, cfg.TaskKey)
+		logDownloadRequest, err = s.APIClient.GetLogDownloadRequestByTaskKey(ctx, cfg.RunID, cfg.TaskKey)
 	} else {
-		logDownloadRequest, err = s.APIClient.GetLogDownloadRequest(cfg.TaskID)
+		logDownloadRequest, err = s.APIClient.GetLogDownloadRequest(ctx, cfg.TaskID)
 	}
 	if err != nil {
 		if errors.Is(err, api.ErrNotFound) {
 			if cfg.TaskKey != "" {
-				return nil, errors.WrapSentinel(errors.New(fmt.Sprintf("Task with key '%s' not found", cfg.TaskKey)), api.ErrNotFound)
+				return nil, withTaskIDs(errors.WrapSentinel(errors.New(fmt.Sprintf("Task with key '%s' not found", cfg.TaskKey)), api.ErrNotFound), cfg.RunID, cfg.TaskID, cfg.TaskKey)
 			}
-			return nil, errors.WrapSentinel(errors.New(fmt.Sprintf("Task %s not found", cfg.TaskID)), api.ErrNotFound)
+			return nil, withTaskIDs(errors.WrapSentinel(errors.New(fmt.Sprintf("Task %s not found", cfg.TaskID)), api.ErrNotFound), cfg.RunID, cfg.TaskID, cfg.TaskKey)
 		}
 		return nil, errors.Wrap(err, "unable to fetch log archive request")
 	}
@@ -81,7 +81,7 @@ func (s Service) DownloadLogs(cfg DownloadLogsConfig) (_ *DownloadLogsResult, dl
 		s.Stderr,
 	)
 
-	logBytes, err := s.APIClient.DownloadLogs(logDownloadRequest)
+	logBytes, err := s.APIClient.DownloadLogs(ctx, logDownloadRequest)
 	stopSpinner()
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to download logs")
@@ -163,7 +163,7 @@ func (s Service) DownloadLogs(cfg DownloadLogsConfig) (_ *DownloadLogsResult, dl
 	result := &DownloadLogsResult{OutputFiles: outputFiles}
 
 	if cfg.Json {
-		if err := json.NewEncoder(s.Stdout).Encode(result); err != nil {
+		if err := s.Output.Write(s.Stdout, result); err != nil {
 			return nil, errors.Wrap(err, "unable to encode JSON output")
 		}
 	}
@@ -225,3 +225,10 @@ func extractZip(zipPath, destDir string) ([]string, error) {
 
 	return extractedFiles, nil
 }
+
+// withTaskIDs attaches the IDs that identify a task to err.
+func withTaskIDs(err error, runID, taskID, taskKey string) error {
+	err = errors.WithID(err, "run_id", runID)
+	err = errors.WithID(err, "task_id", taskID)
+	return errors.WithID(err, "task_key", taskKey)
+}
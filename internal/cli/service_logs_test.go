@@ -22,7 +22,7 @@ func TestService_DownloadLogs(t *testing.T) {
 			return api.LogDownloadRequestResult{}, api.ErrNotFound
 		}
 
-		_, err := s.service.DownloadLogs(cli.DownloadLogsConfig{
+		_, err := s.service.DownloadLogs(t.Context(), cli.DownloadLogsConfig{
 			TaskID:    "task-123",
 			OutputDir: s.tmp,
 		})
@@ -38,7 +38,7 @@ func TestService_DownloadLogs(t *testing.T) {
 			return api.LogDownloadRequestResult{}, errors.New("network error")
 		}
 
-		_, err := s.service.DownloadLogs(cli.DownloadLogsConfig{
+		_, err := s.service.DownloadLogs(t.Context(), cli.DownloadLogsConfig{
 			TaskID:    "task-123",
 			OutputDir: s.tmp,
 		})
@@ -64,7 +64,7 @@ func TestService_DownloadLogs(t *testing.T) {
 			return nil, errors.New("download failed")
 		}
 
-		_, err := s.service.DownloadLogs(cli.DownloadLogsConfig{
+		_, err := s.service.DownloadLogs(t.Context(), cli.DownloadLogsConfig{
 			TaskID:    "task-123",
 			OutputDir: s.tmp,
 		})
@@ -96,7 +96,7 @@ func TestService_DownloadLogs(t *testing.T) {
 		}
 
 		nestedDir := filepath.Join(s.tmp, "nonexistent", "subdir")
-		_, err := s.service.DownloadLogs(cli.DownloadLogsConfig{
+		_, err := s.service.DownloadLogs(t.Context(), cli.DownloadLogsConfig{
 			TaskID:    "task-123",
 			OutputDir: nestedDir,
 		})
@@ -131,7 +131,7 @@ func TestService_DownloadLogs(t *testing.T) {
 			return zipBytes, nil
 		}
 
-		result, err := s.service.DownloadLogs(cli.DownloadLogsConfig{
+		result, err := s.service.DownloadLogs(t.Context(), cli.DownloadLogsConfig{
 			TaskID:    "task-123",
 			OutputDir: s.tmp,
 		})
@@ -172,7 +172,7 @@ func TestService_DownloadLogs(t *testing.T) {
 			return zipBytes, nil
 		}
 
-		result, err := s.service.DownloadLogs(cli.DownloadLogsConfig{
+		result, err := s.service.DownloadLogs(t.Context(), cli.DownloadLogsConfig{
 			TaskID:    "task-456",
 			OutputDir: s.tmp,
 		})
@@ -217,7 +217,7 @@ func TestService_DownloadLogs(t *testing.T) {
 			return zipBytes, nil
 		}
 
-		_, err := s.service.DownloadLogs(cli.DownloadLogsConfig{
+		_, err := s.service.DownloadLogs(t.Context(), cli.DownloadLogsConfig{
 			TaskID:    "task-123",
 			OutputDir: s.tmp,
 		})
@@ -232,7 +232,7 @@ func TestService_DownloadLogs(t *testing.T) {
 	t.Run("when validation fails - missing task ID", func(t *testing.T) {
 		s := setupTest(t)
 
-		_, err := s.service.DownloadLogs(cli.DownloadLogsConfig{
+		_, err := s.service.DownloadLogs(t.Context(), cli.DownloadLogsConfig{
 			TaskID:    "",
 			OutputDir: s.tmp,
 		})
@@ -245,7 +245,7 @@ func TestService_DownloadLogs(t *testing.T) {
 	t.Run("when validation fails - both output-dir and output-file set", func(t *testing.T) {
 		s := setupTest(t)
 
-		_, err := s.service.DownloadLogs(cli.DownloadLogsConfig{
+		_, err := s.service.DownloadLogs(t.Context(), cli.DownloadLogsConfig{
 			TaskID:     "task-123",
 			OutputDir:  s.tmp,
 			OutputFile: filepath.Join(s.tmp, "custom.zip"),
@@ -276,7 +276,7 @@ func TestService_DownloadLogs(t *testing.T) {
 			return zipBytes, nil
 		}
 
-		result, err := s.service.DownloadLogs(cli.DownloadLogsConfig{
+		result, err := s.service.DownloadLogs(t.Context(), cli.DownloadLogsConfig{
 			TaskID:    "task-123",
 			OutputDir: s.tmp,
 			Zip:       true,
@@ -322,7 +322,7 @@ func TestService_DownloadLogs(t *testing.T) {
 			return zipBytes, nil
 		}
 
-		_, err := s.service.DownloadLogs(cli.DownloadLogsConfig{
+		_, err := s.service.DownloadLogs(t.Context(), cli.DownloadLogsConfig{
 			TaskID:     "task-123",
 			OutputFile: customPath,
 			Zip:        true,
@@ -352,7 +352,7 @@ func TestService_DownloadLogs(t *testing.T) {
 			return zipBytes, nil
 		}
 
-		_, err := s.service.DownloadLogs(cli.DownloadLogsConfig{
+		_, err := s.service.DownloadLogs(t.Context(), cli.DownloadLogsConfig{
 			TaskID:    "task-123",
 			OutputDir: s.tmp,
 			Zip:       true,
@@ -388,7 +388,7 @@ func TestService_DownloadLogs(t *testing.T) {
 			return zipBytes, nil
 		}
 
-		_, err := s.service.DownloadLogs(cli.DownloadLogsConfig{
+		_, err := s.service.DownloadLogs(t.Context(), cli.DownloadLogsConfig{
 			TaskID:    "task-456",
 			OutputDir: s.tmp,
 			Json:      true,
@@ -429,7 +429,7 @@ func TestService_DownloadLogs(t *testing.T) {
 
 		// AutoExtract is gone from DownloadLogsConfig; --auto-extract is a no-op at the CLI layer.
 		// The service always extracts by default (Zip=false).
-		_, err := s.service.DownloadLogs(cli.DownloadLogsConfig{
+		_, err := s.service.DownloadLogs(t.Context(), cli.DownloadLogsConfig{
 			TaskID:    "task-123",
 			OutputDir: s.tmp,
 			Zip:       false,
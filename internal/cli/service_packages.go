@@ -1,7 +1,7 @@
 package cli
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"regexp"
 	"sort"
@@ -62,7 +62,7 @@ func (r ResolvePackagesResult) HasChanges() bool {
 	return len(r.ResolvedPackages) > 0
 }
 
-func (s Service) ResolvePackages(cfg ResolvePackagesConfig) (ResolvePackagesResult, error) {
+func (s Service) ResolvePackages(ctx context.Context, cfg ResolvePackagesConfig) (ResolvePackagesResult, error) {
 	err := cfg.Validate()
 	if err != nil {
 		return ResolvePackagesResult{}, errors.Wrap(err, "validation failed")
@@ -86,7 +86,7 @@ func (s Service) ResolvePackages(cfg ResolvePackagesConfig) (ResolvePackagesResu
 		return true
 	})
 
-	replacements, err := s.resolveOrUpdatePackagesForFiles(mintFiles, false, cfg.LatestVersionPicker)
+	replacements, err := s.resolveOrUpdatePackagesForFiles(ctx, mintFiles, false, cfg.LatestVersionPicker)
 	if err != nil {
 		return ResolvePackagesResult{}, err
 	}
@@ -97,7 +97,7 @@ func (s Service) ResolvePackages(cfg ResolvePackagesConfig) (ResolvePackagesResu
 		}{
 			ResolvedPackages: replacements,
 		}
-		if err := json.NewEncoder(s.Stdout).Encode(output); err != nil {
+		if err := s.Output.Write(s.Stdout, output); err != nil {
 			return ResolvePackagesResult{}, errors.Wrap(err, "unable to encode JSON output")
 		}
 	} else {
@@ -118,7 +118,7 @@ func (s Service) ResolvePackages(cfg ResolvePackagesConfig) (ResolvePackagesResu
 	return ResolvePackagesResult{ResolvedPackages: replacements}, nil
 }
 
-func (s Service) UpdatePackages(cfg UpdatePackagesConfig) (*UpdatePackagesResult, error) {
+func (s Service) UpdatePackages(ctx context.Context, cfg UpdatePackagesConfig) (*UpdatePackagesResult, error) {
 	err := cfg.Validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "validation failed")
@@ -142,7 +142,7 @@ func (s Service) UpdatePackages(cfg UpdatePackagesConfig) (*UpdatePackagesResult
 		return true
 	})
 
-	replacements, err := s.resolveOrUpdatePackagesForFiles(mintFiles, true, cfg.ReplacementVersionPicker)
+	replacements, err := s.resolveOrUpdatePackagesForFiles(ctx, mintFiles, true, cfg.ReplacementVersionPicker)
 	if err != nil {
 		return nil, err
 	}
@@ -154,7 +154,7 @@ func (s Service) UpdatePackages(cfg UpdatePackagesConfig) (*UpdatePackagesResult
 	result := &UpdatePackagesResult{UpdatedPackages: replacements}
 
 	if cfg.Json {
-		if err := json.NewEncoder(s.Stdout).Encode(result); err != nil {
+		if err := s.Output.Write(s.Stdout, result); err != nil {
 			return nil, errors.Wrap(err, "unable to encode JSON output")
 		}
 	} else {
@@ -194,8 +194,8 @@ func (s Service) parsePackageVersion(str string) PackageVersion {
 	}
 }
 
-func (s Service) resolveOrUpdatePackagesForFiles(mintFiles []*MintYAMLFile, update bool, versionPicker func(versions api.PackageVersionsResult, rwxPackage string, major string) (string, error)) (map[string]string, error) {
-	packageVersions, err := s.APIClient.GetPackageVersions()
+func (s Service) resolveOrUpdatePackagesForFiles(ctx context.Context, mintFiles []*MintYAMLFile, update bool, versionPicker func(versions api.PackageVersionsResult, rwxPackage string, major string) (string, error)) (map[string]string, error) {
+	packageVersions, err := s.APIClient.GetPackageVersions(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to fetch package versions")
 	}
@@ -317,8 +317,8 @@ type ListPackagesResult struct {
 	Packages []PackageInfo
 }
 
-func (s Service) ListPackages(cfg ListPackagesConfig) (*ListPackagesResult, error) {
-	packageVersions, err := s.APIClient.GetPackageVersions()
+func (s Service) ListPackages(ctx context.Context, cfg ListPackagesConfig) (*ListPackagesResult, error) {
+	packageVersions, err := s.APIClient.GetPackageVersions(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to fetch package versions")
 	}
@@ -350,7 +350,7 @@ func (s Service) ListPackages(cfg ListPackagesConfig) (*ListPackagesResult, erro
 	result := &ListPackagesResult{Packages: packages}
 
 	if cfg.Json {
-		if err := json.NewEncoder(s.Stdout).Encode(result); err != nil {
+		if err := s.Output.Write(s.Stdout, result); err != nil {
 			return nil, errors.Wrap(err, "unable to encode JSON output")
 		}
 	} else {
@@ -408,8 +408,8 @@ type ShowPackageResult struct {
 	Readme          string
 }
 
-func (s Service) ShowPackage(cfg ShowPackageConfig) (*ShowPackageResult, error) {
-	doc, err := s.APIClient.GetPackageDocumentation(cfg.PackageName)
+func (s Service) ShowPackage(ctx context.Context, cfg ShowPackageConfig) (*ShowPackageResult, error) {
+	doc, err := s.APIClient.GetPackageDocumentation(ctx, cfg.PackageName)
 	if err != nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("unable to fetch documentation for package %q", cfg.PackageName))
 	}
@@ -425,7 +425,7 @@ func (s Service) ShowPackage(cfg ShowPackageConfig) (*ShowPackageResult, error)
 	}
 
 	if cfg.Json {
-		if err := json.NewEncoder(s.Stdout).Encode(result); err != nil {
+		if err := s.Output.Write(s.Stdout, result); err != nil {
 			return nil, errors.Wrap(err, "unable to encode JSON output")
 		}
 	} else {
@@ -0,0 +1,204 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rwx-cloud/rwx/internal/accesstoken"
+	"github.com/rwx-cloud/rwx/internal/config"
+	"github.com/rwx-cloud/rwx/internal/errors"
+	"github.com/rwx-cloud/rwx/internal/profiles"
+)
+
+type ListProfilesConfig struct {
+	Json bool
+}
+
+type ProfileInfo struct {
+	Name         string `json:"name"`
+	Host         string `json:"host"`
+	Organization string `json:"organization,omitempty"`
+	Active       bool   `json:"active"`
+	LoggedIn     bool   `json:"logged_in"`
+}
+
+type ListProfilesResult struct {
+	Profiles []ProfileInfo `json:"profiles"`
+}
+
+func (s Service) requireProfiles() error {
+	if s.Profiles == nil {
+		return errors.New("profiles are not available")
+	}
+
+	return nil
+}
+
+func (s Service) ListProfiles(cfg ListProfilesConfig) (*ListProfilesResult, error) {
+	if err := s.requireProfiles(); err != nil {
+		return nil, err
+	}
+
+	list, _, err := s.Profiles.List()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ListProfilesResult{Profiles: make([]ProfileInfo, 0, len(list))}
+	for _, profile := range list {
+		loggedIn, err := s.profileLoggedIn(profile)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Profiles = append(result.Profiles, ProfileInfo{
+			Name:         profile.Name,
+			Host:         profile.Host,
+			Organization: profile.Organization,
+			Active:       profile.Name == s.Profile,
+			LoggedIn:     loggedIn,
+		})
+	}
+
+	if !cfg.Json {
+		s.printProfileList(result.Profiles)
+	}
+
+	return result, nil
+}
+
+func (s Service) printProfileList(list []ProfileInfo) {
+	if len(list) == 0 {
+		fmt.Fprintln(s.Stdout, "No profiles found. Create one with `rwx login --profile <name>`.")
+		return
+	}
+
+	fmt.Fprintf(s.Stdout, "  %-20s %-25s %s\n", "PROFILE", "HOST", "ORGANIZATION")
+	for _, profile := range list {
+		marker := " "
+		if profile.Active {
+			marker = "*"
+		}
+		organization := profile.Organization
+		if !profile.LoggedIn {
+			organization = "(logged out)"
+		}
+		fmt.Fprintf(s.Stdout, "%s %-20s %-25s %s\n", marker, profile.Name, profile.Host, organization)
+	}
+}
+
+// UseProfile makes a profile the default for subsequent commands.
+func (s Service) UseProfile(name string) error {
+	if err := s.requireProfiles(); err != nil {
+		return err
+	}
+
+	if err := s.Profiles.Use(name); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(s.Stdout, "Now using profile %q.\n", name)
+	return nil
+}
+
+// RemoveProfile deletes a profile along with its access token.
+func (s Service) RemoveProfile(name string) error {
+	if err := s.requireProfiles(); err != nil {
+		return err
+	}
+
+	profile, exists, err := s.Profiles.Get(name)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.Profiles.Current()
+	if err != nil {
+		return err
+	}
+
+	if helper := s.profileHelper(profile); exists && helper != nil {
+		if err := helper.Set(""); err != nil {
+			return errors.Wrapf(err, "unable to erase the access token of profile %q", name)
+		}
+	}
+
+	if err := s.Profiles.Remove(name); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(s.Stdout, "Removed profile %q.\n", name)
+	if current == name {
+		fmt.Fprintln(s.Stdout, "No profile is in use now. Choose another with `rwx profiles use <name>`.")
+	}
+	return nil
+}
+
+// profileHelper is the credential helper that keeps a profile's access token,
+// or nil when none is configured and the token is kept in the profile itself.
+func (s Service) profileHelper(profile profiles.Profile) *accesstoken.HelperBackend {
+	if s.Settings == nil {
+		return nil
+	}
+
+	command := s.Settings.Get("credential_helper")
+	if command == "" {
+		return nil
+	}
+
+	// Like the root command, the host from the environment wins over the
+	// profile's
+	host := profile.Host
+	if host == "" || s.Settings.Lookup("host").Source == config.SourceEnv {
+		host = s.Settings.Get("host")
+	}
+
+	return accesstoken.NewHelperBackend(command, host, profile.Name)
+}
+
+func (s Service) profileLoggedIn(profile profiles.Profile) (bool, error) {
+	helper := s.profileHelper(profile)
+	if helper == nil {
+		return profile.AccessToken != "", nil
+	}
+
+	token, err := helper.Get()
+	if err != nil {
+		return false, errors.Wrapf(err, "unable to get the access token of profile %q", profile.Name)
+	}
+	return token != "", nil
+}
+
+// recordProfileOrganization stores the organization of the token that was
+// just saved to a profile, and makes it the current profile when there is
+// none yet.
+func (s Service) recordProfileOrganization(ctx context.Context, name string) error {
+	if err := s.requireProfiles(); err != nil {
+		return err
+	}
+
+	profile, _, err := s.Profiles.Get(name)
+	if err != nil {
+		return err
+	}
+
+	whoami, err := s.APIClient.Whoami(ctx)
+	if err != nil {
+		return errors.Wrap(err, "unable to determine the organization of the new access token")
+	}
+	profile.Organization = whoami.OrganizationSlug
+
+	if err := s.Profiles.Save(profile); err != nil {
+		return err
+	}
+
+	current, err := s.Profiles.Current()
+	if err != nil {
+		return err
+	}
+	if current == "" {
+		return s.Profiles.Use(name)
+	}
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/rwx-cloud/rwx/internal/api"
+	"github.com/rwx-cloud/rwx/internal/cli"
+	"github.com/rwx-cloud/rwx/internal/config"
+	"github.com/rwx-cloud/rwx/internal/errors"
+	"github.com/rwx-cloud/rwx/internal/profiles"
+	"github.com/stretchr/testify/require"
+)
+
+func setupProfiles(t *testing.T, s *testSetup, active string) *profiles.Store {
+	store := profiles.NewStore(config.NewMemoryBackend())
+	s.config.Profiles = store
+	s.config.Profile = active
+
+	var err error
+	s.service, err = cli.NewService(s.config)
+	require.NoError(t, err)
+	return store
+}
+
+// setupProfileHelper configures a credential helper that keeps a token file
+// per profile in the returned directory.
+func setupProfileHelper(t *testing.T, s *testSetup) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the stub credential helper is a shell script")
+	}
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "helper")
+	require.NoError(t, os.WriteFile(script, []byte(`#!/bin/sh
+dir=$(dirname "$0")
+profile=$(sed -n 's/^profile=//p')
+case "$1" in
+  get)
+    if [ -f "$dir/$profile.token" ]; then printf 'token=%s\n' "$(cat "$dir/$profile.token")"; fi ;;
+  erase)
+    rm -f "$dir/$profile.token" ;;
+esac
+`), 0o755))
+
+	setupSettings(t, s, "credential_helper: "+script+"\n")
+	return dir
+}
+
+func TestService_ListProfiles(t *testing.T) {
+	t.Run("explains how to create a profile when there are none", func(t *testing.T) {
+		s := setupTest(t)
+		setupProfiles(t, s, "")
+
+		result, err := s.service.ListProfiles(cli.ListProfilesConfig{})
+		require.NoError(t, err)
+		require.Empty(t, result.Profiles)
+		require.Contains(t, s.mockStdout.String(), "rwx login --profile <name>")
+	})
+
+	t.Run("marks the active profile", func(t *testing.T) {
+		s := setupTest(t)
+		store := setupProfiles(t, s, "work")
+		require.NoError(t, store.Save(profiles.Profile{Name: "work", Host: "cloud.rwx.com", Organization: "acme", AccessToken: "token"}))
+		require.NoError(t, store.Save(profiles.Profile{Name: "personal", Host: "cloud.rwx.com"}))
+
+		result, err := s.service.ListProfiles(cli.ListProfilesConfig{})
+		require.NoError(t, err)
+		require.Equal(t, []cli.ProfileInfo{
+			{Name: "personal", Host: "cloud.rwx.com"},
+			{Name: "work", Host: "cloud.rwx.com", Organization: "acme", Active: true, LoggedIn: true},
+		}, result.Profiles)
+		require.Contains(t, s.mockStdout.String(), "* work")
+		require.Contains(t, s.mockStdout.String(), "(logged out)")
+	})
+
+	t.Run("asks the credential helper whether a profile is logged in", func(t *testing.T) {
+		s := setupTest(t)
+		dir := setupProfileHelper(t, s)
+		store := setupProfiles(t, s, "work")
+		require.NoError(t, store.Save(profiles.Profile{Name: "work", Host: "cloud.rwx.com", Organization: "acme"}))
+		require.NoError(t, store.Save(profiles.Profile{Name: "personal", Host: "cloud.rwx.com"}))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "work.token"), []byte("token"), 0o600))
+
+		result, err := s.service.ListProfiles(cli.ListProfilesConfig{})
+		require.NoError(t, err)
+		require.Equal(t, []cli.ProfileInfo{
+			{Name: "personal", Host: "cloud.rwx.com"},
+			{Name: "work", Host: "cloud.rwx.com", Organization: "acme", Active: true, LoggedIn: true},
+		}, result.Profiles)
+	})
+
+	t.Run("does not print when outputting json", func(t *testing.T) {
+		s := setupTest(t)
+		store := setupProfiles(t, s, "")
+		require.NoError(t, store.Save(profiles.Profile{Name: "work"}))
+
+		result, err := s.service.ListProfiles(cli.ListProfilesConfig{Json: true})
+		require.NoError(t, err)
+		require.Len(t, result.Profiles, 1)
+		require.Empty(t, s.mockStdout.String())
+	})
+}
+
+func TestService_UseProfile(t *testing.T) {
+	t.Run("makes the profile current", func(t *testing.T) {
+		s := setupTest(t)
+		store := setupProfiles(t, s, "")
+		require.NoError(t, store.Save(profiles.Profile{Name: "work"}))
+
+		require.NoError(t, s.service.UseProfile("work"))
+
+		current, err := store.Current()
+		require.NoError(t, err)
+		require.Equal(t, "work", current)
+		require.Contains(t, s.mockStdout.String(), `Now using profile "work".`)
+	})
+
+	t.Run("errors when the profile does not exist", func(t *testing.T) {
+		s := setupTest(t)
+		setupProfiles(t, s, "")
+
+		err := s.service.UseProfile("work")
+		require.ErrorIs(t, err, errors.ErrNotFound)
+	})
+}
+
+func TestService_RemoveProfile(t *testing.T) {
+	t.Run("removes the profile and says when none is in use", func(t *testing.T) {
+		s := setupTest(t)
+		store := setupProfiles(t, s, "work")
+		require.NoError(t, store.Save(profiles.Profile{Name: "work", AccessToken: "token"}))
+		require.NoError(t, store.Use("work"))
+
+		require.NoError(t, s.service.RemoveProfile("work"))
+
+		_, found, err := store.Get("work")
+		require.NoError(t, err)
+		require.False(t, found)
+		require.Contains(t, s.mockStdout.String(), "No profile is in use now.")
+	})
+
+	t.Run("erases the profile's token from the credential helper", func(t *testing.T) {
+		s := setupTest(t)
+		dir := setupProfileHelper(t, s)
+		store := setupProfiles(t, s, "")
+		require.NoError(t, store.Save(profiles.Profile{Name: "work", Host: "cloud.rwx.com"}))
+		require.NoError(t, store.Save(profiles.Profile{Name: "personal", Host: "cloud.rwx.com"}))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "work.token"), []byte("work-token"), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "personal.token"), []byte("personal-token"), 0o600))
+
+		require.NoError(t, s.service.RemoveProfile("work"))
+
+		require.NoFileExists(t, filepath.Join(dir, "work.token"))
+		require.FileExists(t, filepath.Join(dir, "personal.token"))
+	})
+
+	t.Run("errors without running the credential helper when the profile does not exist", func(t *testing.T) {
+		s := setupTest(t)
+		dir := setupProfileHelper(t, s)
+		setupProfiles(t, s, "")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "work.token"), []byte("work-token"), 0o600))
+
+		err := s.service.RemoveProfile("work")
+		require.ErrorIs(t, err, errors.ErrNotFound)
+		require.FileExists(t, filepath.Join(dir, "work.token"))
+	})
+}
+
+func TestService_LoggingInToProfile(t *testing.T) {
+	s := setupTest(t)
+	store := setupProfiles(t, s, "work")
+
+	s.mockAPI.MockObtainAuthCode = func(oacc api.ObtainAuthCodeConfig) (*api.ObtainAuthCodeResult, error) {
+		return &api.ObtainAuthCodeResult{
+			AuthorizationUrl: "https://cloud.local/_/auth/code?code=your-code",
+			TokenUrl:         "https://cloud.local/api/auth/codes/code-uuid/token",
+		}, nil
+	}
+	s.mockAPI.MockAcquireToken = func(tokenUrl string) (*api.AcquireTokenResult, error) {
+		return &api.AcquireTokenResult{State: "authorized", Token: "work-token"}, nil
+	}
+	s.mockAPI.MockWhoami = func() (*api.WhoamiResult, error) {
+		return &api.WhoamiResult{TokenKind: "personal_access_token", OrganizationSlug: "acme"}, nil
+	}
+
+	err := s.service.Login(t.Context(), cli.LoginConfig{
+		DeviceName:         "some-device",
+		AccessTokenBackend: profiles.NewTokenBackend(store, "work", "cloud.rwx.com", nil),
+		Profile:            "work",
+		OpenUrl:            func(url string) error { return nil },
+	})
+	require.NoError(t, err)
+
+	profile, found, err := store.Get("work")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Equal(t, "work-token", profile.AccessToken)
+	require.Equal(t, "cloud.rwx.com", profile.Host)
+	require.Equal(t, "acme", profile.Organization)
+
+	current, err := store.Current()
+	require.NoError(t, err)
+	require.Equal(t, "work", current)
+	require.Contains(t, s.mockStdout.String(), `Saved to profile "work".`)
+}
@@ -1,11 +1,13 @@
 package cli
 
+import "context"
+
 type GetRunPromptResult struct {
 	Prompt string
 }
 
-func (s Service) GetRunPrompt(runID string) (*GetRunPromptResult, error) {
-	prompt, err := s.APIClient.GetRunPrompt(runID)
+func (s Service) GetRunPrompt(ctx context.Context, runID string) (*GetRunPromptResult, error) {
+	prompt, err := s.APIClient.GetRunPrompt(ctx, runID)
 	if err != nil {
 		return nil, err
 	}
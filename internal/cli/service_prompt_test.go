@@ -16,7 +16,7 @@ func TestService_GetRunPrompt(t *testing.T) {
 			return "prompt text", nil
 		}
 
-		result, err := setup.service.GetRunPrompt("run-123")
+		result, err := setup.service.GetRunPrompt(t.Context(), "run-123")
 
 		require.NoError(t, err)
 		require.Equal(t, "prompt text", result.Prompt)
@@ -29,7 +29,7 @@ func TestService_GetRunPrompt(t *testing.T) {
 			return "", api.ErrNotFound
 		}
 
-		result, err := setup.service.GetRunPrompt("run-123")
+		result, err := setup.service.GetRunPrompt(t.Context(), "run-123")
 
 		require.Nil(t, result)
 		require.Error(t, err)
@@ -25,7 +25,7 @@ func TestService_ResolvingPackages(t *testing.T) {
 			err = os.WriteFile(filepath.Join(mintDir, "bar.json"), []byte("some json"), 0o644)
 			require.NoError(t, err)
 
-			_, err = s.service.ResolvePackages(cli.ResolvePackagesConfig{
+			_, err = s.service.ResolvePackages(t.Context(), cli.ResolvePackagesConfig{
 				RwxDirectory:        mintDir,
 				LatestVersionPicker: cli.PickLatestMajorVersion,
 			})
@@ -73,7 +73,7 @@ tasks:
 				}, nil
 			}
 
-			_, err = s.service.ResolvePackages(cli.ResolvePackagesConfig{
+			_, err = s.service.ResolvePackages(t.Context(), cli.ResolvePackagesConfig{
 				RwxDirectory:        mintDir,
 				LatestVersionPicker: cli.PickLatestMajorVersion,
 			})
@@ -106,7 +106,7 @@ tasks:
 			err := os.WriteFile(filepath.Join(s.tmp, "foo.yaml"), []byte(""), 0o644)
 			require.NoError(t, err)
 
-			_, err = s.service.ResolvePackages(cli.ResolvePackagesConfig{
+			_, err = s.service.ResolvePackages(t.Context(), cli.ResolvePackagesConfig{
 				RwxDirectory:        s.tmp,
 				LatestVersionPicker: cli.PickLatestMajorVersion,
 			})
@@ -131,7 +131,7 @@ tasks:
 `), 0o644)
 			require.NoError(t, err)
 
-			_, err = s.service.ResolvePackages(cli.ResolvePackagesConfig{
+			_, err = s.service.ResolvePackages(t.Context(), cli.ResolvePackagesConfig{
 				RwxDirectory:        s.tmp,
 				LatestVersionPicker: cli.PickLatestMajorVersion,
 			})
@@ -182,7 +182,7 @@ tasks:
 			require.NoError(t, err)
 
 			t.Run("updates all files", func(t *testing.T) {
-				_, err = s.service.ResolvePackages(cli.ResolvePackagesConfig{
+				_, err = s.service.ResolvePackages(t.Context(), cli.ResolvePackagesConfig{
 					RwxDirectory:        s.tmp,
 					LatestVersionPicker: cli.PickLatestMajorVersion,
 				})
@@ -215,7 +215,7 @@ tasks:
 				err = os.WriteFile(filepath.Join(s.tmp, "bar.yaml"), []byte(originalBarContents), 0o644)
 				require.NoError(t, err)
 
-				_, err = s.service.ResolvePackages(cli.ResolvePackagesConfig{
+				_, err = s.service.ResolvePackages(t.Context(), cli.ResolvePackagesConfig{
 					RwxDirectory:        s.tmp,
 					LatestVersionPicker: cli.PickLatestMajorVersion,
 				})
@@ -233,7 +233,7 @@ tasks:
 				err = os.WriteFile(filepath.Join(s.tmp, "bar.yaml"), []byte(originalBarContents), 0o644)
 				require.NoError(t, err)
 
-				_, err = s.service.ResolvePackages(cli.ResolvePackagesConfig{
+				_, err = s.service.ResolvePackages(t.Context(), cli.ResolvePackagesConfig{
 					RwxDirectory:        s.tmp,
 					Files:               []string{filepath.Join(s.tmp, "bar.yaml")},
 					LatestVersionPicker: cli.PickLatestMajorVersion,
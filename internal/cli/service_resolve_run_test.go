@@ -19,7 +19,7 @@ func TestResolveRunIDFromGitContext(t *testing.T) {
 			return api.RunStatusResult{RunID: "run-abc123"}, nil
 		}
 
-		runID, err := setup.service.ResolveRunIDFromGitContext()
+		runID, err := setup.service.ResolveRunIDFromGitContext(t.Context())
 		require.NoError(t, err)
 		require.Equal(t, "run-abc123", runID)
 	})
@@ -29,7 +29,7 @@ func TestResolveRunIDFromGitContext(t *testing.T) {
 		setup.mockGit.MockGetBranch = ""
 		setup.mockGit.MockGetOriginUrl = "git@github.com:rwx-cloud/rwx.git"
 
-		_, err := setup.service.ResolveRunIDFromGitContext()
+		_, err := setup.service.ResolveRunIDFromGitContext(t.Context())
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "unable to determine the current branch and repository from git")
 	})
@@ -39,7 +39,7 @@ func TestResolveRunIDFromGitContext(t *testing.T) {
 		setup.mockGit.MockGetBranch = "my-branch"
 		setup.mockGit.MockGetOriginUrl = ""
 
-		_, err := setup.service.ResolveRunIDFromGitContext()
+		_, err := setup.service.ResolveRunIDFromGitContext(t.Context())
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "unable to determine the current branch and repository from git")
 	})
@@ -52,7 +52,7 @@ func TestResolveRunIDFromGitContext(t *testing.T) {
 			return api.RunStatusResult{}, api.ErrNotFound
 		}
 
-		_, err := setup.service.ResolveRunIDFromGitContext()
+		_, err := setup.service.ResolveRunIDFromGitContext(t.Context())
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "no run found for rwx repository on branch my-branch")
 	})
@@ -67,7 +67,7 @@ func TestResolveRunIDFromGitContext(t *testing.T) {
 			return api.RunStatusResult{RunID: "run-override"}, nil
 		}
 
-		runID, err := setup.service.ResolveRunIDFromGitContext(cli.ResolveRunIDConfig{
+		runID, err := setup.service.ResolveRunIDFromGitContext(t.Context(), cli.ResolveRunIDConfig{
 			BranchName: "other-branch",
 		})
 		require.NoError(t, err)
@@ -84,7 +84,7 @@ func TestResolveRunIDFromGitContext(t *testing.T) {
 			return api.RunStatusResult{RunID: "run-repo-override"}, nil
 		}
 
-		runID, err := setup.service.ResolveRunIDFromGitContext(cli.ResolveRunIDConfig{
+		runID, err := setup.service.ResolveRunIDFromGitContext(t.Context(), cli.ResolveRunIDConfig{
 			RepositoryName: "other-repo",
 		})
 		require.NoError(t, err)
@@ -100,7 +100,7 @@ func TestResolveRunIDFromGitContext(t *testing.T) {
 			return api.RunStatusResult{RunID: "run-def"}, nil
 		}
 
-		runID, err := setup.service.ResolveRunIDFromGitContext(cli.ResolveRunIDConfig{
+		runID, err := setup.service.ResolveRunIDFromGitContext(t.Context(), cli.ResolveRunIDConfig{
 			DefinitionPath: ".rwx/ci.yml",
 		})
 		require.NoError(t, err)
@@ -115,7 +115,7 @@ func TestResolveRunIDFromGitContext(t *testing.T) {
 			return api.RunStatusResult{RunID: ""}, nil
 		}
 
-		_, err := setup.service.ResolveRunIDFromGitContext()
+		_, err := setup.service.ResolveRunIDFromGitContext(t.Context())
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "no run found for rwx repository on branch my-branch")
 	})
@@ -1,11 +1,14 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
+	"github.com/goccy/go-yaml/ast"
 	"github.com/rwx-cloud/rwx/internal/api"
 	"github.com/rwx-cloud/rwx/internal/errors"
 	"github.com/rwx-cloud/rwx/internal/git"
@@ -23,8 +26,16 @@ type InitiateRunConfig struct {
 	GitSha         string
 	Patchable      bool
 	CliState       string
+	Breakpoints    map[string]string
 }
 
+// Positions of a breakpoint added with InitiateRunConfig.Breakpoints, which
+// maps task keys to one of these.
+const (
+	BreakpointBefore = "before"
+	BreakpointAfter  = "after"
+)
+
 func (c InitiateRunConfig) Validate() error {
 	if c.MintFilePath == "" {
 		return errors.New("the path to a run definition must be provided using the --file flag.")
@@ -34,7 +45,7 @@ func (c InitiateRunConfig) Validate() error {
 }
 
 // InitiateRun will connect to the Cloud API and start a new run in Mint.
-func (s Service) InitiateRun(cfg InitiateRunConfig) (*api.InitiateRunResult, error) {
+func (s Service) InitiateRun(ctx context.Context, cfg InitiateRunConfig) (*api.InitiateRunResult, error) {
 	err := cfg.Validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "validation failed")
@@ -186,7 +197,7 @@ func (s Service) InitiateRun(cfg InitiateRunConfig) (*api.InitiateRunResult, err
 		fmt.Fprintln(s.Stderr, "")
 	}
 
-	addBaseIfNeeded, err := s.insertDefaultBaseIfMissing(runDefinition)
+	addBaseIfNeeded, err := s.insertDefaultBaseIfMissing(ctx, runDefinition)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to resolve base")
 	}
@@ -209,7 +220,7 @@ func (s Service) InitiateRun(cfg InitiateRunConfig) (*api.InitiateRunResult, err
 	mintFiles := filterYAMLFilesForModification(runDefinition, func(doc *YAMLDoc) bool {
 		return true
 	})
-	resolvedPackages, err := s.resolveOrUpdatePackagesForFiles(mintFiles, false, PickLatestMajorVersion)
+	resolvedPackages, err := s.resolveOrUpdatePackagesForFiles(ctx, mintFiles, false, PickLatestMajorVersion)
 	if err != nil {
 		return nil, err
 	}
@@ -224,6 +235,12 @@ func (s Service) InitiateRun(cfg InitiateRunConfig) (*api.InitiateRunResult, err
 		}
 	}
 
+	if len(cfg.Breakpoints) > 0 {
+		if err := addBreakpoints(runDefinition, cfg.Breakpoints); err != nil {
+			return nil, err
+		}
+	}
+
 	i := 0
 	initializationParameters := make([]api.InitializationParameter, len(cfg.InitParameters))
 	for key, value := range cfg.InitParameters {
@@ -235,7 +252,7 @@ func (s Service) InitiateRun(cfg InitiateRunConfig) (*api.InitiateRunResult, err
 	}
 
 	initiateStart := time.Now()
-	runResult, err := s.APIClient.InitiateRun(api.InitiateRunConfig{
+	runResult, err := s.APIClient.InitiateRun(ctx, api.InitiateRunConfig{
 		InitializationParameters: initializationParameters,
 		TaskDefinitions:          runDefinition,
 		RwxDirectory:             rwxDirectory,
@@ -273,3 +290,49 @@ func (s Service) InitiateRun(cfg InitiateRunConfig) (*api.InitiateRunResult, err
 
 	return runResult, nil
 }
+
+// addBreakpoints sets `breakpoint` on the tasks with the given keys in the
+// run definition's contents. The run definition file itself isn't changed.
+func addBreakpoints(runDefinition []RwxDirectoryEntry, breakpoints map[string]string) error {
+	for i, entry := range runDefinition {
+		doc, err := ParseYAMLDoc(entry.FileContents)
+		if err != nil {
+			return errors.Wrapf(err, "unable to add breakpoints to %q", entry.Path)
+		}
+
+		taskIndexes := make(map[string]int)
+		index := 0
+		err = doc.ForEachNode("$.tasks[*]", func(node ast.Node) error {
+			key := doc.TryReadStringAtPath(fmt.Sprintf("$.tasks[%d].key", index))
+			if _, exists := taskIndexes[key]; !exists {
+				taskIndexes[key] = index
+			}
+			index++
+			return nil
+		})
+		if err != nil {
+			return errors.Wrapf(err, "unable to add breakpoints to %q", entry.Path)
+		}
+
+		keys := make([]string, 0, len(breakpoints))
+		for key := range breakpoints {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			index, ok := taskIndexes[key]
+			if !ok {
+				return fmt.Errorf("unable to add a breakpoint to task %q: no task with that key in %q", key, entry.Path)
+			}
+
+			if err := doc.SetAtPath(fmt.Sprintf("$.tasks[%d].breakpoint", index), breakpoints[key]); err != nil {
+				return errors.Wrapf(err, "unable to add a breakpoint to task %q", key)
+			}
+		}
+
+		runDefinition[i].FileContents = doc.String()
+	}
+
+	return nil
+}
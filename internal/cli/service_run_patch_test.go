@@ -66,7 +66,7 @@ func initiateRun(t *testing.T, patchFile git.PatchFile, expectedPatchMetadata ap
 			DefinitionPath:   ".mint/mint.yml",
 		}, nil
 	}
-	_, err = s.service.InitiateRun(runConfig)
+	_, err = s.service.InitiateRun(t.Context(), runConfig)
 	require.NoError(t, err)
 	return initiateRunResult{rwxDir: receivedRwxDir, stderr: s.mockStderr.String()}
 }
@@ -106,7 +106,7 @@ func TestService_InitiatingRunPatch(t *testing.T) {
 			}, nil
 		}
 
-		_, err = s.service.InitiateRun(cli.InitiateRunConfig{
+		_, err = s.service.InitiateRun(t.Context(), cli.InitiateRunConfig{
 			RwxDirectory: rwxDir,
 			MintFilePath: definitionsFile,
 		})
@@ -147,7 +147,7 @@ func TestService_InitiatingRunPatch(t *testing.T) {
 			}, nil
 		}
 
-		_, err = s.service.InitiateRun(cli.InitiateRunConfig{
+		_, err = s.service.InitiateRun(t.Context(), cli.InitiateRunConfig{
 			RwxDirectory: rwxDir,
 			MintFilePath: definitionsFile,
 		})
@@ -189,7 +189,7 @@ func TestService_InitiatingRunPatch(t *testing.T) {
 			}, nil
 		}
 
-		_, err = s.service.InitiateRun(cli.InitiateRunConfig{
+		_, err = s.service.InitiateRun(t.Context(), cli.InitiateRunConfig{
 			RwxDirectory: rwxDir,
 			MintFilePath: definitionsFile,
 		})
@@ -232,7 +232,7 @@ func TestService_InitiatingRunPatch(t *testing.T) {
 			}, nil
 		}
 
-		_, err = s.service.InitiateRun(cli.InitiateRunConfig{
+		_, err = s.service.InitiateRun(t.Context(), cli.InitiateRunConfig{
 			RwxDirectory: rwxDir,
 			MintFilePath: definitionsFile,
 			Patchable:    true,
@@ -425,7 +425,7 @@ func TestService_InitiatingRunPatch(t *testing.T) {
 				}, nil
 			}
 
-			_, err = s.service.InitiateRun(runConfig)
+			_, err = s.service.InitiateRun(t.Context(), runConfig)
 			require.NoError(t, err)
 
 			// Verify patch generation was skipped entirely — no .patches entries in the rwx directory
@@ -114,7 +114,7 @@ func TestService_InitiatingRun(t *testing.T) {
 					}, nil
 				}
 
-				_, err = s.service.InitiateRun(runConfig)
+				_, err = s.service.InitiateRun(t.Context(), runConfig)
 				require.NoError(t, err)
 
 				require.Equal(t, originalSpecifiedFileContent, receivedSpecifiedFileContent)
@@ -187,7 +187,7 @@ func TestService_InitiatingRun(t *testing.T) {
 					}, nil
 				}
 
-				_, err = s.service.InitiateRun(runConfig)
+				_, err = s.service.InitiateRun(t.Context(), runConfig)
 				require.NoError(t, err)
 
 				require.Equal(t, originalSpecifiedFileContent, receivedSpecifiedFileContent)
@@ -249,7 +249,7 @@ func TestService_InitiatingRun(t *testing.T) {
 					}, nil
 				}
 
-				_, err = s.service.InitiateRun(runConfig)
+				_, err = s.service.InitiateRun(t.Context(), runConfig)
 				require.NoError(t, err)
 
 				// Verify temp .rwx directory was cleaned up
@@ -346,7 +346,7 @@ func TestService_InitiatingRun(t *testing.T) {
 					}, nil
 				}
 
-				_, err = s.service.InitiateRun(runConfig)
+				_, err = s.service.InitiateRun(t.Context(), runConfig)
 				require.NoError(t, err)
 
 				require.Equal(t, originalSpecifiedFileContent, receivedSpecifiedFileContent)
@@ -416,7 +416,7 @@ func TestService_InitiatingRun(t *testing.T) {
 					}, nil
 				}
 
-				_, err = s.service.InitiateRun(runConfig)
+				_, err = s.service.InitiateRun(t.Context(), runConfig)
 				require.NoError(t, err)
 
 				require.Equal(t, originalSpecifiedFileContent, receivedSpecifiedFileContent)
@@ -478,7 +478,7 @@ func TestService_InitiatingRun(t *testing.T) {
 					}, nil
 				}
 
-				_, err = s.service.InitiateRun(runConfig)
+				_, err = s.service.InitiateRun(t.Context(), runConfig)
 				require.NoError(t, err)
 
 				// Verify temp .rwx directory was cleaned up
@@ -495,6 +495,49 @@ func TestService_InitiatingRun(t *testing.T) {
 				require.False(t, getDefaultBaseCalled)
 			})
 
+			t.Run("when the directory includes the project's cli.yml", func(t *testing.T) {
+				s := setupTest(t)
+
+				baseSpec := "base:\n  image: ubuntu:24.04\n  config: rwx/base 1.0.0\n"
+
+				s.mockAPI.MockGetPackageVersions = func() (*api.PackageVersionsResult, error) {
+					return &api.PackageVersionsResult{
+						LatestMajor: make(map[string]string),
+						LatestMinor: make(map[string]map[string]string),
+					}, nil
+				}
+
+				rwxDir := filepath.Join(s.tmp, ".rwx")
+				err := os.MkdirAll(rwxDir, 0o755)
+				require.NoError(t, err)
+
+				err = os.WriteFile(filepath.Join(rwxDir, "ci.yml"), []byte("tasks:\n  - key: foo\n    run: echo 'bar'\n"+baseSpec), 0o644)
+				require.NoError(t, err)
+
+				err = os.WriteFile(filepath.Join(rwxDir, "cli.yml"), []byte("vault: team\n"), 0o644)
+				require.NoError(t, err)
+
+				var receivedRwxDir []api.RwxDirectoryEntry
+				s.mockAPI.MockInitiateRun = func(cfg api.InitiateRunConfig) (*api.InitiateRunResult, error) {
+					receivedRwxDir = cfg.RwxDirectory
+					return &api.InitiateRunResult{
+						RunID:            "785ce4e8-17b9-4c8b-8869-a55e95adffe7",
+						RunURL:           "https://cloud.rwx.com/mint/rwx/runs/785ce4e8-17b9-4c8b-8869-a55e95adffe7",
+						TargetedTaskKeys: []string{},
+						DefinitionPath:   ".rwx/ci.yml",
+					}, nil
+				}
+
+				_, err = s.service.InitiateRun(t.Context(), cli.InitiateRunConfig{MintFilePath: ".rwx/ci.yml"})
+				require.NoError(t, err)
+
+				paths := make([]string, 0, len(receivedRwxDir))
+				for _, entry := range receivedRwxDir {
+					paths = append(paths, entry.Path)
+				}
+				require.Equal(t, []string{".", "ci.yml"}, paths)
+			})
+
 			t.Run("when the directory includes a test-suites directory inside it", func(t *testing.T) {
 				s := setupTest(t)
 
@@ -580,7 +623,7 @@ func TestService_InitiatingRun(t *testing.T) {
 					}, nil
 				}
 
-				_, err = s.service.InitiateRun(runConfig)
+				_, err = s.service.InitiateRun(t.Context(), runConfig)
 				require.NoError(t, err)
 
 				require.Equal(t, originalSpecifiedFileContent, receivedSpecifiedFileContent)
@@ -648,7 +691,7 @@ func TestService_InitiatingRun(t *testing.T) {
 				}, nil
 			}
 
-			_, err = s.service.InitiateRun(runConfig)
+			_, err = s.service.InitiateRun(t.Context(), runConfig)
 			require.NoError(t, err)
 
 			require.True(t, getDefaultBaseCalled)
@@ -714,7 +757,7 @@ func TestService_InitiatingRun(t *testing.T) {
 				}, nil
 			}
 
-			_, err = s.service.InitiateRun(runConfig)
+			_, err = s.service.InitiateRun(t.Context(), runConfig)
 			require.NoError(t, err)
 
 			require.True(t, getPackageVersionsCalled)
@@ -732,7 +775,7 @@ func TestService_InitiatingRun(t *testing.T) {
 			RwxDirectory: "",
 		}
 
-		_, err := s.service.InitiateRun(runConfig)
+		_, err := s.service.InitiateRun(t.Context(), runConfig)
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "the path to a run definition must be provided")
@@ -806,7 +849,7 @@ func TestService_InitiatingRun(t *testing.T) {
 				}, nil
 			}
 
-			_, err = s.service.InitiateRun(runConfig)
+			_, err = s.service.InitiateRun(t.Context(), runConfig)
 			require.NoError(t, err)
 
 			require.Equal(t, originalSpecifiedFileContent, receivedSpecifiedFileContent)
@@ -872,7 +915,7 @@ func TestService_InitiatingRun(t *testing.T) {
 				}, nil
 			}
 
-			_, err = s.service.InitiateRun(runConfig)
+			_, err = s.service.InitiateRun(t.Context(), runConfig)
 			require.NoError(t, err)
 
 			require.Equal(t, originalSpecifiedFileContent, receivedSpecifiedFileContent)
@@ -900,7 +943,7 @@ func TestService_InitiatingRun(t *testing.T) {
 			runConfig.MintFilePath = "mint.yml"
 			runConfig.RwxDirectory = mintDir
 
-			_, err = s.service.InitiateRun(runConfig)
+			_, err = s.service.InitiateRun(t.Context(), runConfig)
 
 			require.Error(t, err)
 			require.Contains(t, err.Error(), "is not a directory")
@@ -929,7 +972,7 @@ func TestService_InitiatingRun(t *testing.T) {
 			runConfig.MintFilePath = "mint.yml"
 			runConfig.RwxDirectory = mintDir
 
-			_, err = s.service.InitiateRun(runConfig)
+			_, err = s.service.InitiateRun(t.Context(), runConfig)
 
 			require.Error(t, err)
 			require.Contains(t, err.Error(), "unable to find .rwx directory")
@@ -944,7 +987,7 @@ func TestService_InitiatingRun(t *testing.T) {
 			RwxDirectory: "some-dir",
 		}
 
-		_, err := s.service.InitiateRun(runConfig)
+		_, err := s.service.InitiateRun(t.Context(), runConfig)
 
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "the path to a run definition must be provided")
@@ -981,7 +1024,7 @@ func TestService_InitiatingRun(t *testing.T) {
 		runConfig.RwxDirectory = ".mint"
 
 		// This should not crash even when base resolution fails
-		_, err = s.service.InitiateRun(runConfig)
+		_, err = s.service.InitiateRun(t.Context(), runConfig)
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "unable to resolve base")
 
@@ -1055,7 +1098,7 @@ tasks:
 			}, nil
 		}
 
-		_, err = s.service.InitiateRun(runConfig)
+		_, err = s.service.InitiateRun(t.Context(), runConfig)
 		require.NoError(t, err)
 
 		require.Contains(t, receivedFileContent, "cli:")
@@ -1067,3 +1110,64 @@ tasks:
 		require.Contains(t, string(modifiedContent), "cli:")
 	})
 }
+
+func TestService_InitiatingRunWithBreakpoints(t *testing.T) {
+	setupBreakpointRun := func(t *testing.T) (*testSetup, string, *string) {
+		s := setupTest(t)
+
+		s.mockAPI.MockGetPackageVersions = func() (*api.PackageVersionsResult, error) {
+			return &api.PackageVersionsResult{
+				LatestMajor: make(map[string]string),
+				LatestMinor: make(map[string]map[string]string),
+			}, nil
+		}
+
+		content := "on:\n  cli:\n    init:\n      sha: ${{ event.git.sha }}\n\nbase:\n  image: ubuntu:24.04\n  config: rwx/base 1.0.0\n\ntasks:\n  - key: lint\n    run: echo lint\n  - key: test\n    run: echo test\n"
+		rwxDir := filepath.Join(s.tmp, ".rwx")
+		require.NoError(t, os.MkdirAll(rwxDir, 0o755))
+		definitionPath := filepath.Join(rwxDir, "ci.yml")
+		require.NoError(t, os.WriteFile(definitionPath, []byte(content), 0o644))
+
+		var received string
+		s.mockAPI.MockInitiateRun = func(cfg api.InitiateRunConfig) (*api.InitiateRunResult, error) {
+			received = cfg.TaskDefinitions[0].FileContents
+			return &api.InitiateRunResult{RunID: "run-123", RunURL: "https://cloud.rwx.com/mint/rwx/runs/run-123"}, nil
+		}
+
+		return s, definitionPath, &received
+	}
+
+	t.Run("adds breakpoints to the tasks in the definition that is sent", func(t *testing.T) {
+		s, definitionPath, received := setupBreakpointRun(t)
+		before, err := os.ReadFile(definitionPath)
+		require.NoError(t, err)
+
+		_, err = s.service.InitiateRun(t.Context(), cli.InitiateRunConfig{
+			MintFilePath: definitionPath,
+			Breakpoints:  map[string]string{"test": cli.BreakpointAfter, "lint": cli.BreakpointBefore},
+		})
+		require.NoError(t, err)
+
+		doc, err := cli.ParseYAMLDoc(*received)
+		require.NoError(t, err)
+		require.Equal(t, "before", doc.TryReadStringAtPath("$.tasks[0].breakpoint"))
+		require.Equal(t, "lint", doc.TryReadStringAtPath("$.tasks[0].key"))
+		require.Equal(t, "after", doc.TryReadStringAtPath("$.tasks[1].breakpoint"))
+		require.Equal(t, "echo test", doc.TryReadStringAtPath("$.tasks[1].run"))
+
+		after, err := os.ReadFile(definitionPath)
+		require.NoError(t, err)
+		require.Equal(t, string(before), string(after))
+	})
+
+	t.Run("errors when a task key isn't in the definition", func(t *testing.T) {
+		s, definitionPath, _ := setupBreakpointRun(t)
+
+		_, err := s.service.InitiateRun(t.Context(), cli.InitiateRunConfig{
+			MintFilePath: definitionPath,
+			Breakpoints:  map[string]string{"build": cli.BreakpointBefore},
+		})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `unable to add a breakpoint to task "build"`)
+	})
+}
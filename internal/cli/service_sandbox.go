@@ -2,13 +2,17 @@ package cli
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"sync/atomic"
+	"syscall"
 	"time"
 
-	"al.essio.dev/pkg/shellescape"
 	"github.com/rwx-cloud/rwx/internal/api"
 	"github.com/rwx-cloud/rwx/internal/errors"
 	"github.com/rwx-cloud/rwx/internal/git"
@@ -25,6 +29,7 @@ const (
 
 type StartSandboxConfig struct {
 	ConfigFile     string
+	Name           string
 	RunID          string
 	RwxDirectory   string
 	Json           bool
@@ -45,12 +50,20 @@ func StartSandboxConfigWithLock(cfg StartSandboxConfig, lock *SandboxStorageLock
 
 type ExecSandboxConfig struct {
 	ConfigFile     string
+	Name           string
 	Command        []string
 	RunID          string
 	RwxDirectory   string
 	Json           bool
 	Sync           bool
+	AutoReset      bool
+	Detach         bool
+	Timeout        time.Duration
+	Env            []string
+	EnvFile        string
+	WorkDir        string
 	InitParameters map[string]string
+	Record         string
 }
 
 type ListSandboxesConfig struct {
@@ -59,12 +72,14 @@ type ListSandboxesConfig struct {
 
 type StopSandboxConfig struct {
 	RunID string
+	Name  string
 	All   bool
 	Json  bool
 }
 
 type ResetSandboxConfig struct {
 	ConfigFile     string
+	Name           string
 	RwxDirectory   string
 	Json           bool
 	Wait           bool
@@ -77,6 +92,7 @@ type StartSandboxResult struct {
 	RunID      string
 	RunURL     string
 	ConfigFile string
+	Name       string
 }
 
 type ExecSandboxResult struct {
@@ -84,6 +100,8 @@ type ExecSandboxResult struct {
 	ExitCode    int
 	RunURL      string
 	PulledFiles []string
+	JobID       string
+	TimedOut    bool
 }
 
 type ListSandboxesResult struct {
@@ -94,6 +112,7 @@ type SandboxInfo struct {
 	RunID      string
 	Status     string
 	ConfigFile string
+	Name       string
 	Branch     string
 }
 
@@ -122,8 +141,8 @@ type GetSandboxInitTemplateResult struct {
 
 // Service methods
 
-func (s Service) GetSandboxInitTemplate(cfg GetSandboxInitTemplateConfig) (*GetSandboxInitTemplateResult, error) {
-	result, err := s.APIClient.GetSandboxInitTemplate()
+func (s Service) GetSandboxInitTemplate(ctx context.Context, cfg GetSandboxInitTemplateConfig) (*GetSandboxInitTemplateResult, error) {
+	result, err := s.APIClient.GetSandboxInitTemplate(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to fetch sandbox init template")
 	}
@@ -141,7 +160,7 @@ type CheckExistingSandboxResult struct {
 	ConfigFile string
 }
 
-func (s Service) CheckExistingSandbox(configFile string) (*CheckExistingSandboxResult, error) {
+func (s Service) CheckExistingSandbox(ctx context.Context, configFile, name string) (*CheckExistingSandboxResult, error) {
 	cwd, err := os.Getwd()
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to get current directory")
@@ -153,10 +172,10 @@ func (s Service) CheckExistingSandbox(configFile string) (*CheckExistingSandboxR
 		return &CheckExistingSandboxResult{Exists: false}, nil
 	}
 
-	session, found := storage.GetSession(branch, configFile)
+	session, found := storage.GetSession(branch, configFile, name)
 	if !found && IsDetachedBranch(branch) {
 		gitClient := &git.Client{Binary: "git", Dir: cwd}
-		session, found = storage.GetSessionByAncestry(branch, configFile, gitClient)
+		session, found = storage.GetSessionByAncestry(branch, configFile, name, gitClient)
 		if found {
 			_ = storage.Save()
 		}
@@ -166,7 +185,10 @@ func (s Service) CheckExistingSandbox(configFile string) (*CheckExistingSandboxR
 	}
 
 	// Check if the run is still active (use scoped token if available)
-	connInfo, err := s.APIClient.GetSandboxConnectionInfo(session.RunID, session.ScopedToken)
+	connInfo, refreshed, err := s.sandboxConnectionInfo(ctx, session)
+	if refreshed {
+		s.saveSessionToken(*session)
+	}
 	if err != nil {
 		// Can't check status, treat as not active
 		return &CheckExistingSandboxResult{
@@ -197,7 +219,14 @@ func (s Service) CheckExistingSandbox(configFile string) (*CheckExistingSandboxR
 	}, nil
 }
 
-func (s Service) StartSandbox(cfg StartSandboxConfig) (*StartSandboxResult, error) {
+func (s Service) StartSandbox(ctx context.Context, cfg StartSandboxConfig) (*StartSandboxResult, error) {
+	if err := ValidateSandboxName(cfg.Name); err != nil {
+		if cfg.storageLock != nil {
+			UnlockSandboxStorage(cfg.storageLock)
+		}
+		return nil, err
+	}
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to get current directory")
@@ -211,15 +240,18 @@ func (s Service) StartSandbox(cfg StartSandboxConfig) (*StartSandboxResult, erro
 			UnlockSandboxStorage(cfg.storageLock)
 		}
 		// Check if we have an existing session with a scoped token
-		var existingScopedToken string
+		existingSession := &SandboxSession{RunID: cfg.RunID}
 		storage, err := LoadSandboxStorage()
 		if err == nil {
-			if existingSession, _, found := storage.FindByRunID(cfg.RunID); found {
-				existingScopedToken = existingSession.ScopedToken
+			if session, _, found := storage.FindByRunID(cfg.RunID); found {
+				existingSession = session
 			}
 		}
 
-		connInfo, err := s.APIClient.GetSandboxConnectionInfo(cfg.RunID, existingScopedToken)
+		connInfo, refreshed, err := s.sandboxConnectionInfo(ctx, existingSession)
+		if refreshed {
+			s.saveSessionToken(*existingSession)
+		}
 		if err != nil {
 			return nil, errors.Wrapf(err, "unable to get sandbox info for %s", cfg.RunID)
 		}
@@ -230,7 +262,7 @@ func (s Service) StartSandbox(cfg StartSandboxConfig) (*StartSandboxResult, erro
 
 		// Only wait for sandbox to be ready if --wait flag is set
 		if cfg.Wait && !connInfo.Sandboxable {
-			if _, err := s.waitForSandboxReadyWithToken(cfg.RunID, existingScopedToken, cfg.Json); err != nil {
+			if _, err := s.waitForSandboxReadyWithToken(ctx, cfg.RunID, existingSession.ScopedToken, cfg.Json); err != nil {
 				return nil, err
 			}
 		}
@@ -239,14 +271,9 @@ func (s Service) StartSandbox(cfg StartSandboxConfig) (*StartSandboxResult, erro
 		if storage != nil {
 			if _, _, found := storage.FindByRunID(cfg.RunID); !found {
 				// Create a scoped token for this reattached session
-				var scopedToken string
-				tokenResult, err := s.APIClient.CreateSandboxToken(api.CreateSandboxTokenConfig{
-					RunID: cfg.RunID,
-				})
+				scopedToken, tokenExpiresAt, err := s.createSandboxToken(ctx, cfg.RunID)
 				if err != nil {
 					fmt.Fprintf(s.Stderr, "Warning: Unable to create scoped token: %v\n", err)
-				} else {
-					scopedToken = tokenResult.Token
 				}
 
 				lockFile, lockErr := s.lockSandboxStorageWithInfo(cfg.Json)
@@ -258,11 +285,12 @@ func (s Service) StartSandbox(cfg StartSandboxConfig) (*StartSandboxResult, erro
 					if err != nil {
 						fmt.Fprintf(s.Stderr, "Warning: Unable to load sandbox sessions: %v\n", err)
 					} else {
-						storage.SetSession(branch, cfg.ConfigFile, SandboxSession{
-							RunID:       cfg.RunID,
-							ConfigFile:  cfg.ConfigFile,
-							ScopedToken: scopedToken,
-							ConfigHash:  HashConfigFile(cfg.ConfigFile),
+						storage.SetSession(branch, cfg.ConfigFile, cfg.Name, SandboxSession{
+							RunID:          cfg.RunID,
+							ConfigFile:     cfg.ConfigFile,
+							ScopedToken:    scopedToken,
+							TokenExpiresAt: tokenExpiresAt,
+							ConfigHash:     HashConfigFile(cfg.ConfigFile),
 						})
 						if err := storage.Save(); err != nil {
 							fmt.Fprintf(s.Stderr, "Warning: Unable to save sandbox session: %v\n", err)
@@ -290,6 +318,7 @@ func (s Service) StartSandbox(cfg StartSandboxConfig) (*StartSandboxResult, erro
 			RunID:      cfg.RunID,
 			RunURL:     runURL,
 			ConfigFile: cfg.ConfigFile,
+			Name:       cfg.Name,
 		}, nil
 	}
 
@@ -300,16 +329,16 @@ func (s Service) StartSandbox(cfg StartSandboxConfig) (*StartSandboxResult, erro
 	}
 
 	// Construct a descriptive title for the sandbox run
-	title := SandboxTitle(cwd, branch, cfg.ConfigFile)
+	title := SandboxTitle(cwd, branch, cfg.ConfigFile, cfg.Name)
 
-	runResult, err := s.InitiateRun(InitiateRunConfig{
+	runResult, err := s.InitiateRun(ctx, InitiateRunConfig{
 		MintFilePath:   cfg.ConfigFile,
 		RwxDirectory:   cfg.RwxDirectory,
 		Json:           cfg.Json,
 		Title:          title,
 		InitParameters: cfg.InitParameters,
 		Patchable:      false,
-		CliState:       EncodeCliState(branch, cfg.ConfigFile),
+		CliState:       EncodeCliState(branch, cfg.ConfigFile, cfg.Name),
 	})
 
 	if err != nil {
@@ -343,7 +372,7 @@ func (s Service) StartSandbox(cfg StartSandboxConfig) (*StartSandboxResult, erro
 	if err != nil {
 		fmt.Fprintf(s.Stderr, "Warning: Unable to load sandbox sessions: %v\n", err)
 	} else {
-		storage.SetSession(branch, cfg.ConfigFile, SandboxSession{
+		storage.SetSession(branch, cfg.ConfigFile, cfg.Name, SandboxSession{
 			RunID:      runResult.RunID,
 			ConfigFile: cfg.ConfigFile,
 			RunURL:     runResult.RunURL,
@@ -358,14 +387,9 @@ func (s Service) StartSandbox(cfg StartSandboxConfig) (*StartSandboxResult, erro
 	UnlockSandboxStorage(lockFile)
 
 	// Request a scoped token for this run
-	var scopedToken string
-	tokenResult, err := s.APIClient.CreateSandboxToken(api.CreateSandboxTokenConfig{
-		RunID: runResult.RunID,
-	})
+	scopedToken, tokenExpiresAt, err := s.createSandboxToken(ctx, runResult.RunID)
 	if err != nil {
 		fmt.Fprintf(s.Stderr, "Warning: Unable to create scoped token: %v\n", err)
-	} else {
-		scopedToken = tokenResult.Token
 	}
 
 	// Update session with scoped token now that we have it
@@ -381,13 +405,14 @@ func (s Service) StartSandbox(cfg StartSandboxConfig) (*StartSandboxResult, erro
 		if err != nil {
 			fmt.Fprintf(s.Stderr, "Warning: Unable to load sandbox sessions: %v\n", err)
 		} else {
-			storage.SetSession(branch, cfg.ConfigFile, SandboxSession{
-				RunID:       runResult.RunID,
-				ConfigFile:  cfg.ConfigFile,
-				ScopedToken: scopedToken,
-				RunURL:      runResult.RunURL,
-				ConfigHash:  HashConfigFile(cfg.ConfigFile),
-				CreatedAt:   &now,
+			storage.SetSession(branch, cfg.ConfigFile, cfg.Name, SandboxSession{
+				RunID:          runResult.RunID,
+				ConfigFile:     cfg.ConfigFile,
+				ScopedToken:    scopedToken,
+				TokenExpiresAt: tokenExpiresAt,
+				RunURL:         runResult.RunURL,
+				ConfigHash:     HashConfigFile(cfg.ConfigFile),
+				CreatedAt:      &now,
 			})
 			if err := storage.Save(); err != nil {
 				fmt.Fprintf(s.Stderr, "Warning: Unable to save sandbox session: %v\n", err)
@@ -406,11 +431,12 @@ func (s Service) StartSandbox(cfg StartSandboxConfig) (*StartSandboxResult, erro
 		RunID:      runResult.RunID,
 		RunURL:     runResult.RunURL,
 		ConfigFile: cfg.ConfigFile,
+		Name:       cfg.Name,
 	}
 
 	// Only wait for sandbox to be ready if --wait flag is set
 	if cfg.Wait {
-		_, err = s.waitForSandboxReadyWithToken(runResult.RunID, scopedToken, cfg.Json)
+		_, err = s.waitForSandboxReadyWithToken(ctx, runResult.RunID, scopedToken, cfg.Json)
 		if err != nil {
 			// Return result WITH error so caller can still use the URL
 			return result, err
@@ -420,8 +446,20 @@ func (s Service) StartSandbox(cfg StartSandboxConfig) (*StartSandboxResult, erro
 	return result, nil
 }
 
-func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error) {
+func (s Service) ExecSandbox(ctx context.Context, cfg ExecSandboxConfig) (*ExecSandboxResult, error) {
 	execStart := time.Now()
+
+	if err := ValidateSandboxName(cfg.Name); err != nil {
+		return nil, err
+	}
+
+	// Resolve the environment up front so a bad --env or --env-file fails
+	// before a sandbox is started or locked.
+	env, err := ParseSandboxEnv(cfg.Env, cfg.EnvFile)
+	if err != nil {
+		return nil, err
+	}
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to get current directory")
@@ -433,6 +471,9 @@ func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error)
 	var scopedToken string
 	var sessionRunURL string
 	var storedConfigHash string
+	// resettable is set when the sandbox is the stored session for this
+	// branch, which --auto-reset can replace
+	var resettable bool
 
 	// Sandbox selection priority:
 	// 1. --id flag
@@ -444,10 +485,20 @@ func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error)
 		runID = cfg.RunID
 		configFile = cfg.ConfigFile
 
-		// Look up scoped token from storage if session exists
+		// Look up scoped token from storage if session exists, replacing it
+		// first if it is about to expire
 		storage, err := LoadSandboxStorage()
 		if err == nil {
-			if existingSession, _, found := storage.FindByRunID(cfg.RunID); found {
+			if existingSession, key, found := storage.FindByRunID(cfg.RunID); found {
+				sessionBranch, _, _ := ParseSessionKey(key)
+				resettable = sessionBranch == branch && existingSession.ConfigFile == configFile && existingSession.Name == cfg.Name
+				if existingSession.ScopedTokenExpiring(time.Now()) {
+					if err := s.refreshSessionToken(ctx, existingSession); err != nil {
+						fmt.Fprintf(s.Stderr, "Warning: %v\n", err)
+					} else {
+						s.saveSessionToken(*existingSession)
+					}
+				}
 				scopedToken = existingSession.ScopedToken
 				sessionRunURL = existingSession.RunURL
 				storedConfigHash = existingSession.ConfigHash
@@ -475,23 +526,27 @@ func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error)
 
 		if cfg.ConfigFile != "" {
 			// Config file provided - look up specific session
-			session, found = storage.GetSession(branch, cfg.ConfigFile)
+			session, found = storage.GetSession(branch, cfg.ConfigFile, cfg.Name)
 			if !found && IsDetachedBranch(branch) {
 				gitClient := &git.Client{Binary: "git", Dir: cwd}
-				session, found = storage.GetSessionByAncestry(branch, cfg.ConfigFile, gitClient)
+				session, found = storage.GetSessionByAncestry(branch, cfg.ConfigFile, cfg.Name, gitClient)
 				if found {
 					_ = storage.Save()
 				}
 			}
 			if found {
 				// Check if session is still valid (use scoped token if available)
-				connInfo, err := s.APIClient.GetSandboxConnectionInfo(session.RunID, session.ScopedToken)
+				connInfo, refreshed, err := s.sandboxConnectionInfo(ctx, session)
+				if refreshed {
+					storage.UpdateScopedToken(session.RunID, session.ScopedToken, session.TokenExpiresAt)
+					_ = storage.Save()
+				}
 				if err != nil {
-					storage.DeleteSession(branch, cfg.ConfigFile)
+					storage.DeleteSession(branch, cfg.ConfigFile, cfg.Name)
 					_ = storage.Save()
 					found = false
 				} else if connInfo.Polling.Completed {
-					storage.DeleteSession(branch, cfg.ConfigFile)
+					storage.DeleteSession(branch, cfg.ConfigFile, cfg.Name)
 					_ = storage.Save()
 					found = false
 				} else {
@@ -500,10 +555,12 @@ func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error)
 					scopedToken = session.ScopedToken
 					sessionRunURL = session.RunURL
 					storedConfigHash = session.ConfigHash
+					resettable = true
 				}
 			}
 		} else {
-			// No config file - find any session for this branch
+			// No config file - find any session for this branch with the
+			// requested name (or any unnamed session when no name is given)
 			sessions := storage.GetSessionsForBranch(branch)
 			if len(sessions) == 0 && IsDetachedBranch(branch) {
 				gitClient := &git.Client{Binary: "git", Dir: cwd}
@@ -516,12 +573,18 @@ func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error)
 			// Filter to only active sessions
 			var activeSessions []SandboxSession
 			for _, sess := range sessions {
-				connInfo, err := s.APIClient.GetSandboxConnectionInfo(sess.RunID, sess.ScopedToken)
+				if sess.Name != cfg.Name {
+					continue
+				}
+				connInfo, refreshed, err := s.sandboxConnectionInfo(ctx, &sess)
+				if refreshed {
+					storage.UpdateScopedToken(sess.RunID, sess.ScopedToken, sess.TokenExpiresAt)
+				}
 				if err == nil && !connInfo.Polling.Completed {
 					activeSessions = append(activeSessions, sess)
 				} else {
 					// Clean up expired session
-					storage.DeleteSession(branch, sess.ConfigFile)
+					storage.DeleteSession(branch, sess.ConfigFile, sess.Name)
 				}
 			}
 			_ = storage.Save()
@@ -532,6 +595,7 @@ func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error)
 				scopedToken = activeSessions[0].ScopedToken
 				sessionRunURL = activeSessions[0].RunURL
 				storedConfigHash = activeSessions[0].ConfigHash
+				resettable = true
 				found = true
 			} else if len(activeSessions) > 1 {
 				UnlockSandboxStorage(lockFile)
@@ -547,7 +611,7 @@ func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error)
 
 		if !found {
 			// Check if a matching sandbox already exists remotely
-			listResult, listErr := s.APIClient.ListSandboxRuns()
+			listResult, listErr := s.APIClient.ListSandboxRuns(ctx)
 			if listErr == nil {
 				for _, run := range listResult.Runs {
 					if run.CliState == "" {
@@ -565,9 +629,9 @@ func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error)
 							branchMatch = gitClient.IsAncestor(storedSHA, "HEAD")
 						}
 					}
-					if branchMatch && state.ConfigFile == cfgFile {
+					if branchMatch && state.ConfigFile == cfgFile && state.Name == cfg.Name {
 						// Verify the remote sandbox is still alive before reusing
-						connInfo, connErr := s.APIClient.GetSandboxConnectionInfo(run.ID, "")
+						connInfo, connErr := s.APIClient.GetSandboxConnectionInfo(ctx, run.ID, "")
 						if connErr != nil || connInfo.Polling.Completed {
 							continue
 						}
@@ -577,22 +641,21 @@ func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error)
 						sessionRunURL = run.RunURL
 
 						// Create a scoped token for this recovered session
-						tokenResult, tokenErr := s.APIClient.CreateSandboxToken(api.CreateSandboxTokenConfig{
-							RunID: run.ID,
-						})
+						var tokenExpiresAt *time.Time
+						var tokenErr error
+						scopedToken, tokenExpiresAt, tokenErr = s.createSandboxToken(ctx, run.ID)
 						if tokenErr != nil {
 							fmt.Fprintf(s.Stderr, "Warning: Unable to create scoped token: %v\n", tokenErr)
-						} else {
-							scopedToken = tokenResult.Token
 						}
 
 						// Store locally so future execs find it without an API call
-						storage.SetSession(branch, cfgFile, SandboxSession{
-							RunID:       run.ID,
-							ConfigFile:  cfgFile,
-							ScopedToken: scopedToken,
-							RunURL:      run.RunURL,
-							ConfigHash:  HashConfigFile(cfgFile),
+						storage.SetSession(branch, cfgFile, cfg.Name, SandboxSession{
+							RunID:          run.ID,
+							ConfigFile:     cfgFile,
+							ScopedToken:    scopedToken,
+							TokenExpiresAt: tokenExpiresAt,
+							RunURL:         run.RunURL,
+							ConfigHash:     HashConfigFile(cfgFile),
 						})
 						if saveErr := storage.Save(); saveErr != nil {
 							fmt.Fprintf(s.Stderr, "Warning: Unable to save sandbox session: %v\n", saveErr)
@@ -609,8 +672,9 @@ func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error)
 			// Pass the lock to StartSandbox so the "no session found → create
 			// new sandbox → persist session" sequence is atomic. StartSandbox
 			// will release it after the initial session is saved.
-			startResult, err := s.StartSandbox(StartSandboxConfig{
+			startResult, err := s.StartSandbox(ctx, StartSandboxConfig{
 				ConfigFile:     cfgFile,
+				Name:           cfg.Name,
 				RwxDirectory:   cfg.RwxDirectory,
 				Json:           cfg.Json,
 				InitParameters: cfg.InitParameters,
@@ -625,7 +689,7 @@ func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error)
 			// Load the newly created session to get the scoped token
 			storage, err = LoadSandboxStorage()
 			if err == nil {
-				if newSession, ok := storage.GetSession(branch, cfgFile); ok {
+				if newSession, ok := storage.GetSession(branch, cfgFile, cfg.Name); ok {
 					scopedToken = newSession.ScopedToken
 				}
 			}
@@ -636,20 +700,65 @@ func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error)
 		}
 	}
 
-	// Warn if the sandbox definition has changed since this sandbox was started
+	// Reset the sandbox, or warn, if its definition has changed since it was
+	// started
 	if storedConfigHash != "" && configFile != "" {
 		currentHash := HashConfigFile(configFile)
 		if currentHash != "" && currentHash != storedConfigHash {
-			fmt.Fprintf(s.Stderr, "Warning: %s has changed since this sandbox was started.\nThe running sandbox does not reflect these changes.\nRun 'rwx sandbox reset' to apply the new definition.\n\n", configFile)
+			if cfg.AutoReset && resettable {
+				if !cfg.Json {
+					fmt.Fprintf(s.Stderr, "%s has changed since this sandbox was started. Resetting the sandbox...\n", configFile)
+				}
+
+				resetResult, err := s.ResetSandbox(ctx, ResetSandboxConfig{
+					ConfigFile:     configFile,
+					Name:           cfg.Name,
+					RwxDirectory:   cfg.RwxDirectory,
+					Json:           cfg.Json,
+					InitParameters: cfg.InitParameters,
+				})
+				if err != nil {
+					return nil, errors.Wrap(err, "unable to reset sandbox")
+				}
+
+				runID = resetResult.NewRunID
+				sessionRunURL = resetResult.RunURL
+				scopedToken = ""
+				if storage, err := LoadSandboxStorage(); err == nil {
+					if session, _, found := storage.FindByRunID(runID); found {
+						scopedToken = session.ScopedToken
+					}
+				}
+			} else {
+				fmt.Fprintf(s.Stderr, "Warning: %s has changed since this sandbox was started.\nThe running sandbox does not reflect these changes.\nRun 'rwx sandbox reset' to apply the new definition, or use --auto-reset to do so automatically.\n\n", configFile)
+			}
 		}
 	}
 
 	// Get connection info (use scoped token if available)
-	connInfo, err := s.waitForSandboxReadyWithToken(runID, scopedToken, cfg.Json)
+	connInfo, err := s.waitForSandboxReadyWithToken(ctx, runID, scopedToken, cfg.Json)
 	if err != nil {
 		return nil, err
 	}
 
+	if cfg.Record != "" {
+		if s.NewSSHClient == nil {
+			return nil, errors.New("recording requires an SSH client constructor")
+		}
+
+		recorder, err := s.startRecording(cfg.Record, "rwx sandbox exec -- "+strings.Join(cfg.Command, " "))
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := recorder.Close(); err != nil {
+				fmt.Fprintf(s.Stderr, "Warning: %v\n", err)
+			}
+		}()
+
+		s.SSHClient = s.NewSSHClient(io.MultiWriter(s.Stdout, recorder), io.MultiWriter(s.Stderr, recorder))
+	}
+
 	// Connect via SSH
 	err = s.connectSSH(connInfo)
 	if err != nil {
@@ -657,6 +766,13 @@ func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error)
 	}
 	defer s.SSHClient.Close()
 
+	// Capture SIGINT/SIGTERM for the rest of the exec so an interrupt never
+	// leaves the sandbox locked or dirty. While the command runs, signals are
+	// forwarded to it; otherwise they are held until cleanup has finished.
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupts)
+
 	// Acquire the distributed lock so concurrent exec calls on the same
 	// sandbox are serialized by the agent. Blocks until the lock is granted.
 	// Show a spinner if another exec is holding the lock.
@@ -697,7 +813,13 @@ func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error)
 	var syncPushPatchBytes int
 	if cfg.Sync {
 		syncPushStart := time.Now()
+		if s.sandboxSyncLock != nil {
+			s.sandboxSyncLock.RLock()
+		}
 		patchBytes, err := s.syncChangesToSandbox(cfg.Json)
+		if s.sandboxSyncLock != nil {
+			s.sandboxSyncLock.RUnlock()
+		}
 		syncPushMs = time.Since(syncPushStart).Milliseconds()
 		syncPushPatchBytes = patchBytes
 		if err != nil {
@@ -724,20 +846,76 @@ func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error)
 		}
 	}
 
+	// Don't start the command if we were interrupted while waiting for the
+	// lock or syncing.
+	select {
+	case <-interrupts:
+		if revertErr := s.revertSandbox(); revertErr != nil {
+			fmt.Fprintf(s.Stderr, "Warning: failed to revert sandbox: %v\n", revertErr)
+		}
+		return nil, errors.New("interrupted before the command started")
+	default:
+	}
+
 	// Execute command — shell-quote each argument so the remote shell
 	// preserves the original grouping (e.g. bash -c "cat README.md").
-	command := shellescape.QuoteCommand(cfg.Command)
+	command := BuildSandboxCommand(cfg.Command, env, cfg.WorkDir)
+
+	// Revert sandbox to clean HEAD so the next exec starts from a known state.
+	// Deferred so it also runs when the command fails or is interrupted, or
+	// once a detached job has started; it runs before the lock is released.
+	defer func() {
+		if revertErr := s.revertSandbox(); revertErr != nil {
+			fmt.Fprintf(s.Stderr, "Warning: failed to revert sandbox: %v\n", revertErr)
+		}
+	}()
+
+	// Detached commands are started in the background in their own copy of
+	// the synced working tree, so the sandbox's tree can be reverted and used
+	// by other commands while they run. Changes they make are not pulled back.
+	if cfg.Detach {
+		jobID, err := s.startSandboxJob(command)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to start command in sandbox")
+		}
+
+		if !cfg.Json {
+			fmt.Fprintf(s.Stdout, "Started job %s in sandbox %s\nRun 'rwx sandbox attach %s' to stream its output.\n", jobID, runID, jobID)
+		}
+
+		s.recordTelemetry("sandbox.exec", map[string]any{
+			"duration_ms":      time.Since(execStart).Milliseconds(),
+			"sync_push_ms":     syncPushMs,
+			"push_patch_bytes": syncPushPatchBytes,
+			"detached":         true,
+		})
+
+		runURL := s.sandboxRunURL(&SandboxSession{RunURL: sessionRunURL})
+		return &ExecSandboxResult{RunID: runID, RunURL: runURL, JobID: jobID}, nil
+	}
+
 	cmdStart := time.Now()
-	exitCode, err := s.SSHClient.ExecuteCommand(command)
+	exitCode, timedOut, err := s.runSandboxCommand(command, cfg.Timeout, interrupts)
 	cmdDuration := time.Since(cmdStart).Milliseconds()
 	s.recordTelemetry("ssh.command", map[string]any{
 		"duration_ms": cmdDuration,
 		"exit_code":   exitCode,
 		"interactive": false,
+		"timed_out":   timedOut,
 	})
 	if err != nil {
+		if timedOut {
+			// The command was abandoned after it ignored SIGTERM and SIGKILL
+			return nil, errors.WrapSentinel(
+				errors.Wrapf(err, "command timed out after %s and could not be terminated", cfg.Timeout),
+				errors.ErrTimeout,
+			)
+		}
 		return nil, errors.Wrap(err, "failed to execute command in sandbox")
 	}
+	if timedOut && !cfg.Json {
+		fmt.Fprintf(s.Stderr, "Command timed out after %s and was terminated.\n", cfg.Timeout)
+	}
 
 	// Pull changes from sandbox back to local
 	var pulledFiles []string
@@ -747,7 +925,13 @@ func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error)
 		var syncPullSuccess bool
 		var syncPullRejCount int
 		pullStart := time.Now()
+		if s.sandboxSyncLock != nil {
+			s.sandboxSyncLock.Lock()
+		}
 		pulled, pullPatchBytes, pullErr := s.pullChangesFromSandbox(cwd, cfg.Json)
+		if s.sandboxSyncLock != nil {
+			s.sandboxSyncLock.Unlock()
+		}
 		syncPullMs = time.Since(pullStart).Milliseconds()
 		syncPullPatchBytes = pullPatchBytes
 		if pullErr != nil {
@@ -768,19 +952,14 @@ func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error)
 		})
 	}
 
-	// Revert sandbox to clean HEAD so the next exec starts from a known state
-	if revertErr := s.revertSandbox(); revertErr != nil {
-		fmt.Fprintf(s.Stderr, "Warning: failed to revert sandbox: %v\n", revertErr)
-	}
-
 	// Update session exec count and last exec time
 	execNow := time.Now().UTC()
 	if lockFile, lockErr := s.lockSandboxStorageWithInfo(cfg.Json); lockErr == nil {
 		if storage, loadErr := LoadSandboxStorage(); loadErr == nil {
-			if session, ok := storage.GetSession(branch, configFile); ok {
+			if session, ok := storage.GetSession(branch, configFile, cfg.Name); ok {
 				session.LastExecAt = &execNow
 				session.ExecCount++
-				storage.SetSession(branch, configFile, *session)
+				storage.SetSession(branch, configFile, cfg.Name, *session)
 				_ = storage.Save()
 			}
 		}
@@ -797,10 +976,82 @@ func (s Service) ExecSandbox(cfg ExecSandboxConfig) (*ExecSandboxResult, error)
 	})
 
 	runURL := s.sandboxRunURL(&SandboxSession{RunURL: sessionRunURL})
-	return &ExecSandboxResult{RunID: runID, ExitCode: exitCode, RunURL: runURL, PulledFiles: pulledFiles}, nil
+	return &ExecSandboxResult{RunID: runID, ExitCode: exitCode, RunURL: runURL, PulledFiles: pulledFiles, TimedOut: timedOut}, nil
 }
 
-func (s Service) ListSandboxes(cfg ListSandboxesConfig) (*ListSandboxesResult, error) {
+// sandboxExecKillGracePeriod is how long a timed-out command has to exit after
+// SIGTERM before it is sent SIGKILL, and how long after SIGKILL before the
+// session is abandoned.
+const sandboxExecKillGracePeriod = 10 * time.Second
+
+// runSandboxCommand executes command on the connected sandbox, forwarding
+// interrupts to the remote process. When timeout is non-zero the command is
+// terminated once it elapses.
+func (s Service) runSandboxCommand(command string, timeout time.Duration, interrupts <-chan os.Signal) (int, bool, error) {
+	forward := make(chan ssh.Signal, 1)
+	done := make(chan struct{})
+	var timedOut atomic.Bool
+
+	go func() {
+		send := func(sig ssh.Signal) bool {
+			select {
+			case forward <- sig:
+				return true
+			case <-done:
+				return false
+			}
+		}
+
+		var timeoutC <-chan time.Time
+		if timeout > 0 {
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			timeoutC = timer.C
+		}
+
+		var escalateC <-chan time.Time
+		killed := false
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-interrupts:
+				sshSignal := ssh.SIGINT
+				if sig == syscall.SIGTERM {
+					sshSignal = ssh.SIGTERM
+				}
+				if !send(sshSignal) {
+					return
+				}
+			case <-timeoutC:
+				timeoutC = nil
+				timedOut.Store(true)
+				if !send(ssh.SIGTERM) {
+					return
+				}
+				escalateC = time.After(sandboxExecKillGracePeriod)
+			case <-escalateC:
+				if killed {
+					// The remote process ignored SIGKILL; give up on it
+					close(forward)
+					return
+				}
+				killed = true
+				if !send(ssh.SIGKILL) {
+					return
+				}
+				escalateC = time.After(sandboxExecKillGracePeriod)
+			}
+		}
+	}()
+
+	exitCode, err := s.SSHClient.ExecuteCommandWithSignals(command, forward)
+	close(done)
+
+	return exitCode, timedOut.Load(), err
+}
+
+func (s Service) ListSandboxes(ctx context.Context, cfg ListSandboxesConfig) (*ListSandboxesResult, error) {
 	lockFile, lockErr := s.lockSandboxStorageWithInfo(cfg.Json)
 	if lockErr != nil {
 		return nil, errors.Wrap(lockErr, "unable to lock sandbox storage")
@@ -812,7 +1063,7 @@ func (s Service) ListSandboxes(cfg ListSandboxesConfig) (*ListSandboxesResult, e
 		return nil, errors.Wrap(err, "unable to load sandbox sessions")
 	}
 
-	listResult, err := s.APIClient.ListSandboxRuns()
+	listResult, err := s.APIClient.ListSandboxRuns(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to list sandbox runs")
 	}
@@ -834,10 +1085,10 @@ func (s Service) ListSandboxes(cfg ListSandboxesConfig) (*ListSandboxesResult, e
 			continue
 		}
 		// Only create a local session if none exists for this key
-		if _, exists := storage.GetSession(state.Branch, state.ConfigFile); exists {
+		if _, exists := storage.GetSession(state.Branch, state.ConfigFile, state.Name); exists {
 			continue
 		}
-		storage.SetSession(state.Branch, state.ConfigFile, SandboxSession{
+		storage.SetSession(state.Branch, state.ConfigFile, state.Name, SandboxSession{
 			RunID:      run.ID,
 			ConfigFile: state.ConfigFile,
 			RunURL:     run.RunURL,
@@ -850,13 +1101,14 @@ func (s Service) ListSandboxes(cfg ListSandboxesConfig) (*ListSandboxesResult, e
 	var expiredKeys []string
 
 	for key, session := range storage.AllSessions() {
-		branch, _ := ParseSessionKey(key)
+		branch, _, _ := ParseSessionKey(key)
 
 		if _, active := activeRuns[session.RunID]; active {
 			sandboxes = append(sandboxes, SandboxInfo{
 				RunID:      session.RunID,
 				Status:     "active",
 				ConfigFile: session.ConfigFile,
+				Name:       session.Name,
 				Branch:     branch,
 			})
 		} else {
@@ -864,7 +1116,11 @@ func (s Service) ListSandboxes(cfg ListSandboxesConfig) (*ListSandboxesResult, e
 			// Verify individually: only keep as active if the API positively
 			// confirms the run is still alive (200 OK, not completed).
 			status := "expired"
-			connInfo, connErr := s.APIClient.GetSandboxConnectionInfo(session.RunID, session.ScopedToken)
+			connInfo, refreshed, connErr := s.sandboxConnectionInfo(ctx, &session)
+			if refreshed {
+				storage.Sandboxes[key] = session
+				storageChanged = true
+			}
 			if connErr == nil && !connInfo.Polling.Completed {
 				status = "active"
 			}
@@ -876,6 +1132,7 @@ func (s Service) ListSandboxes(cfg ListSandboxesConfig) (*ListSandboxesResult, e
 					RunID:      session.RunID,
 					Status:     status,
 					ConfigFile: session.ConfigFile,
+					Name:       session.Name,
 					Branch:     branch,
 				})
 			}
@@ -918,14 +1175,14 @@ func (s Service) printSandboxList(sandboxes []SandboxInfo) {
 	if len(sandboxes) == 0 {
 		fmt.Fprintln(s.Stdout, "No sandbox sessions found.")
 	} else {
-		fmt.Fprintf(s.Stdout, "%-40s %-10s %-25s %s\n", "RUN", "STATUS", "CONFIG", "BRANCH")
+		fmt.Fprintf(s.Stdout, "%-40s %-10s %-25s %-15s %s\n", "RUN", "STATUS", "CONFIG", "NAME", "BRANCH")
 		for _, sb := range sandboxes {
-			fmt.Fprintf(s.Stdout, "%-40s %-10s %-25s %s\n", sb.RunID, sb.Status, sb.ConfigFile, sb.Branch)
+			fmt.Fprintf(s.Stdout, "%-40s %-10s %-25s %-15s %s\n", sb.RunID, sb.Status, sb.ConfigFile, sb.Name, sb.Branch)
 		}
 	}
 }
 
-func (s Service) StopSandbox(cfg StopSandboxConfig) (*StopSandboxResult, error) {
+func (s Service) StopSandbox(ctx context.Context, cfg StopSandboxConfig) (*StopSandboxResult, error) {
 	lockFile, lockErr := s.lockSandboxStorageWithInfo(cfg.Json)
 	if lockErr != nil {
 		return nil, errors.Wrap(lockErr, "unable to lock sandbox storage")
@@ -968,12 +1225,24 @@ func (s Service) StopSandbox(cfg StopSandboxConfig) (*StopSandboxResult, error)
 				_ = storage.Save()
 			}
 		}
+		if cfg.Name != "" {
+			var named []SandboxSession
+			for _, session := range sessions {
+				if session.Name == cfg.Name {
+					named = append(named, session)
+				}
+			}
+			if len(named) == 0 {
+				return nil, fmt.Errorf("No sandbox named '%s' found for branch %s.\nUse 'rwx sandbox list' to see available sandboxes.", cfg.Name, branch)
+			}
+			sessions = named
+		}
 		if len(sessions) == 0 {
 			return nil, fmt.Errorf("No sandbox found for branch %s.\nUse 'rwx sandbox list' to see available sandboxes, or use --id to specify a run ID.", branch)
 		}
 		for _, session := range sessions {
 			toStop = append(toStop, session)
-			keys = append(keys, SessionKey(branch, session.ConfigFile))
+			keys = append(keys, SessionKey(branch, session.ConfigFile, session.Name))
 		}
 	}
 
@@ -984,7 +1253,7 @@ func (s Service) StopSandbox(cfg StopSandboxConfig) (*StopSandboxResult, error)
 		wasRunning := false
 
 		// Check if sandbox is still active and send stop command (use scoped token if available)
-		connInfo, err := s.APIClient.GetSandboxConnectionInfo(session.RunID, session.ScopedToken)
+		connInfo, _, err := s.sandboxConnectionInfo(ctx, &session)
 		if err == nil && connInfo.Sandboxable {
 			if err := s.connectSSH(&connInfo); err == nil {
 				_, _ = s.SSHClient.ExecuteCommand("__rwx_sandbox_end__")
@@ -993,7 +1262,7 @@ func (s Service) StopSandbox(cfg StopSandboxConfig) (*StopSandboxResult, error)
 			}
 		} else if err == nil && !connInfo.Polling.Completed {
 			// Run is still active but not yet sandboxable — cancel it server-side
-			if cancelErr := s.APIClient.CancelRun(session.RunID, session.ScopedToken); cancelErr != nil {
+			if cancelErr := s.APIClient.CancelRun(ctx, session.RunID, session.ScopedToken); cancelErr != nil {
 				fmt.Fprintf(s.Stderr, "Warning: failed to cancel run %s: %v\n", session.RunID, cancelErr)
 			}
 			wasRunning = true
@@ -1032,7 +1301,11 @@ func (s Service) StopSandbox(cfg StopSandboxConfig) (*StopSandboxResult, error)
 	return &StopSandboxResult{Stopped: stopped}, nil
 }
 
-func (s Service) ResetSandbox(cfg ResetSandboxConfig) (*ResetSandboxResult, error) {
+func (s Service) ResetSandbox(ctx context.Context, cfg ResetSandboxConfig) (*ResetSandboxResult, error) {
+	if err := ValidateSandboxName(cfg.Name); err != nil {
+		return nil, err
+	}
+
 	cwd, err := os.Getwd()
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to get current directory")
@@ -1052,16 +1325,16 @@ func (s Service) ResetSandbox(cfg ResetSandboxConfig) (*ResetSandboxResult, erro
 	if err != nil {
 		fmt.Fprintf(s.Stderr, "Warning: Unable to load sandbox sessions: %v\n", err)
 	} else {
-		session, found := storage.GetSession(branch, cfg.ConfigFile)
+		session, found := storage.GetSession(branch, cfg.ConfigFile, cfg.Name)
 		if !found && IsDetachedBranch(branch) {
 			gitClient := &git.Client{Binary: "git", Dir: cwd}
-			session, found = storage.GetSessionByAncestry(branch, cfg.ConfigFile, gitClient)
+			session, found = storage.GetSessionByAncestry(branch, cfg.ConfigFile, cfg.Name, gitClient)
 		}
 		if found {
 			oldRunID = session.RunID
 
 			// Check if still running and stop it (use scoped token if available)
-			connInfo, err := s.APIClient.GetSandboxConnectionInfo(session.RunID, session.ScopedToken)
+			connInfo, _, err := s.sandboxConnectionInfo(ctx, session)
 			if err == nil && connInfo.Sandboxable {
 				if err := s.connectSSH(&connInfo); err == nil {
 					_, _ = s.SSHClient.ExecuteCommand("__rwx_sandbox_end__")
@@ -1070,7 +1343,7 @@ func (s Service) ResetSandbox(cfg ResetSandboxConfig) (*ResetSandboxResult, erro
 			}
 
 			// Remove old session
-			storage.DeleteSession(branch, cfg.ConfigFile)
+			storage.DeleteSession(branch, cfg.ConfigFile, cfg.Name)
 			if err := storage.Save(); err != nil {
 				fmt.Fprintf(s.Stderr, "Warning: Unable to save sandbox sessions: %v\n", err)
 			}
@@ -1083,8 +1356,9 @@ func (s Service) ResetSandbox(cfg ResetSandboxConfig) (*ResetSandboxResult, erro
 
 	// Pass the lock to StartSandbox so no concurrent process can slip in
 	// between the old session removal and the new session creation.
-	startResult, err := s.StartSandbox(StartSandboxConfig{
+	startResult, err := s.StartSandbox(ctx, StartSandboxConfig{
 		ConfigFile:     cfg.ConfigFile,
+		Name:           cfg.Name,
 		RwxDirectory:   cfg.RwxDirectory,
 		Json:           cfg.Json,
 		Wait:           cfg.Wait,
@@ -1191,31 +1465,65 @@ func (s Service) pullChangesFromSandbox(cwd string, jsonMode bool) ([]string, in
 			// Save the full patch so it can be inspected or applied manually
 			patchSavePath := saveRejectedPatch([]byte(patch))
 
-			// Retry with --reject: applies hunks that succeed, writes .rej files for the rest
-			rejectCmd := s.GitClient.ApplyPatchReject([]byte(patch))
-			rejectOutput, rejectErr := rejectCmd.CombinedOutput()
+			// Fall back to a per-file three-way merge against the state the
+			// sandbox was synced to, leaving conflict markers where needed. A
+			// merge that fails partway still reports the files it merged, so
+			// that they aren't patched again.
+			merge, mergeErr := s.mergeSandboxPatch(cwd, patch)
+			if mergeErr != nil {
+				fmt.Fprintf(s.Stderr, "Warning: %v\n", mergeErr)
+			}
+
+			// Anything that can't be merged is applied with --reject: hunks that
+			// succeed are applied and .rej files are written for the rest
+			var rejectErr error
+			var rejectOutput []byte
+			if len(merge.Unmerged) > 0 {
+				var unmergedPatch strings.Builder
+				for _, file := range merge.Unmerged {
+					unmergedPatch.WriteString(file.Patch)
+				}
+				rejectCmd := s.GitClient.ApplyPatchReject([]byte(unmergedPatch.String()))
+				rejectOutput, rejectErr = rejectCmd.CombinedOutput()
+			}
+
+			if len(merge.Conflicted) > 0 || rejectErr != nil {
+				var msg string
+				if len(merge.Conflicted) > 0 {
+					msg = fmt.Sprintf("%d file(s) have merge conflicts:\n", len(merge.Conflicted))
+					for _, f := range merge.Conflicted {
+						msg += fmt.Sprintf("  %s\n", f)
+					}
+					msg += "Resolve the conflict markers in each file."
+				}
 
-			if rejectErr != nil {
-				// Find which files got .rej files by checking the patch file list
-				rejFiles := findRejFiles(cwd, sandboxPatchFiles)
+				if rejectErr != nil {
+					// Find which files got .rej files by checking the patch file list
+					rejFiles := findRejFiles(cwd, sandboxPatchFiles)
 
-				msg := "failed to apply patch locally"
-				if len(rejFiles) > 0 {
-					msg = fmt.Sprintf("patch partially applied. %d file(s) have conflicts:\n", len(rejFiles))
-					for _, f := range rejFiles {
-						msg += fmt.Sprintf("  %s (see %s.rej)\n", f, f)
+					if msg != "" {
+						msg += "\n"
+					}
+					if len(rejFiles) > 0 {
+						msg += fmt.Sprintf("patch partially applied. %d file(s) have conflicts:\n", len(rejFiles))
+						for _, f := range rejFiles {
+							msg += fmt.Sprintf("  %s (see %s.rej)\n", f, f)
+						}
+						msg += "Resolve the conflicts in each .rej file, then delete the .rej files."
+					} else if len(rejectOutput) > 0 {
+						msg += fmt.Sprintf("failed to apply patch locally: %s", strings.TrimSpace(string(rejectOutput)))
+					} else {
+						msg += "failed to apply patch locally"
 					}
-					msg += "Resolve the conflicts in each .rej file, then delete the .rej files."
-				} else if len(rejectOutput) > 0 {
-					msg = fmt.Sprintf("failed to apply patch locally: %s", strings.TrimSpace(string(rejectOutput)))
 				}
+
 				if patchSavePath != "" {
 					msg += fmt.Sprintf("\nFull patch saved to %s", patchSavePath)
 				}
 				return sandboxPatchFiles, patchBytes, errors.WrapSentinel(fmt.Errorf("%s", msg), errors.ErrPatch)
 			}
 
-			// --reject succeeded fully (all hunks applied despite initial failure)
+			// Everything merged or applied cleanly despite the initial failure
 			if patchSavePath != "" {
 				_ = os.Remove(patchSavePath)
 			}
@@ -1374,9 +1682,27 @@ func (s Service) lockSandboxStorageWithInfo(jsonMode bool) (*SandboxStorageLock,
 
 // Helper methods
 
-func (s Service) waitForSandboxReadyWithToken(runID, scopedToken string, jsonMode bool) (*api.SandboxConnectionInfo, error) {
+func (s Service) waitForSandboxReadyWithToken(ctx context.Context, runID, scopedToken string, jsonMode bool) (*api.SandboxConnectionInfo, error) {
+	// A scoped token the API rejects is replaced and the request retried, so
+	// a token that expires while waiting doesn't fail the wait
+	getConnectionInfo := func() (api.SandboxConnectionInfo, error) {
+		connInfo, err := s.APIClient.GetSandboxConnectionInfo(ctx, runID, scopedToken)
+		if !errors.Is(err, errors.ErrUnauthorized) || scopedToken == "" {
+			return connInfo, err
+		}
+
+		session := SandboxSession{RunID: runID, ScopedToken: scopedToken}
+		if refreshErr := s.refreshSessionToken(ctx, &session); refreshErr != nil {
+			return connInfo, err
+		}
+		s.saveSessionToken(session)
+		scopedToken = session.ScopedToken
+
+		return s.APIClient.GetSandboxConnectionInfo(ctx, runID, scopedToken)
+	}
+
 	// Check once before showing spinner - sandbox may already be ready
-	connInfo, err := s.APIClient.GetSandboxConnectionInfo(runID, scopedToken)
+	connInfo, err := getConnectionInfo()
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to get sandbox connection info")
 	}
@@ -1402,9 +1728,11 @@ func (s Service) waitForSandboxReadyWithToken(runID, scopedToken string, jsonMod
 		if connInfo.Polling.BackoffMs != nil {
 			backoffMs = *connInfo.Polling.BackoffMs
 		}
-		time.Sleep(time.Duration(backoffMs) * time.Millisecond)
+		if err := sleep(ctx, time.Duration(backoffMs)*time.Millisecond); err != nil {
+			return nil, err
+		}
 
-		connInfo, err = s.APIClient.GetSandboxConnectionInfo(runID, scopedToken)
+		connInfo, err = getConnectionInfo()
 		if err != nil {
 			return nil, errors.Wrap(err, "unable to get sandbox connection info")
 		}
@@ -1460,8 +1788,11 @@ func (s Service) connectSSH(connInfo *api.SandboxConnectionInfo) error {
 
 // SandboxTitle constructs a descriptive title for sandbox runs using the
 // project directory name, branch, and config file (if non-default).
-func SandboxTitle(cwd, branch, configFile string) string {
+func SandboxTitle(cwd, branch, configFile, name string) string {
 	project := filepath.Base(cwd)
+	if name != "" {
+		project = fmt.Sprintf("%s/%s", project, name)
+	}
 
 	displayBranch := branch
 	if sha := DetachedShortSHA(branch); sha != "" {
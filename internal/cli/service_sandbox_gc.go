@@ -0,0 +1,244 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/rwx-cloud/rwx/internal/api"
+	"github.com/rwx-cloud/rwx/internal/errors"
+)
+
+type GCSandboxesConfig struct {
+	DryRun bool
+	Json   bool
+}
+
+type GCSandboxesResult struct {
+	DryRun bool
+	// Repaired describes how a corrupt storage file was repaired, and is empty
+	// when the file was readable.
+	Repaired      string
+	Removed       []GCSandboxChange
+	TokensCleared []GCSandboxChange
+}
+
+type GCSandboxChange struct {
+	RunID      string
+	Branch     string
+	ConfigFile string
+	Name       string
+	Reason     string
+}
+
+// GCSandboxes prunes sandbox sessions whose runs have ended, clears scoped
+// tokens the API no longer accepts, and repairs a sandboxes.json that can't be
+// parsed. With DryRun set it reports what it would change without saving.
+func (s Service) GCSandboxes(ctx context.Context, cfg GCSandboxesConfig) (*GCSandboxesResult, error) {
+	lockFile, lockErr := s.lockSandboxStorageWithInfo(cfg.Json)
+	if lockErr != nil {
+		return nil, errors.Wrap(lockErr, "unable to lock sandbox storage")
+	}
+	defer UnlockSandboxStorage(lockFile)
+
+	result := &GCSandboxesResult{DryRun: cfg.DryRun}
+
+	path, err := sandboxStoragePath()
+	if err != nil {
+		return nil, err
+	}
+
+	storage, loadErr := loadSandboxStorageFile(path)
+	if loadErr != nil {
+		storage, result.Repaired = repairSandboxStorage(path)
+	}
+
+	listResult, err := s.APIClient.ListSandboxRuns(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to list sandbox runs")
+	}
+
+	activeRuns := make(map[string]api.SandboxRunSummary, len(listResult.Runs))
+	for _, run := range listResult.Runs {
+		activeRuns[run.ID] = run
+	}
+
+	keys := make([]string, 0, len(storage.Sandboxes))
+	for key := range storage.Sandboxes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		session := storage.Sandboxes[key]
+		branch, configFile, name := ParseSessionKey(key)
+		change := GCSandboxChange{
+			RunID:      session.RunID,
+			Branch:     branch,
+			ConfigFile: configFile,
+			Name:       name,
+		}
+
+		if session.RunID == "" {
+			change.Reason = "incomplete session"
+			result.Removed = append(result.Removed, change)
+			delete(storage.Sandboxes, key)
+			continue
+		}
+
+		liveness, tokenExpired := s.checkSandboxSession(ctx, session, activeRuns)
+
+		switch liveness {
+		case sandboxEnded:
+			change.Reason = "run has ended"
+			result.Removed = append(result.Removed, change)
+			delete(storage.Sandboxes, key)
+		case sandboxAlive:
+			if tokenExpired {
+				change.Reason = "scoped token expired"
+				result.TokensCleared = append(result.TokensCleared, change)
+				session.ScopedToken = ""
+				session.TokenExpiresAt = nil
+				storage.Sandboxes[key] = session
+			}
+		}
+	}
+
+	changed := result.Repaired != "" || len(result.Removed) > 0 || len(result.TokensCleared) > 0
+	if changed && !cfg.DryRun {
+		// Keep the unreadable file around for inspection. It's only moved
+		// aside once its replacement is about to be saved, so that a failure
+		// before then leaves it, and the backup, to be repaired next time.
+		if result.Repaired != "" {
+			if err := os.Rename(path, path+".corrupt"); err != nil {
+				return nil, errors.Wrapf(err, "unable to move aside %q", path)
+			}
+		}
+		if err := storage.Save(); err != nil {
+			if result.Repaired != "" {
+				_ = os.Rename(path+".corrupt", path)
+			}
+			return nil, errors.Wrap(err, "unable to save sandbox storage")
+		}
+	}
+
+	if !cfg.Json {
+		s.printSandboxGC(result)
+	}
+
+	s.recordTelemetry("sandbox.gc", map[string]any{
+		"dry_run":        cfg.DryRun,
+		"repaired":       result.Repaired != "",
+		"removed_count":  len(result.Removed),
+		"tokens_cleared": len(result.TokensCleared),
+	})
+
+	return result, nil
+}
+
+// repairSandboxStorage returns the storage to use in place of the unreadable
+// file at path, preferring the backup Save keeps, along with a description of
+// the repair.
+func repairSandboxStorage(path string) (*SandboxStorage, string) {
+	backupPath := sandboxStorageBackupPath(path)
+	if _, err := os.Stat(backupPath); err == nil {
+		if backup, err := loadSandboxStorageFile(backupPath); err == nil {
+			return backup, "restored from backup"
+		}
+	}
+
+	return &SandboxStorage{
+		Version:   sandboxStorageVersion,
+		Sandboxes: make(map[string]SandboxSession),
+	}, "reset (no usable backup)"
+}
+
+type sandboxLiveness int
+
+const (
+	sandboxUnknown sandboxLiveness = iota
+	sandboxAlive
+	sandboxEnded
+)
+
+// checkSandboxSession reports whether the session's run is still alive, and
+// whether its scoped token has stopped working. Runs whose state can't be
+// determined (e.g. the API is unreachable) are reported as unknown and kept.
+func (s Service) checkSandboxSession(ctx context.Context, session SandboxSession, activeRuns map[string]api.SandboxRunSummary) (sandboxLiveness, bool) {
+	tokenExpired := session.TokenExpiresAt != nil && !time.Now().Before(*session.TokenExpiresAt)
+
+	if session.ScopedToken != "" && !tokenExpired {
+		connInfo, err := s.APIClient.GetSandboxConnectionInfo(ctx, session.RunID, session.ScopedToken)
+		if !errors.Is(err, errors.ErrUnauthorized) {
+			return sandboxLivenessOf(connInfo, err), false
+		}
+		tokenExpired = true
+	}
+
+	if _, active := activeRuns[session.RunID]; active {
+		return sandboxAlive, tokenExpired
+	}
+
+	connInfo, err := s.APIClient.GetSandboxConnectionInfo(ctx, session.RunID, "")
+	return sandboxLivenessOf(connInfo, err), tokenExpired
+}
+
+func sandboxLivenessOf(connInfo api.SandboxConnectionInfo, err error) sandboxLiveness {
+	switch {
+	case err == nil && connInfo.Polling.Completed:
+		return sandboxEnded
+	case err == nil:
+		return sandboxAlive
+	case errors.Is(err, errors.ErrGone), errors.Is(err, errors.ErrNotFound):
+		return sandboxEnded
+	default:
+		return sandboxUnknown
+	}
+}
+
+func (s Service) printSandboxGC(result *GCSandboxesResult) {
+	if result.Repaired == "" && len(result.Removed) == 0 && len(result.TokensCleared) == 0 {
+		fmt.Fprintln(s.Stdout, "Nothing to clean up.")
+		return
+	}
+
+	verb := func(done, dryRun string) string {
+		if result.DryRun {
+			return dryRun
+		}
+		return done
+	}
+
+	if result.Repaired != "" {
+		fmt.Fprintf(s.Stdout, "%s sandbox storage: %s\n", verb("Repaired", "Would repair"), result.Repaired)
+	}
+
+	describe := func(change GCSandboxChange) string {
+		label := change.RunID
+		if label == "" {
+			label = "(no run)"
+		}
+		details := change.Branch
+		if change.ConfigFile != "" {
+			details += ", " + filepath.Base(change.ConfigFile)
+		}
+		if change.Name != "" {
+			details += ", " + change.Name
+		}
+		return fmt.Sprintf("%s (%s): %s", label, details, change.Reason)
+	}
+
+	for _, change := range result.Removed {
+		fmt.Fprintf(s.Stdout, "%s session %s\n", verb("Removed", "Would remove"), describe(change))
+	}
+	for _, change := range result.TokensCleared {
+		fmt.Fprintf(s.Stdout, "%s token for %s\n", verb("Cleared", "Would clear"), describe(change))
+	}
+
+	if result.DryRun {
+		fmt.Fprintln(s.Stdout, "\nDry run: no changes were made.")
+	}
+}
@@ -0,0 +1,270 @@
+package cli_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rwx-cloud/rwx/internal/api"
+	"github.com/rwx-cloud/rwx/internal/cli"
+	"github.com/rwx-cloud/rwx/internal/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_GCSandboxes(t *testing.T) {
+	t.Run("removes sessions whose runs have ended", func(t *testing.T) {
+		setup := setupTest(t)
+		configFile := setup.absConfig(".rwx/sandbox.yml")
+		seedSandboxStorageMulti(t, setup.tmp, map[string]cli.SandboxSession{
+			"main:" + configFile:              {RunID: "run-active", ConfigFile: configFile},
+			"feature:" + configFile:           {RunID: "run-completed", ConfigFile: configFile},
+			"other:" + configFile:             {RunID: "run-gone", ConfigFile: configFile},
+			"main:" + configFile + "#backend": {RunID: "run-unknown", ConfigFile: configFile, Name: "backend"},
+			"broken:" + configFile:            {ConfigFile: configFile},
+		})
+
+		setup.mockAPI.MockListSandboxRuns = func() (*api.ListSandboxRunsResult, error) {
+			return &api.ListSandboxRunsResult{Runs: []api.SandboxRunSummary{{ID: "run-active"}}}, nil
+		}
+		setup.mockAPI.MockGetSandboxConnectionInfo = func(runID, token string) (api.SandboxConnectionInfo, error) {
+			switch runID {
+			case "run-completed":
+				return api.SandboxConnectionInfo{Polling: api.PollingResult{Completed: true}}, nil
+			case "run-gone":
+				return api.SandboxConnectionInfo{}, errors.ErrGone
+			default:
+				return api.SandboxConnectionInfo{}, errors.New("connection refused")
+			}
+		}
+
+		result, err := setup.service.GCSandboxes(t.Context(), cli.GCSandboxesConfig{})
+		require.NoError(t, err)
+
+		removed := make([]string, 0, len(result.Removed))
+		for _, change := range result.Removed {
+			removed = append(removed, change.RunID)
+		}
+		require.ElementsMatch(t, []string{"run-completed", "run-gone", ""}, removed)
+
+		storage, err := cli.LoadSandboxStorage()
+		require.NoError(t, err)
+		require.Len(t, storage.Sandboxes, 2)
+		_, found := storage.GetSession("main", configFile, "")
+		require.True(t, found)
+		_, found = storage.GetSession("main", configFile, "backend")
+		require.True(t, found, "sessions whose state can't be determined are kept")
+
+		require.Contains(t, setup.mockStdout.String(), "Removed session run-gone (other, sandbox.yml): run has ended")
+		require.Contains(t, setup.mockStdout.String(), "incomplete session")
+	})
+
+	t.Run("clears scoped tokens that are no longer accepted", func(t *testing.T) {
+		setup := setupTest(t)
+		configFile := setup.absConfig(".rwx/sandbox.yml")
+		seedSandboxStorageMulti(t, setup.tmp, map[string]cli.SandboxSession{
+			"main:" + configFile:    {RunID: "run-1", ConfigFile: configFile, ScopedToken: "expired"},
+			"feature:" + configFile: {RunID: "run-2", ConfigFile: configFile, ScopedToken: "expired"},
+		})
+
+		setup.mockAPI.MockListSandboxRuns = func() (*api.ListSandboxRunsResult, error) {
+			return &api.ListSandboxRunsResult{}, nil
+		}
+		setup.mockAPI.MockGetSandboxConnectionInfo = func(runID, token string) (api.SandboxConnectionInfo, error) {
+			if token != "" {
+				return api.SandboxConnectionInfo{}, errors.ErrUnauthorized
+			}
+			if runID == "run-2" {
+				return api.SandboxConnectionInfo{}, errors.ErrNotFound
+			}
+			return api.SandboxConnectionInfo{Sandboxable: true}, nil
+		}
+
+		result, err := setup.service.GCSandboxes(t.Context(), cli.GCSandboxesConfig{})
+		require.NoError(t, err)
+		require.Len(t, result.TokensCleared, 1)
+		require.Equal(t, "run-1", result.TokensCleared[0].RunID)
+		require.Len(t, result.Removed, 1)
+		require.Equal(t, "run-2", result.Removed[0].RunID)
+
+		storage, err := cli.LoadSandboxStorage()
+		require.NoError(t, err)
+		session, found := storage.GetSession("main", configFile, "")
+		require.True(t, found)
+		require.Empty(t, session.ScopedToken)
+	})
+
+	t.Run("clears tokens past their recorded expiry without using them", func(t *testing.T) {
+		setup := setupTest(t)
+		configFile := setup.absConfig(".rwx/sandbox.yml")
+		expired := time.Now().Add(-time.Hour).UTC()
+		seedSandboxStorageMulti(t, setup.tmp, map[string]cli.SandboxSession{
+			"main:" + configFile: {RunID: "run-1", ConfigFile: configFile, ScopedToken: "expired", TokenExpiresAt: &expired},
+		})
+
+		setup.mockAPI.MockListSandboxRuns = func() (*api.ListSandboxRunsResult, error) {
+			return &api.ListSandboxRunsResult{Runs: []api.SandboxRunSummary{{ID: "run-1"}}}, nil
+		}
+		setup.mockAPI.MockGetSandboxConnectionInfo = func(runID, token string) (api.SandboxConnectionInfo, error) {
+			t.Fatal("should not check a listed run")
+			return api.SandboxConnectionInfo{}, nil
+		}
+
+		result, err := setup.service.GCSandboxes(t.Context(), cli.GCSandboxesConfig{})
+		require.NoError(t, err)
+		require.Len(t, result.TokensCleared, 1)
+
+		storage, err := cli.LoadSandboxStorage()
+		require.NoError(t, err)
+		session, found := storage.GetSession("main", configFile, "")
+		require.True(t, found)
+		require.Empty(t, session.ScopedToken)
+		require.Nil(t, session.TokenExpiresAt)
+	})
+
+	t.Run("reports changes without making them in dry-run mode", func(t *testing.T) {
+		setup := setupTest(t)
+		configFile := setup.absConfig(".rwx/sandbox.yml")
+		seedSandboxStorageMulti(t, setup.tmp, map[string]cli.SandboxSession{
+			"main:" + configFile: {RunID: "run-completed", ConfigFile: configFile},
+		})
+		storagePath := filepath.Join(setup.tmp, ".rwx", "sandboxes", "sandboxes.json")
+		before, err := os.ReadFile(storagePath)
+		require.NoError(t, err)
+
+		setup.mockAPI.MockListSandboxRuns = func() (*api.ListSandboxRunsResult, error) {
+			return &api.ListSandboxRunsResult{}, nil
+		}
+		setup.mockAPI.MockGetSandboxConnectionInfo = func(runID, token string) (api.SandboxConnectionInfo, error) {
+			return api.SandboxConnectionInfo{Polling: api.PollingResult{Completed: true}}, nil
+		}
+
+		result, err := setup.service.GCSandboxes(t.Context(), cli.GCSandboxesConfig{DryRun: true})
+		require.NoError(t, err)
+		require.True(t, result.DryRun)
+		require.Len(t, result.Removed, 1)
+
+		after, err := os.ReadFile(storagePath)
+		require.NoError(t, err)
+		require.Equal(t, string(before), string(after))
+		require.Contains(t, setup.mockStdout.String(), "Would remove session run-completed")
+		require.Contains(t, setup.mockStdout.String(), "Dry run: no changes were made.")
+	})
+
+	t.Run("restores a corrupt storage file from its backup", func(t *testing.T) {
+		setup := setupTest(t)
+		configFile := setup.absConfig(".rwx/sandbox.yml")
+		seedSandboxStorageMulti(t, setup.tmp, map[string]cli.SandboxSession{
+			"main:" + configFile: {RunID: "run-1", ConfigFile: configFile},
+		})
+
+		// Saving moves the seeded file to the backup
+		storage, err := cli.LoadSandboxStorage()
+		require.NoError(t, err)
+		storage.SetSession("feature", configFile, "", cli.SandboxSession{RunID: "run-2", ConfigFile: configFile})
+		require.NoError(t, storage.Save())
+
+		storagePath := filepath.Join(setup.tmp, ".rwx", "sandboxes", "sandboxes.json")
+		require.NoError(t, os.WriteFile(storagePath, []byte(`{"version": 2, "sandboxes": {"main:`), 0o644))
+		_, err = cli.LoadSandboxStorage()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "rwx sandbox gc")
+
+		setup.mockAPI.MockListSandboxRuns = func() (*api.ListSandboxRunsResult, error) {
+			return &api.ListSandboxRunsResult{Runs: []api.SandboxRunSummary{{ID: "run-1"}}}, nil
+		}
+
+		result, err := setup.service.GCSandboxes(t.Context(), cli.GCSandboxesConfig{})
+		require.NoError(t, err)
+		require.Equal(t, "restored from backup", result.Repaired)
+		require.Empty(t, result.Removed)
+
+		storage, err = cli.LoadSandboxStorage()
+		require.NoError(t, err)
+		require.Len(t, storage.Sandboxes, 1)
+		_, found := storage.GetSession("main", configFile, "")
+		require.True(t, found)
+
+		_, err = os.Stat(storagePath + ".corrupt")
+		require.NoError(t, err)
+	})
+
+	t.Run("resets a corrupt storage file without a backup", func(t *testing.T) {
+		setup := setupTest(t)
+		storageDir := filepath.Join(setup.tmp, ".rwx", "sandboxes")
+		require.NoError(t, os.MkdirAll(storageDir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(storageDir, "sandboxes.json"), []byte(`{invalid`), 0o644))
+
+		setup.mockAPI.MockListSandboxRuns = func() (*api.ListSandboxRunsResult, error) {
+			return &api.ListSandboxRunsResult{}, nil
+		}
+
+		result, err := setup.service.GCSandboxes(t.Context(), cli.GCSandboxesConfig{})
+		require.NoError(t, err)
+		require.Equal(t, "reset (no usable backup)", result.Repaired)
+
+		storage, err := cli.LoadSandboxStorage()
+		require.NoError(t, err)
+		require.Empty(t, storage.Sandboxes)
+	})
+
+	t.Run("leaves a corrupt storage file in place when listing runs fails", func(t *testing.T) {
+		setup := setupTest(t)
+		configFile := setup.absConfig(".rwx/sandbox.yml")
+		seedSandboxStorageMulti(t, setup.tmp, map[string]cli.SandboxSession{
+			"main:" + configFile: {RunID: "run-1", ConfigFile: configFile},
+		})
+
+		// Saving moves the seeded file to the backup
+		storage, err := cli.LoadSandboxStorage()
+		require.NoError(t, err)
+		require.NoError(t, storage.Save())
+
+		storagePath := filepath.Join(setup.tmp, ".rwx", "sandboxes", "sandboxes.json")
+		corrupt := []byte(`{"version": 2, "sandboxes": {"main:`)
+		require.NoError(t, os.WriteFile(storagePath, corrupt, 0o644))
+
+		setup.mockAPI.MockListSandboxRuns = func() (*api.ListSandboxRunsResult, error) {
+			return nil, errors.New("service unavailable")
+		}
+
+		_, err = setup.service.GCSandboxes(t.Context(), cli.GCSandboxesConfig{})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unable to list sandbox runs")
+
+		contents, err := os.ReadFile(storagePath)
+		require.NoError(t, err)
+		require.Equal(t, corrupt, contents)
+		require.NoFileExists(t, storagePath+".corrupt")
+
+		// A later gc still repairs the file from its backup
+		setup.mockAPI.MockListSandboxRuns = func() (*api.ListSandboxRunsResult, error) {
+			return &api.ListSandboxRunsResult{Runs: []api.SandboxRunSummary{{ID: "run-1"}}}, nil
+		}
+
+		result, err := setup.service.GCSandboxes(t.Context(), cli.GCSandboxesConfig{})
+		require.NoError(t, err)
+		require.Equal(t, "restored from backup", result.Repaired)
+
+		storage, err = cli.LoadSandboxStorage()
+		require.NoError(t, err)
+		_, found := storage.GetSession("main", configFile, "")
+		require.True(t, found)
+	})
+
+	t.Run("reports when there is nothing to clean up", func(t *testing.T) {
+		setup := setupTest(t)
+
+		setup.mockAPI.MockListSandboxRuns = func() (*api.ListSandboxRunsResult, error) {
+			return &api.ListSandboxRunsResult{}, nil
+		}
+
+		result, err := setup.service.GCSandboxes(t.Context(), cli.GCSandboxesConfig{})
+		require.NoError(t, err)
+		require.Empty(t, result.Removed)
+		require.Contains(t, setup.mockStdout.String(), "Nothing to clean up.")
+
+		event := findEvent(setup.drainEvents(), "sandbox.gc")
+		require.NotNil(t, event)
+	})
+}
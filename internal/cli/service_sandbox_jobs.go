@@ -0,0 +1,463 @@
+package cli
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"al.essio.dev/pkg/shellescape"
+	"github.com/rwx-cloud/rwx/internal/errors"
+	"github.com/rwx-cloud/rwx/internal/git"
+)
+
+// sandboxJobsDir is where detached jobs keep their state on the sandbox. Each
+// job gets its own directory containing the command, pid, output and (once
+// finished) exit code, and the copy of the working tree it runs in.
+const sandboxJobsDir = "/tmp/rwx-sandbox-jobs"
+
+const (
+	SandboxJobStatusRunning    = "running"
+	SandboxJobStatusExited     = "exited"
+	SandboxJobStatusTerminated = "terminated"
+)
+
+var sandboxSignalPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]*$`)
+
+// Config types
+
+type SandboxJobTarget struct {
+	ConfigFile string
+	Name       string
+	RunID      string
+	Json       bool
+}
+
+type ListSandboxJobsConfig struct {
+	SandboxJobTarget
+}
+
+type AttachSandboxJobConfig struct {
+	SandboxJobTarget
+	JobID string
+}
+
+type KillSandboxJobConfig struct {
+	SandboxJobTarget
+	JobID  string
+	Signal string
+}
+
+// Result types
+
+type SandboxJob struct {
+	ID        string
+	Status    string
+	ExitCode  *int
+	StartedAt string
+	Command   string
+}
+
+type ListSandboxJobsResult struct {
+	RunID string
+	Jobs  []SandboxJob
+}
+
+type AttachSandboxJobResult struct {
+	RunID    string
+	JobID    string
+	Status   string
+	ExitCode int
+}
+
+type KillSandboxJobResult struct {
+	RunID  string
+	JobID  string
+	Signal string
+}
+
+// Service methods
+
+func (s Service) ListSandboxJobs(ctx context.Context, cfg ListSandboxJobsConfig) (*ListSandboxJobsResult, error) {
+	runID, err := s.connectExistingSandbox(ctx, cfg.SandboxJobTarget)
+	if err != nil {
+		return nil, err
+	}
+	defer s.SSHClient.Close()
+
+	jobs, err := s.sandboxJobs()
+	if err != nil {
+		return nil, err
+	}
+
+	if !cfg.Json {
+		s.printSandboxJobs(jobs)
+	}
+
+	s.recordTelemetry("sandbox.jobs", map[string]any{
+		"job_count": len(jobs),
+	})
+
+	return &ListSandboxJobsResult{RunID: runID, Jobs: jobs}, nil
+}
+
+func (s Service) AttachSandboxJob(ctx context.Context, cfg AttachSandboxJobConfig) (*AttachSandboxJobResult, error) {
+	if err := validateSandboxJobID(cfg.JobID); err != nil {
+		return nil, err
+	}
+
+	runID, err := s.connectExistingSandbox(ctx, cfg.SandboxJobTarget)
+	if err != nil {
+		return nil, err
+	}
+	defer s.SSHClient.Close()
+
+	job, err := s.findSandboxJob(cfg.JobID)
+	if err != nil {
+		return nil, errors.WithID(err, "run_id", runID)
+	}
+
+	// Stream the output from the beginning; tail exits once the job's
+	// process is gone, so attaching to a finished job just prints its output.
+	dir := sandboxJobDir(job.ID)
+	tailCmd := fmt.Sprintf(
+		"tail -n +1 --pid=\"$(cat %s)\" -f %s",
+		shellescape.Quote(dir+"/pid"),
+		shellescape.Quote(dir+"/output"),
+	)
+	if _, err := s.SSHClient.ExecuteCommand(tailCmd); err != nil {
+		return nil, errors.Wrapf(err, "failed to attach to job %s", job.ID)
+	}
+
+	// Re-read the job now that it has finished to pick up its exit code
+	job, err = s.findSandboxJob(cfg.JobID)
+	if err != nil {
+		return nil, errors.WithID(err, "run_id", runID)
+	}
+
+	exitCode := 0
+	if job.ExitCode != nil {
+		exitCode = *job.ExitCode
+	} else if job.Status == SandboxJobStatusTerminated {
+		exitCode = 1
+	}
+
+	s.recordTelemetry("sandbox.attach", map[string]any{
+		"exit_code": exitCode,
+	})
+
+	return &AttachSandboxJobResult{
+		RunID:    runID,
+		JobID:    job.ID,
+		Status:   job.Status,
+		ExitCode: exitCode,
+	}, nil
+}
+
+func (s Service) KillSandboxJob(ctx context.Context, cfg KillSandboxJobConfig) (*KillSandboxJobResult, error) {
+	if err := validateSandboxJobID(cfg.JobID); err != nil {
+		return nil, err
+	}
+
+	signal, err := normalizeSandboxSignal(cfg.Signal)
+	if err != nil {
+		return nil, err
+	}
+
+	runID, err := s.connectExistingSandbox(ctx, cfg.SandboxJobTarget)
+	if err != nil {
+		return nil, err
+	}
+	defer s.SSHClient.Close()
+
+	job, err := s.findSandboxJob(cfg.JobID)
+	if err != nil {
+		return nil, errors.WithID(err, "run_id", runID)
+	}
+
+	if job.Status != SandboxJobStatusRunning {
+		return nil, fmt.Errorf("Job '%s' is not running (status: %s)", job.ID, job.Status)
+	}
+
+	// Jobs are started with setsid, so the pid is also the process group id.
+	// Signal the whole group so children of the command are signalled too.
+	killCmd := fmt.Sprintf("kill -s %s -- -\"$(cat %s)\"", signal, shellescape.Quote(sandboxJobDir(job.ID)+"/pid"))
+	exitCode, output, err := s.SSHClient.ExecuteCommandWithOutput(killCmd)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to signal job %s", job.ID)
+	}
+	if exitCode != 0 {
+		if msg := strings.TrimSpace(output); msg != "" {
+			return nil, fmt.Errorf("failed to signal job %s: %s", job.ID, msg)
+		}
+		return nil, fmt.Errorf("failed to signal job %s (exit code %d)", job.ID, exitCode)
+	}
+
+	if !cfg.Json {
+		fmt.Fprintf(s.Stdout, "Sent SIG%s to job %s\n", signal, job.ID)
+	}
+
+	s.recordTelemetry("sandbox.kill", map[string]any{
+		"signal": signal,
+	})
+
+	return &KillSandboxJobResult{RunID: runID, JobID: job.ID, Signal: signal}, nil
+}
+
+// startSandboxJob launches command in the background on the connected sandbox
+// and returns the new job's ID. It assumes the SSH connection is already
+// established.
+func (s Service) startSandboxJob(command string) (string, error) {
+	jobID, err := newSandboxJobID()
+	if err != nil {
+		return "", err
+	}
+
+	dir := sandboxJobDir(jobID)
+	quotedDir := shellescape.Quote(dir)
+
+	// The job runs in a copy of the working tree, including the changes that
+	// were just synced, so that reverting the tree after it has started
+	// doesn't pull files out from under it. It runs in its own session so it
+	// survives the SSH session closing and can be signalled as a process
+	// group. The exit code is written by the wrapper shell once the command
+	// finishes, and the copy is removed.
+	tree := dir + "/tree"
+	wrapper := fmt.Sprintf(
+		"cd %[1]s && %[2]s; echo $? > %[3]s; rm -rf %[1]s",
+		shellescape.Quote(tree),
+		command,
+		shellescape.Quote(dir+"/exit_code"),
+	)
+	startCmd := fmt.Sprintf(
+		"mkdir -p %[1]s && printf '%%s' %[2]s > %[1]s/command && date -u +%%Y-%%m-%%dT%%H:%%M:%%SZ > %[1]s/started_at && "+
+			"cp -a . %[4]s && "+
+			"{ setsid sh -c %[3]s > %[1]s/output 2>&1 < /dev/null & echo $! > %[1]s/pid; }",
+		quotedDir,
+		shellescape.Quote(command),
+		shellescape.Quote(wrapper),
+		shellescape.Quote(tree),
+	)
+
+	exitCode, output, err := s.SSHClient.ExecuteCommandWithOutput(startCmd)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to start detached job")
+	}
+	if exitCode != 0 {
+		if msg := strings.TrimSpace(output); msg != "" {
+			return "", fmt.Errorf("failed to start detached job: %s", msg)
+		}
+		return "", fmt.Errorf("failed to start detached job (exit code %d)", exitCode)
+	}
+
+	return jobID, nil
+}
+
+// sandboxJobs lists the detached jobs on the connected sandbox.
+func (s Service) sandboxJobs() ([]SandboxJob, error) {
+	listCmd := fmt.Sprintf(
+		`for d in %s/*/; do [ -d "$d" ] || continue; `+
+			`pid=$(cat "$d/pid" 2>/dev/null); code=$(cat "$d/exit_code" 2>/dev/null); `+
+			`if [ -n "$code" ]; then status=%s; elif [ -n "$pid" ] && kill -0 "$pid" 2>/dev/null; then status=%s; else status=%s; fi; `+
+			`printf '%%s\t%%s\t%%s\t%%s\t%%s\n' "$(basename "$d")" "$status" "$code" "$(cat "$d/started_at" 2>/dev/null)" "$(cat "$d/command" 2>/dev/null | tr '\t\n' '  ')"; `+
+			`done`,
+		sandboxJobsDir,
+		SandboxJobStatusExited,
+		SandboxJobStatusRunning,
+		SandboxJobStatusTerminated,
+	)
+
+	exitCode, output, err := s.SSHClient.ExecuteCommandWithOutput(listCmd)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list jobs in sandbox")
+	}
+	if exitCode != 0 {
+		return nil, fmt.Errorf("failed to list jobs in sandbox (exit code %d)", exitCode)
+	}
+
+	return ParseSandboxJobs(output), nil
+}
+
+func (s Service) findSandboxJob(jobID string) (*SandboxJob, error) {
+	jobs, err := s.sandboxJobs()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, job := range jobs {
+		if job.ID == jobID {
+			return &job, nil
+		}
+	}
+
+	err = errors.WrapSentinel(fmt.Errorf("Job '%s' not found in sandbox.", jobID), errors.ErrNotFound)
+	return nil, errors.WithID(errors.WithHint(err, "Use 'rwx sandbox jobs' to see available jobs."), "job_id", jobID)
+}
+
+func (s Service) printSandboxJobs(jobs []SandboxJob) {
+	if len(jobs) == 0 {
+		fmt.Fprintln(s.Stdout, "No jobs found.")
+		return
+	}
+
+	fmt.Fprintf(s.Stdout, "%-10s %-12s %-6s %-22s %s\n", "JOB", "STATUS", "EXIT", "STARTED", "COMMAND")
+	for _, job := range jobs {
+		exit := "-"
+		if job.ExitCode != nil {
+			exit = strconv.Itoa(*job.ExitCode)
+		}
+		fmt.Fprintf(s.Stdout, "%-10s %-12s %-6s %-22s %s\n", job.ID, job.Status, exit, job.StartedAt, job.Command)
+	}
+}
+
+// connectExistingSandbox resolves an already-running sandbox (never creating
+// one) and connects to it over SSH. The caller must close the SSH client.
+func (s Service) connectExistingSandbox(ctx context.Context, target SandboxJobTarget) (string, error) {
+	session, err := s.resolveExistingSandbox(target.RunID, target.ConfigFile, target.Name)
+	if err != nil {
+		return "", err
+	}
+
+	if session.ScopedTokenExpiring(time.Now()) {
+		if err := s.refreshSessionToken(ctx, session); err != nil {
+			fmt.Fprintf(s.Stderr, "Warning: %v\n", err)
+		} else {
+			s.saveSessionToken(*session)
+		}
+	}
+
+	connInfo, err := s.waitForSandboxReadyWithToken(ctx, session.RunID, session.ScopedToken, target.Json)
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.connectSSH(connInfo); err != nil {
+		return "", fmt.Errorf("Failed to connect to sandbox '%s': %v", session.RunID, err)
+	}
+
+	return session.RunID, nil
+}
+
+// resolveExistingSandbox finds the sandbox to operate on without creating a
+// new one. An explicit run ID wins; otherwise the session for the current
+// branch with the given name (and config file, when given) is used.
+func (s Service) resolveExistingSandbox(runID, configFile, name string) (*SandboxSession, error) {
+	storage, err := LoadSandboxStorage()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load sandbox sessions")
+	}
+
+	if runID != "" {
+		if session, _, found := storage.FindByRunID(runID); found {
+			return session, nil
+		}
+		return &SandboxSession{RunID: runID, ConfigFile: configFile}, nil
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get current directory")
+	}
+	branch := GetCurrentGitBranch(cwd)
+
+	var sessions []SandboxSession
+	if configFile != "" {
+		session, found := storage.GetSession(branch, configFile, name)
+		if !found && IsDetachedBranch(branch) {
+			session, found = storage.GetSessionByAncestry(branch, configFile, name, &git.Client{Binary: "git", Dir: cwd})
+		}
+		if found {
+			sessions = append(sessions, *session)
+		}
+	} else {
+		branchSessions := storage.GetSessionsForBranch(branch)
+		if len(branchSessions) == 0 && IsDetachedBranch(branch) {
+			branchSessions = storage.GetSessionsForBranchByAncestry(branch, &git.Client{Binary: "git", Dir: cwd})
+		}
+		for _, session := range branchSessions {
+			if session.Name == name {
+				sessions = append(sessions, session)
+			}
+		}
+	}
+
+	switch len(sessions) {
+	case 0:
+		return nil, fmt.Errorf("No sandbox found for branch %s.\nUse 'rwx sandbox list' to see available sandboxes, or use --id to specify a run ID.", branch)
+	case 1:
+		return &sessions[0], nil
+	default:
+		return nil, fmt.Errorf("Multiple sandboxes found for branch %s.\nSpecify a config file to select one, or use --id to specify a run ID.", branch)
+	}
+}
+
+// ParseSandboxJobs parses the tab-separated job listing produced on the sandbox.
+func ParseSandboxJobs(output string) []SandboxJob {
+	jobs := []SandboxJob{}
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		fields := strings.SplitN(line, "\t", 5)
+		if len(fields) < 5 {
+			continue
+		}
+
+		job := SandboxJob{
+			ID:        fields[0],
+			Status:    fields[1],
+			StartedAt: strings.TrimSpace(fields[3]),
+			Command:   strings.TrimSpace(fields[4]),
+		}
+		if code, err := strconv.Atoi(strings.TrimSpace(fields[2])); err == nil {
+			job.ExitCode = &code
+		}
+
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+func sandboxJobDir(jobID string) string {
+	return sandboxJobsDir + "/" + jobID
+}
+
+func newSandboxJobID() (string, error) {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		return "", errors.Wrap(err, "unable to generate job ID")
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func validateSandboxJobID(jobID string) error {
+	if jobID == "" {
+		return errors.New("a job ID must be provided")
+	}
+	for _, r := range jobID {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return fmt.Errorf("invalid job ID %q", jobID)
+		}
+	}
+	return nil
+}
+
+// normalizeSandboxSignal accepts signal names with or without the SIG prefix
+// (e.g. "INT", "sigint") and returns the bare upper-case name. Defaults to TERM.
+func normalizeSandboxSignal(signal string) (string, error) {
+	if signal == "" {
+		return "TERM", nil
+	}
+
+	normalized := strings.TrimPrefix(strings.ToUpper(signal), "SIG")
+	if !sandboxSignalPattern.MatchString(normalized) {
+		return "", fmt.Errorf("invalid signal %q", signal)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,248 @@
+package cli_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/rwx-cloud/rwx/internal/api"
+	"github.com/rwx-cloud/rwx/internal/cli"
+	"github.com/rwx-cloud/rwx/internal/git"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func sandboxJobsSetup(t *testing.T, setup *testSetup) {
+	t.Helper()
+	setup.mockAPI.MockGetSandboxConnectionInfo = func(id, token string) (api.SandboxConnectionInfo, error) {
+		return api.SandboxConnectionInfo{
+			Sandboxable:    true,
+			Address:        "192.168.1.1:22",
+			PrivateUserKey: sandboxPrivateTestKey,
+			PublicHostKey:  sandboxPublicTestKey,
+		}, nil
+	}
+	setup.mockSSH.MockConnect = func(addr string, _ ssh.ClientConfig) error { return nil }
+}
+
+func TestParseSandboxJobs(t *testing.T) {
+	t.Run("parses running, exited and terminated jobs", func(t *testing.T) {
+		output := "aaaa1111\trunning\t\t2026-01-02T03:04:05Z\tnpm test\n" +
+			"bbbb2222\texited\t3\t2026-01-02T03:04:06Z\tmake build\n" +
+			"cccc3333\tterminated\t\t2026-01-02T03:04:07Z\tsleep 100\n"
+
+		jobs := cli.ParseSandboxJobs(output)
+
+		require.Len(t, jobs, 3)
+		require.Equal(t, "aaaa1111", jobs[0].ID)
+		require.Equal(t, cli.SandboxJobStatusRunning, jobs[0].Status)
+		require.Nil(t, jobs[0].ExitCode)
+		require.Equal(t, "npm test", jobs[0].Command)
+		require.Equal(t, cli.SandboxJobStatusExited, jobs[1].Status)
+		require.NotNil(t, jobs[1].ExitCode)
+		require.Equal(t, 3, *jobs[1].ExitCode)
+		require.Equal(t, "2026-01-02T03:04:06Z", jobs[1].StartedAt)
+		require.Equal(t, cli.SandboxJobStatusTerminated, jobs[2].Status)
+	})
+
+	t.Run("returns an empty list when there are no jobs", func(t *testing.T) {
+		jobs := cli.ParseSandboxJobs("")
+		require.NotNil(t, jobs)
+		require.Empty(t, jobs)
+	})
+}
+
+func TestService_ExecSandbox_Detach(t *testing.T) {
+	t.Run("starts the command in the background and returns a job ID", func(t *testing.T) {
+		setup := setupTest(t)
+		sandboxJobsSetup(t, setup)
+
+		var executedCommands []string
+		setup.mockSSH.MockExecuteCommand = func(cmd string) (int, error) {
+			executedCommands = append(executedCommands, cmd)
+			return 0, nil
+		}
+
+		var startCmd string
+		setup.mockSSH.MockExecuteCommandWithOutput = func(cmd string) (int, string, error) {
+			if strings.Contains(cmd, "setsid") {
+				startCmd = cmd
+				executedCommands = append(executedCommands, "<start job>")
+				return 0, "", nil
+			}
+			t.Fatalf("unexpected command: %s", cmd)
+			return -1, "", nil
+		}
+
+		setup.mockGit.MockGeneratePatch = func(pathspec []string) ([]byte, *git.LFSChangedFilesMetadata, error) {
+			return nil, nil, nil
+		}
+
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
+			Command:    []string{"bash", "-c", "npm test"},
+			RunID:      "run-detach",
+			Sync:       true,
+			Detach:     true,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "run-detach", result.RunID)
+		require.Equal(t, 0, result.ExitCode)
+		require.Regexp(t, `^[0-9a-f]{8}$`, result.JobID)
+		require.Contains(t, startCmd, "/tmp/rwx-sandbox-jobs/"+result.JobID)
+		require.Contains(t, startCmd, "npm test")
+		require.NotContains(t, executedCommands, "bash -c 'npm test'")
+		require.Contains(t, setup.mockStdout.String(), "Started job "+result.JobID)
+
+		// The job runs in its own copy of the synced working tree
+		jobTree := "/tmp/rwx-sandbox-jobs/" + result.JobID + "/tree"
+		require.Contains(t, startCmd, "cp -a . "+jobTree+" && ")
+		require.Contains(t, startCmd, "cd "+jobTree+" && ")
+
+		// The sandbox's tree is reverted once the job has started, before the
+		// lock is released
+		started := slices.Index(executedCommands, "<start job>")
+		reverted := slices.IndexFunc(executedCommands, func(cmd string) bool {
+			return strings.Contains(cmd, "refs/rwx-sync HEAD 2>/dev/null")
+		})
+		require.NotEqual(t, -1, started)
+		require.Greater(t, reverted, started, "the sandbox is reverted after the job starts")
+		require.Equal(t, "__rwx_sandbox_lock_released__", executedCommands[len(executedCommands)-1])
+	})
+}
+
+func TestService_ListSandboxJobs(t *testing.T) {
+	t.Run("lists jobs on the sandbox", func(t *testing.T) {
+		setup := setupTest(t)
+		sandboxJobsSetup(t, setup)
+
+		setup.mockSSH.MockExecuteCommandWithOutput = func(cmd string) (int, string, error) {
+			return 0, "aaaa1111\texited\t0\t2026-01-02T03:04:05Z\tnpm test\n", nil
+		}
+
+		result, err := setup.service.ListSandboxJobs(t.Context(), cli.ListSandboxJobsConfig{
+			SandboxJobTarget: cli.SandboxJobTarget{RunID: "run-jobs"},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "run-jobs", result.RunID)
+		require.Len(t, result.Jobs, 1)
+		require.Contains(t, setup.mockStdout.String(), "aaaa1111")
+		require.Contains(t, setup.mockStdout.String(), "npm test")
+	})
+
+	t.Run("errors when no sandbox exists for the branch", func(t *testing.T) {
+		setup := setupTest(t)
+
+		_, err := setup.service.ListSandboxJobs(t.Context(), cli.ListSandboxJobsConfig{})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "No sandbox found")
+	})
+}
+
+func TestService_AttachSandboxJob(t *testing.T) {
+	t.Run("streams output and returns the job's exit code", func(t *testing.T) {
+		setup := setupTest(t)
+		sandboxJobsSetup(t, setup)
+
+		finished := false
+		setup.mockSSH.MockExecuteCommand = func(cmd string) (int, error) {
+			require.Contains(t, cmd, "tail -n +1")
+			require.Contains(t, cmd, "/tmp/rwx-sandbox-jobs/aaaa1111/output")
+			finished = true
+			return 0, nil
+		}
+		setup.mockSSH.MockExecuteCommandWithOutput = func(cmd string) (int, string, error) {
+			if finished {
+				return 0, "aaaa1111\texited\t4\t2026-01-02T03:04:05Z\tnpm test\n", nil
+			}
+			return 0, "aaaa1111\trunning\t\t2026-01-02T03:04:05Z\tnpm test\n", nil
+		}
+
+		result, err := setup.service.AttachSandboxJob(t.Context(), cli.AttachSandboxJobConfig{
+			SandboxJobTarget: cli.SandboxJobTarget{RunID: "run-attach"},
+			JobID:            "aaaa1111",
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, 4, result.ExitCode)
+		require.Equal(t, cli.SandboxJobStatusExited, result.Status)
+	})
+
+	t.Run("errors when the job does not exist", func(t *testing.T) {
+		setup := setupTest(t)
+		sandboxJobsSetup(t, setup)
+
+		setup.mockSSH.MockExecuteCommandWithOutput = func(cmd string) (int, string, error) {
+			return 0, "", nil
+		}
+
+		_, err := setup.service.AttachSandboxJob(t.Context(), cli.AttachSandboxJobConfig{
+			SandboxJobTarget: cli.SandboxJobTarget{RunID: "run-attach"},
+			JobID:            "deadbeef",
+		})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Job 'deadbeef' not found")
+	})
+}
+
+func TestService_KillSandboxJob(t *testing.T) {
+	t.Run("signals the job's process group", func(t *testing.T) {
+		setup := setupTest(t)
+		sandboxJobsSetup(t, setup)
+
+		var killCmd string
+		setup.mockSSH.MockExecuteCommandWithOutput = func(cmd string) (int, string, error) {
+			if strings.HasPrefix(cmd, "kill ") {
+				killCmd = cmd
+				return 0, "", nil
+			}
+			return 0, "aaaa1111\trunning\t\t2026-01-02T03:04:05Z\tnpm test\n", nil
+		}
+
+		result, err := setup.service.KillSandboxJob(t.Context(), cli.KillSandboxJobConfig{
+			SandboxJobTarget: cli.SandboxJobTarget{RunID: "run-kill"},
+			JobID:            "aaaa1111",
+			Signal:           "sigint",
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "INT", result.Signal)
+		require.Contains(t, killCmd, "kill -s INT -- -")
+		require.Contains(t, killCmd, "/tmp/rwx-sandbox-jobs/aaaa1111/pid")
+	})
+
+	t.Run("errors when the job is not running", func(t *testing.T) {
+		setup := setupTest(t)
+		sandboxJobsSetup(t, setup)
+
+		setup.mockSSH.MockExecuteCommandWithOutput = func(cmd string) (int, string, error) {
+			require.False(t, strings.HasPrefix(cmd, "kill "), "finished jobs should not be signalled")
+			return 0, "aaaa1111\texited\t0\t2026-01-02T03:04:05Z\tnpm test\n", nil
+		}
+
+		_, err := setup.service.KillSandboxJob(t.Context(), cli.KillSandboxJobConfig{
+			SandboxJobTarget: cli.SandboxJobTarget{RunID: "run-kill"},
+			JobID:            "aaaa1111",
+		})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "is not running")
+	})
+
+	t.Run("rejects invalid signals before connecting", func(t *testing.T) {
+		setup := setupTest(t)
+
+		_, err := setup.service.KillSandboxJob(t.Context(), cli.KillSandboxJobConfig{
+			SandboxJobTarget: cli.SandboxJobTarget{RunID: "run-kill"},
+			JobID:            "aaaa1111",
+			Signal:           "TERM; rm -rf /",
+		})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid signal")
+	})
+}
@@ -0,0 +1,311 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/rwx-cloud/rwx/internal/errors"
+	"github.com/rwx-cloud/rwx/internal/git"
+)
+
+type ExecSandboxesConfig struct {
+	// ExecSandboxConfig holds the command and the options shared by every
+	// sandbox. Its RunID and Name are ignored in favor of RunIDs and Names.
+	ExecSandboxConfig
+	All    bool
+	RunIDs []string
+	Names  []string
+}
+
+type ExecSandboxesResult struct {
+	ExitCode  int
+	Sandboxes []SandboxExecOutcome
+}
+
+type SandboxExecOutcome struct {
+	Label       string
+	RunID       string
+	Name        string
+	ExitCode    int
+	TimedOut    bool
+	JobID       string
+	PulledFiles []string
+	Error       string
+}
+
+// sandboxExecTarget is one sandbox selected for ExecSandboxes.
+type sandboxExecTarget struct {
+	Label      string
+	RunID      string
+	Name       string
+	ConfigFile string
+}
+
+// ExecSandboxes runs the same command in several sandboxes at once. Each
+// sandbox goes through the full exec cycle (sync, lock, run, pull, revert)
+// on its own SSH connection, with its output prefixed by the sandbox's label.
+// The combined exit code is 0 when every sandbox succeeded, and otherwise the
+// highest exit code (a sandbox that failed to run counts as 1).
+func (s Service) ExecSandboxes(ctx context.Context, cfg ExecSandboxesConfig) (*ExecSandboxesResult, error) {
+	if s.NewSSHClient == nil {
+		return nil, errors.New("running in multiple sandboxes requires an SSH client constructor")
+	}
+
+	targets, err := s.resolveSandboxExecTargets(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	width := 0
+	for _, target := range targets {
+		width = max(width, len(target.Label))
+	}
+
+	var outputLock sync.Mutex
+	syncLock := new(sync.RWMutex)
+	outcomes := make([]SandboxExecOutcome, len(targets))
+
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func(i int, target sandboxExecTarget) {
+			defer wg.Done()
+
+			prefix := fmt.Sprintf("[%-*s] ", width, target.Label)
+			stdout := newPrefixWriter(s.Stdout, prefix, &outputLock)
+			stderr := newPrefixWriter(s.Stderr, prefix, &outputLock)
+			defer stdout.Flush()
+			defer stderr.Flush()
+
+			svc := s
+			svc.SSHClient = s.NewSSHClient(stdout, stderr)
+			svc.Stdout = stdout
+			svc.Stderr = stderr
+			svc.StdoutIsTTY = false
+			svc.StderrIsTTY = false
+			svc.sandboxSyncLock = syncLock
+
+			execCfg := cfg.ExecSandboxConfig
+			execCfg.RunID = target.RunID
+			execCfg.Name = target.Name
+			if target.ConfigFile != "" {
+				execCfg.ConfigFile = target.ConfigFile
+			}
+
+			outcome := SandboxExecOutcome{Label: target.Label, RunID: target.RunID, Name: target.Name}
+			result, err := svc.ExecSandbox(ctx, execCfg)
+			if err != nil {
+				outcome.ExitCode = 1
+				outcome.Error = err.Error()
+				fmt.Fprintf(stderr, "Error: %v\n", err)
+			} else {
+				outcome.RunID = result.RunID
+				outcome.ExitCode = result.ExitCode
+				outcome.TimedOut = result.TimedOut
+				outcome.JobID = result.JobID
+				outcome.PulledFiles = result.PulledFiles
+			}
+			outcomes[i] = outcome
+		}(i, target)
+	}
+	wg.Wait()
+
+	exitCode := 0
+	failed := 0
+	for _, outcome := range outcomes {
+		if outcome.ExitCode != 0 {
+			failed++
+			exitCode = max(exitCode, outcome.ExitCode)
+		}
+	}
+
+	if !cfg.Json {
+		s.printSandboxExecSummary(outcomes)
+	}
+
+	s.recordTelemetry("sandbox.exec_multi", map[string]any{
+		"sandbox_count": len(outcomes),
+		"failed_count":  failed,
+	})
+
+	return &ExecSandboxesResult{ExitCode: exitCode, Sandboxes: outcomes}, nil
+}
+
+// resolveSandboxExecTargets turns --all, --id and --name into the list of
+// sandboxes to run in. --all selects every active sandbox on the current
+// branch; names that don't exist yet are created by ExecSandbox. A sandbox
+// selected more than once, e.g. by --all and by its name, runs only once.
+func (s Service) resolveSandboxExecTargets(ctx context.Context, cfg ExecSandboxesConfig) ([]sandboxExecTarget, error) {
+	var targets []sandboxExecTarget
+	seen := make(map[string]bool)
+	add := func(target sandboxExecTarget, runID string) {
+		key := "id:" + runID
+		if runID == "" {
+			key = "name:" + target.Name
+		}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		targets = append(targets, target)
+	}
+
+	// Stored sessions are only needed to pick sandboxes by branch or name
+	var storage *SandboxStorage
+	var cwd, branch string
+	if cfg.All || len(cfg.Names) > 0 {
+		var err error
+		storage, err = LoadSandboxStorage()
+		if err != nil {
+			if cfg.All {
+				return nil, errors.Wrap(err, "unable to load sandbox sessions")
+			}
+			// Names are still resolved by ExecSandbox; they just can't be
+			// matched to sandboxes selected by --id
+			storage = &SandboxStorage{Sandboxes: make(map[string]SandboxSession)}
+		}
+
+		cwd, err = os.Getwd()
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to get current directory")
+		}
+		branch = GetCurrentGitBranch(cwd)
+	}
+
+	if cfg.All {
+		sessions := storage.GetSessionsForBranch(branch)
+		if len(sessions) == 0 && IsDetachedBranch(branch) {
+			sessions = storage.GetSessionsForBranchByAncestry(branch, &git.Client{Binary: "git", Dir: cwd})
+			if len(sessions) > 0 {
+				_ = storage.Save()
+			}
+		}
+
+		for _, session := range sessions {
+			connInfo, refreshed, err := s.sandboxConnectionInfo(ctx, &session)
+			if refreshed {
+				s.saveSessionToken(session)
+			}
+			if err != nil || connInfo.Polling.Completed {
+				continue
+			}
+			label := session.Name
+			if label == "" {
+				label = session.RunID
+			}
+			add(sandboxExecTarget{Label: label, RunID: session.RunID, Name: session.Name, ConfigFile: session.ConfigFile}, session.RunID)
+		}
+
+		if len(targets) == 0 {
+			return nil, fmt.Errorf("No active sandboxes found for branch %s.\nUse 'rwx sandbox list' to see available sandboxes.", branch)
+		}
+	}
+
+	for _, runID := range cfg.RunIDs {
+		add(sandboxExecTarget{Label: runID, RunID: runID}, runID)
+	}
+
+	for _, name := range cfg.Names {
+		if err := ValidateSandboxName(name); err != nil {
+			return nil, err
+		}
+		// A name that's already stored is keyed by its run ID, so it isn't
+		// run twice when --all or --id also selected it
+		add(sandboxExecTarget{Label: name, Name: name}, storedSandboxRunID(storage, branch, cfg.ConfigFile, name))
+	}
+
+	if len(targets) == 0 {
+		return nil, errors.New("no sandboxes selected")
+	}
+
+	return targets, nil
+}
+
+// storedSandboxRunID returns the run ID of the session ExecSandbox would use
+// for the sandbox with name on branch, or "" when there isn't exactly one.
+func storedSandboxRunID(storage *SandboxStorage, branch, configFile, name string) string {
+	if configFile != "" {
+		if session, found := storage.GetSession(branch, configFile, name); found {
+			return session.RunID
+		}
+		return ""
+	}
+
+	runID := ""
+	for _, session := range storage.GetSessionsForBranch(branch) {
+		if session.Name != name {
+			continue
+		}
+		if runID != "" {
+			return ""
+		}
+		runID = session.RunID
+	}
+	return runID
+}
+
+func (s Service) printSandboxExecSummary(outcomes []SandboxExecOutcome) {
+	fmt.Fprintln(s.Stdout)
+	fmt.Fprintf(s.Stdout, "%-25s %-40s %s\n", "SANDBOX", "RUN", "EXIT")
+	for _, outcome := range outcomes {
+		status := fmt.Sprintf("%d", outcome.ExitCode)
+		switch {
+		case outcome.Error != "":
+			status = "error"
+		case outcome.TimedOut:
+			status = "timed out"
+		case outcome.JobID != "":
+			status = "detached (job " + outcome.JobID + ")"
+		}
+		fmt.Fprintf(s.Stdout, "%-25s %-40s %s\n", outcome.Label, outcome.RunID, status)
+	}
+}
+
+// prefixWriter writes each complete line to w prefixed with prefix. Writers
+// that share a lock never interleave within a line.
+type prefixWriter struct {
+	w       io.Writer
+	prefix  string
+	lock    *sync.Mutex
+	pending bytes.Buffer
+}
+
+func newPrefixWriter(w io.Writer, prefix string, lock *sync.Mutex) *prefixWriter {
+	return &prefixWriter{w: w, prefix: prefix, lock: lock}
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.pending.Write(b)
+	for {
+		line, err := p.pending.ReadBytes('\n')
+		if err != nil {
+			// No newline yet; keep the partial line for the next write
+			p.pending.Reset()
+			p.pending.Write(line)
+			break
+		}
+		if _, err := fmt.Fprintf(p.w, "%s%s", p.prefix, line); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(b), nil
+}
+
+// Flush writes any trailing partial line.
+func (p *prefixWriter) Flush() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.pending.Len() > 0 {
+		fmt.Fprintf(p.w, "%s%s\n", p.prefix, p.pending.String())
+		p.pending.Reset()
+	}
+}
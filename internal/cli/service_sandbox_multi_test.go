@@ -0,0 +1,222 @@
+package cli_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/rwx-cloud/rwx/internal/api"
+	"github.com/rwx-cloud/rwx/internal/cli"
+	"github.com/rwx-cloud/rwx/internal/mocks"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// multiExecSetup gives every sandbox its own address and makes each SSH
+// client print which sandbox it ran in. Commands in run-fail exit with 3.
+func multiExecSetup(t *testing.T, setup *testSetup) *[]string {
+	t.Helper()
+
+	var lock sync.Mutex
+	var commands []string
+
+	setup.mockAPI.MockGetSandboxConnectionInfo = func(id, token string) (api.SandboxConnectionInfo, error) {
+		return api.SandboxConnectionInfo{
+			Sandboxable:    true,
+			Address:        id + ":22",
+			PrivateUserKey: sandboxPrivateTestKey,
+			PublicHostKey:  sandboxPublicTestKey,
+		}, nil
+	}
+
+	setup.service.NewSSHClient = func(stdout, stderr io.Writer) cli.SSHClient {
+		var addr string
+		return &mocks.SSH{
+			MockConnect: func(a string, _ ssh.ClientConfig) error {
+				addr = a
+				return nil
+			},
+			MockExecuteCommand: func(cmd string) (int, error) {
+				lock.Lock()
+				commands = append(commands, addr+" "+cmd)
+				lock.Unlock()
+
+				if cmd == "echo hello" {
+					fmt.Fprintf(stdout, "hello from %s\n", addr)
+					if addr == "run-fail:22" {
+						return 3, nil
+					}
+				}
+				return 0, nil
+			},
+			MockExecuteCommandWithOutput: func(cmd string) (int, string, error) {
+				return 0, "", nil
+			},
+		}
+	}
+
+	return &commands
+}
+
+func TestService_ExecSandboxes(t *testing.T) {
+	t.Run("runs the command in every selected sandbox with prefixed output", func(t *testing.T) {
+		setup := setupTest(t)
+		commands := multiExecSetup(t, setup)
+
+		result, err := setup.service.ExecSandboxes(t.Context(), cli.ExecSandboxesConfig{
+			ExecSandboxConfig: cli.ExecSandboxConfig{
+				Command: []string{"echo", "hello"},
+			},
+			RunIDs: []string{"run-ok", "run-fail"},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, 3, result.ExitCode)
+		require.Len(t, result.Sandboxes, 2)
+		require.Equal(t, "run-ok", result.Sandboxes[0].RunID)
+		require.Equal(t, 0, result.Sandboxes[0].ExitCode)
+		require.Equal(t, "run-fail", result.Sandboxes[1].RunID)
+		require.Equal(t, 3, result.Sandboxes[1].ExitCode)
+
+		stdout := setup.mockStdout.String()
+		require.Contains(t, stdout, "[run-ok  ] hello from run-ok:22\n")
+		require.Contains(t, stdout, "[run-fail] hello from run-fail:22\n")
+		require.Contains(t, stdout, "SANDBOX")
+
+		// Each sandbox is locked and released on its own connection
+		for _, runID := range []string{"run-ok", "run-fail"} {
+			require.Contains(t, *commands, runID+":22 __rwx_sandbox_lock_requested__")
+			require.Contains(t, *commands, runID+":22 __rwx_sandbox_lock_released__")
+		}
+	})
+
+	t.Run("runs in every active sandbox on the branch with --all", func(t *testing.T) {
+		setup := setupTest(t)
+		configFile := setup.absConfig(".rwx/sandbox.yml")
+		// The temp dir is not a git repo, so the branch resolves to "detached"
+		seedSandboxStorageMulti(t, setup.tmp, map[string]cli.SandboxSession{
+			"detached:" + configFile + "#pg15": {RunID: "run-pg15", ConfigFile: configFile, Name: "pg15"},
+			"detached:" + configFile + "#pg16": {RunID: "run-pg16", ConfigFile: configFile, Name: "pg16"},
+			"main:" + configFile:               {RunID: "run-other-branch", ConfigFile: configFile},
+		})
+		multiExecSetup(t, setup)
+
+		result, err := setup.service.ExecSandboxes(t.Context(), cli.ExecSandboxesConfig{
+			ExecSandboxConfig: cli.ExecSandboxConfig{
+				Command: []string{"echo", "hello"},
+				Json:    true,
+			},
+			All: true,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, 0, result.ExitCode)
+		require.Len(t, result.Sandboxes, 2)
+
+		stdout := setup.mockStdout.String()
+		require.Contains(t, stdout, "[pg15] hello from run-pg15:22\n")
+		require.Contains(t, stdout, "[pg16] hello from run-pg16:22\n")
+		require.NotContains(t, stdout, "run-other-branch")
+		require.NotContains(t, stdout, "SANDBOX", "the summary is not printed in JSON mode")
+	})
+
+	t.Run("runs once in a sandbox selected both by --id and by its name", func(t *testing.T) {
+		setup := setupTest(t)
+		configFile := setup.absConfig(".rwx/sandbox.yml")
+		seedSandboxStorageMulti(t, setup.tmp, map[string]cli.SandboxSession{
+			"detached:" + configFile + "#pg15": {RunID: "run-pg15", ConfigFile: configFile, Name: "pg15"},
+		})
+		multiExecSetup(t, setup)
+
+		result, err := setup.service.ExecSandboxes(t.Context(), cli.ExecSandboxesConfig{
+			ExecSandboxConfig: cli.ExecSandboxConfig{
+				Command: []string{"echo", "hello"},
+				Json:    true,
+			},
+			RunIDs: []string{"run-pg15"},
+			Names:  []string{"pg15"},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, result.Sandboxes, 1)
+		require.Equal(t, 1, strings.Count(setup.mockStdout.String(), "hello from run-pg15:22"))
+	})
+
+	t.Run("reports sandboxes that fail to run", func(t *testing.T) {
+		setup := setupTest(t)
+		multiExecSetup(t, setup)
+
+		setup.mockAPI.MockGetSandboxConnectionInfo = func(id, token string) (api.SandboxConnectionInfo, error) {
+			if id == "run-gone" {
+				return api.SandboxConnectionInfo{Polling: api.PollingResult{Completed: true}}, nil
+			}
+			return api.SandboxConnectionInfo{
+				Sandboxable:    true,
+				Address:        id + ":22",
+				PrivateUserKey: sandboxPrivateTestKey,
+				PublicHostKey:  sandboxPublicTestKey,
+			}, nil
+		}
+
+		result, err := setup.service.ExecSandboxes(t.Context(), cli.ExecSandboxesConfig{
+			ExecSandboxConfig: cli.ExecSandboxConfig{
+				Command: []string{"echo", "hello"},
+			},
+			RunIDs: []string{"run-ok", "run-gone"},
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, 1, result.ExitCode)
+		require.Equal(t, 0, result.Sandboxes[0].ExitCode)
+		require.NotEmpty(t, result.Sandboxes[1].Error)
+		require.Contains(t, setup.mockStderr.String(), "[run-gone] Error:")
+	})
+
+	t.Run("errors when --all finds no active sandboxes", func(t *testing.T) {
+		setup := setupTest(t)
+		multiExecSetup(t, setup)
+
+		_, err := setup.service.ExecSandboxes(t.Context(), cli.ExecSandboxesConfig{
+			ExecSandboxConfig: cli.ExecSandboxConfig{
+				Command: []string{"echo", "hello"},
+			},
+			All: true,
+		})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "No active sandboxes found")
+	})
+
+	t.Run("prefixes partial lines once they are complete", func(t *testing.T) {
+		setup := setupTest(t)
+		multiExecSetup(t, setup)
+
+		base := setup.service.NewSSHClient
+		setup.service.NewSSHClient = func(stdout, stderr io.Writer) cli.SSHClient {
+			client := base(stdout, stderr).(*mocks.SSH)
+			client.MockExecuteCommand = func(cmd string) (int, error) {
+				if cmd == "echo hello" {
+					fmt.Fprint(stdout, "par")
+					fmt.Fprint(stdout, "tial\nsecond")
+				}
+				return 0, nil
+			}
+			return client
+		}
+
+		_, err := setup.service.ExecSandboxes(t.Context(), cli.ExecSandboxesConfig{
+			ExecSandboxConfig: cli.ExecSandboxConfig{
+				Command: []string{"echo", "hello"},
+				Json:    true,
+			},
+			RunIDs: []string{"run-a", "run-b"},
+		})
+
+		require.NoError(t, err)
+		stdout := setup.mockStdout.String()
+		require.Equal(t, 2, strings.Count(stdout, "] partial\n"))
+		require.Equal(t, 2, strings.Count(stdout, "] second\n"))
+	})
+}
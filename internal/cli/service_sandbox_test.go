@@ -42,7 +42,7 @@ func TestService_ListSandboxes(t *testing.T) {
 			return &api.ListSandboxRunsResult{Runs: []api.SandboxRunSummary{}}, nil
 		}
 
-		result, err := setup.service.ListSandboxes(cli.ListSandboxesConfig{
+		result, err := setup.service.ListSandboxes(t.Context(), cli.ListSandboxesConfig{
 			Json: true,
 		})
 
@@ -60,7 +60,7 @@ func TestService_LockWaitOutput(t *testing.T) {
 			return &api.ListSandboxRunsResult{Runs: []api.SandboxRunSummary{}}, nil
 		}
 
-		_, err := setup.service.ListSandboxes(cli.ListSandboxesConfig{Json: false})
+		_, err := setup.service.ListSandboxes(t.Context(), cli.ListSandboxesConfig{Json: false})
 		require.NoError(t, err)
 		require.Empty(t, setup.mockStderr.String(), "expected no stderr output when lock is uncontended")
 	})
@@ -79,7 +79,7 @@ func TestService_LockWaitOutput(t *testing.T) {
 		done := make(chan struct{})
 		go func() {
 			defer close(done)
-			_, _ = setup.service.ListSandboxes(cli.ListSandboxesConfig{Json: false})
+			_, _ = setup.service.ListSandboxes(t.Context(), cli.ListSandboxesConfig{Json: false})
 		}()
 
 		// Wait for the spinner message to appear, with a timeout
@@ -116,7 +116,7 @@ func TestService_LockWaitOutput(t *testing.T) {
 		done := make(chan struct{})
 		go func() {
 			defer close(done)
-			_, _ = setup.service.ListSandboxes(cli.ListSandboxesConfig{Json: true})
+			_, _ = setup.service.ListSandboxes(t.Context(), cli.ListSandboxesConfig{Json: true})
 		}()
 
 		// Let the goroutine reach the blocking Lock() call, then release
@@ -160,7 +160,7 @@ func TestService_ListSandboxes_BulkAPI(t *testing.T) {
 			return api.SandboxConnectionInfo{}, nil
 		}
 
-		result, err := setup.service.ListSandboxes(cli.ListSandboxesConfig{Json: true})
+		result, err := setup.service.ListSandboxes(t.Context(), cli.ListSandboxesConfig{Json: true})
 
 		require.NoError(t, err)
 		require.Len(t, result.Sandboxes, 1)
@@ -185,7 +185,7 @@ func TestService_ListSandboxes_BulkAPI(t *testing.T) {
 			},
 		})
 
-		remoteCliState := cli.EncodeCliState("develop", setup.absConfig(".rwx/sandbox.yml"))
+		remoteCliState := cli.EncodeCliState("develop", setup.absConfig(".rwx/sandbox.yml"), "")
 		setup.mockAPI.MockListSandboxRuns = func() (*api.ListSandboxRunsResult, error) {
 			return &api.ListSandboxRunsResult{
 				Runs: []api.SandboxRunSummary{
@@ -195,7 +195,7 @@ func TestService_ListSandboxes_BulkAPI(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.ListSandboxes(cli.ListSandboxesConfig{Json: true})
+		result, err := setup.service.ListSandboxes(t.Context(), cli.ListSandboxesConfig{Json: true})
 
 		require.NoError(t, err)
 		require.Len(t, result.Sandboxes, 2)
@@ -210,7 +210,7 @@ func TestService_ListSandboxes_BulkAPI(t *testing.T) {
 		storage, err := cli.LoadSandboxStorage()
 		require.NoError(t, err)
 		require.Len(t, storage.Sandboxes, 2)
-		session, found := storage.GetSession("develop", setup.absConfig(".rwx/sandbox.yml"))
+		session, found := storage.GetSession("develop", setup.absConfig(".rwx/sandbox.yml"), "")
 		require.True(t, found)
 		require.Equal(t, "run-remote", session.RunID)
 		require.Equal(t, "https://cloud.rwx.com/runs/run-remote", session.RunURL)
@@ -228,7 +228,7 @@ func TestService_ListSandboxes_BulkAPI(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.ListSandboxes(cli.ListSandboxesConfig{Json: true})
+		result, err := setup.service.ListSandboxes(t.Context(), cli.ListSandboxesConfig{Json: true})
 
 		require.NoError(t, err)
 		require.Empty(t, result.Sandboxes)
@@ -245,7 +245,7 @@ func TestService_ListSandboxes_BulkAPI(t *testing.T) {
 		})
 
 		// Remote has a run with cli_state pointing to the same key but different run ID
-		remoteCliState := cli.EncodeCliState("main", setup.absConfig(".rwx/sandbox.yml"))
+		remoteCliState := cli.EncodeCliState("main", setup.absConfig(".rwx/sandbox.yml"), "")
 		setup.mockAPI.MockListSandboxRuns = func() (*api.ListSandboxRunsResult, error) {
 			return &api.ListSandboxRunsResult{
 				Runs: []api.SandboxRunSummary{
@@ -255,7 +255,7 @@ func TestService_ListSandboxes_BulkAPI(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.ListSandboxes(cli.ListSandboxesConfig{Json: true})
+		result, err := setup.service.ListSandboxes(t.Context(), cli.ListSandboxesConfig{Json: true})
 		require.NoError(t, err)
 
 		// Local session key is occupied by run-local, so run-remote-new should NOT overwrite it
@@ -265,7 +265,7 @@ func TestService_ListSandboxes_BulkAPI(t *testing.T) {
 		// Verify the scoped token was preserved
 		storage, err := cli.LoadSandboxStorage()
 		require.NoError(t, err)
-		session, found := storage.GetSession("main", setup.absConfig(".rwx/sandbox.yml"))
+		session, found := storage.GetSession("main", setup.absConfig(".rwx/sandbox.yml"), "")
 		require.True(t, found)
 		require.Equal(t, "run-local", session.RunID)
 		require.Equal(t, "my-scoped-token", session.ScopedToken)
@@ -305,7 +305,7 @@ func TestService_ListSandboxes_PrunesExpired(t *testing.T) {
 			return api.SandboxConnectionInfo{}, nil
 		}
 
-		result, err := setup.service.ListSandboxes(cli.ListSandboxesConfig{
+		result, err := setup.service.ListSandboxes(t.Context(), cli.ListSandboxesConfig{
 			Json: true,
 		})
 
@@ -362,7 +362,7 @@ func TestService_ListSandboxes_PrunesExpired(t *testing.T) {
 			return api.SandboxConnectionInfo{}, nil
 		}
 
-		result, err := setup.service.ListSandboxes(cli.ListSandboxesConfig{
+		result, err := setup.service.ListSandboxes(t.Context(), cli.ListSandboxesConfig{
 			Json: true,
 		})
 
@@ -397,7 +397,7 @@ func TestService_ListSandboxes_PrunesExpired(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.ListSandboxes(cli.ListSandboxesConfig{Json: true})
+		result, err := setup.service.ListSandboxes(t.Context(), cli.ListSandboxesConfig{Json: true})
 
 		require.NoError(t, err)
 		require.Len(t, result.Sandboxes, 1)
@@ -429,7 +429,7 @@ func TestService_ListSandboxes_PrunesExpired(t *testing.T) {
 			return api.SandboxConnectionInfo{}, fmt.Errorf("run has been cancelled")
 		}
 
-		result, err := setup.service.ListSandboxes(cli.ListSandboxesConfig{Json: true})
+		result, err := setup.service.ListSandboxes(t.Context(), cli.ListSandboxesConfig{Json: true})
 
 		require.NoError(t, err)
 		require.Empty(t, result.Sandboxes)
@@ -447,7 +447,7 @@ func seedSandboxStorageMulti(t *testing.T, tmpHome string, sessions map[string]c
 	require.NoError(t, os.MkdirAll(storageDir, 0o755))
 
 	storage := cli.SandboxStorage{
-		Version:   1,
+		Version:   2,
 		Sandboxes: sessions,
 	}
 
@@ -501,7 +501,7 @@ func TestService_ExecSandbox(t *testing.T) {
 			return nil, nil, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -557,7 +557,7 @@ func TestService_ExecSandbox(t *testing.T) {
 			return nil, nil, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"false"},
 			RunID:      runID,
@@ -599,7 +599,7 @@ func TestService_ExecSandbox(t *testing.T) {
 			return nil, nil, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"bash", "-c", "cat README.md"},
 			RunID:      runID,
@@ -623,7 +623,7 @@ func TestService_ExecSandbox(t *testing.T) {
 			}, nil
 		}
 
-		_, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -677,7 +677,7 @@ func TestService_ExecSandbox_Sync(t *testing.T) {
 			return 0, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -727,7 +727,7 @@ func TestService_ExecSandbox_Sync(t *testing.T) {
 			return nil, nil, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -780,7 +780,7 @@ func TestService_ExecSandbox_Sync(t *testing.T) {
 
 		// Pull mocks (no changes on sandbox)
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -833,7 +833,7 @@ func TestService_ExecSandbox_Sync(t *testing.T) {
 			return 0, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -900,7 +900,7 @@ func TestService_ExecSandbox_Sync(t *testing.T) {
 
 		// Pull mocks (no changes on sandbox)
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -950,7 +950,7 @@ func TestService_ExecSandbox_Sync(t *testing.T) {
 			return 1, "error: patch failed", nil // git apply failed
 		}
 
-		_, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -1001,7 +1001,7 @@ func TestService_ExecSandbox_Sync(t *testing.T) {
 			return 0, "", nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -1104,7 +1104,7 @@ func TestService_ExecSandbox_Sync(t *testing.T) {
 			return 127, "", nil // command not found
 		}
 
-		_, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -1152,7 +1152,7 @@ func TestService_ExecSandbox_Sync(t *testing.T) {
 			return 0, nil
 		}
 
-		_, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -1213,7 +1213,7 @@ func TestService_ExecSandbox_Pull(t *testing.T) {
 			return nil, nil, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -1266,7 +1266,7 @@ func TestService_ExecSandbox_Pull(t *testing.T) {
 			return nil, nil, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"make", "test"},
 			RunID:      runID,
@@ -1332,7 +1332,7 @@ func TestService_ExecSandbox_Pull(t *testing.T) {
 			return nil, nil, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -1407,7 +1407,7 @@ func TestService_ExecSandbox_Pull(t *testing.T) {
 			return 0, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -1452,7 +1452,7 @@ func TestService_ExecSandbox_Pull(t *testing.T) {
 			return 0, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -1505,7 +1505,7 @@ func TestService_ExecSandbox_Pull(t *testing.T) {
 			return nil, nil, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -1570,7 +1570,7 @@ func TestService_ExecSandbox_PullPatchFailureRecovery(t *testing.T) {
 			return exec.Command("false")
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -1637,7 +1637,7 @@ func TestService_ExecSandbox_PullPatchFailureRecovery(t *testing.T) {
 			return exec.Command("true")
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -1702,7 +1702,7 @@ func TestService_ExecSandbox_PullPatchFailureRecovery(t *testing.T) {
 			return exec.Command("false")
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -1758,7 +1758,7 @@ func TestService_ExecSandbox_PullPatchFailureRecovery(t *testing.T) {
 			return nil, nil, nil
 		}
 
-		_, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -1822,7 +1822,7 @@ func TestService_StartSandbox(t *testing.T) {
 			return &api.CreateSandboxTokenResult{Token: "test-token"}, nil
 		}
 
-		_, err = setup.service.StartSandbox(cli.StartSandboxConfig{
+		_, err = setup.service.StartSandbox(t.Context(), cli.StartSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Json:       true,
 		})
@@ -1875,7 +1875,7 @@ func TestService_StartSandbox(t *testing.T) {
 			return &api.CreateSandboxTokenResult{Token: "test-token"}, nil
 		}
 
-		result, err := setup.service.StartSandbox(cli.StartSandboxConfig{
+		result, err := setup.service.StartSandbox(t.Context(), cli.StartSandboxConfig{
 			ConfigFile:     setup.absConfig(".rwx/sandbox.yml"),
 			Json:           true,
 			InitParameters: map[string]string{"foo": "bar"},
@@ -1926,7 +1926,7 @@ func TestService_StartSandbox_StorageLock(t *testing.T) {
 		lock, err := cli.LockSandboxStorage()
 		require.NoError(t, err)
 
-		result, err := setup.service.StartSandbox(cli.StartSandboxConfigWithLock(cli.StartSandboxConfig{
+		result, err := setup.service.StartSandbox(t.Context(), cli.StartSandboxConfigWithLock(cli.StartSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Json:       true,
 		}, lock))
@@ -1939,7 +1939,7 @@ func TestService_StartSandbox_StorageLock(t *testing.T) {
 		require.NoError(t, err)
 		require.NotEmpty(t, storage.Sandboxes, "expected at least one session in storage")
 		// The temp dir is not a git repo, so the branch resolves to "detached"
-		session, found := storage.GetSession("detached", setup.absConfig(".rwx/sandbox.yml"))
+		session, found := storage.GetSession("detached", setup.absConfig(".rwx/sandbox.yml"), "")
 		require.True(t, found)
 		require.Equal(t, "run-lock-test", session.RunID)
 
@@ -1977,7 +1977,7 @@ func TestService_StartSandbox_StorageLock(t *testing.T) {
 		lock, err := cli.LockSandboxStorage()
 		require.NoError(t, err)
 
-		_, err = setup.service.StartSandbox(cli.StartSandboxConfigWithLock(cli.StartSandboxConfig{
+		_, err = setup.service.StartSandbox(t.Context(), cli.StartSandboxConfigWithLock(cli.StartSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Json:       true,
 		}, lock))
@@ -2073,7 +2073,7 @@ func TestService_ExecSandbox_ConcurrentAutoCreate(t *testing.T) {
 		var err1 error
 		go func() {
 			defer wg.Done()
-			result1, err1 = setup.service.ExecSandbox(cli.ExecSandboxConfig{
+			result1, err1 = setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 				Command: []string{"echo", "hello"},
 				Json:    true,
 			})
@@ -2083,7 +2083,7 @@ func TestService_ExecSandbox_ConcurrentAutoCreate(t *testing.T) {
 		var err2 error
 		go func() {
 			defer wg.Done()
-			result2, err2 = service2.ExecSandbox(cli.ExecSandboxConfig{
+			result2, err2 = service2.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 				Command: []string{"echo", "world"},
 				Json:    true,
 			})
@@ -2116,7 +2116,7 @@ func TestService_ExecSandbox_RecoverFromAPI(t *testing.T) {
 		configFile := setup.absConfig(".rwx/sandbox.yml")
 
 		// Encode cli_state matching branch+configFile
-		encodedState := cli.EncodeCliState(branch, configFile)
+		encodedState := cli.EncodeCliState(branch, configFile, "")
 
 		// No local session — ListSandboxRuns returns a matching run
 		setup.mockAPI.MockListSandboxRuns = func() (*api.ListSandboxRunsResult, error) {
@@ -2156,7 +2156,7 @@ func TestService_ExecSandbox_RecoverFromAPI(t *testing.T) {
 			return nil, nil, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: configFile,
 			Command:    []string{"echo", "hello"},
 			Json:       true,
@@ -2169,7 +2169,7 @@ func TestService_ExecSandbox_RecoverFromAPI(t *testing.T) {
 		// Verify the session was stored locally
 		storage, err := cli.LoadSandboxStorage()
 		require.NoError(t, err)
-		session, found := storage.GetSession(branch, configFile)
+		session, found := storage.GetSession(branch, configFile, "")
 		require.True(t, found)
 		require.Equal(t, "run-recovered", session.RunID)
 		require.Equal(t, "recovered-token", session.ScopedToken)
@@ -2240,7 +2240,7 @@ func TestService_ExecSandbox_RecoverFromAPI(t *testing.T) {
 			return nil, nil, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			Command: []string{"echo", "hello"},
 			Json:    true,
 		})
@@ -2317,7 +2317,7 @@ func TestService_ExecSandbox_InitParams(t *testing.T) {
 		}
 
 		// No RunID provided - forces lazy-create path
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			Command:        []string{"echo", "hello"},
 			Json:           true,
 			InitParameters: map[string]string{"foo": "bar"},
@@ -2335,7 +2335,7 @@ func TestService_StopSandbox(t *testing.T) {
 	t.Run("returns error when no sandbox exists for current directory", func(t *testing.T) {
 		setup := setupTest(t)
 
-		_, err := setup.service.StopSandbox(cli.StopSandboxConfig{
+		_, err := setup.service.StopSandbox(t.Context(), cli.StopSandboxConfig{
 			Json: true,
 		})
 
@@ -2346,7 +2346,7 @@ func TestService_StopSandbox(t *testing.T) {
 	t.Run("returns error when sandbox ID not found in storage", func(t *testing.T) {
 		setup := setupTest(t)
 
-		_, err := setup.service.StopSandbox(cli.StopSandboxConfig{
+		_, err := setup.service.StopSandbox(t.Context(), cli.StopSandboxConfig{
 			RunID: "nonexistent-run",
 			Json:  true,
 		})
@@ -2373,7 +2373,7 @@ func TestService_StopSandbox(t *testing.T) {
 			return nil
 		}
 
-		result, err := setup.service.StopSandbox(cli.StopSandboxConfig{
+		result, err := setup.service.StopSandbox(t.Context(), cli.StopSandboxConfig{
 			RunID: "run-initializing",
 			Json:  true,
 		})
@@ -2398,7 +2398,7 @@ func TestService_StopSandbox(t *testing.T) {
 			return errors.New("server error")
 		}
 
-		result, err := setup.service.StopSandbox(cli.StopSandboxConfig{
+		result, err := setup.service.StopSandbox(t.Context(), cli.StopSandboxConfig{
 			RunID: "run-cancel-fail",
 			Json:  true,
 		})
@@ -2432,7 +2432,7 @@ func TestService_StopSandbox(t *testing.T) {
 			return nil
 		}
 
-		result, err := setup.service.StopSandbox(cli.StopSandboxConfig{
+		result, err := setup.service.StopSandbox(t.Context(), cli.StopSandboxConfig{
 			RunID: "run-sandboxable",
 			Json:  true,
 		})
@@ -2457,7 +2457,7 @@ func TestService_StopSandbox(t *testing.T) {
 			return nil
 		}
 
-		result, err := setup.service.StopSandbox(cli.StopSandboxConfig{
+		result, err := setup.service.StopSandbox(t.Context(), cli.StopSandboxConfig{
 			RunID: "run-completed",
 			Json:  true,
 		})
@@ -2520,7 +2520,7 @@ func TestService_ExecSandbox_RunURL(t *testing.T) {
 			return nil, nil, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -2549,7 +2549,7 @@ func TestService_StartSandbox_RunURL(t *testing.T) {
 			return &api.CreateSandboxTokenResult{Token: "test-token"}, nil
 		}
 
-		result, err := setup.service.StartSandbox(cli.StartSandboxConfig{
+		result, err := setup.service.StartSandbox(t.Context(), cli.StartSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			RunID:      runID,
 			Json:       true,
@@ -2599,7 +2599,7 @@ func TestService_StartSandbox_RunURL(t *testing.T) {
 			return &api.CreateSandboxTokenResult{Token: "test-token"}, nil
 		}
 
-		result, err := setup.service.StartSandbox(cli.StartSandboxConfig{
+		result, err := setup.service.StartSandbox(t.Context(), cli.StartSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Json:       true,
 		})
@@ -2644,7 +2644,7 @@ func TestService_ExecSandbox_Lock(t *testing.T) {
 			return 0, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -2696,7 +2696,7 @@ func TestService_ExecSandbox_Lock(t *testing.T) {
 			return 1, "error: patch failed", nil
 		}
 
-		_, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -2738,7 +2738,7 @@ func TestService_ExecSandbox_Lock(t *testing.T) {
 			return 0, nil
 		}
 
-		_, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -2785,7 +2785,7 @@ func TestService_ExecSandbox_Lock(t *testing.T) {
 			return 0, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -2863,7 +2863,7 @@ func TestService_ExecSandbox_Lock(t *testing.T) {
 			return 0, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -2912,7 +2912,7 @@ func TestService_ExecSandbox_Lock(t *testing.T) {
 			return 0, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"false"},
 			RunID:      runID,
@@ -2964,7 +2964,7 @@ func TestService_ExecSandbox_DefinitionDrift(t *testing.T) {
 
 		driftExecSetup(t, setup, runID)
 
-		_, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: configFile,
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -2976,6 +2976,102 @@ func TestService_ExecSandbox_DefinitionDrift(t *testing.T) {
 		require.Contains(t, setup.mockStderr.String(), "rwx sandbox reset")
 	})
 
+	t.Run("resets the sandbox with auto-reset when the definition changed", func(t *testing.T) {
+		setup := setupTest(t)
+		configFile := setup.absConfig(".rwx/sandbox.yml")
+
+		require.NoError(t, os.WriteFile(configFile, []byte("tasks:\n  - key: setup\n    call: ${{ run.dir }}/setup.yml\n"), 0o644))
+		require.NoError(t, os.WriteFile(setup.absConfig(".rwx/setup.yml"), []byte("tasks:\n  - key: original\n"), 0o644))
+		originalHash := cli.HashConfigFile(configFile)
+
+		// The temp dir is not a git repo, so the branch resolves to "detached"
+		seedSandboxStorageMulti(t, setup.tmp, map[string]cli.SandboxSession{
+			"detached:" + configFile: {
+				RunID:      "run-old",
+				ConfigFile: configFile,
+				ConfigHash: originalHash,
+			},
+		})
+
+		// Only the embedded run changes
+		require.NoError(t, os.WriteFile(setup.absConfig(".rwx/setup.yml"), []byte("tasks:\n  - key: modified\n"), 0o644))
+
+		driftExecSetup(t, setup, "run-old")
+		var sshCommands []string
+		setup.mockSSH.MockExecuteCommand = func(cmd string) (int, error) {
+			sshCommands = append(sshCommands, cmd)
+			return 0, nil
+		}
+		setup.mockGit.MockGetBranch = "main"
+		setup.mockGit.MockGetCommit = "abc123"
+		setup.mockGit.MockGetOriginUrl = "git@github.com:example/repo.git"
+		setup.mockAPI.MockGetDefaultBase = func() (api.DefaultBaseResult, error) {
+			return api.DefaultBaseResult{Image: "ubuntu:24.04", Config: "rwx/base 1.0.0", Arch: "x86_64"}, nil
+		}
+		setup.mockAPI.MockGetPackageVersions = func() (*api.PackageVersionsResult, error) {
+			return &api.PackageVersionsResult{
+				LatestMajor: make(map[string]string),
+				LatestMinor: make(map[string]map[string]string),
+			}, nil
+		}
+		setup.mockAPI.MockInitiateRun = func(cfg api.InitiateRunConfig) (*api.InitiateRunResult, error) {
+			return &api.InitiateRunResult{RunID: "run-new", RunURL: "https://cloud.rwx.com/runs/run-new"}, nil
+		}
+		setup.mockAPI.MockCreateSandboxToken = func(cfg api.CreateSandboxTokenConfig) (*api.CreateSandboxTokenResult, error) {
+			return &api.CreateSandboxTokenResult{Token: "token-new"}, nil
+		}
+
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			ConfigFile: configFile,
+			Command:    []string{"echo", "hello"},
+			AutoReset:  true,
+			Json:       true,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "run-new", result.RunID)
+		require.Contains(t, sshCommands, "__rwx_sandbox_end__")
+		require.Contains(t, sshCommands, "echo hello")
+		require.NotContains(t, setup.mockStderr.String(), "Warning:")
+
+		storage, err := cli.LoadSandboxStorage()
+		require.NoError(t, err)
+		session, found := storage.GetSession("detached", configFile, "")
+		require.True(t, found)
+		require.Equal(t, "run-new", session.RunID)
+		require.Equal(t, cli.HashConfigFile(configFile), session.ConfigHash)
+	})
+
+	t.Run("warns instead of resetting a sandbox selected by another branch's run ID", func(t *testing.T) {
+		setup := setupTest(t)
+		runID := "run-other-branch"
+		configFile := setup.absConfig(".rwx/sandbox.yml")
+
+		require.NoError(t, os.WriteFile(configFile, []byte("tasks:\n  - key: original\n"), 0o644))
+		seedSandboxStorageMulti(t, setup.tmp, map[string]cli.SandboxSession{
+			"feature:" + configFile: {
+				RunID:      runID,
+				ConfigFile: configFile,
+				ConfigHash: cli.HashConfigFile(configFile),
+			},
+		})
+		require.NoError(t, os.WriteFile(configFile, []byte("tasks:\n  - key: modified\n"), 0o644))
+
+		driftExecSetup(t, setup, runID)
+
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			ConfigFile: configFile,
+			Command:    []string{"echo", "hello"},
+			RunID:      runID,
+			AutoReset:  true,
+			Json:       true,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, runID, result.RunID)
+		require.Contains(t, setup.mockStderr.String(), "has changed since this sandbox was started")
+	})
+
 	t.Run("does not warn when config file has not changed", func(t *testing.T) {
 		setup := setupTest(t)
 		runID := "run-no-drift"
@@ -2995,7 +3091,7 @@ func TestService_ExecSandbox_DefinitionDrift(t *testing.T) {
 
 		driftExecSetup(t, setup, runID)
 
-		_, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: configFile,
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -3023,7 +3119,7 @@ func TestService_ExecSandbox_DefinitionDrift(t *testing.T) {
 
 		driftExecSetup(t, setup, runID)
 
-		_, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: configFile,
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -3034,3 +3130,489 @@ func TestService_ExecSandbox_DefinitionDrift(t *testing.T) {
 		require.NotContains(t, setup.mockStderr.String(), "has changed since this sandbox was started")
 	})
 }
+
+func TestService_ExecSandbox_Interrupts(t *testing.T) {
+	interruptExecSetup := func(t *testing.T, setup *testSetup) *[]string {
+		t.Helper()
+		var executedCommands []string
+		setup.mockAPI.MockGetSandboxConnectionInfo = func(id, token string) (api.SandboxConnectionInfo, error) {
+			return api.SandboxConnectionInfo{
+				Sandboxable:    true,
+				Address:        "192.168.1.1:22",
+				PrivateUserKey: sandboxPrivateTestKey,
+				PublicHostKey:  sandboxPublicTestKey,
+			}, nil
+		}
+		setup.mockSSH.MockConnect = func(addr string, _ ssh.ClientConfig) error { return nil }
+		setup.mockSSH.MockExecuteCommand = func(cmd string) (int, error) {
+			executedCommands = append(executedCommands, cmd)
+			return 0, nil
+		}
+		return &executedCommands
+	}
+
+	t.Run("terminates the remote command when the timeout elapses", func(t *testing.T) {
+		setup := setupTest(t)
+		executedCommands := interruptExecSetup(t, setup)
+
+		setup.mockSSH.MockExecuteCommandWithSignals = func(cmd string, signals <-chan ssh.Signal) (int, error) {
+			select {
+			case sig := <-signals:
+				require.Equal(t, ssh.SIGTERM, sig)
+				return 143, nil
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for SIGTERM")
+				return -1, nil
+			}
+		}
+
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
+			Command:    []string{"sleep", "100"},
+			RunID:      "run-timeout",
+			Timeout:    10 * time.Millisecond,
+		})
+
+		require.NoError(t, err)
+		require.True(t, result.TimedOut)
+		require.Equal(t, 143, result.ExitCode)
+		require.Contains(t, setup.mockStderr.String(), "Command timed out after 10ms")
+		require.Contains(t, *executedCommands, "__rwx_sandbox_lock_released__")
+	})
+
+	t.Run("reports a timeout when the timed out command can't be terminated", func(t *testing.T) {
+		setup := setupTest(t)
+		executedCommands := interruptExecSetup(t, setup)
+
+		setup.mockSSH.MockExecuteCommandWithSignals = func(cmd string, signals <-chan ssh.Signal) (int, error) {
+			select {
+			case sig := <-signals:
+				require.Equal(t, ssh.SIGTERM, sig)
+				return -1, errors.New("SSH command execution failed: session closed")
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for SIGTERM")
+				return -1, nil
+			}
+		}
+
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
+			Command:    []string{"sleep", "100"},
+			RunID:      "run-timeout",
+			Timeout:    10 * time.Millisecond,
+		})
+
+		require.Error(t, err)
+		require.ErrorIs(t, err, errors.ErrTimeout)
+		require.Contains(t, err.Error(), "command timed out after 10ms")
+		require.Contains(t, *executedCommands, "__rwx_sandbox_lock_released__")
+	})
+
+	t.Run("forwards SIGINT to the remote command", func(t *testing.T) {
+		setup := setupTest(t)
+		interruptExecSetup(t, setup)
+
+		setup.mockSSH.MockExecuteCommandWithSignals = func(cmd string, signals <-chan ssh.Signal) (int, error) {
+			process, err := os.FindProcess(os.Getpid())
+			require.NoError(t, err)
+			require.NoError(t, process.Signal(os.Interrupt))
+
+			select {
+			case sig := <-signals:
+				require.Equal(t, ssh.SIGINT, sig)
+				return 130, nil
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for SIGINT")
+				return -1, nil
+			}
+		}
+
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
+			Command:    []string{"sleep", "100"},
+			RunID:      "run-interrupt",
+		})
+
+		require.NoError(t, err)
+		require.False(t, result.TimedOut)
+		require.Equal(t, 130, result.ExitCode)
+	})
+
+	t.Run("reverts and releases the lock when the command fails to run", func(t *testing.T) {
+		setup := setupTest(t)
+		executedCommands := interruptExecSetup(t, setup)
+
+		setup.mockSSH.MockExecuteCommandWithSignals = func(cmd string, signals <-chan ssh.Signal) (int, error) {
+			return -1, errors.New("connection lost")
+		}
+
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
+			Command:    []string{"echo", "hello"},
+			RunID:      "run-failure",
+		})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "connection lost")
+
+		commands := *executedCommands
+		require.Equal(t, "__rwx_sandbox_lock_released__", commands[len(commands)-1])
+		reverted := false
+		for _, cmd := range commands {
+			if strings.Contains(cmd, "update-ref refs/rwx-sync HEAD 2>/dev/null") {
+				reverted = true
+			}
+		}
+		require.True(t, reverted, "expected sandbox to be reverted")
+	})
+}
+
+func TestService_ExecSandbox_Env(t *testing.T) {
+	t.Run("applies env and workdir to the remote command", func(t *testing.T) {
+		setup := setupTest(t)
+
+		var executedCommands []string
+		setup.mockAPI.MockGetSandboxConnectionInfo = func(id, token string) (api.SandboxConnectionInfo, error) {
+			return api.SandboxConnectionInfo{
+				Sandboxable:    true,
+				Address:        "192.168.1.1:22",
+				PrivateUserKey: sandboxPrivateTestKey,
+				PublicHostKey:  sandboxPublicTestKey,
+			}, nil
+		}
+		setup.mockSSH.MockConnect = func(addr string, _ ssh.ClientConfig) error { return nil }
+		setup.mockSSH.MockExecuteCommand = func(cmd string) (int, error) {
+			executedCommands = append(executedCommands, cmd)
+			return 0, nil
+		}
+
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
+			Command:    []string{"npm", "test"},
+			RunID:      "run-env",
+			Env:        []string{"NODE_ENV=test"},
+			WorkDir:    "frontend",
+		})
+
+		require.NoError(t, err)
+		require.Contains(t, executedCommands, "cd -- frontend && env -- NODE_ENV=test npm test")
+	})
+
+	t.Run("rejects invalid env before connecting", func(t *testing.T) {
+		setup := setupTest(t)
+
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
+			Command:    []string{"npm", "test"},
+			RunID:      "run-env",
+			Env:        []string{"NODE_ENV"},
+		})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Invalid environment variable")
+	})
+}
+
+func TestService_ExecSandbox_PullThreeWayMerge(t *testing.T) {
+	mergeExecSetup := func(t *testing.T, setup *testSetup, sandboxPatch, base, theirs string) {
+		t.Helper()
+		setup.mockAPI.MockGetSandboxConnectionInfo = func(id, token string) (api.SandboxConnectionInfo, error) {
+			return api.SandboxConnectionInfo{
+				Sandboxable:    true,
+				Address:        "192.168.1.1:22",
+				PrivateUserKey: sandboxPrivateTestKey,
+				PublicHostKey:  sandboxPublicTestKey,
+			}, nil
+		}
+		setup.mockSSH.MockConnect = func(addr string, _ ssh.ClientConfig) error { return nil }
+		setup.mockSSH.MockExecuteCommand = func(cmd string) (int, error) { return 0, nil }
+		setup.mockSSH.MockExecuteCommandWithOutput = func(cmd string) (int, string, error) {
+			switch {
+			case strings.Contains(cmd, "git diff refs/rwx-sync"):
+				return 0, sandboxPatch, nil
+			case cmd == "/usr/bin/git show refs/rwx-sync:file.txt":
+				return 0, base, nil
+			case cmd == "cat -- file.txt":
+				return 0, theirs, nil
+			}
+			return 0, "", nil
+		}
+		setup.mockGit.MockGeneratePatch = func(pathspec []string) ([]byte, *git.LFSChangedFilesMetadata, error) {
+			return nil, nil, nil
+		}
+		// The patch never applies directly, forcing a merge
+		setup.mockGit.MockApplyPatch = func(patch []byte) *exec.Cmd {
+			return exec.Command("false")
+		}
+		setup.mockGit.MockApplyPatchReject = func(patch []byte) *exec.Cmd {
+			t.Fatal("--reject should not be used when the merge succeeds")
+			return nil
+		}
+		realGit := &git.Client{Binary: "git", Dir: setup.tmp}
+		setup.mockGit.MockMergeFile = realGit.MergeFile
+	}
+
+	sandboxPatch := "diff --git a/file.txt b/file.txt\nindex abc..def 100644\n--- a/file.txt\n+++ b/file.txt\n@@ -1,4 +1,4 @@\n-a\n+A\n b\n c\n d\n"
+
+	t.Run("merges non-overlapping changes cleanly", func(t *testing.T) {
+		setup := setupTest(t)
+		mergeExecSetup(t, setup, sandboxPatch, "a\nb\nc\nd\n", "A\nb\nc\nd\n")
+		require.NoError(t, os.WriteFile(filepath.Join(setup.tmp, "file.txt"), []byte("a\nb\nc\nlocal\n"), 0o644))
+
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
+			Command:    []string{"echo", "hello"},
+			RunID:      "run-merge",
+			Json:       true,
+			Sync:       true,
+		})
+
+		require.NoError(t, err)
+		require.Contains(t, result.PulledFiles, "file.txt")
+		require.NotContains(t, setup.mockStderr.String(), "Warning: failed to pull changes")
+
+		merged, err := os.ReadFile(filepath.Join(setup.tmp, "file.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "A\nb\nc\nlocal\n", string(merged))
+		require.NoFileExists(t, filepath.Join(setup.tmp, ".rwx", "sandboxes", "patch-rejected.diff"))
+	})
+
+	t.Run("leaves conflict markers for overlapping changes", func(t *testing.T) {
+		setup := setupTest(t)
+		mergeExecSetup(t, setup, sandboxPatch, "a\nb\nc\nd\n", "A\nb\nc\nd\n")
+		require.NoError(t, os.WriteFile(filepath.Join(setup.tmp, "file.txt"), []byte("local-a\nb\nc\nd\n"), 0o644))
+
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
+			Command:    []string{"echo", "hello"},
+			RunID:      "run-merge-conflict",
+			Json:       true,
+			Sync:       true,
+		})
+
+		require.NoError(t, err)
+		require.Contains(t, setup.mockStderr.String(), "1 file(s) have merge conflicts")
+		require.Contains(t, setup.mockStderr.String(), "Resolve the conflict markers")
+
+		merged, err := os.ReadFile(filepath.Join(setup.tmp, "file.txt"))
+		require.NoError(t, err)
+		require.Contains(t, string(merged), "<<<<<<< local\nlocal-a\n=======\nA\n>>>>>>> sandbox\n")
+		require.NoFileExists(t, filepath.Join(setup.tmp, "file.txt.rej"))
+	})
+
+	t.Run("only rejects the files left when the merge fails partway", func(t *testing.T) {
+		setup := setupTest(t)
+		otherPatch := "diff --git a/other.txt b/other.txt\nindex abc..def 100644\n--- a/other.txt\n+++ b/other.txt\n@@ -1 +1 @@\n-x\n+X\n"
+		mergeExecSetup(t, setup, sandboxPatch+otherPatch, "a\nb\nc\nd\n", "A\nb\nc\nd\n")
+		require.NoError(t, os.WriteFile(filepath.Join(setup.tmp, "file.txt"), []byte("a\nb\nc\nlocal\n"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(setup.tmp, "other.txt"), []byte("x\n"), 0o644))
+
+		// Removing the merge's temporary directory fails it at the next file
+		realGit := &git.Client{Binary: "git", Dir: setup.tmp}
+		setup.mockGit.MockMergeFile = func(localPath, basePath, theirsPath string) (int, error) {
+			conflicts, err := realGit.MergeFile(localPath, basePath, theirsPath)
+			require.NoError(t, os.RemoveAll(filepath.Dir(basePath)))
+			return conflicts, err
+		}
+		var rejectedPatch string
+		setup.mockGit.MockApplyPatchReject = func(patch []byte) *exec.Cmd {
+			rejectedPatch = string(patch)
+			return exec.Command("true")
+		}
+
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
+			Command:    []string{"echo", "hello"},
+			RunID:      "run-merge-partial",
+			Json:       true,
+			Sync:       true,
+		})
+
+		require.NoError(t, err)
+		require.Contains(t, setup.mockStderr.String(), "Warning: unable to write merge base")
+		require.Equal(t, otherPatch, rejectedPatch)
+
+		merged, err := os.ReadFile(filepath.Join(setup.tmp, "file.txt"))
+		require.NoError(t, err)
+		require.Equal(t, "A\nb\nc\nlocal\n", string(merged))
+	})
+}
+
+func TestService_NamedSandboxes(t *testing.T) {
+	// The temp dir is not a git repo, so the branch resolves to "detached"
+	seedNamed := func(t *testing.T, setup *testSetup) {
+		t.Helper()
+		configFile := setup.absConfig(".rwx/sandbox.yml")
+		seedSandboxStorageMulti(t, setup.tmp, map[string]cli.SandboxSession{
+			"detached:" + configFile: {
+				RunID:       "run-default",
+				ConfigFile:  configFile,
+				ScopedToken: "token-default",
+			},
+			"detached:" + configFile + "#backend": {
+				RunID:       "run-backend",
+				ConfigFile:  configFile,
+				Name:        "backend",
+				ScopedToken: "token-backend",
+			},
+		})
+	}
+
+	execSetup := func(t *testing.T, setup *testSetup, connectedRunIDs *[]string) {
+		t.Helper()
+		setup.mockAPI.MockGetSandboxConnectionInfo = func(id, token string) (api.SandboxConnectionInfo, error) {
+			*connectedRunIDs = append(*connectedRunIDs, id)
+			return api.SandboxConnectionInfo{
+				Sandboxable:    true,
+				Address:        "192.168.1.1:22",
+				PrivateUserKey: sandboxPrivateTestKey,
+				PublicHostKey:  sandboxPublicTestKey,
+			}, nil
+		}
+		setup.mockSSH.MockConnect = func(addr string, _ ssh.ClientConfig) error { return nil }
+		setup.mockSSH.MockExecuteCommand = func(cmd string) (int, error) { return 0, nil }
+		setup.mockSSH.MockExecuteCommandWithOutput = func(cmd string) (int, string, error) { return 0, "", nil }
+	}
+
+	t.Run("exec with --name uses the named sandbox", func(t *testing.T) {
+		setup := setupTest(t)
+		seedNamed(t, setup)
+
+		var connected []string
+		execSetup(t, setup, &connected)
+
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			Name:    "backend",
+			Command: []string{"echo", "hello"},
+			Json:    true,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "run-backend", result.RunID)
+		require.NotContains(t, connected, "run-default")
+	})
+
+	t.Run("exec without --name ignores named sandboxes", func(t *testing.T) {
+		setup := setupTest(t)
+		seedNamed(t, setup)
+
+		var connected []string
+		execSetup(t, setup, &connected)
+
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			Command: []string{"echo", "hello"},
+			Json:    true,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "run-default", result.RunID)
+		require.NotContains(t, connected, "run-backend")
+	})
+
+	t.Run("exec with a new name starts a separate sandbox", func(t *testing.T) {
+		setup := setupTest(t)
+		seedNamed(t, setup)
+
+		var connected []string
+		execSetup(t, setup, &connected)
+
+		rwxDir := filepath.Join(setup.tmp, ".rwx")
+		require.NoError(t, os.MkdirAll(rwxDir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(rwxDir, "sandbox.yml"), []byte("tasks:\n  - key: sandbox\n    run: rwx-sandbox\n"), 0o644))
+
+		setup.mockGit.MockGetBranch = "main"
+		setup.mockGit.MockGetCommit = "abc123"
+		setup.mockGit.MockGetOriginUrl = "git@github.com:example/repo.git"
+		setup.mockAPI.MockGetDefaultBase = func() (api.DefaultBaseResult, error) {
+			return api.DefaultBaseResult{Image: "ubuntu:24.04", Config: "rwx/base 1.0.0", Arch: "x86_64"}, nil
+		}
+		setup.mockAPI.MockGetPackageVersions = func() (*api.PackageVersionsResult, error) {
+			return &api.PackageVersionsResult{
+				LatestMajor: make(map[string]string),
+				LatestMinor: make(map[string]map[string]string),
+			}, nil
+		}
+
+		var startedTitle string
+		setup.mockAPI.MockInitiateRun = func(cfg api.InitiateRunConfig) (*api.InitiateRunResult, error) {
+			startedTitle = cfg.Title
+			return &api.InitiateRunResult{RunID: "run-frontend", RunURL: "https://cloud.rwx.com/runs/run-frontend"}, nil
+		}
+		setup.mockAPI.MockCreateSandboxToken = func(cfg api.CreateSandboxTokenConfig) (*api.CreateSandboxTokenResult, error) {
+			return &api.CreateSandboxTokenResult{Token: "token-frontend"}, nil
+		}
+		setup.mockAPI.MockListSandboxRuns = func() (*api.ListSandboxRunsResult, error) {
+			return &api.ListSandboxRunsResult{}, nil
+		}
+
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
+			Name:       "frontend",
+			Command:    []string{"echo", "hello"},
+			Json:       true,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "run-frontend", result.RunID)
+		require.Contains(t, startedTitle, "/frontend")
+
+		storage, err := cli.LoadSandboxStorage()
+		require.NoError(t, err)
+		require.Len(t, storage.Sandboxes, 3)
+		session, found := storage.GetSession("detached", setup.absConfig(".rwx/sandbox.yml"), "frontend")
+		require.True(t, found)
+		require.Equal(t, "run-frontend", session.RunID)
+		require.Equal(t, "frontend", session.Name)
+	})
+
+	t.Run("rejects invalid names", func(t *testing.T) {
+		setup := setupTest(t)
+
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
+			Name:    "not a name",
+			Command: []string{"echo", "hello"},
+			Json:    true,
+		})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Invalid sandbox name")
+	})
+
+	t.Run("stop with --name stops only the named sandbox", func(t *testing.T) {
+		setup := setupTest(t)
+		seedNamed(t, setup)
+
+		setup.mockAPI.MockGetSandboxConnectionInfo = func(id, token string) (api.SandboxConnectionInfo, error) {
+			return api.SandboxConnectionInfo{Polling: api.PollingResult{Completed: true}}, nil
+		}
+
+		result, err := setup.service.StopSandbox(t.Context(), cli.StopSandboxConfig{
+			Name: "backend",
+			Json: true,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, result.Stopped, 1)
+		require.Equal(t, "run-backend", result.Stopped[0].RunID)
+
+		storage, err := cli.LoadSandboxStorage()
+		require.NoError(t, err)
+		_, found := storage.GetSession("detached", setup.absConfig(".rwx/sandbox.yml"), "")
+		require.True(t, found)
+		_, found = storage.GetSession("detached", setup.absConfig(".rwx/sandbox.yml"), "backend")
+		require.False(t, found)
+	})
+
+	t.Run("stop with an unknown name errors", func(t *testing.T) {
+		setup := setupTest(t)
+		seedNamed(t, setup)
+
+		_, err := setup.service.StopSandbox(t.Context(), cli.StopSandboxConfig{
+			Name: "frontend",
+			Json: true,
+		})
+
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "No sandbox named 'frontend'")
+	})
+}
@@ -1,7 +1,7 @@
 package cli
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -33,7 +33,7 @@ func (c DeleteSecretConfig) Validate() error {
 
 type DeleteSecretResult struct{}
 
-func (s Service) DeleteSecret(cfg DeleteSecretConfig) (*DeleteSecretResult, error) {
+func (s Service) DeleteSecret(ctx context.Context, cfg DeleteSecretConfig) (*DeleteSecretResult, error) {
 	err := cfg.Validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "validation failed")
@@ -46,7 +46,7 @@ func (s Service) DeleteSecret(cfg DeleteSecretConfig) (*DeleteSecretResult, erro
 		return nil, err
 	}
 
-	_, err = s.APIClient.DeleteSecret(api.DeleteSecretConfig{
+	_, err = s.APIClient.DeleteSecret(ctx, api.DeleteSecretConfig{
 		SecretName: cfg.SecretName,
 		VaultName:  cfg.Vault,
 	})
@@ -62,7 +62,7 @@ func (s Service) DeleteSecret(cfg DeleteSecretConfig) (*DeleteSecretResult, erro
 			Secret: cfg.SecretName,
 			Vault:  cfg.Vault,
 		}
-		if err := json.NewEncoder(s.Stdout).Encode(output); err != nil {
+		if err := s.Output.Write(s.Stdout, output); err != nil {
 			return nil, errors.Wrap(err, "unable to encode JSON output")
 		}
 	} else {
@@ -91,7 +91,7 @@ func (c SetSecretsInVaultConfig) Validate() error {
 	return nil
 }
 
-func (s Service) SetSecretsInVault(cfg SetSecretsInVaultConfig) (*api.SetSecretsInVaultResult, error) {
+func (s Service) SetSecretsInVault(ctx context.Context, cfg SetSecretsInVaultConfig) (*api.SetSecretsInVaultResult, error) {
 	err := cfg.Validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "validation failed")
@@ -135,7 +135,7 @@ func (s Service) SetSecretsInVault(cfg SetSecretsInVaultConfig) (*api.SetSecrets
 		}
 	}
 
-	result, err := s.APIClient.SetSecretsInVault(api.SetSecretsInVaultConfig{
+	result, err := s.APIClient.SetSecretsInVault(ctx, api.SetSecretsInVaultConfig{
 		VaultName: cfg.Vault,
 		Secrets:   secrets,
 	})
@@ -152,7 +152,7 @@ func (s Service) SetSecretsInVault(cfg SetSecretsInVaultConfig) (*api.SetSecrets
 			Vault:      cfg.Vault,
 			SetSecrets: result.SetSecrets,
 		}
-		if err := json.NewEncoder(s.Stdout).Encode(output); err != nil {
+		if err := s.Output.Write(s.Stdout, output); err != nil {
 			return nil, errors.Wrap(err, "unable to encode JSON output")
 		}
 	} else if result != nil && len(result.SetSecrets) > 0 {
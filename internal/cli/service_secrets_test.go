@@ -23,7 +23,7 @@ func TestService_SettingSecrets(t *testing.T) {
 			return nil, errors.New("error setting secret")
 		}
 
-		result, err := s.service.SetSecretsInVault(cli.SetSecretsInVaultConfig{
+		result, err := s.service.SetSecretsInVault(t.Context(), cli.SetSecretsInVaultConfig{
 			Vault:   "default",
 			Secrets: []string{"ABC=123"},
 		})
@@ -47,7 +47,7 @@ func TestService_SettingSecrets(t *testing.T) {
 			}, nil
 		}
 
-		result, err := s.service.SetSecretsInVault(cli.SetSecretsInVaultConfig{
+		result, err := s.service.SetSecretsInVault(t.Context(), cli.SetSecretsInVaultConfig{
 			Vault:   "default",
 			Secrets: []string{"ABC=123", `DEF="xyz"`},
 		})
@@ -82,7 +82,7 @@ func TestService_SettingSecrets(t *testing.T) {
 		err := os.WriteFile(secretsFile, []byte("A=123\nB=\"xyz\"\nC='q\\nqq'\nD=\"a multiline\nstring\nspanning lines\""), 0o644)
 		require.NoError(t, err)
 
-		result, err := s.service.SetSecretsInVault(cli.SetSecretsInVaultConfig{
+		result, err := s.service.SetSecretsInVault(t.Context(), cli.SetSecretsInVaultConfig{
 			Vault:   "default",
 			Secrets: []string{},
 			File:    secretsFile,
@@ -104,7 +104,7 @@ func TestService_DeleteSecret(t *testing.T) {
 			return &api.DeleteSecretResult{}, nil
 		}
 
-		result, err := s.service.DeleteSecret(cli.DeleteSecretConfig{
+		result, err := s.service.DeleteSecret(t.Context(), cli.DeleteSecretConfig{
 			SecretName: "MY_SECRET",
 			Vault:      "default",
 			Yes:        true,
@@ -122,7 +122,7 @@ func TestService_DeleteSecret(t *testing.T) {
 			return nil, errors.New("not found")
 		}
 
-		result, err := s.service.DeleteSecret(cli.DeleteSecretConfig{
+		result, err := s.service.DeleteSecret(t.Context(), cli.DeleteSecretConfig{
 			SecretName: "MISSING",
 			Vault:      "default",
 			Yes:        true,
@@ -140,7 +140,7 @@ func TestService_DeleteSecret(t *testing.T) {
 			return &api.DeleteSecretResult{}, nil
 		}
 
-		result, err := s.service.DeleteSecret(cli.DeleteSecretConfig{
+		result, err := s.service.DeleteSecret(t.Context(), cli.DeleteSecretConfig{
 			SecretName: "MY_SECRET",
 			Vault:      "default",
 			Json:       true,
@@ -156,7 +156,7 @@ func TestService_DeleteSecret(t *testing.T) {
 	t.Run("requires --yes in non-interactive environments", func(t *testing.T) {
 		s := setupTest(t)
 
-		_, err := s.service.DeleteSecret(cli.DeleteSecretConfig{
+		_, err := s.service.DeleteSecret(t.Context(), cli.DeleteSecretConfig{
 			SecretName: "MY_SECRET",
 			Vault:      "default",
 		})
@@ -173,7 +173,7 @@ func TestService_DeleteSecret(t *testing.T) {
 			return &api.DeleteSecretResult{}, nil
 		}
 
-		result, err := s.service.DeleteSecret(cli.DeleteSecretConfig{
+		result, err := s.service.DeleteSecret(t.Context(), cli.DeleteSecretConfig{
 			SecretName: "MY_SECRET",
 			Vault:      "default",
 		})
@@ -187,7 +187,7 @@ func TestService_DeleteSecret(t *testing.T) {
 		s := setupTestWithTTY(t)
 		s.mockStdin.WriteString("n\n")
 
-		_, err := s.service.DeleteSecret(cli.DeleteSecretConfig{
+		_, err := s.service.DeleteSecret(t.Context(), cli.DeleteSecretConfig{
 			SecretName: "MY_SECRET",
 			Vault:      "default",
 		})
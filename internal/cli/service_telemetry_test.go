@@ -14,6 +14,7 @@ import (
 	"github.com/rwx-cloud/rwx/internal/api"
 	"github.com/rwx-cloud/rwx/internal/cli"
 	"github.com/rwx-cloud/rwx/internal/git"
+	internalssh "github.com/rwx-cloud/rwx/internal/ssh"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/ssh"
 )
@@ -59,7 +60,7 @@ func TestTelemetry_Login(t *testing.T) {
 
 		backend := &mockAccessTokenBackend{}
 
-		err := setup.service.Login(cli.LoginConfig{
+		err := setup.service.Login(t.Context(), cli.LoginConfig{
 			DeviceName:         "test-device",
 			AccessTokenBackend: backend,
 			OpenUrl:            func(url string) error { return nil },
@@ -93,7 +94,7 @@ func TestTelemetry_Login(t *testing.T) {
 
 		backend := &mockAccessTokenBackend{}
 
-		err := setup.service.Login(cli.LoginConfig{
+		err := setup.service.Login(t.Context(), cli.LoginConfig{
 			DeviceName:         "test-device",
 			AccessTokenBackend: backend,
 			OpenUrl:            func(url string) error { return nil },
@@ -131,7 +132,7 @@ func TestTelemetry_ImagePush(t *testing.T) {
 		config, err := cli.NewImagePushConfig("task-123", []string{"docker.io/test/repo:latest"}, "zstd", true, false, func(url string) error { return nil })
 		require.NoError(t, err)
 
-		_, err = setup.service.ImagePush(config)
+		_, err = setup.service.ImagePush(t.Context(), config)
 		require.NoError(t, err)
 
 		events := setup.drainEvents()
@@ -182,7 +183,7 @@ func TestTelemetry_ImageBuild(t *testing.T) {
 		setup.mockDocker.RegistryValue = "registry.rwx.com"
 		setup.mockDocker.PasswordValue = "docker-pass"
 
-		_, err := setup.service.ImageBuild(cli.ImageBuildConfig{
+		_, err := setup.service.ImageBuild(t.Context(), cli.ImageBuildConfig{
 			MintFilePath:  ".rwx/build.yml",
 			TargetTaskKey: "build-task",
 			NoPull:        true,
@@ -212,7 +213,7 @@ func TestTelemetry_ImagePull(t *testing.T) {
 		setup.mockDocker.RegistryValue = "registry.rwx.com"
 		setup.mockDocker.PasswordValue = "docker-pass"
 
-		_, err := setup.service.ImagePull(cli.ImagePullConfig{
+		_, err := setup.service.ImagePull(t.Context(), cli.ImagePullConfig{
 			TaskID:     "task-pull-1",
 			Timeout:    10 * time.Second,
 			OutputJSON: true,
@@ -248,7 +249,7 @@ func TestTelemetry_ArtifactDownload(t *testing.T) {
 		outputDir := filepath.Join(setup.tmp, "output")
 		require.NoError(t, os.MkdirAll(outputDir, 0o755))
 
-		_, err := setup.service.DownloadArtifact(cli.DownloadArtifactConfig{
+		_, err := setup.service.DownloadArtifact(t.Context(), cli.DownloadArtifactConfig{
 			TaskID:      "task-1",
 			ArtifactKey: "test-artifact",
 			OutputDir:   outputDir,
@@ -283,7 +284,7 @@ func TestTelemetry_ArtifactDownload(t *testing.T) {
 		outputDir := filepath.Join(setup.tmp, "output-all")
 		require.NoError(t, os.MkdirAll(outputDir, 0o755))
 
-		_, err := setup.service.DownloadAllArtifacts(cli.DownloadAllArtifactsConfig{
+		_, err := setup.service.DownloadAllArtifacts(t.Context(), cli.DownloadAllArtifactsConfig{
 			TaskID:    "task-2",
 			OutputDir: outputDir,
 			Json:      true,
@@ -320,7 +321,7 @@ func TestTelemetry_LogsDownload(t *testing.T) {
 		outputDir := filepath.Join(setup.tmp, "logs-output")
 		require.NoError(t, os.MkdirAll(outputDir, 0o755))
 
-		_, err := setup.service.DownloadLogs(cli.DownloadLogsConfig{
+		_, err := setup.service.DownloadLogs(t.Context(), cli.DownloadLogsConfig{
 			TaskID:    "task-logs-1",
 			OutputDir: outputDir,
 			Json:      true,
@@ -352,7 +353,7 @@ func TestTelemetry_PackagesResolve(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.ResolvePackages(cli.ResolvePackagesConfig{
+		result, err := setup.service.ResolvePackages(t.Context(), cli.ResolvePackagesConfig{
 			RwxDirectory:        filepath.Join(setup.tmp, ".rwx"),
 			LatestVersionPicker: cli.PickLatestMajorVersion,
 			Json:                true,
@@ -385,7 +386,7 @@ func TestTelemetry_PackagesUpdate(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.UpdatePackages(cli.UpdatePackagesConfig{
+		result, err := setup.service.UpdatePackages(t.Context(), cli.UpdatePackagesConfig{
 			RwxDirectory:             filepath.Join(setup.tmp, ".rwx"),
 			ReplacementVersionPicker: cli.PickLatestMinorVersion,
 			Json:                     true,
@@ -462,7 +463,7 @@ func TestTelemetry_SandboxStart(t *testing.T) {
 			return &api.PackageVersionsResult{}, nil
 		}
 
-		result, err := setup.service.StartSandbox(cli.StartSandboxConfig{
+		result, err := setup.service.StartSandbox(t.Context(), cli.StartSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Json:       true,
 		})
@@ -492,7 +493,7 @@ func TestTelemetry_SandboxStart(t *testing.T) {
 			return &api.CreateSandboxTokenResult{Token: "token-456"}, nil
 		}
 
-		result, err := setup.service.StartSandbox(cli.StartSandboxConfig{
+		result, err := setup.service.StartSandbox(t.Context(), cli.StartSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			RunID:      runID,
 			Json:       true,
@@ -535,7 +536,7 @@ func TestTelemetry_SessionCreatedAt(t *testing.T) {
 			return &api.PackageVersionsResult{}, nil
 		}
 
-		_, err := setup.service.StartSandbox(cli.StartSandboxConfig{
+		_, err := setup.service.StartSandbox(t.Context(), cli.StartSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Json:       true,
 		})
@@ -545,7 +546,7 @@ func TestTelemetry_SessionCreatedAt(t *testing.T) {
 		branch := cli.GetCurrentGitBranch(setup.tmp)
 		storage, err := cli.LoadSandboxStorage()
 		require.NoError(t, err)
-		session, ok := storage.GetSession(branch, setup.absConfig(".rwx/sandbox.yml"))
+		session, ok := storage.GetSession(branch, setup.absConfig(".rwx/sandbox.yml"), "")
 		require.True(t, ok)
 		require.NotNil(t, session.CreatedAt)
 		require.True(t, session.CreatedAt.After(before))
@@ -580,7 +581,7 @@ func TestTelemetry_SSHConnect(t *testing.T) {
 			return nil, nil, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo", "hello"},
 			RunID:      runID,
@@ -621,7 +622,7 @@ func TestTelemetry_SSHConnect(t *testing.T) {
 			return &ssh.ExitError{}
 		}
 
-		_, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo"},
 			RunID:      runID,
@@ -654,11 +655,11 @@ func TestTelemetry_DebugSSH(t *testing.T) {
 			return nil
 		}
 
-		setup.mockSSH.MockInteractiveSession = func() error {
+		setup.mockSSH.MockInteractiveSession = func(_ internalssh.TerminalRecorder) error {
 			return nil
 		}
 
-		err := setup.service.DebugTask(cli.DebugTaskConfig{DebugKey: "run-debug-123"})
+		err := setup.service.DebugTask(t.Context(), cli.DebugTaskConfig{DebugKey: "run-debug-123"})
 		require.NoError(t, err)
 
 		events := setup.drainEvents()
@@ -703,7 +704,7 @@ func TestTelemetry_SandboxExec(t *testing.T) {
 			return []byte("mock patch"), nil, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"make", "test"},
 			RunID:      "run-exec-123",
@@ -759,7 +760,7 @@ func TestTelemetry_SandboxExecNonZeroExitCode(t *testing.T) {
 			return []byte("mock patch"), nil, nil
 		}
 
-		result, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		result, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"false"},
 			RunID:      "run-exec-fail",
@@ -809,7 +810,7 @@ func TestTelemetry_SandboxSyncPush(t *testing.T) {
 			return patchData, nil, nil
 		}
 
-		_, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo"},
 			RunID:      "run-sync-push",
@@ -853,7 +854,7 @@ func TestTelemetry_SandboxSyncPull(t *testing.T) {
 			return nil, nil, nil
 		}
 
-		_, err := setup.service.ExecSandbox(cli.ExecSandboxConfig{
+		_, err := setup.service.ExecSandbox(t.Context(), cli.ExecSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Command:    []string{"echo"},
 			RunID:      "run-sync-pull",
@@ -882,7 +883,7 @@ func TestTelemetry_SandboxStop(t *testing.T) {
 
 		storage, err := cli.LoadSandboxStorage()
 		require.NoError(t, err)
-		storage.SetSession(branch, setup.absConfig(".rwx/sandbox.yml"), cli.SandboxSession{
+		storage.SetSession(branch, setup.absConfig(".rwx/sandbox.yml"), "", cli.SandboxSession{
 			RunID:      "run-stop-123",
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			CreatedAt:  &createdAt,
@@ -897,7 +898,7 @@ func TestTelemetry_SandboxStop(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.StopSandbox(cli.StopSandboxConfig{
+		result, err := setup.service.StopSandbox(t.Context(), cli.StopSandboxConfig{
 			Json: true,
 		})
 
@@ -945,7 +946,7 @@ func TestTelemetry_SandboxReset(t *testing.T) {
 			return &api.PackageVersionsResult{}, nil
 		}
 
-		result, err := setup.service.ResetSandbox(cli.ResetSandboxConfig{
+		result, err := setup.service.ResetSandbox(t.Context(), cli.ResetSandboxConfig{
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 			Json:       true,
 		})
@@ -969,11 +970,11 @@ func TestTelemetry_SandboxList(t *testing.T) {
 		// Seed one active and one expired session
 		storage, err := cli.LoadSandboxStorage()
 		require.NoError(t, err)
-		storage.SetSession(branch, setup.absConfig(".rwx/sandbox.yml"), cli.SandboxSession{
+		storage.SetSession(branch, setup.absConfig(".rwx/sandbox.yml"), "", cli.SandboxSession{
 			RunID:      "run-list-active",
 			ConfigFile: setup.absConfig(".rwx/sandbox.yml"),
 		})
-		storage.SetSession(branch, setup.absConfig(".rwx/other.yml"), cli.SandboxSession{
+		storage.SetSession(branch, setup.absConfig(".rwx/other.yml"), "", cli.SandboxSession{
 			RunID:      "run-list-expired",
 			ConfigFile: setup.absConfig(".rwx/other.yml"),
 		})
@@ -998,7 +999,7 @@ func TestTelemetry_SandboxList(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.ListSandboxes(cli.ListSandboxesConfig{Json: true})
+		result, err := setup.service.ListSandboxes(t.Context(), cli.ListSandboxesConfig{Json: true})
 		require.NoError(t, err)
 		require.Len(t, result.Sandboxes, 1)
 
@@ -1038,7 +1039,7 @@ func TestTelemetry_RunInitiate(t *testing.T) {
 			return &api.PackageVersionsResult{}, nil
 		}
 
-		result, err := setup.service.InitiateRun(cli.InitiateRunConfig{
+		result, err := setup.service.InitiateRun(t.Context(), cli.InitiateRunConfig{
 			MintFilePath:  ".rwx/run.yml",
 			Patchable:     true,
 			TargetedTasks: []string{"test"},
@@ -1072,7 +1073,7 @@ func TestTelemetry_RunComplete(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.GetRunStatus(cli.GetRunStatusConfig{
+		result, err := setup.service.GetRunStatus(t.Context(), cli.GetRunStatusConfig{
 			RunID: "run-complete-123",
 			Wait:  true,
 			Json:  true,
@@ -1104,7 +1105,7 @@ func TestTelemetry_RunComplete(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.GetRunStatus(cli.GetRunStatusConfig{
+		result, err := setup.service.GetRunStatus(t.Context(), cli.GetRunStatusConfig{
 			RunID: "run-pending-123",
 			Wait:  false,
 			Json:  true,
@@ -1,7 +1,7 @@
 package cli
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -35,7 +35,7 @@ type SetVarsResult struct {
 	SetVars []string
 }
 
-func (s Service) SetVars(cfg SetVarsConfig) (*SetVarsResult, error) {
+func (s Service) SetVars(ctx context.Context, cfg SetVarsConfig) (*SetVarsResult, error) {
 	err := cfg.Validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "validation failed")
@@ -82,7 +82,7 @@ func (s Service) SetVars(cfg SetVarsConfig) (*SetVarsResult, error) {
 	setVars := []string{}
 	var setErrors []string
 	for _, v := range vars {
-		_, err := s.APIClient.SetVar(api.SetVarConfig{
+		_, err := s.APIClient.SetVar(ctx, api.SetVarConfig{
 			VaultName: cfg.Vault,
 			Var:       v,
 		})
@@ -103,7 +103,7 @@ func (s Service) SetVars(cfg SetVarsConfig) (*SetVarsResult, error) {
 		}{
 			SetVars: setVars,
 		}
-		if err := json.NewEncoder(s.Stdout).Encode(output); err != nil {
+		if err := s.Output.Write(s.Stdout, output); err != nil {
 			return nil, errors.Wrap(err, "unable to encode JSON output")
 		}
 	} else if len(setVars) > 0 {
@@ -140,13 +140,13 @@ type ShowVarResult struct {
 	Value string
 }
 
-func (s Service) ShowVar(cfg ShowVarConfig) (*ShowVarResult, error) {
+func (s Service) ShowVar(ctx context.Context, cfg ShowVarConfig) (*ShowVarResult, error) {
 	err := cfg.Validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "validation failed")
 	}
 
-	apiResult, err := s.APIClient.ShowVar(api.ShowVarConfig{
+	apiResult, err := s.APIClient.ShowVar(ctx, api.ShowVarConfig{
 		VarName:   cfg.VarName,
 		VaultName: cfg.Vault,
 	})
@@ -167,7 +167,7 @@ func (s Service) ShowVar(cfg ShowVarConfig) (*ShowVarResult, error) {
 			Name:  apiResult.Name,
 			Value: apiResult.Value,
 		}
-		if err := json.NewEncoder(s.Stdout).Encode(output); err != nil {
+		if err := s.Output.Write(s.Stdout, output); err != nil {
 			return nil, errors.Wrap(err, "unable to encode JSON output")
 		}
 	} else {
@@ -198,7 +198,7 @@ func (c DeleteVarConfig) Validate() error {
 
 type DeleteVarResult struct{}
 
-func (s Service) DeleteVar(cfg DeleteVarConfig) (*DeleteVarResult, error) {
+func (s Service) DeleteVar(ctx context.Context, cfg DeleteVarConfig) (*DeleteVarResult, error) {
 	err := cfg.Validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "validation failed")
@@ -211,7 +211,7 @@ func (s Service) DeleteVar(cfg DeleteVarConfig) (*DeleteVarResult, error) {
 		return nil, err
 	}
 
-	_, err = s.APIClient.DeleteVar(api.DeleteVarConfig{
+	_, err = s.APIClient.DeleteVar(ctx, api.DeleteVarConfig{
 		VarName:   cfg.VarName,
 		VaultName: cfg.Vault,
 	})
@@ -227,7 +227,7 @@ func (s Service) DeleteVar(cfg DeleteVarConfig) (*DeleteVarResult, error) {
 			Var:   cfg.VarName,
 			Vault: cfg.Vault,
 		}
-		if err := json.NewEncoder(s.Stdout).Encode(output); err != nil {
+		if err := s.Output.Write(s.Stdout, output); err != nil {
 			return nil, errors.Wrap(err, "unable to encode JSON output")
 		}
 	} else {
@@ -19,7 +19,7 @@ func TestService_SetVars(t *testing.T) {
 			return nil, errors.New("error setting var")
 		}
 
-		result, err := s.service.SetVars(cli.SetVarsConfig{
+		result, err := s.service.SetVars(t.Context(), cli.SetVarsConfig{
 			Vault: "default",
 			Vars:  []string{"ABC=123"},
 		})
@@ -37,7 +37,7 @@ func TestService_SetVars(t *testing.T) {
 			return &api.SetVarResult{}, nil
 		}
 
-		result, err := s.service.SetVars(cli.SetVarsConfig{
+		result, err := s.service.SetVars(t.Context(), cli.SetVarsConfig{
 			Vault: "default",
 			Vars:  []string{"ABC=123", "DEF=xyz"},
 		})
@@ -59,7 +59,7 @@ func TestService_SetVars(t *testing.T) {
 		err := os.WriteFile(varsFile, []byte("A=123\nB=xyz"), 0o644)
 		require.NoError(t, err)
 
-		result, err := s.service.SetVars(cli.SetVarsConfig{
+		result, err := s.service.SetVars(t.Context(), cli.SetVarsConfig{
 			Vault: "default",
 			Vars:  []string{},
 			File:  varsFile,
@@ -72,7 +72,7 @@ func TestService_SetVars(t *testing.T) {
 	t.Run("with invalid var format", func(t *testing.T) {
 		s := setupTest(t)
 
-		result, err := s.service.SetVars(cli.SetVarsConfig{
+		result, err := s.service.SetVars(t.Context(), cli.SetVarsConfig{
 			Vault: "default",
 			Vars:  []string{"BADFORMAT"},
 		})
@@ -89,7 +89,7 @@ func TestService_SetVars(t *testing.T) {
 			return &api.SetVarResult{}, nil
 		}
 
-		result, err := s.service.SetVars(cli.SetVarsConfig{
+		result, err := s.service.SetVars(t.Context(), cli.SetVarsConfig{
 			Vault: "default",
 			Vars:  []string{"ABC=123"},
 			Json:  true,
@@ -114,7 +114,7 @@ func TestService_ShowVar(t *testing.T) {
 			}, nil
 		}
 
-		result, err := s.service.ShowVar(cli.ShowVarConfig{
+		result, err := s.service.ShowVar(t.Context(), cli.ShowVarConfig{
 			VarName: "MY_VAR",
 			Vault:   "default",
 		})
@@ -132,7 +132,7 @@ func TestService_ShowVar(t *testing.T) {
 			return nil, errors.New("not found")
 		}
 
-		result, err := s.service.ShowVar(cli.ShowVarConfig{
+		result, err := s.service.ShowVar(t.Context(), cli.ShowVarConfig{
 			VarName: "MISSING",
 			Vault:   "default",
 		})
@@ -152,7 +152,7 @@ func TestService_ShowVar(t *testing.T) {
 			}, nil
 		}
 
-		result, err := s.service.ShowVar(cli.ShowVarConfig{
+		result, err := s.service.ShowVar(t.Context(), cli.ShowVarConfig{
 			VarName: "MY_VAR",
 			Vault:   "default",
 			Json:    true,
@@ -175,7 +175,7 @@ func TestService_DeleteVar(t *testing.T) {
 			return &api.DeleteVarResult{}, nil
 		}
 
-		result, err := s.service.DeleteVar(cli.DeleteVarConfig{
+		result, err := s.service.DeleteVar(t.Context(), cli.DeleteVarConfig{
 			VarName: "MY_VAR",
 			Vault:   "default",
 			Yes:     true,
@@ -193,7 +193,7 @@ func TestService_DeleteVar(t *testing.T) {
 			return nil, errors.New("not found")
 		}
 
-		result, err := s.service.DeleteVar(cli.DeleteVarConfig{
+		result, err := s.service.DeleteVar(t.Context(), cli.DeleteVarConfig{
 			VarName: "MISSING",
 			Vault:   "default",
 			Yes:     true,
@@ -211,7 +211,7 @@ func TestService_DeleteVar(t *testing.T) {
 			return &api.DeleteVarResult{}, nil
 		}
 
-		result, err := s.service.DeleteVar(cli.DeleteVarConfig{
+		result, err := s.service.DeleteVar(t.Context(), cli.DeleteVarConfig{
 			VarName: "MY_VAR",
 			Vault:   "default",
 			Json:    true,
@@ -227,7 +227,7 @@ func TestService_DeleteVar(t *testing.T) {
 	t.Run("requires --yes in non-interactive environments", func(t *testing.T) {
 		s := setupTest(t)
 
-		_, err := s.service.DeleteVar(cli.DeleteVarConfig{
+		_, err := s.service.DeleteVar(t.Context(), cli.DeleteVarConfig{
 			VarName: "MY_VAR",
 			Vault:   "default",
 		})
@@ -244,7 +244,7 @@ func TestService_DeleteVar(t *testing.T) {
 			return &api.DeleteVarResult{}, nil
 		}
 
-		result, err := s.service.DeleteVar(cli.DeleteVarConfig{
+		result, err := s.service.DeleteVar(t.Context(), cli.DeleteVarConfig{
 			VarName: "MY_VAR",
 			Vault:   "default",
 		})
@@ -258,7 +258,7 @@ func TestService_DeleteVar(t *testing.T) {
 		s := setupTestWithTTY(t)
 		s.mockStdin.WriteString("n\n")
 
-		_, err := s.service.DeleteVar(cli.DeleteVarConfig{
+		_, err := s.service.DeleteVar(t.Context(), cli.DeleteVarConfig{
 			VarName: "MY_VAR",
 			Vault:   "default",
 		})
@@ -1,7 +1,7 @@
 package cli
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 
 	"github.com/rwx-cloud/rwx/internal/api"
@@ -23,7 +23,7 @@ type CreateVaultOidcTokenResult struct {
 	DocumentationURL string
 }
 
-func (s Service) CreateVaultOidcToken(cfg CreateVaultOidcTokenConfig) (*CreateVaultOidcTokenResult, error) {
+func (s Service) CreateVaultOidcToken(ctx context.Context, cfg CreateVaultOidcTokenConfig) (*CreateVaultOidcTokenResult, error) {
 	if cfg.Provider == "" {
 		if cfg.Name == "" {
 			return nil, errors.New("--name is required (or use --provider)")
@@ -33,7 +33,7 @@ func (s Service) CreateVaultOidcToken(cfg CreateVaultOidcTokenConfig) (*CreateVa
 		}
 	}
 
-	apiResult, err := s.APIClient.CreateVaultOidcToken(api.CreateVaultOidcTokenConfig{
+	apiResult, err := s.APIClient.CreateVaultOidcToken(ctx, api.CreateVaultOidcTokenConfig{
 		VaultName: cfg.Vault,
 		Name:      cfg.Name,
 		Audience:  cfg.Audience,
@@ -62,7 +62,7 @@ func (s Service) CreateVaultOidcToken(cfg CreateVaultOidcTokenConfig) (*CreateVa
 			Expression:       result.Expression,
 			DocumentationURL: result.DocumentationURL,
 		}
-		if err := json.NewEncoder(s.Stdout).Encode(output); err != nil {
+		if err := s.Output.Write(s.Stdout, output); err != nil {
 			return nil, errors.Wrap(err, "unable to encode JSON output")
 		}
 	} else {
@@ -26,7 +26,7 @@ func TestService_CreateVaultOidcToken(t *testing.T) {
 			}, nil
 		}
 
-		result, err := s.service.CreateVaultOidcToken(cli.CreateVaultOidcTokenConfig{
+		result, err := s.service.CreateVaultOidcToken(t.Context(), cli.CreateVaultOidcTokenConfig{
 			Vault:    "my-vault",
 			Name:     "my-token",
 			Audience: "sts.amazonaws.com",
@@ -57,7 +57,7 @@ func TestService_CreateVaultOidcToken(t *testing.T) {
 			}, nil
 		}
 
-		result, err := s.service.CreateVaultOidcToken(cli.CreateVaultOidcTokenConfig{
+		result, err := s.service.CreateVaultOidcToken(t.Context(), cli.CreateVaultOidcTokenConfig{
 			Vault:    "my-vault",
 			Provider: "aws",
 		})
@@ -81,7 +81,7 @@ func TestService_CreateVaultOidcToken(t *testing.T) {
 			}, nil
 		}
 
-		result, err := s.service.CreateVaultOidcToken(cli.CreateVaultOidcTokenConfig{
+		result, err := s.service.CreateVaultOidcToken(t.Context(), cli.CreateVaultOidcTokenConfig{
 			Vault:    "my-vault",
 			Provider: "aws",
 			Name:     "custom",
@@ -95,7 +95,7 @@ func TestService_CreateVaultOidcToken(t *testing.T) {
 	t.Run("errors when name is missing without provider", func(t *testing.T) {
 		s := setupTest(t)
 
-		result, err := s.service.CreateVaultOidcToken(cli.CreateVaultOidcTokenConfig{
+		result, err := s.service.CreateVaultOidcToken(t.Context(), cli.CreateVaultOidcTokenConfig{
 			Vault:    "my-vault",
 			Audience: "sts.amazonaws.com",
 		})
@@ -108,7 +108,7 @@ func TestService_CreateVaultOidcToken(t *testing.T) {
 	t.Run("errors when audience is missing without provider", func(t *testing.T) {
 		s := setupTest(t)
 
-		result, err := s.service.CreateVaultOidcToken(cli.CreateVaultOidcTokenConfig{
+		result, err := s.service.CreateVaultOidcToken(t.Context(), cli.CreateVaultOidcTokenConfig{
 			Vault: "my-vault",
 			Name:  "my-token",
 		})
@@ -125,7 +125,7 @@ func TestService_CreateVaultOidcToken(t *testing.T) {
 			return nil, errors.New("vault not found")
 		}
 
-		result, err := s.service.CreateVaultOidcToken(cli.CreateVaultOidcTokenConfig{
+		result, err := s.service.CreateVaultOidcToken(t.Context(), cli.CreateVaultOidcTokenConfig{
 			Vault:    "my-vault",
 			Name:     "my-token",
 			Audience: "sts.amazonaws.com",
@@ -148,7 +148,7 @@ func TestService_CreateVaultOidcToken(t *testing.T) {
 			}, nil
 		}
 
-		result, err := s.service.CreateVaultOidcToken(cli.CreateVaultOidcTokenConfig{
+		result, err := s.service.CreateVaultOidcToken(t.Context(), cli.CreateVaultOidcTokenConfig{
 			Vault:    "my-vault",
 			Name:     "my-token",
 			Audience: "sts.amazonaws.com",
@@ -1,7 +1,7 @@
 package cli
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"strings"
 
@@ -32,7 +32,7 @@ func (c CreateVaultConfig) Validate() error {
 
 type CreateVaultResult struct{}
 
-func (s Service) CreateVault(cfg CreateVaultConfig) (*CreateVaultResult, error) {
+func (s Service) CreateVault(ctx context.Context, cfg CreateVaultConfig) (*CreateVaultResult, error) {
 	err := cfg.Validate()
 	if err != nil {
 		return nil, errors.Wrap(err, "validation failed")
@@ -47,7 +47,7 @@ func (s Service) CreateVault(cfg CreateVaultConfig) (*CreateVaultResult, error)
 		})
 	}
 
-	_, err = s.APIClient.CreateVault(api.CreateVaultConfig{
+	_, err = s.APIClient.CreateVault(ctx, api.CreateVaultConfig{
 		Name:                  cfg.Name,
 		Unlocked:              cfg.Unlocked,
 		RepositoryPermissions: repoPermissions,
@@ -63,7 +63,7 @@ func (s Service) CreateVault(cfg CreateVaultConfig) (*CreateVaultResult, error)
 		}{
 			Vault: cfg.Name,
 		}
-		if err := json.NewEncoder(s.Stdout).Encode(output); err != nil {
+		if err := s.Output.Write(s.Stdout, output); err != nil {
 			return nil, errors.Wrap(err, "unable to encode JSON output")
 		}
 	} else {
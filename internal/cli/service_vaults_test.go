@@ -19,7 +19,7 @@ func TestService_CreateVault(t *testing.T) {
 			return nil, errors.New("vault already exists")
 		}
 
-		result, err := s.service.CreateVault(cli.CreateVaultConfig{
+		result, err := s.service.CreateVault(t.Context(), cli.CreateVaultConfig{
 			Name: "my-vault",
 		})
 
@@ -38,7 +38,7 @@ func TestService_CreateVault(t *testing.T) {
 			return &api.CreateVaultResult{}, nil
 		}
 
-		result, err := s.service.CreateVault(cli.CreateVaultConfig{
+		result, err := s.service.CreateVault(t.Context(), cli.CreateVaultConfig{
 			Name: "my-vault",
 		})
 
@@ -61,7 +61,7 @@ func TestService_CreateVault(t *testing.T) {
 			return &api.CreateVaultResult{}, nil
 		}
 
-		result, err := s.service.CreateVault(cli.CreateVaultConfig{
+		result, err := s.service.CreateVault(t.Context(), cli.CreateVaultConfig{
 			Name:                  "my-vault",
 			Unlocked:              true,
 			RepositoryPermissions: []string{"my-repo:main", "other-repo:release/*"},
@@ -74,7 +74,7 @@ func TestService_CreateVault(t *testing.T) {
 	t.Run("validates repository permission format", func(t *testing.T) {
 		s := setupTest(t)
 
-		result, err := s.service.CreateVault(cli.CreateVaultConfig{
+		result, err := s.service.CreateVault(t.Context(), cli.CreateVaultConfig{
 			Name:                  "my-vault",
 			RepositoryPermissions: []string{"bad-format"},
 		})
@@ -87,7 +87,7 @@ func TestService_CreateVault(t *testing.T) {
 	t.Run("validates name is required", func(t *testing.T) {
 		s := setupTest(t)
 
-		result, err := s.service.CreateVault(cli.CreateVaultConfig{})
+		result, err := s.service.CreateVault(t.Context(), cli.CreateVaultConfig{})
 
 		require.Nil(t, result)
 		require.Error(t, err)
@@ -101,7 +101,7 @@ func TestService_CreateVault(t *testing.T) {
 			return &api.CreateVaultResult{}, nil
 		}
 
-		result, err := s.service.CreateVault(cli.CreateVaultConfig{
+		result, err := s.service.CreateVault(t.Context(), cli.CreateVaultConfig{
 			Name: "my-vault",
 			Json: true,
 		})
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"time"
 
 	"github.com/rwx-cloud/rwx/internal/api"
@@ -24,7 +25,7 @@ type GetRunStatusResult struct {
 	Completed    bool
 }
 
-func (s Service) GetRunStatus(cfg GetRunStatusConfig) (*GetRunStatusResult, error) {
+func (s Service) GetRunStatus(ctx context.Context, cfg GetRunStatusConfig) (*GetRunStatusResult, error) {
 	waitStart := time.Now()
 	var stopSpinner func()
 	if cfg.Wait && !cfg.Json {
@@ -32,7 +33,7 @@ func (s Service) GetRunStatus(cfg GetRunStatusConfig) (*GetRunStatusResult, erro
 	}
 
 	for {
-		statusResult, err := s.APIClient.RunStatus(api.RunStatusConfig{
+		statusResult, err := s.APIClient.RunStatus(ctx, api.RunStatusConfig{
 			RunID:          cfg.RunID,
 			BranchName:     cfg.BranchName,
 			RepositoryName: cfg.RepositoryName,
@@ -86,6 +87,11 @@ func (s Service) GetRunStatus(cfg GetRunStatusConfig) (*GetRunStatusResult, erro
 			}
 			return nil, errors.New("unable to wait for run")
 		}
-		time.Sleep(time.Duration(*statusResult.Polling.BackoffMs) * time.Millisecond)
+		if err := sleep(ctx, time.Duration(*statusResult.Polling.BackoffMs)*time.Millisecond); err != nil {
+			if stopSpinner != nil {
+				stopSpinner()
+			}
+			return nil, err
+		}
 	}
 }
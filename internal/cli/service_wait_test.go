@@ -1,6 +1,7 @@
 package cli_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/rwx-cloud/rwx/internal/api"
@@ -21,7 +22,7 @@ func TestService_GetRunStatus(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.GetRunStatus(cli.GetRunStatusConfig{
+		result, err := setup.service.GetRunStatus(t.Context(), cli.GetRunStatusConfig{
 			RunID: "run-123",
 			Wait:  true,
 			Json:  false,
@@ -45,7 +46,7 @@ func TestService_GetRunStatus(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.GetRunStatus(cli.GetRunStatusConfig{
+		result, err := setup.service.GetRunStatus(t.Context(), cli.GetRunStatusConfig{
 			RunID:    "run-123",
 			Wait:     true,
 			FailFast: true,
@@ -77,7 +78,7 @@ func TestService_GetRunStatus(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.GetRunStatus(cli.GetRunStatusConfig{
+		result, err := setup.service.GetRunStatus(t.Context(), cli.GetRunStatusConfig{
 			RunID: "run-456",
 			Wait:  true,
 			Json:  false,
@@ -111,7 +112,7 @@ func TestService_GetRunStatus(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.GetRunStatus(cli.GetRunStatusConfig{
+		result, err := setup.service.GetRunStatus(t.Context(), cli.GetRunStatusConfig{
 			RunID: "run-789",
 			Wait:  true,
 			Json:  false,
@@ -135,7 +136,7 @@ func TestService_GetRunStatus(t *testing.T) {
 			}, nil
 		}
 
-		_, err := setup.service.GetRunStatus(cli.GetRunStatusConfig{
+		_, err := setup.service.GetRunStatus(t.Context(), cli.GetRunStatusConfig{
 			RunID: "run-789",
 			Wait:  true,
 			Json:  false,
@@ -145,6 +146,34 @@ func TestService_GetRunStatus(t *testing.T) {
 		require.Contains(t, err.Error(), "unable to wait for run")
 	})
 
+	t.Run("stops polling when the context is cancelled", func(t *testing.T) {
+		setup := setupTest(t)
+
+		ctx, cancel := context.WithCancel(t.Context())
+		defer cancel()
+
+		callCount := 0
+		backoffMs := 60_000
+		setup.mockAPI.MockRunStatus = func(cfg api.RunStatusConfig) (api.RunStatusResult, error) {
+			callCount++
+			cancel()
+			return api.RunStatusResult{
+				Status:  &api.RunStatus{Result: "in_progress"},
+				RunID:   "run-789",
+				Polling: api.PollingResult{Completed: false, BackoffMs: &backoffMs},
+			}, nil
+		}
+
+		_, err := setup.service.GetRunStatus(ctx, cli.GetRunStatusConfig{
+			RunID: "run-789",
+			Wait:  true,
+			Json:  false,
+		})
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, 1, callCount)
+	})
+
 	t.Run("returns empty status when run not found", func(t *testing.T) {
 		setup := setupTest(t)
 
@@ -155,7 +184,7 @@ func TestService_GetRunStatus(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.GetRunStatus(cli.GetRunStatusConfig{
+		result, err := setup.service.GetRunStatus(t.Context(), cli.GetRunStatusConfig{
 			RunID: "nonexistent",
 			Wait:  true,
 			Json:  false,
@@ -173,7 +202,7 @@ func TestService_GetRunStatus(t *testing.T) {
 			return api.RunStatusResult{}, api.ErrNotFound
 		}
 
-		_, err := setup.service.GetRunStatus(cli.GetRunStatusConfig{
+		_, err := setup.service.GetRunStatus(t.Context(), cli.GetRunStatusConfig{
 			RunID: "run-123",
 			Wait:  true,
 			Json:  false,
@@ -198,7 +227,7 @@ func TestService_GetRunStatus(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.GetRunStatus(cli.GetRunStatusConfig{
+		result, err := setup.service.GetRunStatus(t.Context(), cli.GetRunStatusConfig{
 			BranchName:     "main",
 			RepositoryName: "cli",
 			Wait:           false,
@@ -219,7 +248,7 @@ func TestService_GetRunStatus(t *testing.T) {
 			return api.RunStatusResult{}, api.ErrNotFound
 		}
 
-		_, err := setup.service.GetRunStatus(cli.GetRunStatusConfig{
+		_, err := setup.service.GetRunStatus(t.Context(), cli.GetRunStatusConfig{
 			BranchName:     "no-runs-here",
 			RepositoryName: "cli",
 			Wait:           false,
@@ -241,7 +270,7 @@ func TestService_GetRunStatus(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.GetRunStatus(cli.GetRunStatusConfig{
+		result, err := setup.service.GetRunStatus(t.Context(), cli.GetRunStatusConfig{
 			BranchName:     "no-runs-here",
 			RepositoryName: "cli",
 			Wait:           false,
@@ -275,7 +304,7 @@ func TestService_GetRunStatus(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.GetRunStatus(cli.GetRunStatusConfig{
+		result, err := setup.service.GetRunStatus(t.Context(), cli.GetRunStatusConfig{
 			BranchName:     "main",
 			RepositoryName: "cli",
 			Wait:           true,
@@ -302,7 +331,7 @@ func TestService_GetRunStatus(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.GetRunStatus(cli.GetRunStatusConfig{
+		result, err := setup.service.GetRunStatus(t.Context(), cli.GetRunStatusConfig{
 			RunID: "run-123",
 			Wait:  false,
 			Json:  false,
@@ -324,7 +353,7 @@ func TestService_GetRunStatus(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.GetRunStatus(cli.GetRunStatusConfig{
+		result, err := setup.service.GetRunStatus(t.Context(), cli.GetRunStatusConfig{
 			RunID: "run-123",
 			Wait:  false,
 			Json:  false,
@@ -347,7 +376,7 @@ func TestService_GetRunStatus(t *testing.T) {
 			}, nil
 		}
 
-		result, err := setup.service.GetRunStatus(cli.GetRunStatusConfig{
+		result, err := setup.service.GetRunStatus(t.Context(), cli.GetRunStatusConfig{
 			RunID: "run-123",
 			Wait:  false,
 			Json:  false,
@@ -1,7 +1,7 @@
 package cli
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"strings"
 
@@ -17,20 +17,25 @@ func (c WhoamiConfig) Validate() error {
 	return nil
 }
 
-func (s Service) Whoami(cfg WhoamiConfig) (*api.WhoamiResult, error) {
-	result, err := s.APIClient.Whoami()
+func (s Service) Whoami(ctx context.Context, cfg WhoamiConfig) (*api.WhoamiResult, error) {
+	result, err := s.APIClient.Whoami(ctx)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to determine details about the access token")
 	}
 
 	if cfg.Json {
-		encoded, err := json.MarshalIndent(result, "", "  ")
-		if err != nil {
+		output := struct {
+			*api.WhoamiResult
+			Profile string `json:"profile,omitempty"`
+		}{result, s.Profile}
+
+		if err := s.Output.Write(s.Stdout, output); err != nil {
 			return nil, errors.Wrap(err, "unable to JSON encode the result")
 		}
-
-		fmt.Fprint(s.Stdout, string(encoded))
 	} else {
+		if s.Profile != "" {
+			fmt.Fprintf(s.Stdout, "Profile: %v\n", s.Profile)
+		}
 		fmt.Fprintf(s.Stdout, "Token Kind: %v\n", strings.ReplaceAll(result.TokenKind, "_", " "))
 		fmt.Fprintf(s.Stdout, "Organization: %v\n", result.OrganizationSlug)
 		if result.UserEmail != nil {
@@ -18,7 +18,7 @@ func TestService_Whoami(t *testing.T) {
 				return nil, errors.New("uh oh can't figure out who you are")
 			}
 
-			result, err := s.service.Whoami(cli.WhoamiConfig{
+			result, err := s.service.Whoami(t.Context(), cli.WhoamiConfig{
 				Json: true,
 			})
 
@@ -40,7 +40,7 @@ func TestService_Whoami(t *testing.T) {
 				}, nil
 			}
 
-			result, err := s.service.Whoami(cli.WhoamiConfig{
+			result, err := s.service.Whoami(t.Context(), cli.WhoamiConfig{
 				Json: true,
 			})
 
@@ -48,9 +48,9 @@ func TestService_Whoami(t *testing.T) {
 			require.Equal(t, "personal_access_token", result.TokenKind)
 			require.Equal(t, "rwx", result.OrganizationSlug)
 			require.Equal(t, &email, result.UserEmail)
-			require.Contains(t, s.mockStdout.String(), `"token_kind": "personal_access_token"`)
-			require.Contains(t, s.mockStdout.String(), `"organization_slug": "rwx"`)
-			require.Contains(t, s.mockStdout.String(), `"user_email": "someone@rwx.com"`)
+			require.Contains(t, s.mockStdout.String(), `"token_kind":"personal_access_token"`)
+			require.Contains(t, s.mockStdout.String(), `"organization_slug":"rwx"`)
+			require.Contains(t, s.mockStdout.String(), `"user_email":"someone@rwx.com"`)
 		})
 
 		t.Run("when there is not an email", func(t *testing.T) {
@@ -63,7 +63,7 @@ func TestService_Whoami(t *testing.T) {
 				}, nil
 			}
 
-			result, err := s.service.Whoami(cli.WhoamiConfig{
+			result, err := s.service.Whoami(t.Context(), cli.WhoamiConfig{
 				Json: true,
 			})
 
@@ -71,8 +71,8 @@ func TestService_Whoami(t *testing.T) {
 			require.Equal(t, "organization_access_token", result.TokenKind)
 			require.Equal(t, "rwx", result.OrganizationSlug)
 			require.Nil(t, result.UserEmail)
-			require.Contains(t, s.mockStdout.String(), `"token_kind": "organization_access_token"`)
-			require.Contains(t, s.mockStdout.String(), `"organization_slug": "rwx"`)
+			require.Contains(t, s.mockStdout.String(), `"token_kind":"organization_access_token"`)
+			require.Contains(t, s.mockStdout.String(), `"organization_slug":"rwx"`)
 			require.NotContains(t, s.mockStdout.String(), `"user_email"`)
 		})
 	})
@@ -85,7 +85,7 @@ func TestService_Whoami(t *testing.T) {
 				return nil, errors.New("uh oh can't figure out who you are")
 			}
 
-			result, err := s.service.Whoami(cli.WhoamiConfig{
+			result, err := s.service.Whoami(t.Context(), cli.WhoamiConfig{
 				Json: false,
 			})
 
@@ -107,7 +107,7 @@ func TestService_Whoami(t *testing.T) {
 				}, nil
 			}
 
-			result, err := s.service.Whoami(cli.WhoamiConfig{
+			result, err := s.service.Whoami(t.Context(), cli.WhoamiConfig{
 				Json: false,
 			})
 
@@ -130,7 +130,7 @@ func TestService_Whoami(t *testing.T) {
 				}, nil
 			}
 
-			result, err := s.service.Whoami(cli.WhoamiConfig{
+			result, err := s.service.Whoami(t.Context(), cli.WhoamiConfig{
 				Json: false,
 			})
 
@@ -142,5 +142,51 @@ func TestService_Whoami(t *testing.T) {
 			require.Contains(t, s.mockStdout.String(), "Organization: rwx")
 			require.NotContains(t, s.mockStdout.String(), "User:")
 		})
+
+		t.Run("when a profile is active", func(t *testing.T) {
+			s := setupTest(t)
+			s.config.Profile = "work"
+			var err error
+			s.service, err = cli.NewService(s.config)
+			require.NoError(t, err)
+
+			s.mockAPI.MockWhoami = func() (*api.WhoamiResult, error) {
+				return &api.WhoamiResult{
+					TokenKind:        "personal_access_token",
+					OrganizationSlug: "acme",
+				}, nil
+			}
+
+			_, err = s.service.Whoami(t.Context(), cli.WhoamiConfig{
+				Json: false,
+			})
+
+			require.NoError(t, err)
+			require.Contains(t, s.mockStdout.String(), "Profile: work")
+			require.Contains(t, s.mockStdout.String(), "Organization: acme")
+		})
+	})
+
+	t.Run("includes the active profile in json", func(t *testing.T) {
+		s := setupTest(t)
+		s.config.Profile = "work"
+		var err error
+		s.service, err = cli.NewService(s.config)
+		require.NoError(t, err)
+
+		s.mockAPI.MockWhoami = func() (*api.WhoamiResult, error) {
+			return &api.WhoamiResult{
+				TokenKind:        "personal_access_token",
+				OrganizationSlug: "acme",
+			}, nil
+		}
+
+		_, err = s.service.Whoami(t.Context(), cli.WhoamiConfig{
+			Json: true,
+		})
+
+		require.NoError(t, err)
+		require.Contains(t, s.mockStdout.String(), `"organization_slug":"acme"`)
+		require.Contains(t, s.mockStdout.String(), `"profile":"work"`)
 	})
 }
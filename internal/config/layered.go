@@ -0,0 +1,234 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/rwx-cloud/rwx/internal/errors"
+)
+
+const (
+	// ProjectFilename is the project config file, found in the .rwx directory
+	ProjectFilename = "cli.yml"
+	// UserFilename is the user config file, found in the config directory
+	UserFilename = "config.yml"
+)
+
+// Source is where the value of a setting came from. Later sources take
+// precedence over earlier ones.
+type Source string
+
+const (
+	SourceDefault Source = "default"
+	SourceProject Source = "project"
+	SourceUser    Source = "user"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+type Setting struct {
+	Key string
+	// Env lists the environment variables for the setting; the first one
+	// that is set is used
+	Env []string
+	// Flag is the flag that the setting provides a default for, on any
+	// command that has it
+	Flag    string
+	Default string
+	// UserOnly settings can't be set in a project file, since a repository
+	// could otherwise send credentials to another host or run commands
+	UserOnly    bool
+	Description string
+	Validate    func(value string) error
+}
+
+func validateBool(value string) error {
+	if _, err := strconv.ParseBool(value); err != nil {
+		return errors.Errorf("%q is not true or false", value)
+	}
+	return nil
+}
+
+func validateDuration(value string) error {
+	if _, err := time.ParseDuration(value); err != nil {
+		return errors.Errorf("%q is not a duration such as 30s", value)
+	}
+	return nil
+}
+
+func validateOutput(value string) error {
+	switch value {
+	case "text", "json", "yaml", "ndjson":
+	default:
+		return errors.Errorf("%q is not text, json, yaml or ndjson", value)
+	}
+	return nil
+}
+
+// Settings are all of the settings that can be configured.
+var Settings = []Setting{
+	{Key: "host", Env: []string{"MINT_HOST", "RWX_HOST"}, Default: "cloud.rwx.com", UserOnly: true, Description: "the RWX Cloud host"},
+	{Key: "credential_helper", Env: []string{"RWX_CREDENTIAL_HELPER"}, UserOnly: true, Description: "a command that stores access tokens instead of a file"},
+	{Key: "output", Env: []string{"RWX_OUTPUT"}, Flag: "output", Default: "text", Description: "the output format: text, json, yaml or ndjson", Validate: validateOutput},
+	{Key: "vault", Env: []string{"RWX_VAULT"}, Flag: "vault", Default: "default", Description: "the vault used by `rwx vaults` commands"},
+	{Key: "no_cache", Env: []string{"RWX_NO_CACHE"}, Flag: "no-cache", Default: "false", Description: "do not read or write to the cache", Validate: validateBool},
+	{Key: "git_remote", Env: []string{"RWX_GIT_REMOTE"}, Default: "origin", Description: "the git remote used to find the base commit"},
+	{Key: "download_dir", Env: []string{"RWX_DOWNLOAD_DIR"}, Flag: "output-dir", Description: "where logs, artifacts and pulled files are saved"},
+	{Key: "sandbox_auto_reset", Env: []string{"RWX_SANDBOX_AUTO_RESET"}, Flag: "auto-reset", Default: "false", Description: "reset sandboxes whose definition has changed", Validate: validateBool},
+	{Key: "ssh_keepalive_interval", Env: []string{"RWX_SSH_KEEPALIVE_INTERVAL"}, Description: "the interval between SSH keepalives, or 0 to disable them", Validate: validateDuration},
+}
+
+func LookupSetting(key string) (Setting, bool) {
+	for _, setting := range Settings {
+		if setting.Key == key {
+			return setting, true
+		}
+	}
+	return Setting{}, false
+}
+
+// ValidateValue checks that key is a known setting and that value is valid
+// for it.
+func ValidateValue(key, value string) error {
+	setting, ok := LookupSetting(key)
+	if !ok {
+		return errors.Errorf("unknown setting %q", key)
+	}
+	if setting.Validate != nil && value != "" {
+		if err := setting.Validate(value); err != nil {
+			return errors.Wrapf(err, "invalid value for %s", key)
+		}
+	}
+	return nil
+}
+
+type Value struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source Source `json:"source"`
+	// Origin is the file, environment variable or flag the value came from
+	Origin string `json:"origin,omitempty"`
+}
+
+// Layered resolves settings from defaults, the project file, the user file,
+// the environment and flags, in that order.
+type Layered struct {
+	ProjectFile string
+	UserFile    string
+	values      map[string]Value
+}
+
+// LoadLayered reads the project and user files, either of which may be empty
+// or missing, and the environment through lookupEnv.
+func LoadLayered(projectFile, userFile string, lookupEnv func(string) (string, bool)) (*Layered, error) {
+	l := &Layered{ProjectFile: projectFile, UserFile: userFile, values: map[string]Value{}}
+
+	for _, setting := range Settings {
+		l.values[setting.Key] = Value{Key: setting.Key, Value: setting.Default, Source: SourceDefault}
+	}
+
+	for _, layer := range []struct {
+		file   string
+		source Source
+	}{{projectFile, SourceProject}, {userFile, SourceUser}} {
+		values, err := ReadFile(layer.file)
+		if err != nil {
+			return nil, err
+		}
+
+		for key, value := range values {
+			setting, ok := LookupSetting(key)
+			if !ok {
+				// Ignore settings from newer versions of the CLI
+				continue
+			}
+			if setting.UserOnly && layer.source == SourceProject {
+				return nil, errors.Errorf("%s can't be set in %s; set it in %s instead", key, layer.file, UserFilename)
+			}
+			if err := ValidateValue(key, value); err != nil {
+				return nil, errors.Wrapf(err, "invalid setting in %s", layer.file)
+			}
+			l.values[key] = Value{Key: key, Value: value, Source: layer.source, Origin: layer.file}
+		}
+	}
+
+	for _, setting := range Settings {
+		for _, name := range setting.Env {
+			value, ok := lookupEnv(name)
+			if !ok || value == "" {
+				continue
+			}
+			if err := ValidateValue(setting.Key, value); err != nil {
+				return nil, errors.Wrapf(err, "invalid %s", name)
+			}
+			l.values[setting.Key] = Value{Key: setting.Key, Value: value, Source: SourceEnv, Origin: name}
+			break
+		}
+	}
+
+	return l, nil
+}
+
+// ReadFile reads the settings in a config file. A missing file has none.
+func ReadFile(path string) (map[string]string, error) {
+	values := map[string]string{}
+	if path == "" {
+		return values, nil
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return values, nil
+		}
+		return nil, errors.Wrapf(err, "unable to read %q", path)
+	}
+
+	var raw map[string]any
+	if err := yaml.Unmarshal(contents, &raw); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse %q", path)
+	}
+
+	for key, value := range raw {
+		switch value.(type) {
+		case nil:
+			continue
+		case map[string]any, []any:
+			return nil, errors.Errorf("%s in %q must be a single value", key, path)
+		}
+		values[key] = fmt.Sprint(value)
+	}
+
+	return values, nil
+}
+
+func (l *Layered) Get(key string) string {
+	return l.values[key].Value
+}
+
+func (l *Layered) Lookup(key string) Value {
+	return l.values[key]
+}
+
+// Bool returns the value of a boolean setting, which is false when unset.
+func (l *Layered) Bool(key string) bool {
+	value, _ := strconv.ParseBool(l.Get(key))
+	return value
+}
+
+// SetFlag records that a flag set the value of a setting.
+func (l *Layered) SetFlag(key, value, flag string) {
+	l.values[key] = Value{Key: key, Value: value, Source: SourceFlag, Origin: flag}
+}
+
+// Values returns every setting in the order of Settings.
+func (l *Layered) Values() []Value {
+	values := make([]Value, 0, len(Settings))
+	for _, setting := range Settings {
+		values = append(values, l.values[setting.Key])
+	}
+	return values
+}
@@ -0,0 +1,92 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rwx-cloud/rwx/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yml")
+	require.NoError(t, os.WriteFile(path, []byte(contents), 0o644))
+	return path
+}
+
+func env(values map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		value, ok := values[name]
+		return value, ok
+	}
+}
+
+func TestLoadLayered(t *testing.T) {
+	t.Run("uses defaults when nothing is configured", func(t *testing.T) {
+		settings, err := config.LoadLayered("", filepath.Join(t.TempDir(), "missing.yml"), env(nil))
+		require.NoError(t, err)
+
+		require.Equal(t, config.Value{Key: "output", Value: "text", Source: config.SourceDefault}, settings.Lookup("output"))
+		require.Equal(t, "cloud.rwx.com", settings.Get("host"))
+		require.False(t, settings.Bool("no_cache"))
+	})
+
+	t.Run("applies the project file, user file and environment in order", func(t *testing.T) {
+		project := writeConfigFile(t, "output: json\nvault: project-vault\nno_cache: true\ngit_remote: upstream\n")
+		user := writeConfigFile(t, "# personal settings\nvault: user-vault\ngit_remote: fork\n")
+
+		settings, err := config.LoadLayered(project, user, env(map[string]string{"RWX_GIT_REMOTE": "env-remote"}))
+		require.NoError(t, err)
+
+		require.Equal(t, config.Value{Key: "output", Value: "json", Source: config.SourceProject, Origin: project}, settings.Lookup("output"))
+		require.Equal(t, config.Value{Key: "vault", Value: "user-vault", Source: config.SourceUser, Origin: user}, settings.Lookup("vault"))
+		require.Equal(t, config.Value{Key: "git_remote", Value: "env-remote", Source: config.SourceEnv, Origin: "RWX_GIT_REMOTE"}, settings.Lookup("git_remote"))
+		require.True(t, settings.Bool("no_cache"))
+	})
+
+	t.Run("lets flags override everything", func(t *testing.T) {
+		settings, err := config.LoadLayered("", "", env(map[string]string{"RWX_VAULT": "env-vault"}))
+		require.NoError(t, err)
+
+		settings.SetFlag("vault", "flag-vault", "--vault")
+		require.Equal(t, config.Value{Key: "vault", Value: "flag-vault", Source: config.SourceFlag, Origin: "--vault"}, settings.Lookup("vault"))
+	})
+
+	t.Run("prefers MINT_HOST over RWX_HOST", func(t *testing.T) {
+		settings, err := config.LoadLayered("", "", env(map[string]string{"MINT_HOST": "mint.example.com", "RWX_HOST": "rwx.example.com"}))
+		require.NoError(t, err)
+		require.Equal(t, "mint.example.com", settings.Get("host"))
+	})
+
+	t.Run("does not allow user-only settings in the project file", func(t *testing.T) {
+		project := writeConfigFile(t, "credential_helper: curl evil.example.com\n")
+
+		_, err := config.LoadLayered(project, "", env(nil))
+		require.ErrorContains(t, err, "credential_helper can't be set in")
+	})
+
+	t.Run("rejects invalid values", func(t *testing.T) {
+		user := writeConfigFile(t, "no_cache: sometimes\n")
+
+		_, err := config.LoadLayered("", user, env(nil))
+		require.ErrorContains(t, err, `"sometimes" is not true or false`)
+	})
+
+	t.Run("ignores unknown settings", func(t *testing.T) {
+		user := writeConfigFile(t, "from_the_future: true\n")
+
+		_, err := config.LoadLayered("", user, env(nil))
+		require.NoError(t, err)
+	})
+
+	t.Run("lists every setting in order", func(t *testing.T) {
+		settings, err := config.LoadLayered("", "", env(nil))
+		require.NoError(t, err)
+
+		values := settings.Values()
+		require.Len(t, values, len(config.Settings))
+		require.Equal(t, "host", values[0].Key)
+	})
+}
@@ -13,9 +13,10 @@ import (
 )
 
 type Client struct {
-	Host      string // default "www.rwx.com"
-	Scheme    string // default "https"
-	DocsToken string
+	Host       string       // default "www.rwx.com"
+	Scheme     string       // default "https"
+	HTTPClient *http.Client // default http.DefaultClient
+	DocsToken  string
 }
 
 type SearchResponse struct {
@@ -46,6 +47,13 @@ func (c Client) host() string {
 	return "www.rwx.com"
 }
 
+func (c Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
 func (c Client) scheme() string {
 	if c.Scheme != "" {
 		return c.Scheme
@@ -69,7 +77,7 @@ func (c Client) Search(query string, limit int) (*SearchResponse, error) {
 	req.Header.Set("User-Agent", fmt.Sprintf("rwx-cli/%s", config.Version))
 	c.setDocsTokenHeader(req)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.httpClient().Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("unable to search docs: %w", err)
 	}
@@ -126,7 +134,7 @@ func (c Client) FetchArticle(urlOrPath string) (string, error) {
 	req.Header.Set("User-Agent", fmt.Sprintf("rwx-cli/%s", config.Version))
 	c.setDocsTokenHeader(req)
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.httpClient().Do(req)
 	if err != nil {
 		return "", fmt.Errorf("unable to fetch article: %w", err)
 	}
@@ -57,10 +57,98 @@ func wrapSentinel(err, sentinel error) error {
 	return &sentinelError{inner: err, sentinel: sentinel, stack: pcs[:n]}
 }
 
+// detailError attaches a remediation hint or identifiers to an error without
+// changing its message.
+type detailError struct {
+	inner error
+	hint  string
+	ids   map[string]string
+}
+
+func (e *detailError) Error() string { return e.inner.Error() }
+func (e *detailError) Unwrap() error { return e.inner }
+
+// Format implements fmt.Formatter so that %+v renders the inner error's stack trace.
+func (e *detailError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			fmt.Fprintf(s, "%+v", e.inner)
+			return
+		}
+		fmt.Fprint(s, e.Error())
+	case 's':
+		fmt.Fprint(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	}
+}
+
+// withHint attaches a hint on how to resolve err, such as a command to run.
+func withHint(err error, hint string) error {
+	if err == nil {
+		return nil
+	}
+	return &detailError{inner: err, hint: hint}
+}
+
+// withID attaches an identifier relevant to err, such as the ID of a run.
+func withID(err error, name, value string) error {
+	if err == nil || value == "" {
+		return err
+	}
+	return &detailError{inner: err, ids: map[string]string{name: value}}
+}
+
+// hints returns the hints attached to err, innermost first.
+func hints(err error) []string {
+	var result []string
+	walk(err, func(detail *detailError) {
+		if detail.hint != "" {
+			result = append([]string{detail.hint}, result...)
+		}
+	})
+	return result
+}
+
+// ids returns the identifiers attached to err. An inner identifier takes
+// precedence over an outer one with the same name.
+func ids(err error) map[string]string {
+	var result map[string]string
+	walk(err, func(detail *detailError) {
+		for name, value := range detail.ids {
+			if result == nil {
+				result = map[string]string{}
+			}
+			result[name] = value
+		}
+	})
+	return result
+}
+
+// walk calls fn for each detailError in err's chain, outermost first.
+func walk(err error, fn func(*detailError)) {
+	if err == nil {
+		return
+	}
+	if detail, ok := err.(*detailError); ok {
+		fn(detail)
+	}
+	switch unwrapper := err.(type) {
+	case interface{ Unwrap() error }:
+		walk(unwrapper.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, inner := range unwrapper.Unwrap() {
+			walk(inner, fn)
+		}
+	}
+}
+
 var (
 	ErrFileNotExists    = os.ErrNotExist
 	ErrBadRequest       = errors.New("bad request")
 	ErrNotFound         = errors.New("not found")
+	ErrUnauthorized     = errors.New("unauthorized")
 	ErrGone             = errors.New("gone")
 	ErrRetry            = errors.New("retry")
 	ErrSandboxNoGitDir  = errors.New("no .git directory found in sandbox. Set 'preserve-git-dir: true' on your git/clone task")
@@ -74,6 +162,16 @@ var (
 	// WrapSentinel wraps an error so that errors.Is returns true for the sentinel.
 	WrapSentinel = wrapSentinel
 
+	// WithHint attaches a hint on how to resolve an error, and Hints returns
+	// the hints attached to an error.
+	WithHint = withHint
+	Hints    = hints
+
+	// WithID attaches an identifier such as a run ID to an error, and IDs
+	// returns the identifiers attached to an error.
+	WithID = withID
+	IDs    = ids
+
 	As        = errors.As
 	Errorf    = errors.Errorf
 	Is        = errors.Is
@@ -94,3 +94,71 @@ func TestWrapSentinel(t *testing.T) {
 		}
 	})
 }
+
+func TestWithHint(t *testing.T) {
+	t.Run("keeps the error message", func(t *testing.T) {
+		err := errors.WithHint(fmt.Errorf("no access token"), "Run `rwx login`.")
+
+		if err.Error() != "no access token" {
+			t.Fatalf("unexpected error message: %s", err.Error())
+		}
+	})
+
+	t.Run("returns nil for nil error", func(t *testing.T) {
+		if errors.WithHint(nil, "Run `rwx login`.") != nil {
+			t.Fatal("expected nil")
+		}
+	})
+
+	t.Run("collects hints through wrapping, innermost first", func(t *testing.T) {
+		inner := errors.WithHint(fmt.Errorf("job not found"), "List the jobs.")
+		wrapped := errors.WrapSentinel(inner, errors.ErrNotFound)
+		outer := errors.WithHint(errors.Wrap(wrapped, "unable to stop job"), "Try again.")
+
+		hints := errors.Hints(outer)
+		if len(hints) != 2 || hints[0] != "List the jobs." || hints[1] != "Try again." {
+			t.Fatalf("unexpected hints: %v", hints)
+		}
+		if !stderrors.Is(outer, errors.ErrNotFound) {
+			t.Fatal("expected sentinel to be preserved")
+		}
+	})
+
+	t.Run("verbose formatting includes the inner stack trace", func(t *testing.T) {
+		err := errors.WithHint(errors.New("no access token"), "Run `rwx login`.")
+
+		verbose := fmt.Sprintf("%+v", err)
+		if !strings.Contains(verbose, "errors_test.go") {
+			t.Fatalf("expected stack trace with test file name, got: %s", verbose)
+		}
+	})
+}
+
+func TestWithID(t *testing.T) {
+	t.Run("collects IDs through wrapping", func(t *testing.T) {
+		err := errors.WithID(errors.WithID(fmt.Errorf("task not found"), "task_key", "ci.lint"), "run_id", "run-123")
+		wrapped := fmt.Errorf("unable to fetch logs: %w", err)
+
+		ids := errors.IDs(wrapped)
+		if len(ids) != 2 || ids["task_key"] != "ci.lint" || ids["run_id"] != "run-123" {
+			t.Fatalf("unexpected IDs: %v", ids)
+		}
+	})
+
+	t.Run("skips empty IDs", func(t *testing.T) {
+		err := errors.WithID(fmt.Errorf("task not found"), "run_id", "")
+
+		if errors.IDs(err) != nil {
+			t.Fatalf("expected no IDs, got: %v", errors.IDs(err))
+		}
+	})
+
+	t.Run("returns no IDs for a plain error", func(t *testing.T) {
+		if errors.IDs(fmt.Errorf("boom")) != nil {
+			t.Fatal("expected no IDs")
+		}
+		if errors.Hints(fmt.Errorf("boom")) != nil {
+			t.Fatal("expected no hints")
+		}
+	})
+}
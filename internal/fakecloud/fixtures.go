@@ -0,0 +1,348 @@
+package fakecloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/rwx-cloud/rwx/internal/errors"
+	"github.com/rwx-cloud/rwx/internal/httplog"
+)
+
+// RecordEnv enables record mode for UseFixtures when it's set to 1 or true.
+const RecordEnv = "RWX_RECORD_FIXTURES"
+
+// serverPlaceholder stands in for the server's URL in fixtures, since each
+// server listens on a different port.
+const serverPlaceholder = "{{server}}"
+
+// fetchedURLFields are the fields of responses with URLs that the client
+// requests next, such as to download logs. When recording, they are rewritten
+// to go through the server so that those requests are recorded as well.
+var fetchedURLFields = map[string]bool{"url": true, "token_url": true}
+
+// Fixture is a file of interactions recorded against RWX Cloud.
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+type Interaction struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+type FixtureRequest struct {
+	Method string `json:"method"`
+	// Path includes the query, with its parameters sorted
+	Path string `json:"path"`
+	// Body is the request's JSON body, for reference; it isn't matched
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+type FixtureResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type,omitempty"`
+	// Body is set for JSON responses and Raw for any other response
+	Body json.RawMessage `json:"body,omitempty"`
+	Raw  []byte          `json:"raw,omitempty"`
+}
+
+type fixtures struct {
+	server  *Server
+	path    string
+	mu      sync.Mutex
+	fixture Fixture
+	used    []bool
+
+	// Set when recording
+	recording    bool
+	upstream     string
+	token        string
+	upstreamURLs []string
+	client       *http.Client
+}
+
+// UseFixtures answers requests with the responses recorded in the fixture at
+// path. A request gets the first unused response recorded for the same
+// method, path and query, or the last one once they have all been used, such
+// as when polling. Requests without a recorded response are served from the
+// server's state.
+//
+// When RWX_RECORD_FIXTURES is set, the fixture is recorded instead, against
+// the Cloud host in RWX_HOST (cloud.rwx.com by default) with the token in
+// RWX_ACCESS_TOKEN; see RecordFixtures.
+func (s *Server) UseFixtures(path string) error {
+	if recording(os.Getenv(RecordEnv)) {
+		host := os.Getenv("RWX_HOST")
+		if host == "" {
+			host = "cloud.rwx.com"
+		}
+		return s.RecordFixtures(path, host, os.Getenv("RWX_ACCESS_TOKEN"), nil)
+	}
+
+	f := &fixtures{server: s, path: path}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "unable to read fixture %q", path)
+	}
+	if err := json.Unmarshal(contents, &f.fixture); err != nil {
+		return errors.Wrapf(err, "unable to parse fixture %q", path)
+	}
+	f.used = make([]bool, len(f.fixture.Interactions))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fixtures = f
+	return nil
+}
+
+// RecordFixtures forwards every request to the Cloud host, with token in
+// place of the client's own when it's set, and rewrites the fixture at path
+// with each interaction. Credentials and secret values are redacted from the
+// fixture. The forwarded requests use transport, or http.DefaultTransport
+// when it's nil.
+func (s *Server) RecordFixtures(path, host, token string, transport http.RoundTripper) error {
+	f := &fixtures{
+		server:    s,
+		path:      path,
+		recording: true,
+		upstream:  host,
+		token:     token,
+		client:    &http.Client{Transport: transport},
+	}
+	if err := f.save(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fixtures = f
+	return nil
+}
+
+func recording(value string) bool {
+	return value == "1" || strings.EqualFold(value, "true")
+}
+
+// fixturePath is a request's path and query in the form they are matched.
+func fixturePath(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.Path
+	}
+	return u.Path + "?" + u.Query().Encode()
+}
+
+// serve writes the recorded response for a request, reporting whether there
+// was one.
+func (f *fixtures) serve(w http.ResponseWriter, r *http.Request, body []byte) bool {
+	if f.recording {
+		f.record(w, r, body)
+		return true
+	}
+
+	path := fixturePath(r.URL)
+
+	f.mu.Lock()
+	match := -1
+	for i, interaction := range f.fixture.Interactions {
+		if interaction.Request.Method != r.Method || interaction.Request.Path != path {
+			continue
+		}
+		match = i
+		if !f.used[i] {
+			break
+		}
+	}
+	if match >= 0 {
+		f.used[match] = true
+	}
+	f.mu.Unlock()
+
+	if match < 0 {
+		return false
+	}
+
+	response := f.fixture.Interactions[match].Response
+	responseBody := response.Raw
+	if len(response.Body) > 0 {
+		responseBody = bytes.ReplaceAll(response.Body, []byte(serverPlaceholder), []byte(f.server.URL))
+	}
+	if response.ContentType != "" {
+		w.Header().Set("Content-Type", response.ContentType)
+	}
+	w.WriteHeader(response.Status)
+	_, _ = w.Write(responseBody)
+	return true
+}
+
+// record forwards a request to Cloud, or to a URL that a previous response
+// pointed to, and records the interaction.
+func (f *fixtures) record(w http.ResponseWriter, r *http.Request, body []byte) {
+	target, toCloud, err := f.target(r)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	req, err := http.NewRequestWithContext(r.Context(), r.Method, target, bytes.NewReader(body))
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	for _, name := range []string{"Accept", "Content-Type", "User-Agent", "Authorization"} {
+		if value := r.Header.Get(name); value != "" {
+			req.Header.Set(name, value)
+		}
+	}
+	if toCloud && f.token != "" && req.Header.Get("Authorization") != "" {
+		req.Header.Set("Authorization", "Bearer "+f.token)
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	interaction := Interaction{
+		Request: FixtureRequest{Method: r.Method, Path: fixturePath(r.URL)},
+		Response: FixtureResponse{
+			Status:      resp.StatusCode,
+			ContentType: resp.Header.Get("Content-Type"),
+		},
+	}
+	if json.Valid(body) {
+		interaction.Request.Body = httplog.RedactBody(body)
+	}
+
+	if json.Valid(respBody) {
+		rewritten := f.rewriteURLs(respBody)
+		interaction.Response.Body = httplog.RedactBody(rewritten)
+		respBody = bytes.ReplaceAll(rewritten, []byte(serverPlaceholder), []byte(f.server.URL))
+	} else {
+		interaction.Response.Raw = respBody
+	}
+
+	f.mu.Lock()
+	f.fixture.Interactions = append(f.fixture.Interactions, interaction)
+	saveErr := f.saveLocked()
+	f.mu.Unlock()
+	if saveErr != nil {
+		writeError(w, http.StatusInternalServerError, saveErr.Error())
+		return
+	}
+
+	if interaction.Response.ContentType != "" {
+		w.Header().Set("Content-Type", interaction.Response.ContentType)
+	}
+	w.WriteHeader(resp.StatusCode)
+	_, _ = w.Write(respBody)
+}
+
+// target is the URL a request is forwarded to, and whether that's Cloud.
+func (f *fixtures) target(r *http.Request) (string, bool, error) {
+	if index, ok := strings.CutPrefix(r.URL.Path, "/_upstream/"); ok {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		var n int
+		if _, err := fmt.Sscanf(index, "%d", &n); err != nil || n < 0 || n >= len(f.upstreamURLs) {
+			return "", false, errors.Errorf("no upstream URL %q", index)
+		}
+		target, err := url.Parse(f.upstreamURLs[n])
+		if err != nil {
+			return "", false, errors.Wrap(err, "invalid upstream URL")
+		}
+		return target.String(), target.Host == f.upstream, nil
+	}
+
+	return "https://" + f.upstream + r.URL.RequestURI(), true, nil
+}
+
+// rewriteURLs points the URLs in a response that the client requests next
+// at the server, as a path under the server placeholder.
+func (f *fixtures) rewriteURLs(body []byte) []byte {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return body
+	}
+
+	f.mu.Lock()
+	rewritten := f.rewriteValue(value)
+	f.mu.Unlock()
+
+	encoded, err := json.Marshal(rewritten)
+	if err != nil {
+		return body
+	}
+	return encoded
+}
+
+func (f *fixtures) rewriteValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, inner := range v {
+			if s, ok := inner.(string); ok && fetchedURLFields[key] && strings.HasPrefix(s, "http") {
+				v[key] = f.upstreamPath(s)
+			} else {
+				v[key] = f.rewriteValue(inner)
+			}
+		}
+		return v
+	case []any:
+		for i, inner := range v {
+			v[i] = f.rewriteValue(inner)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+func (f *fixtures) upstreamPath(upstreamURL string) string {
+	n := -1
+	for i, existing := range f.upstreamURLs {
+		if existing == upstreamURL {
+			n = i
+			break
+		}
+	}
+	if n < 0 {
+		n = len(f.upstreamURLs)
+		f.upstreamURLs = append(f.upstreamURLs, upstreamURL)
+	}
+	return fmt.Sprintf("%s/_upstream/%d", serverPlaceholder, n)
+}
+
+func (f *fixtures) save() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.saveLocked()
+}
+
+func (f *fixtures) saveLocked() error {
+	if f.fixture.Interactions == nil {
+		f.fixture.Interactions = []Interaction{}
+	}
+	encoded, err := json.MarshalIndent(f.fixture, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "unable to encode fixture")
+	}
+	if err := os.WriteFile(f.path, append(encoded, '\n'), 0o644); err != nil {
+		return errors.Wrapf(err, "unable to write fixture %q", f.path)
+	}
+	return nil
+}
@@ -0,0 +1,873 @@
+package fakecloud
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rwx-cloud/rwx/internal/api"
+)
+
+func (s *Server) routes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /api/skill/latest", s.skillLatest)
+	mux.HandleFunc("POST /api/telemetry", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("POST /api/auth/codes", s.createAuthCode)
+	mux.HandleFunc("GET /_fake/authorize/{code}", s.authorizeCode)
+	mux.HandleFunc("GET /_fake/tokens/{code}", s.acquireToken)
+	mux.HandleFunc("GET /api/auth/whoami", s.whoami)
+	mux.HandleFunc("DELETE /api/auth/token", s.revokeToken)
+	mux.HandleFunc("POST /api/auth/docs_token", s.createDocsToken)
+
+	mux.HandleFunc("GET /mint/api/debug_connection_info", s.debugConnectionInfo)
+	mux.HandleFunc("GET /mint/api/sandbox_connection_info", s.sandboxConnectionInfo)
+	mux.HandleFunc("POST /mint/api/sandbox_tokens", s.createSandboxToken)
+	mux.HandleFunc("GET /mint/api/sandbox_init_template", s.sandboxInitTemplate)
+
+	mux.HandleFunc("POST /mint/api/runs", s.initiateRun)
+	mux.HandleFunc("GET /mint/api/runs", s.listSandboxRuns)
+	mux.HandleFunc("GET /mint/api/runs/latest", s.latestRunStatus)
+	mux.HandleFunc("GET /mint/api/runs/{id}", s.runStatus)
+	mux.HandleFunc("GET /mint/api/runs/{id}/{action}", s.runAction)
+	mux.HandleFunc("POST /mint/api/runs/{id}/cancel", s.cancelRun)
+	mux.HandleFunc("GET /mint/api/tasks/{id}/status", s.taskIDStatus)
+
+	mux.HandleFunc("POST /mint/api/runs/dispatches", s.initiateDispatch)
+
+	mux.HandleFunc("GET /mint/api/log_download", s.logDownloadRequest)
+	mux.HandleFunc("POST /_fake/logs/{task}", s.downloadLogs)
+	mux.HandleFunc("GET /mint/api/artifact_downloads", s.artifactDownloadRequests)
+	mux.HandleFunc("GET /mint/api/artifact_download", s.artifactDownloadRequest)
+	mux.HandleFunc("GET /_fake/artifacts/{task}/{key}", s.downloadArtifact)
+
+	mux.HandleFunc("POST /mint/api/vaults", s.createVault)
+	mux.HandleFunc("POST /mint/api/vaults/secrets", s.setSecrets)
+	mux.HandleFunc("DELETE /mint/api/vaults/secrets/{name}", s.deleteSecret)
+	mux.HandleFunc("POST /mint/api/vaults/vars", s.setVar)
+	mux.HandleFunc("GET /mint/api/vaults/vars/{name}", s.showVar)
+	mux.HandleFunc("DELETE /mint/api/vaults/vars/{name}", s.deleteVar)
+	mux.HandleFunc("POST /mint/api/vaults/oidc_tokens", s.createVaultOidcToken)
+
+	mux.HandleFunc("GET /mint/api/leaves", s.packageVersions)
+	// Package names include a slash, such as rwx/greeting
+	mux.HandleFunc("GET /mint/api/leaves/{path...}", s.packageDocumentation)
+	mux.HandleFunc("GET /mint/api/base/default", s.defaultBase)
+
+	mux.HandleFunc("POST /mint/api/images/pushes", s.startImagePush)
+	mux.HandleFunc("GET /mint/api/images/pushes/{id}", s.imagePushStatus)
+}
+
+func (s *Server) skillLatest(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, map[string]string{"version": s.state.SkillVersion})
+}
+
+func (s *Server) createAuthCode(w http.ResponseWriter, r *http.Request) {
+	var cfg api.ObtainAuthCodeConfig
+	if !decodeJSON(w, r, &cfg) {
+		return
+	}
+	if cfg.Code.DeviceName == "" {
+		writeError(w, http.StatusUnprocessableEntity, "device name must be provided")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code := s.newID("code")
+	s.state.AuthCodes[code] = &AuthCode{DeviceName: cfg.Code.DeviceName, State: "pending"}
+	writeJSON(w, http.StatusCreated, api.ObtainAuthCodeResult{
+		AuthorizationUrl: fmt.Sprintf("%s/_fake/authorize/%s", s.URL, code),
+		TokenUrl:         fmt.Sprintf("%s/_fake/tokens/%s", s.URL, code),
+	})
+}
+
+// authorizeCode stands in for the person who opens the authorization URL and
+// approves the login.
+func (s *Server) authorizeCode(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code, ok := s.state.AuthCodes[r.PathValue("code")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "code not found")
+		return
+	}
+	if code.State == "pending" {
+		code.State = "authorized"
+		code.Token = "token-" + r.PathValue("code")
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) acquireToken(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	code, ok := s.state.AuthCodes[r.PathValue("code")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "code not found")
+		return
+	}
+
+	result := api.AcquireTokenResult{State: code.State}
+	if code.State == "authorized" {
+		// The token is handed out once
+		result.Token = code.Token
+		code.State = "consumed"
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) whoami(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.state.Whoami)
+}
+
+func (s *Server) revokeToken(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state.RevokedTokens[bearerToken(r)] = true
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) createDocsToken(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusCreated, api.DocsTokenResult{Token: s.newID("docs-token")})
+}
+
+func (s *Server) debugConnectionInfo(w http.ResponseWriter, r *http.Request) {
+	key := r.URL.Query().Get("debug_key")
+	if key == "" {
+		writeError(w, http.StatusBadRequest, "missing debug_key")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if connection, ok := s.state.DebugConnections[key]; ok {
+		writeJSON(w, http.StatusOK, api.DebugConnectionInfo{
+			Debuggable:     true,
+			Address:        connection.Address,
+			PublicHostKey:  connection.PublicHostKey,
+			PrivateUserKey: connection.PrivateUserKey,
+		})
+		return
+	}
+
+	if run, ok := s.state.Runs[key]; ok && run.completed() {
+		writeError(w, http.StatusGone, "the run has completed and can no longer be debugged")
+		return
+	}
+	if _, task := s.state.task(key); task != nil && task.Result != "" {
+		writeError(w, http.StatusGone, "the task has completed and can no longer be debugged")
+		return
+	}
+
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func (s *Server) sandboxConnectionInfo(w http.ResponseWriter, r *http.Request) {
+	runID := r.URL.Query().Get("sandbox_key")
+	if runID == "" {
+		writeError(w, http.StatusBadRequest, "missing sandbox_key")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if scopedRunID, ok := s.state.SandboxTokens[bearerToken(r)]; ok && scopedRunID != runID {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	run, ok := s.state.Runs[runID]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("sandbox %s not found", runID))
+		return
+	}
+
+	result := api.SandboxConnectionInfo{}
+	switch {
+	case run.completed():
+		result.Polling = api.PollingResult{Completed: true}
+	case run.Sandbox == nil:
+		result.Polling = s.pending()
+	default:
+		result.Sandboxable = true
+		result.Address = run.Sandbox.Address
+		result.PublicHostKey = run.Sandbox.PublicHostKey
+		result.PrivateUserKey = run.Sandbox.PrivateUserKey
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) createSandboxToken(w http.ResponseWriter, r *http.Request) {
+	var cfg api.CreateSandboxTokenConfig
+	if !decodeJSON(w, r, &cfg) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.state.Runs[cfg.RunID]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("run %s not found", cfg.RunID))
+		return
+	}
+
+	expiresIn := cfg.ExpiresInSeconds
+	if expiresIn == 0 {
+		expiresIn = 3600
+	}
+	token := s.newID("sandbox-token")
+	s.state.SandboxTokens[token] = cfg.RunID
+	writeJSON(w, http.StatusCreated, api.CreateSandboxTokenResult{
+		Token:     token,
+		ExpiresAt: time.Now().Add(time.Duration(expiresIn) * time.Second).UTC().Format(time.RFC3339),
+		RunID:     cfg.RunID,
+	})
+}
+
+func (s *Server) sandboxInitTemplate(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, api.SandboxInitTemplateResult{Template: s.state.SandboxInitTemplate})
+}
+
+func (s *Server) initiateRun(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Run api.InitiateRunConfig `json:"run"`
+	}
+	if !decodeJSON(w, r, &body) {
+		return
+	}
+	cfg := body.Run
+	if len(cfg.TaskDefinitions) == 0 {
+		writeError(w, http.StatusUnprocessableEntity, "no task definitions")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run := &Run{
+		Title:          cfg.Title,
+		Branch:         cfg.Git.Branch,
+		Repository:     cfg.Git.OriginUrl,
+		CommitSha:      cfg.Git.Sha,
+		DefinitionPath: cfg.TaskDefinitions[0].Path,
+		CliState:       cfg.CliState,
+		Config:         cfg,
+	}
+	for _, key := range cfg.TargetedTaskKeys {
+		run.Tasks = append(run.Tasks, &Task{Key: key})
+	}
+	s.addRun(run)
+
+	targeted := cfg.TargetedTaskKeys
+	if targeted == nil {
+		targeted = []string{}
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{
+		"run_id":             run.ID,
+		"run_url":            run.URL,
+		"targeted_task_keys": targeted,
+		"definition_path":    run.DefinitionPath,
+	})
+}
+
+// listSandboxRuns lists the sandboxes that are running; it's the only listing
+// of runs the CLI makes.
+func (s *Server) listSandboxRuns(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := api.ListSandboxRunsResult{Runs: []api.SandboxRunSummary{}}
+	for _, id := range s.state.runOrder {
+		run, ok := s.state.Runs[id]
+		if !ok || run.CliState == "" || run.completed() {
+			continue
+		}
+		result.Runs = append(result.Runs, api.SandboxRunSummary{
+			ID:       run.ID,
+			RunURL:   run.URL,
+			Title:    run.Title,
+			CliState: run.CliState,
+		})
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) runStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run, ok := s.state.Runs[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("run %s not found", r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.pollRun(run, r.URL.Query().Get("fail_fast") == "true"))
+}
+
+func (s *Server) latestRunStatus(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := len(s.state.runOrder) - 1; i >= 0; i-- {
+		run, ok := s.state.Runs[s.state.runOrder[i]]
+		if !ok || run.Branch != query.Get("branch_name") || !matchesRepository(run.Repository, query.Get("repository_name")) {
+			continue
+		}
+		if path := query.Get("definition_path"); path != "" && run.DefinitionPath != path {
+			continue
+		}
+		writeJSON(w, http.StatusOK, s.pollRun(run, query.Get("fail_fast") == "true"))
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "no run found for the branch")
+}
+
+// matchesRepository compares a repository name, such as rwx, with the origin
+// URL of a run's repository.
+func matchesRepository(originURL, name string) bool {
+	return strings.TrimSuffix(originURL[strings.LastIndexAny(originURL, "/:")+1:], ".git") == name
+}
+
+func (s *Server) pollRun(run *Run, failFast bool) api.RunStatusResult {
+	result := api.RunStatusResult{RunID: run.ID, RunURL: run.URL}
+	if run.CommitSha != "" {
+		commit := run.CommitSha
+		result.Commit = &commit
+	}
+
+	if failFast && run.Result == "" {
+		for _, task := range run.Tasks {
+			if task.Result == "failed" {
+				result.Status = &api.RunStatus{Result: "failed"}
+				result.Polling = api.PollingResult{Completed: true}
+				return result
+			}
+		}
+	}
+
+	if !poll(&run.PendingPolls, run.Result) {
+		result.Polling = s.pending()
+		return result
+	}
+	result.Status = &api.RunStatus{Result: run.Result}
+	result.Polling = api.PollingResult{Completed: true}
+	return result
+}
+
+// runAction serves the endpoints under a run, and dispatches, which share
+// their path.
+func (s *Server) runAction(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.PathValue("id") == "dispatches":
+		s.getDispatch(w, r.PathValue("action"))
+	case r.PathValue("action") == "prompt":
+		s.runPrompt(w, r)
+	case r.PathValue("action") == "task_status":
+		s.taskKeyStatus(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) runPrompt(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run, ok := s.state.Runs[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("run %s not found", r.PathValue("id")))
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain")
+	_, _ = w.Write([]byte(run.Prompt))
+}
+
+func (s *Server) cancelRun(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run, ok := s.state.Runs[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("run %s not found", r.PathValue("id")))
+		return
+	}
+	if scopedRunID, ok := s.state.SandboxTokens[bearerToken(r)]; ok && scopedRunID != run.ID {
+		writeError(w, http.StatusUnauthorized, "the token can't cancel this run")
+		return
+	}
+	if run.completed() {
+		writeError(w, http.StatusUnprocessableEntity, "the run has already completed")
+		return
+	}
+
+	run.Cancelled = true
+	run.Result = "cancelled"
+	run.PendingPolls = 0
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+func (s *Server) taskKeyStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, task, ok := s.findTask(w, r.PathValue("id"), r.URL.Query().Get("task_key"))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.pollTask(task))
+}
+
+func (s *Server) taskIDStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, task := s.state.task(r.PathValue("id"))
+	if task == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("task %s not found", r.PathValue("id")))
+		return
+	}
+	writeJSON(w, http.StatusOK, s.pollTask(task))
+}
+
+func (s *Server) pollTask(task *Task) api.TaskStatusResult {
+	result := api.TaskStatusResult{TaskID: task.ID}
+	if !poll(&task.PendingPolls, task.Result) {
+		result.Polling = s.pending()
+		return result
+	}
+	result.Status = &api.TaskStatus{Result: task.Result}
+	result.Polling = api.PollingResult{Completed: true}
+	return result
+}
+
+// findTask finds a task by its run and key, or by its ID when there's no
+// key, writing the error response when there's no such task.
+func (s *Server) findTask(w http.ResponseWriter, runID, key string) (*Run, *Task, bool) {
+	if key == "" {
+		run, task := s.state.task(runID)
+		if task == nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("task %s not found", runID))
+			return nil, nil, false
+		}
+		return run, task, true
+	}
+
+	run, ok := s.state.Runs[runID]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("run %s not found", runID))
+		return nil, nil, false
+	}
+
+	tasks := run.tasksByKey(key)
+	switch len(tasks) {
+	case 0:
+		writeError(w, http.StatusNotFound, fmt.Sprintf("task %q not found in run %s", key, runID))
+		return nil, nil, false
+	case 1:
+		return run, tasks[0], true
+	default:
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("%d tasks in run %s have the key %q", len(tasks), runID, key))
+		return nil, nil, false
+	}
+}
+
+// queriedTask finds the task a download request is for, given either its ID
+// or its run and key.
+func (s *Server) queriedTask(w http.ResponseWriter, query url.Values, idParam string) (*Run, *Task, bool) {
+	if id := query.Get(idParam); id != "" {
+		return s.findTask(w, id, "")
+	}
+	return s.findTask(w, query.Get("run_id"), query.Get("task_key"))
+}
+
+func (s *Server) logDownloadRequest(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run, task, ok := s.queriedTask(w, r.URL.Query(), "id")
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, api.LogDownloadRequestResult{
+		URL:      fmt.Sprintf("%s/_fake/logs/%s", s.URL, url.PathEscape(task.ID)),
+		Token:    "log-token-" + task.ID,
+		Filename: task.Key + ".log",
+		RunID:    run.ID,
+	})
+}
+
+func (s *Server) downloadLogs(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, task := s.state.task(r.PathValue("task"))
+	if task == nil {
+		writeError(w, http.StatusNotFound, "task not found")
+		return
+	}
+	if r.FormValue("token") != "log-token-"+task.ID {
+		writeError(w, http.StatusForbidden, "invalid token")
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = w.Write(task.Logs)
+}
+
+func (s *Server) artifactDownloadRequests(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, task, ok := s.queriedTask(w, r.URL.Query(), "task_id")
+	if !ok {
+		return
+	}
+
+	results := []api.ArtifactDownloadRequestResult{}
+	for _, artifact := range task.Artifacts {
+		results = append(results, s.artifactDownload(task, artifact))
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+func (s *Server) artifactDownloadRequest(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, task, ok := s.queriedTask(w, r.URL.Query(), "task_id")
+	if !ok {
+		return
+	}
+
+	key := r.URL.Query().Get("key")
+	for _, artifact := range task.Artifacts {
+		if artifact.Key == key {
+			writeJSON(w, http.StatusOK, s.artifactDownload(task, artifact))
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("artifact %q not found", key))
+}
+
+func (s *Server) artifactDownload(task *Task, artifact Artifact) api.ArtifactDownloadRequestResult {
+	return api.ArtifactDownloadRequestResult{
+		URL:         fmt.Sprintf("%s/_fake/artifacts/%s/%s", s.URL, url.PathEscape(task.ID), url.PathEscape(artifact.Key)),
+		Filename:    artifact.Filename,
+		SizeInBytes: int64(len(artifact.Contents)),
+		Kind:        artifact.Kind,
+		Key:         artifact.Key,
+	}
+}
+
+func (s *Server) downloadArtifact(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, task := s.state.task(r.PathValue("task"))
+	if task != nil {
+		for _, artifact := range task.Artifacts {
+			if artifact.Key == r.PathValue("key") {
+				w.Header().Set("Content-Type", "application/octet-stream")
+				_, _ = w.Write(artifact.Contents)
+				return
+			}
+		}
+	}
+	writeError(w, http.StatusNotFound, "artifact not found")
+}
+
+func (s *Server) initiateDispatch(w http.ResponseWriter, r *http.Request) {
+	var cfg api.InitiateDispatchConfig
+	if !decodeJSON(w, r, &cfg) {
+		return
+	}
+	if cfg.DispatchKey == "" {
+		writeError(w, http.StatusUnprocessableEntity, "no dispatch key was provided")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dispatch := &Dispatch{ID: s.newID("dispatch"), Config: cfg}
+	dispatch.RunID = s.addRun(&Run{Title: cfg.Title, Branch: cfg.Ref})
+	s.state.Dispatches[dispatch.ID] = dispatch
+	writeJSON(w, http.StatusCreated, map[string]string{"dispatch_id": dispatch.ID})
+}
+
+func (s *Server) getDispatch(w http.ResponseWriter, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dispatch, ok := s.state.Dispatches[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("dispatch %s not found", id))
+		return
+	}
+
+	switch {
+	case dispatch.PendingPolls > 0:
+		dispatch.PendingPolls--
+		writeJSON(w, http.StatusOK, map[string]any{"status": "not_ready"})
+	case dispatch.Error != "":
+		writeJSON(w, http.StatusOK, map[string]any{"status": "error", "error": dispatch.Error})
+	default:
+		runs := []api.GetDispatchRun{}
+		if run, ok := s.state.Runs[dispatch.RunID]; ok {
+			runs = append(runs, api.GetDispatchRun{RunID: run.ID, RunUrl: run.URL})
+		}
+		writeJSON(w, http.StatusOK, map[string]any{"status": "ready", "runs": runs})
+	}
+}
+
+func (s *Server) createVault(w http.ResponseWriter, r *http.Request) {
+	var cfg api.CreateVaultConfig
+	if !decodeJSON(w, r, &cfg) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.state.Vaults[cfg.Name]; ok {
+		writeError(w, http.StatusUnprocessableEntity, fmt.Sprintf("vault %q already exists", cfg.Name))
+		return
+	}
+	s.state.Vaults[cfg.Name] = &Vault{
+		Name:                  cfg.Name,
+		Unlocked:              cfg.Unlocked,
+		RepositoryPermissions: cfg.RepositoryPermissions,
+		Secrets:               map[string]string{},
+		Vars:                  map[string]string{},
+	}
+	writeJSON(w, http.StatusCreated, map[string]any{})
+}
+
+// vault finds a vault, writing the error response when there's no such vault.
+func (s *Server) vault(w http.ResponseWriter, name string) (*Vault, bool) {
+	vault, ok := s.state.Vaults[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("vault %q not found", name))
+	}
+	return vault, ok
+}
+
+func (s *Server) setSecrets(w http.ResponseWriter, r *http.Request) {
+	var cfg api.SetSecretsInVaultConfig
+	if !decodeJSON(w, r, &cfg) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vault, ok := s.vault(w, cfg.VaultName)
+	if !ok {
+		return
+	}
+	result := api.SetSecretsInVaultResult{SetSecrets: []string{}}
+	for _, secret := range cfg.Secrets {
+		vault.Secrets[secret.Name] = secret.Secret
+		result.SetSecrets = append(result.SetSecrets, secret.Name)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) deleteSecret(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vault, ok := s.vault(w, r.URL.Query().Get("vault_name"))
+	if !ok {
+		return
+	}
+	name := r.PathValue("name")
+	if _, ok := vault.Secrets[name]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("secret %q not found", name))
+		return
+	}
+	delete(vault.Secrets, name)
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+func (s *Server) setVar(w http.ResponseWriter, r *http.Request) {
+	var cfg api.SetVarConfig
+	if !decodeJSON(w, r, &cfg) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vault, ok := s.vault(w, cfg.VaultName)
+	if !ok {
+		return
+	}
+	vault.Vars[cfg.Var.Name] = cfg.Var.Value
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+func (s *Server) showVar(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vault, ok := s.vault(w, r.URL.Query().Get("vault_name"))
+	if !ok {
+		return
+	}
+	name := r.PathValue("name")
+	value, ok := vault.Vars[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("var %q not found", name))
+		return
+	}
+	writeJSON(w, http.StatusOK, api.ShowVarResult{Name: name, Value: value})
+}
+
+func (s *Server) deleteVar(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vault, ok := s.vault(w, r.URL.Query().Get("vault_name"))
+	if !ok {
+		return
+	}
+	name := r.PathValue("name")
+	if _, ok := vault.Vars[name]; !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("var %q not found", name))
+		return
+	}
+	delete(vault.Vars, name)
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+func (s *Server) createVaultOidcToken(w http.ResponseWriter, r *http.Request) {
+	var cfg api.CreateVaultOidcTokenConfig
+	if !decodeJSON(w, r, &cfg) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	vault, ok := s.state.Vaults[cfg.VaultName]
+	if !ok {
+		// This endpoint reports validation failures as a list
+		writeJSON(w, http.StatusUnprocessableEntity, map[string][]string{"errors": {fmt.Sprintf("Vault %q not found", cfg.VaultName)}})
+		return
+	}
+	vault.OidcTokens = append(vault.OidcTokens, cfg)
+
+	name := cfg.Name
+	if name == "" {
+		name = "default"
+	}
+	audience := cfg.Audience
+	if audience == "" {
+		audience = s.URL
+	}
+	writeJSON(w, http.StatusCreated, api.CreateVaultOidcTokenResult{
+		Audience:         audience,
+		Subject:          fmt.Sprintf("org:%s:vault:%s", s.state.Whoami.OrganizationSlug, vault.Name),
+		Expression:       fmt.Sprintf("${{ vaults.%s.oidc.%s }}", vault.Name, name),
+		DocumentationURL: s.URL + "/docs/oidc",
+	})
+}
+
+func (s *Server) packageVersions(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.state.Packages)
+}
+
+func (s *Server) packageDocumentation(w http.ResponseWriter, r *http.Request) {
+	name, ok := strings.CutSuffix(r.PathValue("path"), "/documentation")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	documentation, ok := s.state.Documentation[name]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("package %q not found", name))
+		return
+	}
+	writeJSON(w, http.StatusOK, documentation)
+}
+
+func (s *Server) defaultBase(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writeJSON(w, http.StatusOK, s.state.DefaultBase)
+}
+
+func (s *Server) startImagePush(w http.ResponseWriter, r *http.Request) {
+	var cfg api.StartImagePushConfig
+	if !decodeJSON(w, r, &cfg) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	run, task := s.state.task(cfg.TaskID)
+	if task == nil {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("task %s not found", cfg.TaskID))
+		return
+	}
+
+	push := &ImagePush{ID: s.newID("push"), Config: cfg}
+	s.state.ImagePushes[push.ID] = push
+	writeJSON(w, http.StatusCreated, api.StartImagePushResult{PushID: push.ID, RunURL: run.URL})
+}
+
+func (s *Server) imagePushStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	push, ok := s.state.ImagePushes[r.PathValue("id")]
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Sprintf("image push %s not found", r.PathValue("id")))
+		return
+	}
+
+	status := push.Status
+	if status == "" {
+		status = "succeeded"
+	}
+	if !poll(&push.PendingPolls, status) {
+		status = "in_progress"
+	}
+	writeJSON(w, http.StatusOK, api.ImagePushStatusResult{Status: status})
+}
+
+func (s *Server) pending() api.PollingResult {
+	backoff := s.state.PollBackoffMs
+	return api.PollingResult{Completed: false, BackoffMs: &backoff}
+}
+
+func (r *Run) completed() bool {
+	return r.Cancelled || (r.Result != "" && r.PendingPolls == 0)
+}
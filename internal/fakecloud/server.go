@@ -0,0 +1,245 @@
+// Package fakecloud is an in-memory fake of the RWX Cloud API. It serves every
+// endpoint that api.Client calls, so that tests can exercise the real client,
+// and the CLI itself, without network access. Responses can also be replayed
+// from fixtures recorded against Cloud; see UseFixtures.
+package fakecloud
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/rwx-cloud/rwx/internal/accesstoken"
+	"github.com/rwx-cloud/rwx/internal/api"
+	"github.com/rwx-cloud/rwx/internal/errors"
+)
+
+// DefaultAccessToken is the token that APIConfig configures clients with.
+const DefaultAccessToken = "fake-access-token"
+
+// Server is a fake RWX Cloud listening on a local HTTPS address.
+type Server struct {
+	*httptest.Server
+	accessToken string
+	mu          sync.Mutex
+	state       *State
+	seq         int
+	requests    []Request
+	fixtures    *fixtures
+}
+
+// Request is a request the server received, for tests to assert on.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Body   []byte
+}
+
+type Option func(*Server)
+
+// WithAccessToken rejects requests made with any other token, apart from
+// tokens the server issued itself. Any token is accepted by default.
+func WithAccessToken(token string) Option {
+	return func(s *Server) {
+		s.accessToken = token
+	}
+}
+
+// New starts a server. Close it when done.
+func New(opts ...Option) *Server {
+	s := &Server{state: newState()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.state.Vaults["default"] = &Vault{Name: "default", Secrets: map[string]string{}, Vars: map[string]string{}}
+
+	mux := http.NewServeMux()
+	s.routes(mux)
+	s.Server = httptest.NewTLSServer(s.handle(mux))
+	return s
+}
+
+// Host is the address clients are configured with, such as through RWX_HOST.
+func (s *Server) Host() string {
+	return s.Listener.Addr().String()
+}
+
+// APIConfig is the configuration for an api.Client of this server.
+func (s *Server) APIConfig() api.Config {
+	token := s.accessToken
+	if token == "" {
+		token = DefaultAccessToken
+	}
+
+	return api.Config{
+		Host:               s.Host(),
+		AccessToken:        token,
+		AccessTokenBackend: accesstoken.NewMemoryBackend(),
+		Transport:          s.Client().Transport.(*http.Transport),
+	}
+}
+
+// WriteCACert writes the server's certificate as PEM, for a CLI run with
+// --ca-cert or RWX_CA_BUNDLE to trust.
+func (s *Server) WriteCACert(path string) error {
+	encoded := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+	if err := os.WriteFile(path, encoded, 0o644); err != nil {
+		return errors.Wrapf(err, "unable to write %q", path)
+	}
+	return nil
+}
+
+// Update changes the server's state, such as to seed it or to complete a run.
+func (s *Server) Update(fn func(*State)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(s.state)
+}
+
+// View reads the server's state.
+func (s *Server) View(fn func(*State)) {
+	s.Update(fn)
+}
+
+// AddRun adds a run, assigning IDs and a URL to the run and its tasks when
+// they don't have one. It returns the run's ID.
+func (s *Server) AddRun(run Run) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addRun(&run)
+}
+
+// Requests are the requests the server has received, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) addRun(run *Run) string {
+	if run.ID == "" {
+		run.ID = s.newID("run")
+	}
+	if run.URL == "" {
+		run.URL = fmt.Sprintf("%s/mint/%s/runs/%s", s.URL, s.state.Whoami.OrganizationSlug, run.ID)
+	}
+	for _, task := range run.Tasks {
+		if task.ID == "" {
+			task.ID = s.newID("task")
+		}
+	}
+	s.state.Runs[run.ID] = run
+	s.state.runOrder = append(s.state.runOrder, run.ID)
+	return run.ID
+}
+
+func (s *Server) newID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s-%d", prefix, s.seq)
+}
+
+// handle records each request, then replays a fixture for it or serves it
+// from the server's state, unless the server is unavailable.
+func (s *Server) handle(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "unable to read the request body")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		s.mu.Lock()
+		s.requests = append(s.requests, Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query(), Body: body})
+		fixtures := s.fixtures
+		unavailable := s.state.Unavailable > 0
+		if unavailable {
+			s.state.Unavailable--
+		}
+		s.mu.Unlock()
+
+		if unavailable {
+			w.Header().Set("Retry-After", "0")
+			writeError(w, http.StatusServiceUnavailable, "service unavailable")
+			return
+		}
+
+		if fixtures != nil && fixtures.serve(w, r, body) {
+			return
+		}
+
+		if !s.authorized(r) {
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// publicPaths don't need an access token: logging in doesn't have one yet,
+// and downloads are authorized by their URL.
+var publicPaths = []string{"/api/auth/codes", "/api/telemetry", "/_fake/"}
+
+func (s *Server) authorized(r *http.Request) bool {
+	for _, prefix := range publicPaths {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return true
+		}
+	}
+
+	token := bearerToken(r)
+	if token == "" {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state.RevokedTokens[token] {
+		return false
+	}
+	if s.accessToken == "" || token == s.accessToken {
+		return true
+	}
+	if _, ok := s.state.SandboxTokens[token]; ok {
+		return true
+	}
+	for _, code := range s.state.AuthCodes {
+		if code.Token == token {
+			return true
+		}
+	}
+	return false
+}
+
+func bearerToken(r *http.Request) string {
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+func writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+func decodeJSON(w http.ResponseWriter, r *http.Request, value any) bool {
+	if err := json.NewDecoder(r.Body).Decode(value); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON: %s", err))
+		return false
+	}
+	return true
+}
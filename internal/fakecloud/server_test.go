@@ -0,0 +1,433 @@
+package fakecloud_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rwx-cloud/rwx/internal/api"
+	"github.com/rwx-cloud/rwx/internal/errors"
+	"github.com/rwx-cloud/rwx/internal/fakecloud"
+	"github.com/rwx-cloud/rwx/internal/retry"
+	"github.com/stretchr/testify/require"
+)
+
+func newClient(t *testing.T, opts ...fakecloud.Option) (*fakecloud.Server, api.Client) {
+	server := fakecloud.New(opts...)
+	t.Cleanup(server.Close)
+
+	c, err := api.NewClient(server.APIConfig())
+	require.NoError(t, err)
+	return server, c
+}
+
+func TestServer_Runs(t *testing.T) {
+	t.Run("initiates a run and reports its status once it completes", func(t *testing.T) {
+		server, c := newClient(t)
+
+		result, err := c.InitiateRun(t.Context(), api.InitiateRunConfig{
+			TaskDefinitions:  []api.RwxDirectoryEntry{{Path: ".rwx/ci.yml", FileContents: "tasks: []", Type: "file"}},
+			TargetedTaskKeys: []string{"test"},
+			Title:            "CI",
+			Git:              api.GitMetadata{Branch: "main", Sha: "abc123", OriginUrl: "git@github.com:rwx-cloud/rwx.git"},
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, result.RunID)
+		require.Equal(t, []string{"test"}, result.TargetedTaskKeys)
+		require.Equal(t, ".rwx/ci.yml", result.DefinitionPath)
+
+		status, err := c.RunStatus(t.Context(), api.RunStatusConfig{RunID: result.RunID})
+		require.NoError(t, err)
+		require.False(t, status.Polling.Completed)
+		require.NotNil(t, status.Polling.BackoffMs)
+
+		server.Update(func(state *fakecloud.State) {
+			state.Runs[result.RunID].Result = "succeeded"
+			state.Runs[result.RunID].PendingPolls = 1
+		})
+
+		status, err = c.RunStatus(t.Context(), api.RunStatusConfig{BranchName: "main", RepositoryName: "rwx"})
+		require.NoError(t, err)
+		require.Equal(t, result.RunID, status.RunID)
+		require.False(t, status.Polling.Completed)
+
+		status, err = c.RunStatus(t.Context(), api.RunStatusConfig{RunID: result.RunID})
+		require.NoError(t, err)
+		require.True(t, status.Polling.Completed)
+		require.Equal(t, "succeeded", status.Status.Result)
+		require.Equal(t, "abc123", *status.Commit)
+	})
+
+	t.Run("reports a missing run as not found", func(t *testing.T) {
+		_, c := newClient(t)
+
+		_, err := c.RunStatus(t.Context(), api.RunStatusConfig{RunID: "missing"})
+		require.ErrorIs(t, err, api.ErrNotFound)
+	})
+
+	t.Run("cancels a run", func(t *testing.T) {
+		server, c := newClient(t)
+		runID := server.AddRun(fakecloud.Run{})
+
+		require.NoError(t, c.CancelRun(t.Context(), runID, ""))
+
+		status, err := c.RunStatus(t.Context(), api.RunStatusConfig{RunID: runID})
+		require.NoError(t, err)
+		require.True(t, status.Polling.Completed)
+		require.Equal(t, "cancelled", status.Status.Result)
+	})
+}
+
+func TestServer_Tasks(t *testing.T) {
+	server, c := newClient(t)
+	runID := server.AddRun(fakecloud.Run{
+		Prompt: "Fix the failing tests",
+		Tasks: []*fakecloud.Task{
+			{
+				Key:    "test",
+				Result: "failed",
+				Logs:   []byte("1 example, 1 failure\n"),
+				Artifacts: []fakecloud.Artifact{
+					{Key: "report", Filename: "report.json", Kind: "file", Contents: []byte(`{"failures":1}`)},
+				},
+			},
+			{Key: "lint"},
+			{Key: "lint"},
+		},
+	})
+
+	t.Run("reports task status by key and by ID", func(t *testing.T) {
+		status, err := c.TaskKeyStatus(t.Context(), api.TaskKeyStatusConfig{RunID: runID, TaskKey: "test"})
+		require.NoError(t, err)
+		require.True(t, status.Polling.Completed)
+		require.Equal(t, "failed", status.Status.Result)
+
+		status, err = c.TaskIDStatus(t.Context(), api.TaskIDStatusConfig{TaskID: status.TaskID})
+		require.NoError(t, err)
+		require.Equal(t, "failed", status.Status.Result)
+	})
+
+	t.Run("reports an ambiguous task key", func(t *testing.T) {
+		_, err := c.TaskKeyStatus(t.Context(), api.TaskKeyStatusConfig{RunID: runID, TaskKey: "lint"})
+		require.ErrorIs(t, err, errors.ErrAmbiguousTaskKey)
+	})
+
+	t.Run("downloads logs", func(t *testing.T) {
+		request, err := c.GetLogDownloadRequestByTaskKey(t.Context(), runID, "test")
+		require.NoError(t, err)
+		require.Equal(t, runID, request.RunID)
+
+		logs, err := c.DownloadLogs(t.Context(), request)
+		require.NoError(t, err)
+		require.Equal(t, "1 example, 1 failure\n", string(logs))
+	})
+
+	t.Run("downloads artifacts", func(t *testing.T) {
+		requests, err := c.GetAllArtifactDownloadRequestsByTaskKey(t.Context(), runID, "test")
+		require.NoError(t, err)
+		require.Len(t, requests, 1)
+		require.Equal(t, "report", requests[0].Key)
+		require.EqualValues(t, 14, requests[0].SizeInBytes)
+
+		request, err := c.GetArtifactDownloadRequestByTaskKey(t.Context(), runID, "test", "report")
+		require.NoError(t, err)
+
+		contents, err := c.DownloadArtifact(t.Context(), request)
+		require.NoError(t, err)
+		require.Equal(t, `{"failures":1}`, string(contents))
+
+		_, err = c.GetArtifactDownloadRequestByTaskKey(t.Context(), runID, "test", "missing")
+		require.ErrorIs(t, err, api.ErrNotFound)
+	})
+
+	t.Run("returns the run's prompt", func(t *testing.T) {
+		prompt, err := c.GetRunPrompt(t.Context(), runID)
+		require.NoError(t, err)
+		require.Equal(t, "Fix the failing tests", prompt)
+	})
+}
+
+func TestServer_Vaults(t *testing.T) {
+	server, c := newClient(t)
+
+	_, err := c.CreateVault(t.Context(), api.CreateVaultConfig{Name: "production"})
+	require.NoError(t, err)
+
+	_, err = c.CreateVault(t.Context(), api.CreateVaultConfig{Name: "production"})
+	require.ErrorContains(t, err, "already exists")
+
+	secrets, err := c.SetSecretsInVault(t.Context(), api.SetSecretsInVaultConfig{
+		VaultName: "production",
+		Secrets:   []api.Secret{{Name: "API_KEY", Secret: "hunter2"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"API_KEY"}, secrets.SetSecrets)
+
+	_, err = c.SetVar(t.Context(), api.SetVarConfig{VaultName: "production", Var: api.Var{Name: "REGION", Value: "us-east-1"}})
+	require.NoError(t, err)
+
+	shown, err := c.ShowVar(t.Context(), api.ShowVarConfig{VaultName: "production", VarName: "REGION"})
+	require.NoError(t, err)
+	require.Equal(t, "us-east-1", shown.Value)
+
+	_, err = c.DeleteVar(t.Context(), api.DeleteVarConfig{VaultName: "production", VarName: "REGION"})
+	require.NoError(t, err)
+
+	_, err = c.ShowVar(t.Context(), api.ShowVarConfig{VaultName: "production", VarName: "REGION"})
+	require.ErrorContains(t, err, `var "REGION" not found`)
+
+	_, err = c.DeleteSecret(t.Context(), api.DeleteSecretConfig{VaultName: "production", SecretName: "API_KEY"})
+	require.NoError(t, err)
+
+	_, err = c.CreateVaultOidcToken(t.Context(), api.CreateVaultOidcTokenConfig{VaultName: "missing"})
+	require.ErrorContains(t, err, `Vault "missing" not found`)
+
+	server.View(func(state *fakecloud.State) {
+		require.Empty(t, state.Vaults["production"].Secrets)
+		require.Empty(t, state.Vaults["production"].Vars)
+	})
+}
+
+func TestServer_Auth(t *testing.T) {
+	t.Run("logs in a device once the code is authorized", func(t *testing.T) {
+		server, c := newClient(t)
+
+		code, err := c.ObtainAuthCode(t.Context(), api.ObtainAuthCodeConfig{Code: api.ObtainAuthCodeCode{DeviceName: "laptop"}})
+		require.NoError(t, err)
+
+		token, err := c.AcquireToken(t.Context(), code.TokenUrl)
+		require.NoError(t, err)
+		require.Equal(t, "pending", token.State)
+
+		// Stands in for the person approving the login in their browser
+		resp, err := server.Client().Get(code.AuthorizationUrl)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		token, err = c.AcquireToken(t.Context(), code.TokenUrl)
+		require.NoError(t, err)
+		require.Equal(t, "authorized", token.State)
+		require.NotEmpty(t, token.Token)
+
+		token, err = c.AcquireToken(t.Context(), code.TokenUrl)
+		require.NoError(t, err)
+		require.Equal(t, "consumed", token.State)
+	})
+
+	t.Run("rejects other tokens and revoked tokens", func(t *testing.T) {
+		server := fakecloud.New(fakecloud.WithAccessToken("the-token"))
+		t.Cleanup(server.Close)
+
+		cfg := server.APIConfig()
+		cfg.AccessToken = "another-token"
+		other, err := api.NewClient(cfg)
+		require.NoError(t, err)
+
+		_, err = other.Whoami(t.Context())
+		require.ErrorContains(t, err, "401")
+
+		c, err := api.NewClient(server.APIConfig())
+		require.NoError(t, err)
+
+		whoami, err := c.Whoami(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, "fake-org", whoami.OrganizationSlug)
+
+		require.NoError(t, c.RevokeToken(t.Context()))
+		require.ErrorIs(t, c.RevokeToken(t.Context()), errors.ErrUnauthorized)
+	})
+	t.Run("is unavailable for as many requests as asked", func(t *testing.T) {
+		server, c := newClient(t)
+		server.Update(func(state *fakecloud.State) {
+			state.Unavailable = 1
+		})
+
+		_, err := c.Whoami(t.Context())
+		require.ErrorContains(t, err, "503")
+
+		c.RoundTripper = retry.NewRoundTripper(c.RoundTripper)
+		server.Update(func(state *fakecloud.State) {
+			state.Unavailable = 2
+		})
+
+		whoami, err := c.Whoami(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, "fake-org", whoami.OrganizationSlug)
+	})
+}
+
+func TestServer_Sandboxes(t *testing.T) {
+	server, c := newClient(t)
+	runID := server.AddRun(fakecloud.Run{
+		CliState: "encoded-state",
+		Sandbox:  &fakecloud.Connection{Address: "127.0.0.1:2222", PublicHostKey: "host-key", PrivateUserKey: "user-key"},
+	})
+	otherRunID := server.AddRun(fakecloud.Run{CliState: "encoded-state"})
+
+	token, err := c.CreateSandboxToken(t.Context(), api.CreateSandboxTokenConfig{RunID: runID})
+	require.NoError(t, err)
+
+	info, err := c.GetSandboxConnectionInfo(t.Context(), runID, token.Token)
+	require.NoError(t, err)
+	require.True(t, info.Sandboxable)
+	require.Equal(t, "127.0.0.1:2222", info.Address)
+
+	_, err = c.GetSandboxConnectionInfo(t.Context(), otherRunID, token.Token)
+	require.ErrorIs(t, err, errors.ErrUnauthorized)
+
+	info, err = c.GetSandboxConnectionInfo(t.Context(), otherRunID, "")
+	require.NoError(t, err)
+	require.False(t, info.Sandboxable)
+	require.False(t, info.Polling.Completed)
+
+	runs, err := c.ListSandboxRuns(t.Context())
+	require.NoError(t, err)
+	require.Len(t, runs.Runs, 2)
+
+	require.NoError(t, c.CancelRun(t.Context(), otherRunID, ""))
+
+	runs, err = c.ListSandboxRuns(t.Context())
+	require.NoError(t, err)
+	require.Len(t, runs.Runs, 1)
+	require.Equal(t, runID, runs.Runs[0].ID)
+}
+
+func TestServer_DispatchesAndImagePushes(t *testing.T) {
+	server, c := newClient(t)
+
+	dispatch, err := c.InitiateDispatch(t.Context(), api.InitiateDispatchConfig{DispatchKey: "deploy", Title: "Deploy"})
+	require.NoError(t, err)
+
+	server.Update(func(state *fakecloud.State) {
+		state.Dispatches[dispatch.DispatchId].PendingPolls = 1
+	})
+
+	result, err := c.GetDispatch(t.Context(), api.GetDispatchConfig{DispatchId: dispatch.DispatchId})
+	require.NoError(t, err)
+	require.Equal(t, "not_ready", result.Status)
+
+	result, err = c.GetDispatch(t.Context(), api.GetDispatchConfig{DispatchId: dispatch.DispatchId})
+	require.NoError(t, err)
+	require.Equal(t, "ready", result.Status)
+	require.Len(t, result.Runs, 1)
+
+	runID := server.AddRun(fakecloud.Run{Tasks: []*fakecloud.Task{{ID: "image-task", Key: "image", Result: "succeeded"}}})
+
+	push, err := c.StartImagePush(t.Context(), api.StartImagePushConfig{TaskID: "image-task"})
+	require.NoError(t, err)
+	require.Contains(t, push.RunURL, runID)
+
+	status, err := c.ImagePushStatus(t.Context(), push.PushID)
+	require.NoError(t, err)
+	require.Equal(t, "succeeded", status.Status)
+}
+
+func TestServer_Packages(t *testing.T) {
+	server, c := newClient(t)
+	server.Update(func(state *fakecloud.State) {
+		state.Packages.LatestMajor["rwx/greeting"] = "1.0.3"
+		state.Documentation["rwx/greeting"] = api.PackageDocumentationResult{Name: "rwx/greeting", Version: "1.0.3"}
+		state.DefaultBase = api.DefaultBaseResult{Image: "ubuntu:24.04", Config: "rwx/base 1.0.0", Arch: "x86_64"}
+	})
+
+	versions, err := c.GetPackageVersions(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "1.0.3", versions.LatestMajor["rwx/greeting"])
+
+	documentation, err := c.GetPackageDocumentation(t.Context(), "rwx/greeting")
+	require.NoError(t, err)
+	require.Equal(t, "1.0.3", documentation.Version)
+
+	_, err = c.GetPackageDocumentation(t.Context(), "rwx/missing")
+	require.ErrorIs(t, err, api.ErrNotFound)
+
+	base, err := c.GetDefaultBase(t.Context())
+	require.NoError(t, err)
+	require.Equal(t, "ubuntu:24.04", base.Image)
+}
+
+func TestServer_Fixtures(t *testing.T) {
+	t.Run("replays recorded responses before the server's state", func(t *testing.T) {
+		server, c := newClient(t)
+		require.NoError(t, server.UseFixtures(filepath.Join("testdata", "logs.json")))
+
+		whoami, err := c.Whoami(t.Context())
+		require.NoError(t, err)
+		require.Equal(t, "recorded-org", whoami.OrganizationSlug)
+
+		status, err := c.RunStatus(t.Context(), api.RunStatusConfig{RunID: "run-from-cloud"})
+		require.NoError(t, err)
+		require.False(t, status.Polling.Completed)
+
+		// The last recorded response is repeated once they've all been used
+		for range 2 {
+			status, err = c.RunStatus(t.Context(), api.RunStatusConfig{RunID: "run-from-cloud"})
+			require.NoError(t, err)
+			require.True(t, status.Polling.Completed)
+		}
+
+		request, err := c.GetLogDownloadRequestByTaskKey(t.Context(), "run-from-cloud", "test")
+		require.NoError(t, err)
+
+		logs, err := c.DownloadLogs(t.Context(), request)
+		require.NoError(t, err)
+		require.Equal(t, "recorded logs\n", string(logs))
+
+		// Requests without a recording fall back to the server's state
+		_, err = c.GetDefaultBase(t.Context())
+		require.NoError(t, err)
+	})
+
+	t.Run("records interactions with Cloud and redacts them", func(t *testing.T) {
+		upstream, _ := newClient(t)
+		runID := upstream.AddRun(fakecloud.Run{Tasks: []*fakecloud.Task{{Key: "test", Result: "succeeded", Logs: []byte("logs\n")}}})
+
+		path := filepath.Join(t.TempDir(), "fixture.json")
+		recorder, c := newClient(t)
+		require.NoError(t, recorder.RecordFixtures(path, upstream.Host(), fakecloud.DefaultAccessToken, upstream.Client().Transport))
+
+		request, err := c.GetLogDownloadRequestByTaskKey(t.Context(), runID, "test")
+		require.NoError(t, err)
+		require.Contains(t, request.URL, recorder.URL)
+
+		logs, err := c.DownloadLogs(t.Context(), request)
+		require.NoError(t, err)
+		require.Equal(t, "logs\n", string(logs))
+
+		contents, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		var fixture fakecloud.Fixture
+		require.NoError(t, json.Unmarshal(contents, &fixture))
+
+		// The client checks for a newer skill in the background
+		interactions := []fakecloud.Interaction{}
+		for _, interaction := range fixture.Interactions {
+			if interaction.Request.Path != "/api/skill/latest" {
+				interactions = append(interactions, interaction)
+			}
+		}
+		require.Len(t, interactions, 2)
+		require.Equal(t, "/mint/api/log_download?run_id="+runID+"&task_key=test", interactions[0].Request.Path)
+		require.JSONEq(t, `{
+			"url": "{{server}}/_upstream/0",
+			"token": "[REDACTED]",
+			"filename": "test.log",
+			"contents": "",
+			"run_id": "`+runID+`"
+		}`, string(interactions[0].Response.Body))
+		require.Equal(t, "/_upstream/0", interactions[1].Request.Path)
+		require.Equal(t, "logs\n", string(interactions[1].Response.Raw))
+
+		// The recording replays without Cloud
+		replay, replayClient := newClient(t)
+		require.NoError(t, replay.UseFixtures(path))
+
+		request, err = replayClient.GetLogDownloadRequestByTaskKey(t.Context(), runID, "test")
+		require.NoError(t, err)
+		logs, err = replayClient.DownloadLogs(t.Context(), request)
+		require.NoError(t, err)
+		require.Equal(t, "logs\n", string(logs))
+	})
+}
@@ -0,0 +1,180 @@
+package fakecloud
+
+import "github.com/rwx-cloud/rwx/internal/api"
+
+// State is everything the fake knows. Tests seed and inspect it with
+// Server.Update and Server.View; requests read and change it as Cloud would.
+type State struct {
+	Whoami              api.WhoamiResult
+	SkillVersion        string
+	Packages            api.PackageVersionsResult
+	Documentation       map[string]api.PackageDocumentationResult
+	DefaultBase         api.DefaultBaseResult
+	SandboxInitTemplate string
+	// PollBackoffMs is returned to clients polling something that hasn't
+	// completed; it's small so that tests which wait don't take long
+	PollBackoffMs int
+
+	Runs        map[string]*Run
+	Dispatches  map[string]*Dispatch
+	ImagePushes map[string]*ImagePush
+	Vaults      map[string]*Vault
+	// DebugConnections are keyed by the debug key, a run or task ID
+	DebugConnections map[string]Connection
+	// SandboxTokens maps tokens scoped to a single sandbox to its run ID
+	SandboxTokens map[string]string
+	// AuthCodes are device logins, keyed by the code in their token URL
+	AuthCodes map[string]*AuthCode
+	// RevokedTokens are rejected with 401 Unauthorized
+	RevokedTokens map[string]bool
+	// Unavailable is how many of the next requests are answered with 503
+	// Service Unavailable, asking the client to retry right away
+	Unavailable int
+
+	// runOrder is the order runs were added in, to find the latest one
+	runOrder []string
+}
+
+type Run struct {
+	ID             string
+	URL            string
+	Title          string
+	Branch         string
+	Repository     string
+	CommitSha      string
+	DefinitionPath string
+	CliState       string
+	Prompt         string
+	// Result is the run's result once it completes, such as "succeeded" or
+	// "failed". The run is in progress while it's empty.
+	Result string
+	// PendingPolls is how many status requests report the run as in progress
+	// before its Result is reported
+	PendingPolls int
+	Cancelled    bool
+	Tasks        []*Task
+	// Sandbox is the connection for a sandbox run; runs without one are not
+	// sandboxable
+	Sandbox *Connection
+	// Config is what the run was initiated with
+	Config api.InitiateRunConfig
+}
+
+type Task struct {
+	ID           string
+	Key          string
+	Result       string
+	PendingPolls int
+	Logs         []byte
+	Artifacts    []Artifact
+}
+
+type Artifact struct {
+	Key      string
+	Filename string
+	// Kind is "file" or "directory"; the contents of a directory are a tar
+	Kind     string
+	Contents []byte
+}
+
+type Connection struct {
+	Address        string
+	PublicHostKey  string
+	PrivateUserKey string
+}
+
+type Dispatch struct {
+	ID           string
+	Config       api.InitiateDispatchConfig
+	PendingPolls int
+	// Error fails the dispatch with the message
+	Error string
+	RunID string
+}
+
+type ImagePush struct {
+	ID           string
+	Config       api.StartImagePushConfig
+	PendingPolls int
+	// Status is the push's status once it completes; it succeeds by default
+	Status string
+}
+
+type Vault struct {
+	Name                  string
+	Unlocked              bool
+	RepositoryPermissions []api.CreateVaultRepoPermission
+	Secrets               map[string]string
+	Vars                  map[string]string
+	OidcTokens            []api.CreateVaultOidcTokenConfig
+}
+
+type AuthCode struct {
+	DeviceName string
+	// State is reported to the device polling its token URL: pending,
+	// authorized, consumed or expired
+	State string
+	Token string
+}
+
+func newState() *State {
+	email := "test@example.com"
+	return &State{
+		Whoami: api.WhoamiResult{
+			OrganizationSlug: "fake-org",
+			TokenKind:        "personal_access_token",
+			UserEmail:        &email,
+		},
+		SkillVersion: "1.0.0",
+		Packages: api.PackageVersionsResult{
+			Renames:     map[string]string{},
+			LatestMajor: map[string]string{},
+			LatestMinor: map[string]map[string]string{},
+			Packages:    map[string]api.ApiPackageInfo{},
+		},
+		Documentation:    map[string]api.PackageDocumentationResult{},
+		PollBackoffMs:    10,
+		Runs:             map[string]*Run{},
+		Dispatches:       map[string]*Dispatch{},
+		ImagePushes:      map[string]*ImagePush{},
+		Vaults:           map[string]*Vault{},
+		DebugConnections: map[string]Connection{},
+		SandboxTokens:    map[string]string{},
+		AuthCodes:        map[string]*AuthCode{},
+		RevokedTokens:    map[string]bool{},
+	}
+}
+
+// task finds a task of any run by its ID.
+func (s *State) task(id string) (*Run, *Task) {
+	for _, run := range s.Runs {
+		for _, task := range run.Tasks {
+			if task.ID == id {
+				return run, task
+			}
+		}
+	}
+	return nil, nil
+}
+
+// tasksByKey finds the tasks of a run with a key; more than one is an
+// ambiguous key.
+func (r *Run) tasksByKey(key string) []*Task {
+	var tasks []*Task
+	for _, task := range r.Tasks {
+		if task.Key == key {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// poll reports whether something with pending polls has completed, counting
+// this poll against them.
+func poll(pendingPolls *int, result string) bool {
+	if *pendingPolls > 0 {
+		*pendingPolls--
+		return false
+	}
+	return result != ""
+}
@@ -2,6 +2,7 @@ package git
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -16,7 +17,19 @@ type Client struct {
 
 const defaultRemote = "origin"
 
+// configuredRemote is the git_remote setting, which already accounts for
+// RWX_GIT_REMOTE.
+var configuredRemote string
+
+// SetRemote sets the remote used to find the base commit for every client.
+func SetRemote(remote string) {
+	configuredRemote = remote
+}
+
 func getRemote() string {
+	if configuredRemote != "" {
+		return configuredRemote
+	}
 	if remote := os.Getenv("RWX_GIT_REMOTE"); remote != "" {
 		return remote
 	}
@@ -421,6 +434,35 @@ func (c *Client) ApplyPatch(patch []byte) *exec.Cmd {
 	return cmd
 }
 
+// MergeFile performs a three-way merge of the changes between base and other
+// into current, rewriting current in place. Where both sides changed the same
+// lines, standard conflict markers labelled "local" and "sandbox" are written.
+// Returns the number of conflicts (0 for a clean merge).
+func (c *Client) MergeFile(current, base, other string) (int, error) {
+	cmd := exec.Command(c.Binary, "merge-file", "-L", "local", "-L", "base", "-L", "sandbox", current, base, other)
+	cmd.Dir = c.Dir
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err == nil {
+		return 0, nil
+	}
+
+	// git merge-file exits with the number of conflicts, or a negative
+	// status (reported as >127) on error.
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128 {
+		return exitErr.ExitCode(), nil
+	}
+
+	if msg := strings.TrimSpace(stderr.String()); msg != "" {
+		return -1, fmt.Errorf("unable to merge %q: %s", current, msg)
+	}
+	return -1, fmt.Errorf("unable to merge %q: %w", current, err)
+}
+
 // ApplyPatchReject returns an exec.Cmd that applies a patch with --reject,
 // which applies hunks that succeed and writes .rej files for hunks that fail.
 func (c *Client) ApplyPatchReject(patch []byte) *exec.Cmd {
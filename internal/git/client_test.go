@@ -556,3 +556,54 @@ func TestRepoNameFromOriginUrl(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeFile(t *testing.T) {
+	write := func(t *testing.T, dir, name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+		return path
+	}
+
+	t.Run("merges non-overlapping changes", func(t *testing.T) {
+		dir := t.TempDir()
+		current := write(t, dir, "current", "a\nb\nc\nlocal\n")
+		base := write(t, dir, "base", "a\nb\nc\nd\n")
+		other := write(t, dir, "other", "A\nb\nc\nd\n")
+
+		client := &git.Client{Binary: "git", Dir: dir}
+		conflicts, err := client.MergeFile(current, base, other)
+
+		require.NoError(t, err)
+		require.Equal(t, 0, conflicts)
+		merged, err := os.ReadFile(current)
+		require.NoError(t, err)
+		require.Equal(t, "A\nb\nc\nlocal\n", string(merged))
+	})
+
+	t.Run("returns the number of conflicts and writes markers", func(t *testing.T) {
+		dir := t.TempDir()
+		current := write(t, dir, "current", "local\nb\n")
+		base := write(t, dir, "base", "a\nb\n")
+		other := write(t, dir, "other", "sandbox\nb\n")
+
+		client := &git.Client{Binary: "git", Dir: dir}
+		conflicts, err := client.MergeFile(current, base, other)
+
+		require.NoError(t, err)
+		require.Equal(t, 1, conflicts)
+		merged, err := os.ReadFile(current)
+		require.NoError(t, err)
+		require.Equal(t, "<<<<<<< local\nlocal\n=======\nsandbox\n>>>>>>> sandbox\nb\n", string(merged))
+	})
+
+	t.Run("returns an error when a file is missing", func(t *testing.T) {
+		dir := t.TempDir()
+		current := write(t, dir, "current", "a\n")
+
+		client := &git.Client{Binary: "git", Dir: dir}
+		_, err := client.MergeFile(current, filepath.Join(dir, "missing"), filepath.Join(dir, "other"))
+
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,284 @@
+package httplog
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rwx-cloud/rwx/cmd/rwx/config"
+	"github.com/rwx-cloud/rwx/internal/errors"
+	"github.com/rwx-cloud/rwx/internal/retry"
+)
+
+// Recorder is an http.RoundTripper that records requests and responses to a
+// HAR file, which can be opened in browser developer tools or attached to a
+// support ticket. Credentials and secret values are redacted. Each request is
+// written to the file as it completes, just before the closing brackets, so
+// that the file is complete however the CLI exits.
+type Recorder struct {
+	Inner   http.RoundTripper
+	Path    string
+	mu      sync.Mutex
+	har     har
+	end     int64
+	entries int
+}
+
+// NewRecorder records to a new HAR file in dir, creating dir if needed.
+func NewRecorder(inner http.RoundTripper, dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Wrapf(err, "unable to create %q", dir)
+	}
+
+	name := fmt.Sprintf("rwx-%s-%d.har", time.Now().Format("20060102-150405"), os.Getpid())
+	return &Recorder{
+		Inner: inner,
+		Path:  filepath.Join(dir, name),
+		har: har{Log: harLog{
+			Version: "1.2",
+			Creator: harCreator{Name: "rwx-cli", Version: config.Version},
+			Entries: []harEntry{},
+		}},
+	}, nil
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	entry := harEntry{
+		StartedDateTime: time.Now().UTC().Format(time.RFC3339Nano),
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         RedactURL(req.URL),
+			HTTPVersion: "HTTP/1.1",
+			Headers:     harHeaders(req.Header),
+			QueryString: []harNameValue{},
+			Cookies:     []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Cache: struct{}{},
+	}
+	for name, values := range req.URL.Query() {
+		for _, value := range values {
+			if isSensitive(name) {
+				value = Redacted
+			}
+			entry.Request.QueryString = append(entry.Request.QueryString, harNameValue{Name: name, Value: value})
+		}
+	}
+	if n := retry.Attempt(req); n > 1 {
+		entry.Comment = fmt.Sprintf("attempt %d", n)
+	}
+
+	// The body is read to record it, so send a copy of the request with the
+	// body that was read, leaving the caller's request untouched
+	outReq := req
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to read the request body")
+		}
+		outReq = req.Clone(req.Context())
+		outReq.Body = io.NopCloser(bytes.NewReader(body))
+		entry.Request.BodySize = len(body)
+		entry.Request.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     string(RedactBody(body)),
+		}
+	}
+
+	start := time.Now()
+	resp, err := r.Inner.RoundTrip(outReq)
+	entry.Time = time.Since(start).Milliseconds()
+	entry.Timings = harTimings{Send: 0, Wait: entry.Time, Receive: 0}
+
+	entry.Response = harResponse{
+		HTTPVersion: "HTTP/1.1",
+		Headers:     []harNameValue{},
+		Cookies:     []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+
+	if err != nil {
+		entry.Response.Error = err.Error()
+		r.add(entry)
+		return resp, err
+	}
+
+	entry.Response.Status = resp.StatusCode
+	entry.Response.StatusText = http.StatusText(resp.StatusCode)
+	entry.Response.HTTPVersion = resp.Proto
+	entry.Response.Headers = harHeaders(resp.Header)
+
+	body, readErr := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if readErr != nil {
+		// Hand the error on to the caller once it has read what was received
+		entry.Response.Error = readErr.Error()
+		resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{readErr}))
+	} else {
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	entry.Response.BodySize = len(body)
+	entry.Response.Content = harContent{
+		Size:     len(body),
+		MimeType: resp.Header.Get("Content-Type"),
+		Text:     string(RedactBody(body)),
+	}
+
+	r.add(entry)
+	return resp, nil
+}
+
+// harTrailer closes the entries array and the objects around it.
+const harTrailer = "\n    ]\n  }\n}\n"
+
+func (r *Recorder) add(entry harEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Recording is best effort; a failure to write must not fail the request
+	encoded, err := json.MarshalIndent(entry, "      ", "  ")
+	if err != nil {
+		return
+	}
+
+	f, err := os.OpenFile(r.Path, os.O_WRONLY|os.O_CREATE, 0o600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	if r.entries == 0 {
+		header, err := json.MarshalIndent(r.har, "", "  ")
+		if err != nil {
+			return
+		}
+		// Cut the empty entries array open after its opening bracket
+		header = header[:bytes.LastIndex(header, []byte("[]"))+1]
+		if _, err := f.WriteAt(header, 0); err != nil {
+			return
+		}
+		r.end = int64(len(header))
+	}
+
+	var chunk bytes.Buffer
+	if r.entries > 0 {
+		chunk.WriteString(",")
+	}
+	chunk.WriteString("\n      ")
+	chunk.Write(encoded)
+	written := int64(chunk.Len())
+	chunk.WriteString(harTrailer)
+
+	// Overwrite the trailer with the entry followed by the trailer again
+	if _, err := f.WriteAt(chunk.Bytes(), r.end); err != nil {
+		return
+	}
+	r.end += written
+	r.entries++
+	_ = f.Truncate(r.end + int64(len(harTrailer)))
+}
+
+type errReader struct{ err error }
+
+func (e errReader) Read([]byte) (int, error) { return 0, e.err }
+
+func harHeaders(headers http.Header) []harNameValue {
+	redacted := RedactHeaders(headers)
+	names := make([]string, 0, len(redacted))
+	for name := range redacted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := []harNameValue{}
+	for _, name := range names {
+		for _, value := range redacted[name] {
+			result = append(result, harNameValue{Name: name, Value: value})
+		}
+	}
+	return result
+}
+
+// The HAR 1.2 format: http://www.softwareishard.com/blog/har-12-spec/
+type har struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            int64       `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	Cookies     []harNameValue `json:"cookies"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Cookies     []harNameValue `json:"cookies"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+	Error       string         `json:"_error,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harTimings struct {
+	Send    int64 `json:"send"`
+	Wait    int64 `json:"wait"`
+	Receive int64 `json:"receive"`
+}
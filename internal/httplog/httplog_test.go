@@ -0,0 +1,264 @@
+package httplog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rwx-cloud/rwx/internal/httplog"
+	"github.com/rwx-cloud/rwx/internal/retry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("Authorization", "Bearer secret-token")
+	headers.Set("User-Agent", "rwx-cli/1.0")
+	headers.Set("X-Access-Token", "secret-token")
+
+	redacted := httplog.RedactHeaders(headers)
+
+	require.Equal(t, httplog.Redacted, redacted.Get("Authorization"))
+	require.Equal(t, httplog.Redacted, redacted.Get("X-Access-Token"))
+	require.Equal(t, "rwx-cli/1.0", redacted.Get("User-Agent"))
+	require.Equal(t, "Bearer secret-token", headers.Get("Authorization"))
+}
+
+func TestRedactURL(t *testing.T) {
+	u, err := url.Parse("https://cloud.rwx.com/api/runs?token=abc&branch=main")
+	require.NoError(t, err)
+
+	require.Equal(t, "https://cloud.rwx.com/api/runs?branch=main&token=%5BREDACTED%5D", httplog.RedactURL(u))
+}
+
+func TestRedactBody(t *testing.T) {
+	t.Run("redacts sensitive JSON fields at any depth", func(t *testing.T) {
+		body := `{"vault_name":"default","secrets":[{"name":"API_KEY","secret":"hunter2"}],"token":"abc","token_kind":"personal_access_token","scoped_token":"def"}`
+
+		var redacted map[string]any
+		require.NoError(t, json.Unmarshal(httplog.RedactBody([]byte(body)), &redacted))
+
+		require.Equal(t, "default", redacted["vault_name"])
+		require.Equal(t, httplog.Redacted, redacted["secrets"].([]any)[0].(map[string]any)["secret"])
+		require.Equal(t, "API_KEY", redacted["secrets"].([]any)[0].(map[string]any)["name"])
+		require.Equal(t, httplog.Redacted, redacted["token"])
+		require.Equal(t, httplog.Redacted, redacted["scoped_token"])
+		require.Equal(t, "personal_access_token", redacted["token_kind"])
+	})
+
+	t.Run("redacts the signatures of presigned URLs", func(t *testing.T) {
+		body := `{"url":"https://rwx-artifacts.s3.amazonaws.com/org/artifacts/build.tar.gz?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Credential=AKIAEXAMPLE%2F20261018%2Fus-east-1%2Fs3%2Faws4_request&X-Amz-Date=20261018T120000Z&X-Amz-Expires=300&X-Amz-Security-Token=session-token&X-Amz-SignedHeaders=host&X-Amz-Signature=abc123signature","filename":"build.tar.gz","size_in_bytes":1024,"kind":"file","key":"build","homepage":"https://www.rwx.com/?ref=cli"}`
+
+		var redacted map[string]any
+		require.NoError(t, json.Unmarshal(httplog.RedactBody([]byte(body)), &redacted))
+
+		downloadURL, err := url.Parse(redacted["url"].(string))
+		require.NoError(t, err)
+		require.Equal(t, "rwx-artifacts.s3.amazonaws.com", downloadURL.Host)
+		require.Equal(t, "/org/artifacts/build.tar.gz", downloadURL.Path)
+		query := downloadURL.Query()
+		require.Equal(t, httplog.Redacted, query.Get("X-Amz-Signature"))
+		require.Equal(t, httplog.Redacted, query.Get("X-Amz-Credential"))
+		require.Equal(t, httplog.Redacted, query.Get("X-Amz-Security-Token"))
+		require.Equal(t, "300", query.Get("X-Amz-Expires"))
+		require.Equal(t, "build.tar.gz", redacted["filename"])
+		require.Equal(t, "https://www.rwx.com/?ref=cli", redacted["homepage"])
+		require.NotContains(t, string(httplog.RedactBody([]byte(body))), "AKIAEXAMPLE")
+	})
+
+	t.Run("leaves other bodies alone", func(t *testing.T) {
+		require.Equal(t, "not json", string(httplog.RedactBody([]byte("not json"))))
+	})
+}
+
+func newServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-123")
+		if r.URL.Path == "/api/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"not found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"token":"abc","organization_slug":"rwx"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLogger(t *testing.T) {
+	server := newServer(t)
+
+	var out bytes.Buffer
+	client := &http.Client{Transport: httplog.NewLogger(http.DefaultTransport, &out)}
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/api/auth/whoami", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret-token")
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	logged := out.String()
+	require.Contains(t, logged, "[http] --> GET "+server.URL+"/api/auth/whoami\n")
+	require.Contains(t, logged, "[http]     Authorization: [REDACTED]\n")
+	require.Contains(t, logged, "[http] <-- 200 OK GET /api/auth/whoami in ")
+	require.Contains(t, logged, "[http]     X-Request-Id: req-123\n")
+	require.NotContains(t, logged, "secret-token")
+}
+
+func TestLogger_RetryAttempts(t *testing.T) {
+	var out bytes.Buffer
+	calls := 0
+	logger := httplog.NewLogger(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		calls++
+		if calls == 1 {
+			return nil, io.EOF
+		}
+		return &http.Response{StatusCode: 200, Status: "200 OK", Header: http.Header{}, Body: http.NoBody}, nil
+	}), &out)
+	rt := &retry.RoundTripper{Inner: logger, Sleep: func(time.Duration) {}}
+
+	req, err := http.NewRequest(http.MethodGet, "https://cloud.rwx.com/api/runs/abc", nil)
+	require.NoError(t, err)
+	_, err = rt.RoundTrip(req)
+	require.NoError(t, err)
+
+	logged := out.String()
+	require.Contains(t, logged, "<-- GET /api/runs/abc failed after ")
+	require.Contains(t, logged, "--> GET https://cloud.rwx.com/api/runs/abc (attempt 2)\n")
+	require.Contains(t, logged, "<-- 200 OK GET /api/runs/abc in ")
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestRecorder(t *testing.T) {
+	server := newServer(t)
+	dir := t.TempDir()
+
+	recorder, err := httplog.NewRecorder(http.DefaultTransport, dir)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(recorder.Path, dir))
+	require.True(t, strings.HasSuffix(recorder.Path, ".har"))
+	client := &http.Client{Transport: recorder}
+
+	body := `{"vault_name":"default","secrets":[{"name":"API_KEY","secret":"hunter2"}]}`
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/api/vaults/secrets", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret-token")
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	responseBody, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.JSONEq(t, `{"token":"abc","organization_slug":"rwx"}`, string(responseBody), "the caller still reads the whole body")
+
+	resp, err = client.Get(server.URL + "/api/missing")
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	contents, err := os.ReadFile(recorder.Path)
+	require.NoError(t, err)
+	require.NotContains(t, string(contents), "secret-token")
+	require.NotContains(t, string(contents), "hunter2")
+
+	var har struct {
+		Log struct {
+			Version string `json:"version"`
+			Entries []struct {
+				Request struct {
+					Method   string `json:"method"`
+					URL      string `json:"url"`
+					PostData struct {
+						Text string `json:"text"`
+					} `json:"postData"`
+				} `json:"request"`
+				Response struct {
+					Status  int `json:"status"`
+					Content struct {
+						Text string `json:"text"`
+					} `json:"content"`
+				} `json:"response"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+	require.NoError(t, json.Unmarshal(contents, &har))
+	require.Equal(t, "1.2", har.Log.Version)
+	require.Len(t, har.Log.Entries, 2)
+
+	first := har.Log.Entries[0]
+	require.Equal(t, http.MethodPost, first.Request.Method)
+	require.Equal(t, server.URL+"/api/vaults/secrets", first.Request.URL)
+	require.Contains(t, first.Request.PostData.Text, `"secret":"[REDACTED]"`)
+	require.Equal(t, 200, first.Response.Status)
+	require.Contains(t, first.Response.Content.Text, `"token":"[REDACTED]"`)
+	require.Contains(t, first.Response.Content.Text, `"organization_slug":"rwx"`)
+
+	require.Equal(t, 404, har.Log.Entries[1].Response.Status)
+}
+
+func TestRecorder_CompleteAfterEveryRequest(t *testing.T) {
+	server := newServer(t)
+
+	recorder, err := httplog.NewRecorder(http.DefaultTransport, t.TempDir())
+	require.NoError(t, err)
+	client := &http.Client{Transport: recorder}
+
+	for i := 1; i <= 3; i++ {
+		resp, err := client.Get(server.URL + "/api/whoami")
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		contents, err := os.ReadFile(recorder.Path)
+		require.NoError(t, err)
+
+		var har struct {
+			Log struct {
+				Creator struct {
+					Name string `json:"name"`
+				} `json:"creator"`
+				Entries []json.RawMessage `json:"entries"`
+			} `json:"log"`
+		}
+		require.NoError(t, json.Unmarshal(contents, &har), "the HAR is valid after request %d", i)
+		require.Equal(t, "rwx-cli", har.Log.Creator.Name)
+		require.Len(t, har.Log.Entries, i)
+	}
+}
+
+func TestRecorder_LeavesRequestUntouched(t *testing.T) {
+	var received string
+	inner := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		received = string(body)
+		return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	})
+
+	recorder, err := httplog.NewRecorder(inner, t.TempDir())
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, "https://cloud.rwx.com/api/runs", strings.NewReader(`{"branch":"main"}`))
+	require.NoError(t, err)
+	body := req.Body
+
+	resp, err := recorder.RoundTrip(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	require.Equal(t, `{"branch":"main"}`, received)
+	require.True(t, body == req.Body, "the caller's request keeps its body")
+}
@@ -0,0 +1,70 @@
+package httplog
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rwx-cloud/rwx/internal/retry"
+)
+
+// Logger is an http.RoundTripper that writes a line for each request and
+// response, including retried attempts, with credentials redacted.
+type Logger struct {
+	Inner http.RoundTripper
+	Out   io.Writer
+	mu    sync.Mutex
+}
+
+func NewLogger(inner http.RoundTripper, out io.Writer) *Logger {
+	return &Logger{Inner: inner, Out: out}
+}
+
+func (l *Logger) RoundTrip(req *http.Request) (*http.Response, error) {
+	attempt := ""
+	if n := retry.Attempt(req); n > 1 {
+		attempt = fmt.Sprintf(" (attempt %d)", n)
+	}
+
+	l.printf("--> %s %s%s\n", req.Method, RedactURL(req.URL), attempt)
+	l.printHeaders(req.Header)
+
+	start := time.Now()
+	resp, err := l.Inner.RoundTrip(req)
+	duration := time.Since(start).Round(time.Millisecond)
+
+	if err != nil {
+		l.printf("<-- %s %s failed after %s%s: %s\n", req.Method, req.URL.Path, duration, attempt, err)
+		return resp, err
+	}
+
+	l.printf("<-- %s %s %s in %s%s\n", resp.Status, req.Method, req.URL.Path, duration, attempt)
+	if requestID := resp.Header.Get("X-Request-Id"); requestID != "" {
+		l.printf("    X-Request-Id: %s\n", requestID)
+	}
+	return resp, nil
+}
+
+func (l *Logger) printHeaders(headers http.Header) {
+	redacted := RedactHeaders(headers)
+	names := make([]string, 0, len(redacted))
+	for name := range redacted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range redacted[name] {
+			l.printf("    %s: %s\n", name, value)
+		}
+	}
+}
+
+func (l *Logger) printf(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fmt.Fprintf(l.Out, "[http] "+format, args...)
+}
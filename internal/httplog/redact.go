@@ -0,0 +1,123 @@
+// Package httplog traces and records the CLI's requests to RWX Cloud, with
+// credentials and secret values redacted, for debugging and bug reports.
+package httplog
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const Redacted = "[REDACTED]"
+
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+}
+
+// isSensitive reports whether a JSON field or query parameter holds a
+// credential or secret, such as `token`, `access_token` or `secret`, or signs
+// a presigned URL, such as `X-Amz-Signature` or `X-Amz-Credential`.
+func isSensitive(name string) bool {
+	name = strings.ReplaceAll(strings.ToLower(name), "-", "_")
+	switch name {
+	case "token", "secret", "password", "private_user_key", "private_key",
+		"signature", "sig", "policy", "key_pair_id":
+		return true
+	}
+	return strings.HasSuffix(name, "_token") ||
+		strings.HasSuffix(name, "_secret") ||
+		strings.HasSuffix(name, "_password") ||
+		strings.HasSuffix(name, "_signature") ||
+		strings.HasSuffix(name, "_credential")
+}
+
+// RedactHeaders returns a copy of headers with credentials redacted.
+func RedactHeaders(headers http.Header) http.Header {
+	redacted := headers.Clone()
+	for name := range redacted {
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] || isSensitive(name) {
+			redacted[name] = []string{Redacted}
+		}
+	}
+	return redacted
+}
+
+// RedactURL returns u as a string with sensitive query parameters redacted.
+func RedactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+
+	query := u.Query()
+	sensitive := false
+	for name := range query {
+		if isSensitive(name) {
+			query[name] = []string{Redacted}
+			sensitive = true
+		}
+	}
+	if !sensitive {
+		return u.String()
+	}
+
+	redacted := *u
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
+
+// RedactBody redacts sensitive fields anywhere in a JSON body. Other bodies
+// are returned unchanged.
+func RedactBody(body []byte) []byte {
+	var value any
+	if err := json.Unmarshal(body, &value); err != nil {
+		return body
+	}
+
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+func redactValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, inner := range v {
+			if isSensitive(key) && inner != nil {
+				v[key] = Redacted
+			} else {
+				v[key] = redactValue(inner)
+			}
+		}
+		return v
+	case []any:
+		for i, inner := range v {
+			v[i] = redactValue(inner)
+		}
+		return v
+	case string:
+		return redactURLString(v)
+	default:
+		return value
+	}
+}
+
+// redactURLString redacts the signature of a presigned URL in a JSON string,
+// such as the `url` of an artifact or log download. Other strings are
+// returned unchanged.
+func redactURLString(value string) string {
+	if !strings.HasPrefix(value, "https://") && !strings.HasPrefix(value, "http://") {
+		return value
+	}
+
+	u, err := url.Parse(value)
+	if err != nil || u.RawQuery == "" {
+		return value
+	}
+	return RedactURL(u)
+}
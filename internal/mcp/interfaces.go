@@ -1,9 +1,13 @@
 package mcp
 
-import "github.com/rwx-cloud/rwx/internal/api"
+import (
+	"context"
+
+	"github.com/rwx-cloud/rwx/internal/api"
+)
 
 type APIClient interface {
-	McpGetRunTestFailures(api.McpGetRunTestFailuresRequest) (*api.McpTextResult, error)
+	McpGetRunTestFailures(context.Context, api.McpGetRunTestFailuresRequest) (*api.McpTextResult, error)
 }
 
 var _ APIClient = api.Client{}
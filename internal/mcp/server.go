@@ -81,7 +81,7 @@ type GetRunFailedTestsInput struct {
 }
 
 func (s *Server) getRunTestFailures(ctx context.Context, request *mcp.CallToolRequest, input GetRunFailedTestsInput) (*mcp.CallToolResult, any, error) {
-	res, err := s.api.McpGetRunTestFailures(api.McpGetRunTestFailuresRequest{RunUrls: input.RunUrls})
+	res, err := s.api.McpGetRunTestFailures(ctx, api.McpGetRunTestFailuresRequest{RunUrls: input.RunUrls})
 	if err != nil {
 		return nil, nil, err
 	}
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/rwx-cloud/rwx/internal/api"
 	"github.com/rwx-cloud/rwx/internal/errors"
 )
@@ -15,6 +17,7 @@ type API struct {
 	MockAcquireToken                            func(tokenUrl string) (*api.AcquireTokenResult, error)
 	MockWhoami                                  func() (*api.WhoamiResult, error)
 	MockCreateDocsToken                         func() (*api.DocsTokenResult, error)
+	MockRevokeToken                             func() error
 	MockSetSecretsInVault                       func(api.SetSecretsInVaultConfig) (*api.SetSecretsInVaultResult, error)
 	MockCreateVault                             func(api.CreateVaultConfig) (*api.CreateVaultResult, error)
 	MockCreateVaultOidcToken                    func(api.CreateVaultOidcTokenConfig) (*api.CreateVaultOidcTokenResult, error)
@@ -47,7 +50,7 @@ type API struct {
 	MockCancelRun                               func(runID, scopedToken string) error
 }
 
-func (c *API) GetSkillLatestVersion() (string, error) {
+func (c *API) GetSkillLatestVersion(_ context.Context) (string, error) {
 	if c.MockGetSkillLatestVersion != nil {
 		return c.MockGetSkillLatestVersion()
 	}
@@ -55,7 +58,7 @@ func (c *API) GetSkillLatestVersion() (string, error) {
 	return "", errors.New("MockGetSkillLatestVersion was not configured")
 }
 
-func (c *API) InitiateRun(cfg api.InitiateRunConfig) (*api.InitiateRunResult, error) {
+func (c *API) InitiateRun(_ context.Context, cfg api.InitiateRunConfig) (*api.InitiateRunResult, error) {
 	if c.MockInitiateRun != nil {
 		return c.MockInitiateRun(cfg)
 	}
@@ -63,7 +66,7 @@ func (c *API) InitiateRun(cfg api.InitiateRunConfig) (*api.InitiateRunResult, er
 	return nil, errors.New("MockInitiateRun was not configured")
 }
 
-func (c *API) GetDebugConnectionInfo(runID string) (api.DebugConnectionInfo, error) {
+func (c *API) GetDebugConnectionInfo(_ context.Context, runID string) (api.DebugConnectionInfo, error) {
 	if c.MockGetDebugConnectionInfo != nil {
 		return c.MockGetDebugConnectionInfo(runID)
 	}
@@ -71,7 +74,7 @@ func (c *API) GetDebugConnectionInfo(runID string) (api.DebugConnectionInfo, err
 	return api.DebugConnectionInfo{}, errors.New("MockGetDebugConnectionInfo was not configured")
 }
 
-func (c *API) GetSandboxConnectionInfo(runID, scopedToken string) (api.SandboxConnectionInfo, error) {
+func (c *API) GetSandboxConnectionInfo(_ context.Context, runID, scopedToken string) (api.SandboxConnectionInfo, error) {
 	if c.MockGetSandboxConnectionInfo != nil {
 		return c.MockGetSandboxConnectionInfo(runID, scopedToken)
 	}
@@ -79,7 +82,7 @@ func (c *API) GetSandboxConnectionInfo(runID, scopedToken string) (api.SandboxCo
 	return api.SandboxConnectionInfo{}, errors.New("MockGetSandboxConnectionInfo was not configured")
 }
 
-func (c *API) CreateSandboxToken(cfg api.CreateSandboxTokenConfig) (*api.CreateSandboxTokenResult, error) {
+func (c *API) CreateSandboxToken(_ context.Context, cfg api.CreateSandboxTokenConfig) (*api.CreateSandboxTokenResult, error) {
 	if c.MockCreateSandboxToken != nil {
 		return c.MockCreateSandboxToken(cfg)
 	}
@@ -87,7 +90,7 @@ func (c *API) CreateSandboxToken(cfg api.CreateSandboxTokenConfig) (*api.CreateS
 	return nil, errors.New("MockCreateSandboxToken was not configured")
 }
 
-func (c *API) ObtainAuthCode(cfg api.ObtainAuthCodeConfig) (*api.ObtainAuthCodeResult, error) {
+func (c *API) ObtainAuthCode(_ context.Context, cfg api.ObtainAuthCodeConfig) (*api.ObtainAuthCodeResult, error) {
 	if c.MockObtainAuthCode != nil {
 		return c.MockObtainAuthCode(cfg)
 	}
@@ -95,7 +98,7 @@ func (c *API) ObtainAuthCode(cfg api.ObtainAuthCodeConfig) (*api.ObtainAuthCodeR
 	return nil, errors.New("MockObtainAuthCode was not configured")
 }
 
-func (c *API) AcquireToken(tokenUrl string) (*api.AcquireTokenResult, error) {
+func (c *API) AcquireToken(_ context.Context, tokenUrl string) (*api.AcquireTokenResult, error) {
 	if c.MockAcquireToken != nil {
 		return c.MockAcquireToken(tokenUrl)
 	}
@@ -103,7 +106,7 @@ func (c *API) AcquireToken(tokenUrl string) (*api.AcquireTokenResult, error) {
 	return nil, errors.New("MockAcquireToken was not configured")
 }
 
-func (c *API) Whoami() (*api.WhoamiResult, error) {
+func (c *API) Whoami(_ context.Context) (*api.WhoamiResult, error) {
 	if c.MockWhoami != nil {
 		return c.MockWhoami()
 	}
@@ -111,7 +114,15 @@ func (c *API) Whoami() (*api.WhoamiResult, error) {
 	return nil, errors.New("MockWhoami was not configured")
 }
 
-func (c *API) CreateDocsToken() (*api.DocsTokenResult, error) {
+func (c *API) RevokeToken(_ context.Context) error {
+	if c.MockRevokeToken != nil {
+		return c.MockRevokeToken()
+	}
+
+	return errors.New("MockRevokeToken was not configured")
+}
+
+func (c *API) CreateDocsToken(_ context.Context) (*api.DocsTokenResult, error) {
 	if c.MockCreateDocsToken != nil {
 		return c.MockCreateDocsToken()
 	}
@@ -119,7 +130,7 @@ func (c *API) CreateDocsToken() (*api.DocsTokenResult, error) {
 	return nil, errors.New("MockCreateDocsToken was not configured")
 }
 
-func (c *API) SetSecretsInVault(cfg api.SetSecretsInVaultConfig) (*api.SetSecretsInVaultResult, error) {
+func (c *API) SetSecretsInVault(_ context.Context, cfg api.SetSecretsInVaultConfig) (*api.SetSecretsInVaultResult, error) {
 	if c.MockSetSecretsInVault != nil {
 		return c.MockSetSecretsInVault(cfg)
 	}
@@ -127,7 +138,7 @@ func (c *API) SetSecretsInVault(cfg api.SetSecretsInVaultConfig) (*api.SetSecret
 	return nil, errors.New("MockSetSecretsInVault was not configured")
 }
 
-func (c *API) CreateVault(cfg api.CreateVaultConfig) (*api.CreateVaultResult, error) {
+func (c *API) CreateVault(_ context.Context, cfg api.CreateVaultConfig) (*api.CreateVaultResult, error) {
 	if c.MockCreateVault != nil {
 		return c.MockCreateVault(cfg)
 	}
@@ -135,7 +146,7 @@ func (c *API) CreateVault(cfg api.CreateVaultConfig) (*api.CreateVaultResult, er
 	return nil, errors.New("MockCreateVault was not configured")
 }
 
-func (c *API) CreateVaultOidcToken(cfg api.CreateVaultOidcTokenConfig) (*api.CreateVaultOidcTokenResult, error) {
+func (c *API) CreateVaultOidcToken(_ context.Context, cfg api.CreateVaultOidcTokenConfig) (*api.CreateVaultOidcTokenResult, error) {
 	if c.MockCreateVaultOidcToken != nil {
 		return c.MockCreateVaultOidcToken(cfg)
 	}
@@ -143,7 +154,7 @@ func (c *API) CreateVaultOidcToken(cfg api.CreateVaultOidcTokenConfig) (*api.Cre
 	return nil, errors.New("MockCreateVaultOidcToken was not configured")
 }
 
-func (c *API) DeleteSecret(cfg api.DeleteSecretConfig) (*api.DeleteSecretResult, error) {
+func (c *API) DeleteSecret(_ context.Context, cfg api.DeleteSecretConfig) (*api.DeleteSecretResult, error) {
 	if c.MockDeleteSecret != nil {
 		return c.MockDeleteSecret(cfg)
 	}
@@ -151,7 +162,7 @@ func (c *API) DeleteSecret(cfg api.DeleteSecretConfig) (*api.DeleteSecretResult,
 	return nil, errors.New("MockDeleteSecret was not configured")
 }
 
-func (c *API) SetVar(cfg api.SetVarConfig) (*api.SetVarResult, error) {
+func (c *API) SetVar(_ context.Context, cfg api.SetVarConfig) (*api.SetVarResult, error) {
 	if c.MockSetVar != nil {
 		return c.MockSetVar(cfg)
 	}
@@ -159,7 +170,7 @@ func (c *API) SetVar(cfg api.SetVarConfig) (*api.SetVarResult, error) {
 	return nil, errors.New("MockSetVar was not configured")
 }
 
-func (c *API) ShowVar(cfg api.ShowVarConfig) (*api.ShowVarResult, error) {
+func (c *API) ShowVar(_ context.Context, cfg api.ShowVarConfig) (*api.ShowVarResult, error) {
 	if c.MockShowVar != nil {
 		return c.MockShowVar(cfg)
 	}
@@ -167,7 +178,7 @@ func (c *API) ShowVar(cfg api.ShowVarConfig) (*api.ShowVarResult, error) {
 	return nil, errors.New("MockShowVar was not configured")
 }
 
-func (c *API) DeleteVar(cfg api.DeleteVarConfig) (*api.DeleteVarResult, error) {
+func (c *API) DeleteVar(_ context.Context, cfg api.DeleteVarConfig) (*api.DeleteVarResult, error) {
 	if c.MockDeleteVar != nil {
 		return c.MockDeleteVar(cfg)
 	}
@@ -175,7 +186,7 @@ func (c *API) DeleteVar(cfg api.DeleteVarConfig) (*api.DeleteVarResult, error) {
 	return nil, errors.New("MockDeleteVar was not configured")
 }
 
-func (c *API) GetPackageVersions() (*api.PackageVersionsResult, error) {
+func (c *API) GetPackageVersions(_ context.Context) (*api.PackageVersionsResult, error) {
 	if c.MockGetPackageVersions != nil {
 		return c.MockGetPackageVersions()
 	}
@@ -183,7 +194,7 @@ func (c *API) GetPackageVersions() (*api.PackageVersionsResult, error) {
 	return nil, errors.New("MockGetPackageVersions was not configured")
 }
 
-func (c *API) GetPackageDocumentation(packageName string) (*api.PackageDocumentationResult, error) {
+func (c *API) GetPackageDocumentation(_ context.Context, packageName string) (*api.PackageDocumentationResult, error) {
 	if c.MockGetPackageDocumentation != nil {
 		return c.MockGetPackageDocumentation(packageName)
 	}
@@ -191,7 +202,7 @@ func (c *API) GetPackageDocumentation(packageName string) (*api.PackageDocumenta
 	return nil, errors.New("MockGetPackageDocumentation was not configured")
 }
 
-func (c *API) InitiateDispatch(cfg api.InitiateDispatchConfig) (*api.InitiateDispatchResult, error) {
+func (c *API) InitiateDispatch(_ context.Context, cfg api.InitiateDispatchConfig) (*api.InitiateDispatchResult, error) {
 	if c.MockInitiateDispatch != nil {
 		return c.MockInitiateDispatch(cfg)
 	}
@@ -199,7 +210,7 @@ func (c *API) InitiateDispatch(cfg api.InitiateDispatchConfig) (*api.InitiateDis
 	return nil, errors.New("MockInitiateDispatch was not configured")
 }
 
-func (c *API) GetDispatch(cfg api.GetDispatchConfig) (*api.GetDispatchResult, error) {
+func (c *API) GetDispatch(_ context.Context, cfg api.GetDispatchConfig) (*api.GetDispatchResult, error) {
 	if c.MockGetDispatch != nil {
 		return c.MockGetDispatch(cfg)
 	}
@@ -207,7 +218,7 @@ func (c *API) GetDispatch(cfg api.GetDispatchConfig) (*api.GetDispatchResult, er
 	return nil, errors.New("MockGetDispatch was not configured")
 }
 
-func (c *API) GetDefaultBase() (api.DefaultBaseResult, error) {
+func (c *API) GetDefaultBase(_ context.Context) (api.DefaultBaseResult, error) {
 	if c.MockGetDefaultBase != nil {
 		return c.MockGetDefaultBase()
 	}
@@ -215,7 +226,7 @@ func (c *API) GetDefaultBase() (api.DefaultBaseResult, error) {
 	return api.DefaultBaseResult{}, errors.New("MockGetDefaultBase was not configured")
 }
 
-func (c *API) McpGetRunTestFailures(cfg api.McpGetRunTestFailuresRequest) (*api.McpTextResult, error) {
+func (c *API) McpGetRunTestFailures(_ context.Context, cfg api.McpGetRunTestFailuresRequest) (*api.McpTextResult, error) {
 	if c.MockMcpGetRunTestFailures != nil {
 		return c.MockMcpGetRunTestFailures(cfg)
 	}
@@ -223,7 +234,7 @@ func (c *API) McpGetRunTestFailures(cfg api.McpGetRunTestFailuresRequest) (*api.
 	return nil, errors.New("MockMcpGetRunTestFailures was not configured")
 }
 
-func (c *API) StartImagePush(cfg api.StartImagePushConfig) (api.StartImagePushResult, error) {
+func (c *API) StartImagePush(_ context.Context, cfg api.StartImagePushConfig) (api.StartImagePushResult, error) {
 	if c.MockStartImagePush != nil {
 		return c.MockStartImagePush(cfg)
 	}
@@ -231,7 +242,7 @@ func (c *API) StartImagePush(cfg api.StartImagePushConfig) (api.StartImagePushRe
 	return api.StartImagePushResult{}, errors.New("MockStartImagePush was not configured")
 }
 
-func (c *API) ImagePushStatus(pushID string) (api.ImagePushStatusResult, error) {
+func (c *API) ImagePushStatus(_ context.Context, pushID string) (api.ImagePushStatusResult, error) {
 	if c.MockImagePushStatus != nil {
 		return c.MockImagePushStatus(pushID)
 	}
@@ -239,7 +250,7 @@ func (c *API) ImagePushStatus(pushID string) (api.ImagePushStatusResult, error)
 	return api.ImagePushStatusResult{}, errors.New("MockImagePushStatus was not configured")
 }
 
-func (c *API) TaskKeyStatus(cfg api.TaskKeyStatusConfig) (api.TaskStatusResult, error) {
+func (c *API) TaskKeyStatus(_ context.Context, cfg api.TaskKeyStatusConfig) (api.TaskStatusResult, error) {
 	if c.MockTaskKeyStatus != nil {
 		return c.MockTaskKeyStatus(cfg)
 	}
@@ -247,7 +258,7 @@ func (c *API) TaskKeyStatus(cfg api.TaskKeyStatusConfig) (api.TaskStatusResult,
 	return api.TaskStatusResult{}, errors.New("MockTaskKeyStatus was not configured")
 }
 
-func (c *API) TaskIDStatus(cfg api.TaskIDStatusConfig) (api.TaskStatusResult, error) {
+func (c *API) TaskIDStatus(_ context.Context, cfg api.TaskIDStatusConfig) (api.TaskStatusResult, error) {
 	if c.MockTaskIDStatus != nil {
 		return c.MockTaskIDStatus(cfg)
 	}
@@ -255,7 +266,7 @@ func (c *API) TaskIDStatus(cfg api.TaskIDStatusConfig) (api.TaskStatusResult, er
 	return api.TaskStatusResult{}, errors.New("MockTaskIDStatus was not configured")
 }
 
-func (c *API) RunStatus(cfg api.RunStatusConfig) (api.RunStatusResult, error) {
+func (c *API) RunStatus(_ context.Context, cfg api.RunStatusConfig) (api.RunStatusResult, error) {
 	if c.MockRunStatus != nil {
 		return c.MockRunStatus(cfg)
 	}
@@ -263,7 +274,7 @@ func (c *API) RunStatus(cfg api.RunStatusConfig) (api.RunStatusResult, error) {
 	return api.RunStatusResult{}, errors.New("MockRunStatus was not configured")
 }
 
-func (c *API) GetLogDownloadRequest(taskId string) (api.LogDownloadRequestResult, error) {
+func (c *API) GetLogDownloadRequest(_ context.Context, taskId string) (api.LogDownloadRequestResult, error) {
 	if c.MockGetLogDownloadRequest != nil {
 		return c.MockGetLogDownloadRequest(taskId)
 	}
@@ -271,14 +282,14 @@ func (c *API) GetLogDownloadRequest(taskId string) (api.LogDownloadRequestResult
 	return api.LogDownloadRequestResult{}, errors.New("MockGetLogDownloadRequest was not configured")
 }
 
-func (c *API) GetLogDownloadRequestByTaskKey(runID, taskKey string) (api.LogDownloadRequestResult, error) {
+func (c *API) GetLogDownloadRequestByTaskKey(_ context.Context, runID, taskKey string) (api.LogDownloadRequestResult, error) {
 	if c.MockGetLogDownloadRequestByTaskKey != nil {
 		return c.MockGetLogDownloadRequestByTaskKey(runID, taskKey)
 	}
 	return api.LogDownloadRequestResult{}, errors.New("MockGetLogDownloadRequestByTaskKey was not configured")
 }
 
-func (c *API) DownloadLogs(request api.LogDownloadRequestResult, maxRetryDurationSeconds ...int) ([]byte, error) {
+func (c *API) DownloadLogs(_ context.Context, request api.LogDownloadRequestResult, maxRetryDurationSeconds ...int) ([]byte, error) {
 	if c.MockDownloadLogs != nil {
 		return c.MockDownloadLogs(request)
 	}
@@ -286,7 +297,7 @@ func (c *API) DownloadLogs(request api.LogDownloadRequestResult, maxRetryDuratio
 	return nil, errors.New("MockDownloadLogs was not configured")
 }
 
-func (c *API) GetAllArtifactDownloadRequests(taskId string) ([]api.ArtifactDownloadRequestResult, error) {
+func (c *API) GetAllArtifactDownloadRequests(_ context.Context, taskId string) ([]api.ArtifactDownloadRequestResult, error) {
 	if c.MockGetAllArtifactDownloadRequests != nil {
 		return c.MockGetAllArtifactDownloadRequests(taskId)
 	}
@@ -294,14 +305,14 @@ func (c *API) GetAllArtifactDownloadRequests(taskId string) ([]api.ArtifactDownl
 	return nil, errors.New("MockGetAllArtifactDownloadRequests was not configured")
 }
 
-func (c *API) GetAllArtifactDownloadRequestsByTaskKey(runID, taskKey string) ([]api.ArtifactDownloadRequestResult, error) {
+func (c *API) GetAllArtifactDownloadRequestsByTaskKey(_ context.Context, runID, taskKey string) ([]api.ArtifactDownloadRequestResult, error) {
 	if c.MockGetAllArtifactDownloadRequestsByTaskKey != nil {
 		return c.MockGetAllArtifactDownloadRequestsByTaskKey(runID, taskKey)
 	}
 	return nil, errors.New("MockGetAllArtifactDownloadRequestsByTaskKey was not configured")
 }
 
-func (c *API) GetArtifactDownloadRequest(taskId, artifactKey string) (api.ArtifactDownloadRequestResult, error) {
+func (c *API) GetArtifactDownloadRequest(_ context.Context, taskId, artifactKey string) (api.ArtifactDownloadRequestResult, error) {
 	if c.MockGetArtifactDownloadRequest != nil {
 		return c.MockGetArtifactDownloadRequest(taskId, artifactKey)
 	}
@@ -309,14 +320,14 @@ func (c *API) GetArtifactDownloadRequest(taskId, artifactKey string) (api.Artifa
 	return api.ArtifactDownloadRequestResult{}, errors.New("MockGetArtifactDownloadRequest was not configured")
 }
 
-func (c *API) GetArtifactDownloadRequestByTaskKey(runID, taskKey, artifactKey string) (api.ArtifactDownloadRequestResult, error) {
+func (c *API) GetArtifactDownloadRequestByTaskKey(_ context.Context, runID, taskKey, artifactKey string) (api.ArtifactDownloadRequestResult, error) {
 	if c.MockGetArtifactDownloadRequestByTaskKey != nil {
 		return c.MockGetArtifactDownloadRequestByTaskKey(runID, taskKey, artifactKey)
 	}
 	return api.ArtifactDownloadRequestResult{}, errors.New("MockGetArtifactDownloadRequestByTaskKey was not configured")
 }
 
-func (c *API) DownloadArtifact(request api.ArtifactDownloadRequestResult) ([]byte, error) {
+func (c *API) DownloadArtifact(_ context.Context, request api.ArtifactDownloadRequestResult) ([]byte, error) {
 	if c.MockDownloadArtifact != nil {
 		return c.MockDownloadArtifact(request)
 	}
@@ -324,7 +335,7 @@ func (c *API) DownloadArtifact(request api.ArtifactDownloadRequestResult) ([]byt
 	return nil, errors.New("MockDownloadArtifact was not configured")
 }
 
-func (c *API) GetRunPrompt(runID string) (string, error) {
+func (c *API) GetRunPrompt(_ context.Context, runID string) (string, error) {
 	if c.MockGetRunPrompt != nil {
 		return c.MockGetRunPrompt(runID)
 	}
@@ -332,7 +343,7 @@ func (c *API) GetRunPrompt(runID string) (string, error) {
 	return "", errors.New("MockGetRunPrompt was not configured")
 }
 
-func (c *API) ListSandboxRuns() (*api.ListSandboxRunsResult, error) {
+func (c *API) ListSandboxRuns(_ context.Context) (*api.ListSandboxRunsResult, error) {
 	if c.MockListSandboxRuns != nil {
 		return c.MockListSandboxRuns()
 	}
@@ -340,7 +351,7 @@ func (c *API) ListSandboxRuns() (*api.ListSandboxRunsResult, error) {
 	return nil, errors.New("MockListSandboxRuns was not configured")
 }
 
-func (c *API) CancelRun(runID, scopedToken string) error {
+func (c *API) CancelRun(_ context.Context, runID, scopedToken string) error {
 	if c.MockCancelRun != nil {
 		return c.MockCancelRun(runID, scopedToken)
 	}
@@ -348,7 +359,7 @@ func (c *API) CancelRun(runID, scopedToken string) error {
 	return errors.New("MockCancelRun was not configured")
 }
 
-func (c *API) GetSandboxInitTemplate() (api.SandboxInitTemplateResult, error) {
+func (c *API) GetSandboxInitTemplate(_ context.Context) (api.SandboxInitTemplateResult, error) {
 	if c.MockGetSandboxInitTemplate != nil {
 		return c.MockGetSandboxInitTemplate()
 	}
@@ -5,6 +5,7 @@ import (
 	"os/exec"
 	"path/filepath"
 
+	"github.com/rwx-cloud/rwx/internal/errors"
 	"github.com/rwx-cloud/rwx/internal/git"
 )
 
@@ -19,6 +20,7 @@ type Git struct {
 	MockGeneratePatch          func(pathspec []string) ([]byte, *git.LFSChangedFilesMetadata, error)
 	MockApplyPatch             func(patch []byte) *exec.Cmd
 	MockApplyPatchReject       func(patch []byte) *exec.Cmd
+	MockMergeFile              func(current, base, other string) (int, error)
 	MockIsInstalled            bool
 	MockIsInsideWorkTree       bool
 }
@@ -87,6 +89,13 @@ func (c *Git) ApplyPatchReject(patch []byte) *exec.Cmd {
 	return exec.Command("true")
 }
 
+func (c *Git) MergeFile(current, base, other string) (int, error) {
+	if c.MockMergeFile != nil {
+		return c.MockMergeFile(current, base, other)
+	}
+	return -1, errors.New("MockMergeFile was not configured")
+}
+
 func (c *Git) IsInstalled() bool {
 	return c.MockIsInstalled
 }
@@ -4,14 +4,16 @@ import (
 	"io"
 
 	"github.com/rwx-cloud/rwx/internal/errors"
+	internalssh "github.com/rwx-cloud/rwx/internal/ssh"
 
 	"golang.org/x/crypto/ssh"
 )
 
 type SSH struct {
 	MockConnect                                  func(addr string, cfg ssh.ClientConfig) error
-	MockInteractiveSession                       func() error
+	MockInteractiveSession                       func(recorder internalssh.TerminalRecorder) error
 	MockExecuteCommand                           func(command string) (int, error)
+	MockExecuteCommandWithSignals                func(command string, signals <-chan ssh.Signal) (int, error)
 	MockExecuteCommandWithStdin                  func(command string, stdin io.Reader) (int, error)
 	MockExecuteCommandWithOutput                 func(command string) (int, string, error)
 	MockExecuteCommandWithStdinAndCombinedOutput func(command string, stdin io.Reader) (int, string, error)
@@ -29,9 +31,9 @@ func (s *SSH) Connect(addr string, cfg ssh.ClientConfig) error {
 	return errors.New("MockConnect was not configured")
 }
 
-func (s *SSH) InteractiveSession() error {
+func (s *SSH) InteractiveSession(recorder internalssh.TerminalRecorder) error {
 	if s.MockInteractiveSession != nil {
-		return s.MockInteractiveSession()
+		return s.MockInteractiveSession(recorder)
 	}
 
 	return errors.New("MockInteractiveSession was not configured")
@@ -45,6 +47,20 @@ func (s *SSH) ExecuteCommand(command string) (int, error) {
 	return -1, errors.New("MockExecuteCommand was not configured")
 }
 
+// ExecuteCommandWithSignals falls back to MockExecuteCommand when no
+// signal-aware mock is configured, since most tests don't care about signals.
+func (s *SSH) ExecuteCommandWithSignals(command string, signals <-chan ssh.Signal) (int, error) {
+	if s.MockExecuteCommandWithSignals != nil {
+		return s.MockExecuteCommandWithSignals(command, signals)
+	}
+
+	if s.MockExecuteCommand != nil {
+		return s.MockExecuteCommand(command)
+	}
+
+	return -1, errors.New("MockExecuteCommandWithSignals was not configured")
+}
+
 func (s *SSH) ExecuteCommandWithStdin(command string, stdin io.Reader) (int, error) {
 	if s.MockExecuteCommandWithStdin != nil {
 		return s.MockExecuteCommandWithStdin(command, stdin)
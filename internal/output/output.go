@@ -0,0 +1,255 @@
+// Package output renders the results of commands in the format chosen with
+// the --output, --template and --jq flags.
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/goccy/go-yaml"
+	"github.com/itchyny/gojq"
+	"github.com/rwx-cloud/rwx/internal/errors"
+)
+
+type Format string
+
+const (
+	FormatText   Format = "text"
+	FormatJSON   Format = "json"
+	FormatYAML   Format = "yaml"
+	FormatNDJSON Format = "ndjson"
+)
+
+// Options are how a command's result is written. The zero value writes JSON,
+// for services that are used without any output flags.
+type Options struct {
+	Format Format
+	// Template is a Go template executed with the result
+	Template string
+	// JQ is a jq expression that selects from the result's JSON
+	JQ string
+}
+
+func (o Options) Validate() error {
+	switch o.Format {
+	case "", FormatText, FormatJSON, FormatYAML, FormatNDJSON:
+	default:
+		return errors.Errorf("unknown output format %q, expected one of text, json, yaml or ndjson", o.Format)
+	}
+
+	if o.Template != "" && o.JQ != "" {
+		return errors.New("--template and --jq can't be used together")
+	}
+
+	if o.Template != "" {
+		if _, err := o.parseTemplate(); err != nil {
+			return err
+		}
+	}
+
+	if o.JQ != "" {
+		if _, err := o.parseJQ(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Structured reports whether commands should write their result through
+// Write rather than printing text for people.
+func (o Options) Structured() bool {
+	return (o.Format != "" && o.Format != FormatText) || o.Template != "" || o.JQ != ""
+}
+
+// Write renders value to w. A template is executed with value itself, so it
+// refers to Go field names, while jq expressions, YAML and JSON use the JSON
+// field names.
+func (o Options) Write(w io.Writer, value any) error {
+	if o.Template != "" {
+		return o.writeTemplate(w, value)
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrap(err, "unable to encode the output as JSON")
+	}
+
+	if o.JQ != "" {
+		return o.writeJQ(w, encoded)
+	}
+
+	switch o.Format {
+	case FormatYAML:
+		return writeYAML(w, encoded)
+	case FormatNDJSON:
+		return writeNDJSON(w, encoded)
+	default:
+		_, err = fmt.Fprintln(w, string(encoded))
+		return err
+	}
+}
+
+func (o Options) parseTemplate() (*template.Template, error) {
+	tmpl, err := template.New("output").Funcs(template.FuncMap{
+		"json": func(value any) (string, error) {
+			encoded, err := json.Marshal(value)
+			return string(encoded), err
+		},
+		"join": func(sep string, values []string) string {
+			return strings.Join(values, sep)
+		},
+	}).Parse(o.Template)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse --template")
+	}
+	return tmpl, nil
+}
+
+func (o Options) writeTemplate(w io.Writer, value any) error {
+	tmpl, err := o.parseTemplate()
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, value); err != nil {
+		return errors.Wrap(err, "unable to execute --template")
+	}
+
+	if buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteString("\n")
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func (o Options) parseJQ() (*gojq.Code, error) {
+	query, err := gojq.Parse(o.JQ)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to parse --jq")
+	}
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to compile --jq")
+	}
+	return code, nil
+}
+
+// writeJQ writes each value the expression produces; strings are written
+// as-is so that they can be used directly in scripts.
+func (o Options) writeJQ(w io.Writer, encoded []byte) error {
+	code, err := o.parseJQ()
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return errors.Wrap(err, "unable to decode the output")
+	}
+
+	iter := code.Run(normalizeNumbers(value))
+	for {
+		result, ok := iter.Next()
+		if !ok {
+			return nil
+		}
+		if err, ok := result.(error); ok {
+			var haltErr *gojq.HaltError
+			if errors.As(err, &haltErr) && haltErr.Value() == nil {
+				return nil
+			}
+			return errors.Wrap(err, "unable to evaluate --jq")
+		}
+
+		if text, ok := result.(string); ok && o.Format != FormatYAML {
+			if _, err := fmt.Fprintln(w, text); err != nil {
+				return err
+			}
+			continue
+		}
+
+		encodedResult, err := json.Marshal(result)
+		if err != nil {
+			return errors.Wrap(err, "unable to encode the --jq result")
+		}
+		if o.Format == FormatYAML {
+			err = writeYAML(w, encodedResult)
+		} else {
+			_, err = fmt.Fprintln(w, string(encodedResult))
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// normalizeNumbers turns json.Numbers into ints where possible and float64s
+// otherwise, which are the number types gojq works with.
+func normalizeNumbers(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, inner := range v {
+			v[key] = normalizeNumbers(inner)
+		}
+		return v
+	case []any:
+		for i, inner := range v {
+			v[i] = normalizeNumbers(inner)
+		}
+		return v
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return int(i)
+		}
+		f, _ := v.Float64()
+		return f
+	default:
+		return value
+	}
+}
+
+// writeYAML converts JSON to YAML, keeping the order of fields.
+func writeYAML(w io.Writer, encoded []byte) error {
+	converted, err := yaml.JSONToYAML(encoded)
+	if err != nil {
+		return errors.Wrap(err, "unable to encode the output as YAML")
+	}
+	_, err = w.Write(converted)
+	return err
+}
+
+// writeNDJSON writes each item of a list on its own line. A result that wraps
+// a single list, such as {"artifacts": [...]}, writes the items of that list.
+// Any other result is written as one line.
+func writeNDJSON(w io.Writer, encoded []byte) error {
+	list := encoded
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &object); err == nil && len(object) == 1 {
+		for _, inner := range object {
+			list = inner
+		}
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(list, &items); err != nil {
+		_, err = fmt.Fprintln(w, string(encoded))
+		return err
+	}
+
+	for _, item := range items {
+		if _, err := fmt.Fprintln(w, string(item)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package output_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rwx-cloud/rwx/internal/output"
+	"github.com/stretchr/testify/require"
+)
+
+type artifact struct {
+	Key       string `json:"key"`
+	SizeBytes int64  `json:"size_bytes"`
+}
+
+type result struct {
+	RunID     string     `json:"run_id"`
+	Artifacts []artifact `json:"artifacts"`
+}
+
+var sample = result{
+	RunID: "run-123",
+	Artifacts: []artifact{
+		{Key: "logs", SizeBytes: 1024},
+		{Key: "coverage", SizeBytes: 2048},
+	},
+}
+
+func write(t *testing.T, options output.Options, value any) string {
+	var buf bytes.Buffer
+	require.NoError(t, options.Write(&buf, value))
+	return buf.String()
+}
+
+func TestOptions_Write(t *testing.T) {
+	t.Run("writes JSON by default", func(t *testing.T) {
+		require.Equal(t,
+			`{"run_id":"run-123","artifacts":[{"key":"logs","size_bytes":1024},{"key":"coverage","size_bytes":2048}]}`+"\n",
+			write(t, output.Options{}, sample),
+		)
+	})
+
+	t.Run("writes YAML in field order", func(t *testing.T) {
+		out := write(t, output.Options{Format: output.FormatYAML}, sample)
+		require.Contains(t, out, "run_id: run-123\n")
+		require.Contains(t, out, "key: coverage")
+		require.Less(t, bytes.Index([]byte(out), []byte("run_id")), bytes.Index([]byte(out), []byte("artifacts")))
+	})
+
+	t.Run("writes the items of a wrapped list as NDJSON", func(t *testing.T) {
+		type list struct {
+			Artifacts []artifact `json:"artifacts"`
+		}
+		require.Equal(t,
+			"{\"key\":\"logs\",\"size_bytes\":1024}\n{\"key\":\"coverage\",\"size_bytes\":2048}\n",
+			write(t, output.Options{Format: output.FormatNDJSON}, list{Artifacts: sample.Artifacts}),
+		)
+	})
+
+	t.Run("writes other results as one NDJSON line", func(t *testing.T) {
+		require.Equal(t,
+			`{"run_id":"run-123","artifacts":[{"key":"logs","size_bytes":1024},{"key":"coverage","size_bytes":2048}]}`+"\n",
+			write(t, output.Options{Format: output.FormatNDJSON}, sample),
+		)
+	})
+
+	t.Run("executes a template with Go field names", func(t *testing.T) {
+		require.Equal(t, "run-123\n", write(t, output.Options{Template: "{{.RunID}}"}, sample))
+		require.Equal(t,
+			"logs 1024\ncoverage 2048\n",
+			write(t, output.Options{Template: "{{range .Artifacts}}{{.Key}} {{.SizeBytes}}\n{{end}}"}, sample),
+		)
+	})
+
+	t.Run("selects with jq using JSON field names", func(t *testing.T) {
+		require.Equal(t, "run-123\n", write(t, output.Options{JQ: ".run_id"}, sample))
+		require.Equal(t, "logs\ncoverage\n", write(t, output.Options{JQ: ".artifacts[].key"}, sample))
+		require.Equal(t, "3072\n", write(t, output.Options{JQ: "[.artifacts[].size_bytes] | add"}, sample))
+		require.Equal(t, `{"key":"logs","size_bytes":1024}`+"\n", write(t, output.Options{JQ: ".artifacts[0]"}, sample))
+	})
+
+	t.Run("writes jq results as YAML", func(t *testing.T) {
+		require.Equal(t, "key: logs\nsize_bytes: 1024\n", write(t, output.Options{Format: output.FormatYAML, JQ: ".artifacts[0]"}, sample))
+	})
+
+	t.Run("returns jq errors", func(t *testing.T) {
+		var buf bytes.Buffer
+		err := output.Options{JQ: ".run_id | error"}.Write(&buf, sample)
+		require.ErrorContains(t, err, "unable to evaluate --jq")
+	})
+}
+
+func TestOptions_Validate(t *testing.T) {
+	t.Run("accepts the known formats", func(t *testing.T) {
+		for _, format := range []output.Format{"", output.FormatText, output.FormatJSON, output.FormatYAML, output.FormatNDJSON} {
+			require.NoError(t, output.Options{Format: format}.Validate())
+		}
+	})
+
+	t.Run("rejects unknown formats", func(t *testing.T) {
+		require.EqualError(t, output.Options{Format: "xml"}.Validate(), `unknown output format "xml", expected one of text, json, yaml or ndjson`)
+	})
+
+	t.Run("rejects a template with jq", func(t *testing.T) {
+		require.EqualError(t, output.Options{Template: "{{.RunID}}", JQ: ".run_id"}.Validate(), "--template and --jq can't be used together")
+	})
+
+	t.Run("rejects invalid templates and jq expressions", func(t *testing.T) {
+		require.ErrorContains(t, output.Options{Template: "{{.RunID"}.Validate(), "unable to parse --template")
+		require.ErrorContains(t, output.Options{JQ: ".["}.Validate(), "unable to parse --jq")
+	})
+}
+
+func TestOptions_Structured(t *testing.T) {
+	require.False(t, output.Options{}.Structured())
+	require.False(t, output.Options{Format: output.FormatText}.Structured())
+	require.True(t, output.Options{Format: output.FormatYAML}.Structured())
+	require.True(t, output.Options{Template: "{{.RunID}}"}.Structured())
+	require.True(t, output.Options{Format: output.FormatText, JQ: ".run_id"}.Structured())
+}
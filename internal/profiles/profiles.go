@@ -0,0 +1,216 @@
+package profiles
+
+import (
+	"encoding/json"
+	"regexp"
+	"sort"
+
+	"github.com/rwx-cloud/rwx/internal/accesstoken"
+	"github.com/rwx-cloud/rwx/internal/config"
+	"github.com/rwx-cloud/rwx/internal/errors"
+)
+
+const Filename = "profiles.json"
+
+var validName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// Profile is a named set of credentials for one RWX host and organization.
+type Profile struct {
+	Name         string `json:"-"`
+	Host         string `json:"host,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	AccessToken  string `json:"access_token,omitempty"`
+}
+
+type file struct {
+	Current  string             `json:"current,omitempty"`
+	Profiles map[string]Profile `json:"profiles"`
+}
+
+// Store keeps every profile, and which one is in use, in a single file.
+type Store struct {
+	backend config.Backend
+}
+
+func NewStore(backend config.Backend) *Store {
+	return &Store{backend: backend}
+}
+
+func ValidateName(name string) error {
+	if !validName.MatchString(name) {
+		return errors.Errorf("invalid profile name %q: use letters, numbers, '.', '-' and '_'", name)
+	}
+
+	return nil
+}
+
+func (s *Store) load() (file, error) {
+	f := file{Profiles: map[string]Profile{}}
+
+	raw, err := s.backend.Get(Filename)
+	if err != nil {
+		return f, errors.Wrap(err, "unable to read profiles")
+	}
+	if raw == "" {
+		return f, nil
+	}
+
+	if err := json.Unmarshal([]byte(raw), &f); err != nil {
+		return f, errors.Wrapf(err, "unable to parse %s", Filename)
+	}
+	if f.Profiles == nil {
+		f.Profiles = map[string]Profile{}
+	}
+
+	return f, nil
+}
+
+func (s *Store) save(f file) error {
+	encoded, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "unable to encode profiles")
+	}
+
+	if err := s.backend.Set(Filename, string(encoded)+"\n"); err != nil {
+		return errors.Wrap(err, "unable to write profiles")
+	}
+
+	return nil
+}
+
+// List returns every profile sorted by name, and the name of the current one.
+func (s *Store) List() ([]Profile, string, error) {
+	f, err := s.load()
+	if err != nil {
+		return nil, "", err
+	}
+
+	list := make([]Profile, 0, len(f.Profiles))
+	for name, profile := range f.Profiles {
+		profile.Name = name
+		list = append(list, profile)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+
+	return list, f.Current, nil
+}
+
+func (s *Store) Get(name string) (Profile, bool, error) {
+	f, err := s.load()
+	if err != nil {
+		return Profile{}, false, err
+	}
+
+	profile, ok := f.Profiles[name]
+	profile.Name = name
+	return profile, ok, nil
+}
+
+// Save creates or replaces the profile with profile.Name.
+func (s *Store) Save(profile Profile) error {
+	if err := ValidateName(profile.Name); err != nil {
+		return err
+	}
+
+	f, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	f.Profiles[profile.Name] = profile
+	return s.save(f)
+}
+
+// Current returns the name of the profile in use, or "" when none is.
+func (s *Store) Current() (string, error) {
+	f, err := s.load()
+	if err != nil {
+		return "", err
+	}
+
+	return f.Current, nil
+}
+
+// Use makes an existing profile the current one.
+func (s *Store) Use(name string) error {
+	f, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := f.Profiles[name]; !ok {
+		return errors.WrapSentinel(errors.Errorf("profile %q does not exist", name), errors.ErrNotFound)
+	}
+
+	f.Current = name
+	return s.save(f)
+}
+
+// Remove deletes a profile. Removing the current profile leaves none in use.
+func (s *Store) Remove(name string) error {
+	f, err := s.load()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := f.Profiles[name]; !ok {
+		return errors.WrapSentinel(errors.Errorf("profile %q does not exist", name), errors.ErrNotFound)
+	}
+
+	delete(f.Profiles, name)
+	if f.Current == name {
+		f.Current = ""
+	}
+	return s.save(f)
+}
+
+// TokenBackend stores the access token of one profile and satisfies
+// accesstoken.Backend. Setting a token on a profile that doesn't exist yet
+// creates it with the backend's host. When tokens is set, such as to a
+// credential helper, the token is kept there rather than in the profile.
+type TokenBackend struct {
+	store  *Store
+	name   string
+	host   string
+	tokens accesstoken.Backend
+}
+
+func NewTokenBackend(store *Store, name, host string, tokens accesstoken.Backend) *TokenBackend {
+	return &TokenBackend{store: store, name: name, host: host, tokens: tokens}
+}
+
+func (b *TokenBackend) Get() (string, error) {
+	if b.tokens != nil {
+		return b.tokens.Get()
+	}
+
+	profile, _, err := b.store.Get(b.name)
+	if err != nil {
+		return "", err
+	}
+
+	return profile.AccessToken, nil
+}
+
+func (b *TokenBackend) Set(token string) error {
+	profile, ok, err := b.store.Get(b.name)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		profile.Host = b.host
+	}
+
+	if b.tokens != nil {
+		if err := b.tokens.Set(token); err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	} else {
+		profile.AccessToken = token
+	}
+
+	return b.store.Save(profile)
+}
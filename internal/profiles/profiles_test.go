@@ -0,0 +1,134 @@
+package profiles_test
+
+import (
+	"testing"
+
+	"github.com/rwx-cloud/rwx/internal/accesstoken"
+	"github.com/rwx-cloud/rwx/internal/config"
+	"github.com/rwx-cloud/rwx/internal/errors"
+	"github.com/rwx-cloud/rwx/internal/profiles"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Run("has no profiles to begin with", func(t *testing.T) {
+		store := profiles.NewStore(config.NewMemoryBackend())
+
+		list, current, err := store.List()
+		require.NoError(t, err)
+		require.Empty(t, list)
+		require.Equal(t, "", current)
+	})
+
+	t.Run("lists saved profiles by name", func(t *testing.T) {
+		store := profiles.NewStore(config.NewMemoryBackend())
+		require.NoError(t, store.Save(profiles.Profile{Name: "work", Host: "cloud.rwx.com", Organization: "acme"}))
+		require.NoError(t, store.Save(profiles.Profile{Name: "personal", Host: "cloud.rwx.com"}))
+
+		list, _, err := store.List()
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		require.Equal(t, "personal", list[0].Name)
+		require.Equal(t, "work", list[1].Name)
+		require.Equal(t, "acme", list[1].Organization)
+	})
+
+	t.Run("rejects invalid names", func(t *testing.T) {
+		store := profiles.NewStore(config.NewMemoryBackend())
+
+		err := store.Save(profiles.Profile{Name: "../work"})
+		require.ErrorContains(t, err, `invalid profile name "../work"`)
+	})
+
+	t.Run("uses an existing profile", func(t *testing.T) {
+		store := profiles.NewStore(config.NewMemoryBackend())
+		require.NoError(t, store.Save(profiles.Profile{Name: "work"}))
+
+		require.NoError(t, store.Use("work"))
+
+		current, err := store.Current()
+		require.NoError(t, err)
+		require.Equal(t, "work", current)
+	})
+
+	t.Run("does not use a missing profile", func(t *testing.T) {
+		store := profiles.NewStore(config.NewMemoryBackend())
+
+		err := store.Use("work")
+		require.ErrorIs(t, err, errors.ErrNotFound)
+	})
+
+	t.Run("clears the current profile when it is removed", func(t *testing.T) {
+		store := profiles.NewStore(config.NewMemoryBackend())
+		require.NoError(t, store.Save(profiles.Profile{Name: "work"}))
+		require.NoError(t, store.Use("work"))
+
+		require.NoError(t, store.Remove("work"))
+
+		list, current, err := store.List()
+		require.NoError(t, err)
+		require.Empty(t, list)
+		require.Equal(t, "", current)
+	})
+}
+
+func TestTokenBackend(t *testing.T) {
+	t.Run("creates the profile with its host when setting a token", func(t *testing.T) {
+		store := profiles.NewStore(config.NewMemoryBackend())
+		backend := profiles.NewTokenBackend(store, "work", "cloud.example.com", nil)
+
+		require.NoError(t, backend.Set("some-token"))
+
+		token, err := backend.Get()
+		require.NoError(t, err)
+		require.Equal(t, "some-token", token)
+
+		profile, found, err := store.Get("work")
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, "cloud.example.com", profile.Host)
+	})
+
+	t.Run("keeps the host of an existing profile", func(t *testing.T) {
+		store := profiles.NewStore(config.NewMemoryBackend())
+		require.NoError(t, store.Save(profiles.Profile{Name: "work", Host: "cloud.rwx.com", AccessToken: "old-token"}))
+		backend := profiles.NewTokenBackend(store, "work", "cloud.example.com", nil)
+
+		require.NoError(t, backend.Set("new-token"))
+
+		profile, _, err := store.Get("work")
+		require.NoError(t, err)
+		require.Equal(t, "cloud.rwx.com", profile.Host)
+		require.Equal(t, "new-token", profile.AccessToken)
+	})
+
+	t.Run("keeps tokens separate between profiles", func(t *testing.T) {
+		store := profiles.NewStore(config.NewMemoryBackend())
+		work := profiles.NewTokenBackend(store, "work", "cloud.rwx.com", nil)
+		personal := profiles.NewTokenBackend(store, "personal", "cloud.rwx.com", nil)
+
+		require.NoError(t, work.Set("work-token"))
+
+		token, err := personal.Get()
+		require.NoError(t, err)
+		require.Equal(t, "", token)
+	})
+
+	t.Run("keeps the token in another backend when given one", func(t *testing.T) {
+		store := profiles.NewStore(config.NewMemoryBackend())
+		tokens := accesstoken.NewMemoryBackend()
+		backend := profiles.NewTokenBackend(store, "work", "cloud.rwx.com", tokens)
+
+		require.NoError(t, backend.Set("some-token"))
+
+		token, err := tokens.Get()
+		require.NoError(t, err)
+		require.Equal(t, "some-token", token)
+
+		profile, found, err := store.Get("work")
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Equal(t, "cloud.rwx.com", profile.Host)
+		require.Equal(t, "", profile.AccessToken)
+	})
+}
@@ -1,49 +1,221 @@
 package retry
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"math/rand/v2"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/rwx-cloud/rwx/internal/errors"
 )
 
+// DefaultBudget is how long a RoundTripper waits in total between attempts at
+// a request before giving up on it.
+const DefaultBudget = time.Minute
+
 // RoundTripper wraps an http.RoundTripper and automatically retries requests
-// that fail with transient network errors, using exponential backoff.
+// that fail with transient network errors, or that Cloud is too busy or
+// unavailable to handle (429, 502, 503 and 504 responses), using jittered
+// exponential backoff or the wait that the response asks for with
+// Retry-After.
+//
+// Network errors are only retried for GET requests. 429 and 503 responses
+// mean the request wasn't handled, so they are retried for any method, while
+// 502 and 504 responses are only retried for idempotent methods. Requests with
+// a body are only retried when it can be replayed with GetBody.
 type RoundTripper struct {
 	Inner http.RoundTripper
+	// Sleep waits between attempts; by default, the wait is cut short when the
+	// request's context is done
 	Sleep func(time.Duration)
+	// Jitter randomizes the backoff between attempts; nil doesn't
+	Jitter func(time.Duration) time.Duration
+	// Budget caps the total time spent waiting between attempts at a request
+	Budget time.Duration
 }
 
 // NewRoundTripper creates a RoundTripper that retries transient errors.
 func NewRoundTripper(inner http.RoundTripper) *RoundTripper {
 	return &RoundTripper{
-		Inner: inner,
-		Sleep: time.Sleep,
+		Inner:  inner,
+		Jitter: EqualJitter,
+		Budget: DefaultBudget,
+	}
+}
+
+// EqualJitter randomizes a backoff to between half of it and all of it, so
+// that clients which failed together don't all retry together.
+func EqualJitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
 	}
+	return half + rand.N(d-half+1)
+}
+
+type attemptKey struct{}
+
+// Attempt returns which attempt at a request this is, starting from 1, for
+// RoundTrippers inside a retrying RoundTripper.
+func Attempt(req *http.Request) int {
+	if attempt, ok := req.Context().Value(attemptKey{}).(int); ok {
+		return attempt
+	}
+	return 1
 }
 
 func (rt *RoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
 	backoff := NewBackoff()
+	var waited time.Duration
+
+	for attempt := 1; ; attempt++ {
+		attemptReq, err := rt.attemptRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := rt.Inner.RoundTrip(attemptReq)
+		if err == nil && !RetryableStatus(req.Method, resp.StatusCode) {
+			return resp, nil
+		}
+		if err != nil && (req.Method != http.MethodGet || !IsTransient(err)) {
+			return nil, err
+		}
+		if !replayable(req) {
+			return resp, err
+		}
 
-	for {
-		resp, err := rt.Inner.RoundTrip(req)
+		wait, retryErr := backoff.Record()
+		if retryErr != nil {
+			if err != nil {
+				return nil, errors.WrapSentinel(
+					fmt.Errorf("request failed after %d consecutive network errors: %w", backoff.MaxFailures, err),
+					errors.ErrNetworkTransient,
+				)
+			}
+			return resp, nil
+		}
+		if rt.Jitter != nil {
+			wait = rt.Jitter(wait)
+		}
 		if err == nil {
+			if retryAfter, ok := RetryAfter(resp, time.Now()); ok {
+				wait = retryAfter
+			}
+		}
+
+		if rt.Budget > 0 && waited+wait > rt.Budget {
+			if err != nil {
+				return nil, errors.WrapSentinel(
+					fmt.Errorf("request failed after retrying for %s: %w", waited, err),
+					errors.ErrNetworkTransient,
+				)
+			}
 			return resp, nil
 		}
+		waited += wait
 
-		if req.Method != http.MethodGet || !IsTransient(err) {
+		if resp != nil {
+			discard(resp)
+		}
+		if err := rt.sleep(req.Context(), wait); err != nil {
 			return nil, err
 		}
+	}
+}
+
+// attemptRequest is the request for an attempt, with a fresh body for every
+// attempt after the first.
+func (rt *RoundTripper) attemptRequest(req *http.Request, attempt int) (*http.Request, error) {
+	attemptReq := req.WithContext(context.WithValue(req.Context(), attemptKey{}, attempt))
+	if attempt > 1 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to replay the request body")
+		}
+		attemptReq.Body = body
+	}
+	return attemptReq, nil
+}
 
-		sleepDur, retryErr := backoff.Record()
-		if retryErr != nil {
-			return nil, errors.WrapSentinel(
-				fmt.Errorf("request failed after %d consecutive network errors: %w", backoff.MaxFailures, err),
-				errors.ErrNetworkTransient,
-			)
+func (rt *RoundTripper) sleep(ctx context.Context, d time.Duration) error {
+	if rt.Sleep != nil {
+		rt.Sleep(d)
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// RetryableStatus reports whether a response with a status means the request
+// can be retried. 429 and 503 responses are retryable for any method; 502 and
+// 504 responses only for idempotent methods, since the request may have been
+// handled.
+func RetryableStatus(method string, status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent(method)
+	default:
+		return false
+	}
+}
+
+// RetryAfter is the wait that a response asks for with its Retry-After header,
+// given as seconds or as an HTTP date.
+func RetryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
 		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// replayable reports whether a request can be sent again: it has no body, or
+// its body can be recreated.
+func replayable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
 
-		rt.Sleep(sleepDur)
+// discard reads what's left of a response that's being retried, so that its
+// connection can be reused, and closes it.
+func discard(resp *http.Response) {
+	if resp.Body == nil {
+		return
 	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	_ = resp.Body.Close()
 }
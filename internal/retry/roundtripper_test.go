@@ -1,9 +1,11 @@
 package retry
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -128,3 +130,250 @@ func TestRoundTripper(t *testing.T) {
 		require.Equal(t, 4*time.Second, sleepDurations[2])
 	})
 }
+
+func TestRoundTripper_Responses(t *testing.T) {
+	respond := func(status int, header http.Header) *http.Response {
+		if header == nil {
+			header = http.Header{}
+		}
+		return &http.Response{StatusCode: status, Header: header, Body: io.NopCloser(strings.NewReader(""))}
+	}
+
+	t.Run("retries unavailable responses and succeeds", func(t *testing.T) {
+		var statuses []int
+		rt := newTestRoundTripper(func(req *http.Request) (*http.Response, error) {
+			if len(statuses) < 3 {
+				status := []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusBadGateway}[len(statuses)]
+				statuses = append(statuses, status)
+				return respond(status, nil), nil
+			}
+			statuses = append(statuses, http.StatusOK)
+			return respond(http.StatusOK, nil), nil
+		})
+
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		resp, err := rt.RoundTrip(req)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, []int{503, 429, 502, 200}, statuses)
+	})
+
+	t.Run("returns the last response after max consecutive failures", func(t *testing.T) {
+		callCount := 0
+		rt := newTestRoundTripper(func(req *http.Request) (*http.Response, error) {
+			callCount++
+			return respond(http.StatusServiceUnavailable, nil), nil
+		})
+
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		resp, err := rt.RoundTrip(req)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		require.Equal(t, 5, callCount)
+	})
+
+	t.Run("does not retry other responses", func(t *testing.T) {
+		callCount := 0
+		rt := newTestRoundTripper(func(req *http.Request) (*http.Response, error) {
+			callCount++
+			return respond(http.StatusInternalServerError, nil), nil
+		})
+
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		resp, err := rt.RoundTrip(req)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		require.Equal(t, 1, callCount)
+	})
+
+	t.Run("replays the body of POST requests that were not handled", func(t *testing.T) {
+		var bodies []string
+		rt := newTestRoundTripper(func(req *http.Request) (*http.Response, error) {
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			bodies = append(bodies, string(body))
+			if len(bodies) == 1 {
+				return respond(http.StatusServiceUnavailable, nil), nil
+			}
+			return respond(http.StatusCreated, nil), nil
+		})
+
+		req, _ := http.NewRequest(http.MethodPost, "http://example.com", strings.NewReader(`{"a":1}`))
+		resp, err := rt.RoundTrip(req)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		require.Equal(t, []string{`{"a":1}`, `{"a":1}`}, bodies)
+	})
+
+	t.Run("does not retry POST requests that may have been handled", func(t *testing.T) {
+		callCount := 0
+		rt := newTestRoundTripper(func(req *http.Request) (*http.Response, error) {
+			callCount++
+			return respond(http.StatusGatewayTimeout, nil), nil
+		})
+
+		req, _ := http.NewRequest(http.MethodPost, "http://example.com", strings.NewReader("{}"))
+		resp, err := rt.RoundTrip(req)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusGatewayTimeout, resp.StatusCode)
+		require.Equal(t, 1, callCount)
+	})
+
+	t.Run("does not retry requests with a body that cannot be replayed", func(t *testing.T) {
+		callCount := 0
+		rt := newTestRoundTripper(func(req *http.Request) (*http.Response, error) {
+			callCount++
+			return respond(http.StatusServiceUnavailable, nil), nil
+		})
+
+		req, _ := http.NewRequest(http.MethodPost, "http://example.com", io.NopCloser(strings.NewReader("{}")))
+		resp, err := rt.RoundTrip(req)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		require.Equal(t, 1, callCount)
+	})
+
+	t.Run("waits as long as Retry-After asks", func(t *testing.T) {
+		var sleepDurations []time.Duration
+		callCount := 0
+		rt := &RoundTripper{
+			Inner: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				callCount++
+				if callCount == 1 {
+					return respond(http.StatusTooManyRequests, http.Header{"Retry-After": {"7"}}), nil
+				}
+				return respond(http.StatusOK, nil), nil
+			}),
+			Sleep: func(d time.Duration) {
+				sleepDurations = append(sleepDurations, d)
+			},
+			Jitter: func(d time.Duration) time.Duration { return 0 },
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		_, err := rt.RoundTrip(req)
+
+		require.NoError(t, err)
+		require.Equal(t, []time.Duration{7 * time.Second}, sleepDurations)
+	})
+
+	t.Run("gives up once the budget is spent", func(t *testing.T) {
+		var sleepDurations []time.Duration
+		callCount := 0
+		rt := &RoundTripper{
+			Inner: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				callCount++
+				return respond(http.StatusServiceUnavailable, nil), nil
+			}),
+			Sleep: func(d time.Duration) {
+				sleepDurations = append(sleepDurations, d)
+			},
+			Budget: 3 * time.Second,
+		}
+
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		resp, err := rt.RoundTrip(req)
+
+		require.NoError(t, err)
+		require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+		require.Equal(t, 3, callCount)
+		require.Equal(t, []time.Duration{1 * time.Second, 2 * time.Second}, sleepDurations)
+	})
+
+	t.Run("gives up on network errors once the budget is spent", func(t *testing.T) {
+		rt := newTestRoundTripper(func(req *http.Request) (*http.Response, error) {
+			return nil, io.EOF
+		})
+		rt.Budget = time.Second
+
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		_, err := rt.RoundTrip(req)
+
+		require.ErrorIs(t, err, internalerrors.ErrNetworkTransient)
+	})
+
+	t.Run("stops waiting when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		rt := NewRoundTripper(roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			cancel()
+			return respond(http.StatusServiceUnavailable, http.Header{"Retry-After": {"60"}}), nil
+		}))
+
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+		_, err := rt.RoundTrip(req)
+
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	retryAfter := func(value string) (time.Duration, bool) {
+		return RetryAfter(&http.Response{Header: http.Header{"Retry-After": {value}}}, now)
+	}
+
+	t.Run("parses seconds", func(t *testing.T) {
+		wait, ok := retryAfter("30")
+		require.True(t, ok)
+		require.Equal(t, 30*time.Second, wait)
+	})
+
+	t.Run("parses HTTP dates", func(t *testing.T) {
+		wait, ok := retryAfter(now.Add(90 * time.Second).Format(http.TimeFormat))
+		require.True(t, ok)
+		require.Equal(t, 90*time.Second, wait)
+
+		wait, ok = retryAfter(now.Add(-time.Minute).Format(http.TimeFormat))
+		require.True(t, ok)
+		require.Equal(t, time.Duration(0), wait)
+	})
+
+	t.Run("ignores missing and invalid values", func(t *testing.T) {
+		_, ok := RetryAfter(&http.Response{Header: http.Header{}}, now)
+		require.False(t, ok)
+
+		_, ok = retryAfter("soon")
+		require.False(t, ok)
+
+		_, ok = retryAfter("-1")
+		require.False(t, ok)
+	})
+}
+
+func TestEqualJitter(t *testing.T) {
+	for range 100 {
+		wait := EqualJitter(4 * time.Second)
+		require.GreaterOrEqual(t, wait, 2*time.Second)
+		require.LessOrEqual(t, wait, 4*time.Second)
+	}
+}
+
+func TestAttempt(t *testing.T) {
+	t.Run("counts attempts inside the round tripper", func(t *testing.T) {
+		var attempts []int
+		rt := newTestRoundTripper(func(req *http.Request) (*http.Response, error) {
+			attempts = append(attempts, Attempt(req))
+			if len(attempts) < 3 {
+				return nil, io.EOF
+			}
+			return &http.Response{StatusCode: 200}, nil
+		})
+
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		_, err := rt.RoundTrip(req)
+
+		require.NoError(t, err)
+		require.Equal(t, []int{1, 2, 3}, attempts)
+	})
+
+	t.Run("is the first attempt outside of a round tripper", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "http://example.com", nil)
+		require.Equal(t, 1, Attempt(req))
+	})
+}
@@ -2,8 +2,13 @@ package ssh
 
 import (
 	"bytes"
+	"crypto/tls"
 	"io"
+	"net"
 	"os"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"github.com/rwx-cloud/rwx/internal/errors"
 
@@ -12,20 +17,225 @@ import (
 	"golang.org/x/term"
 )
 
+const (
+	DefaultKeepaliveInterval  = 15 * time.Second
+	DefaultKeepaliveMaxMissed = 3
+	DefaultReconnectAttempts  = 2
+)
+
+// reconnectBackoff is how long to wait before each reconnect attempt,
+// multiplied by the attempt number.
+var reconnectBackoff = time.Second
+
 type Client struct {
 	*ssh.Client
+
+	// Stdout and Stderr receive the output of non-interactive commands.
+	// They default to os.Stdout and os.Stderr.
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// KeepaliveInterval is how often a keepalive request is sent while
+	// connected. It defaults to DefaultKeepaliveInterval; a negative value
+	// disables keepalives.
+	KeepaliveInterval time.Duration
+	// KeepaliveMaxMissed is how many keepalives may go unanswered before the
+	// connection is considered dead and closed. It defaults to
+	// DefaultKeepaliveMaxMissed.
+	KeepaliveMaxMissed int
+	// ReconnectAttempts is how many times a lost connection is re-established
+	// before starting a non-interactive command. Commands are never retried
+	// once started, since they may not be idempotent. It defaults to
+	// DefaultReconnectAttempts; a negative value disables reconnecting.
+	ReconnectAttempts int
+	// ProxyTLSConfig configures TLS with an https:// proxy, such as the CA
+	// certificates to trust and the client certificate to present. When nil,
+	// the system's CA certificates are trusted.
+	ProxyTLSConfig *tls.Config
+
+	address       string
+	config        ssh.ClientConfig
+	stopKeepalive chan struct{}
+	// keepaliveFailed is set once keepalives have closed the current
+	// connection
+	keepaliveFailed *atomic.Bool
+}
+
+// Connect connects to address, through the proxy from ProxyFromEnvironment
+// if there is one, and starts sending keepalives.
+func (c *Client) Connect(address string, config ssh.ClientConfig) error {
+	client, err := c.dial(address, config)
+	if err != nil {
+		return err
+	}
+
+	c.address = address
+	c.config = config
+	c.setClient(client)
+	return nil
+}
+
+func (c *Client) dial(address string, config ssh.ClientConfig) (*ssh.Client, error) {
+	proxyURL, err := ProxyFromEnvironment(address)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := dialThroughProxy(proxyURL, address, config.Timeout, c.ProxyTLSConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	sshConn, channels, requests, err := ssh.NewClientConn(conn, address, &config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ssh.NewClient(sshConn, channels, requests), nil
+}
+
+func (c *Client) setClient(client *ssh.Client) {
+	if c.stopKeepalive != nil {
+		close(c.stopKeepalive)
+		c.stopKeepalive = nil
+	}
+	c.Client = client
+	c.keepaliveFailed = new(atomic.Bool)
+
+	interval := c.KeepaliveInterval
+	if interval == 0 {
+		interval = DefaultKeepaliveInterval
+	}
+	if interval < 0 {
+		return
+	}
+	maxMissed := c.KeepaliveMaxMissed
+	if maxMissed <= 0 {
+		maxMissed = DefaultKeepaliveMaxMissed
+	}
+
+	c.stopKeepalive = make(chan struct{})
+	go keepalive(client, interval, maxMissed, c.stopKeepalive, c.keepaliveFailed)
+}
+
+// keepalive sends a keepalive request every interval and closes the
+// connection once maxMissed requests in a row have gone unanswered, so that
+// a dropped connection fails instead of hanging. failed is set when it does.
+func keepalive(client *ssh.Client, interval time.Duration, maxMissed int, stop <-chan struct{}, failed *atomic.Bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	missed := 0
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		reply := make(chan error, 1)
+		go func() {
+			// Servers reply to unknown requests with a failure, which still
+			// shows the connection is alive
+			_, _, err := client.SendRequest("keepalive@openssh.com", true, nil)
+			reply <- err
+		}()
+
+		select {
+		case <-stop:
+			return
+		case err := <-reply:
+			if err != nil {
+				failed.Store(true)
+				_ = client.Close()
+				return
+			}
+			missed = 0
+		case <-time.After(interval):
+			missed++
+			if missed >= maxMissed {
+				failed.Store(true)
+				_ = client.Close()
+				return
+			}
+		}
+	}
+}
+
+// newSession opens a session for a non-interactive command, reconnecting
+// first if the connection has been lost. Other failures, such as the server
+// refusing another session, are returned as they are.
+func (c *Client) newSession() (*ssh.Session, error) {
+	session, err := c.Client.NewSession()
+	if err == nil || c.address == "" || !c.connectionLost(err) {
+		return session, err
+	}
+
+	attempts := c.ReconnectAttempts
+	if attempts == 0 {
+		attempts = DefaultReconnectAttempts
+	}
+	for attempt := 1; attempt <= attempts; attempt++ {
+		time.Sleep(time.Duration(attempt) * reconnectBackoff)
+
+		client, dialErr := c.dial(c.address, c.config)
+		if dialErr != nil {
+			err = dialErr
+			continue
+		}
+		_ = c.Client.Close()
+		c.setClient(client)
+
+		session, err = c.Client.NewSession()
+		if err == nil {
+			return session, nil
+		}
+	}
+	return nil, errors.Wrap(err, "unable to reconnect")
 }
 
-func (c *Client) Connect(address string, config ssh.ClientConfig) (err error) {
-	c.Client, err = ssh.Dial("tcp", address, &config)
-	return
+// connectionLost reports whether err from opening a session means the
+// connection is gone: it was closed or reset, or keepalives went unanswered.
+func (c *Client) connectionLost(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	return c.keepaliveFailed != nil && c.keepaliveFailed.Load()
 }
 
 func (c *Client) Close() error {
+	if c.stopKeepalive != nil {
+		close(c.stopKeepalive)
+		c.stopKeepalive = nil
+	}
 	return c.Client.Close()
 }
 
-func (c *Client) InteractiveSession() error {
+func (c *Client) stdout() io.Writer {
+	if c.Stdout != nil {
+		return c.Stdout
+	}
+	return os.Stdout
+}
+
+func (c *Client) stderr() io.Writer {
+	if c.Stderr != nil {
+		return c.Stderr
+	}
+	return os.Stderr
+}
+
+// TerminalRecorder receives a copy of an interactive session's output along
+// with changes to the terminal size.
+type TerminalRecorder interface {
+	io.Writer
+	Resize(width, height int)
+}
+
+// InteractiveSession starts a shell on the remote host attached to the local
+// terminal. If recorder is non-nil, the session's output is also written to it.
+func (c *Client) InteractiveSession(recorder TerminalRecorder) error {
 	session, err := c.Client.NewSession()
 	if err != nil {
 		return errors.Wrapf(err, "unable to start interactive debug session")
@@ -35,6 +245,10 @@ func (c *Client) InteractiveSession() error {
 	session.Stdin = os.Stdin
 	session.Stdout = os.Stdout
 	session.Stderr = os.Stderr
+	if recorder != nil {
+		session.Stdout = io.MultiWriter(os.Stdout, recorder)
+		session.Stderr = io.MultiWriter(os.Stderr, recorder)
+	}
 
 	terminalSize, err := tsize.GetSize()
 	if err != nil {
@@ -62,6 +276,9 @@ func (c *Client) InteractiveSession() error {
 	go func() {
 		for size := range sizeChangeNotification.Change {
 			_ = session.WindowChange(size.Height, size.Width)
+			if recorder != nil {
+				recorder.Resize(size.Width, size.Height)
+			}
 		}
 	}()
 
@@ -84,14 +301,14 @@ func (c *Client) InteractiveSession() error {
 //   - (N, nil)   = command completed with non-zero exit code N
 //   - (-1, err)  = SSH/connection error (command may not have run)
 func (c *Client) ExecuteCommand(command string) (int, error) {
-	session, err := c.Client.NewSession()
+	session, err := c.newSession()
 	if err != nil {
 		return -1, errors.Wrap(err, "unable to create SSH session")
 	}
 	defer session.Close()
 
-	session.Stdout = os.Stdout
-	session.Stderr = os.Stderr
+	session.Stdout = c.stdout()
+	session.Stderr = c.stderr()
 
 	err = session.Run(command)
 	if err != nil {
@@ -103,6 +320,56 @@ func (c *Client) ExecuteCommand(command string) (int, error) {
 	return 0, nil
 }
 
+// ExecuteCommandWithSignals runs a command non-interactively over SSH,
+// forwarding each signal received on signals to the remote process until the
+// command completes. Closing signals abandons the command: the session is
+// closed without waiting for the remote process to exit.
+//
+// Return values:
+//   - (0, nil)   = command succeeded with exit code 0
+//   - (N, nil)   = command completed with non-zero exit code N (128+n if killed by signal n)
+//   - (-1, err)  = SSH/connection error, or the command was abandoned
+func (c *Client) ExecuteCommandWithSignals(command string, signals <-chan ssh.Signal) (int, error) {
+	session, err := c.newSession()
+	if err != nil {
+		return -1, errors.Wrap(err, "unable to create SSH session")
+	}
+	defer session.Close()
+
+	session.Stdout = c.stdout()
+	session.Stderr = c.stderr()
+
+	if err := session.Start(command); err != nil {
+		return -1, errors.Wrap(err, "SSH command execution failed")
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case sig, ok := <-signals:
+				if !ok {
+					_ = session.Close()
+					return
+				}
+				_ = session.Signal(sig)
+			}
+		}
+	}()
+
+	err = session.Wait()
+	if err != nil {
+		if exitErr, ok := err.(*ssh.ExitError); ok {
+			return exitErr.ExitStatus(), nil
+		}
+		return -1, errors.Wrap(err, "SSH command execution failed")
+	}
+	return 0, nil
+}
+
 // ExecuteCommandWithStdin runs a command non-interactively over SSH, piping data to stdin.
 //
 // Return values:
@@ -110,15 +377,15 @@ func (c *Client) ExecuteCommand(command string) (int, error) {
 //   - (N, nil)   = command completed with non-zero exit code N
 //   - (-1, err)  = SSH/connection error (command may not have run)
 func (c *Client) ExecuteCommandWithStdin(command string, stdin io.Reader) (int, error) {
-	session, err := c.Client.NewSession()
+	session, err := c.newSession()
 	if err != nil {
 		return -1, errors.Wrap(err, "unable to create SSH session")
 	}
 	defer session.Close()
 
 	session.Stdin = stdin
-	session.Stdout = os.Stdout
-	session.Stderr = os.Stderr
+	session.Stdout = c.stdout()
+	session.Stderr = c.stderr()
 
 	err = session.Run(command)
 	if err != nil {
@@ -137,7 +404,7 @@ func (c *Client) ExecuteCommandWithStdin(command string, stdin io.Reader) (int,
 //   - (N, output, nil)   = command completed with non-zero exit code N
 //   - (-1, "", err)      = SSH/connection error (command may not have run)
 func (c *Client) ExecuteCommandWithOutput(command string) (int, string, error) {
-	session, err := c.Client.NewSession()
+	session, err := c.newSession()
 	if err != nil {
 		return -1, "", errors.Wrap(err, "unable to create SSH session")
 	}
@@ -145,7 +412,7 @@ func (c *Client) ExecuteCommandWithOutput(command string) (int, string, error) {
 
 	var stdout bytes.Buffer
 	session.Stdout = &stdout
-	session.Stderr = os.Stderr
+	session.Stderr = c.stderr()
 
 	err = session.Run(command)
 	if err != nil {
@@ -165,7 +432,7 @@ func (c *Client) ExecuteCommandWithOutput(command string) (int, string, error) {
 //   - (N, output, nil)   = command completed with non-zero exit code N
 //   - (-1, "", err)      = SSH/connection error (command may not have run)
 func (c *Client) ExecuteCommandWithStdinAndCombinedOutput(command string, stdin io.Reader) (int, string, error) {
-	session, err := c.Client.NewSession()
+	session, err := c.newSession()
 	if err != nil {
 		return -1, "", errors.Wrap(err, "unable to create SSH session")
 	}
@@ -0,0 +1,219 @@
+package ssh
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// testServer is an SSH server that runs every command by printing the
+// command itself and exiting with status 0.
+type testServer struct {
+	listener net.Listener
+	config   *ssh.ServerConfig
+	// ignoreRequests leaves global requests, such as keepalives, unanswered
+	ignoreRequests bool
+	// rejectSessions refuses every session, like a server at its session limit
+	rejectSessions bool
+
+	lock  sync.Mutex
+	conns []net.Conn
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(hostKey)
+	require.NoError(t, err)
+
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(signer)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	server := &testServer{listener: listener, config: config}
+	go server.serve()
+	return server
+}
+
+func (s *testServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.lock.Lock()
+		s.conns = append(s.conns, conn)
+		s.lock.Unlock()
+
+		go s.handle(conn)
+	}
+}
+
+func (s *testServer) handle(conn net.Conn) {
+	_, channels, requests, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		return
+	}
+
+	go func() {
+		for req := range requests {
+			if !s.ignoreRequests && req.WantReply {
+				_ = req.Reply(false, nil)
+			}
+		}
+	}()
+
+	for newChannel := range channels {
+		if s.rejectSessions {
+			_ = newChannel.Reject(ssh.ResourceShortage, "too many sessions")
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go func() {
+			defer channel.Close()
+			for req := range channelRequests {
+				if req.Type != "exec" {
+					_ = req.Reply(false, nil)
+					continue
+				}
+				_ = req.Reply(true, nil)
+				_, _ = channel.Write(req.Payload[4:])
+				_, _ = channel.SendRequest("exit-status", false, ssh.Marshal(struct{ Status uint32 }{0}))
+				return
+			}
+		}()
+	}
+}
+
+// connectionCount is how many connections the server has accepted and not
+// dropped.
+func (s *testServer) connectionCount() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.conns)
+}
+
+// dropConnections closes every connection the server has accepted.
+func (s *testServer) dropConnections() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+	s.conns = nil
+}
+
+func (s *testServer) address() string {
+	return s.listener.Addr().String()
+}
+
+func clientConfig() ssh.ClientConfig {
+	return ssh.ClientConfig{
+		User:            "test",
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         5 * time.Second,
+	}
+}
+
+func TestClient_Reconnect(t *testing.T) {
+	reconnectBackoff = time.Millisecond
+	t.Cleanup(func() { reconnectBackoff = time.Second })
+
+	t.Run("reconnects before running a command on a dropped connection", func(t *testing.T) {
+		server := newTestServer(t)
+		client := &Client{KeepaliveInterval: -1}
+		require.NoError(t, client.Connect(server.address(), clientConfig()))
+		defer client.Close()
+
+		exitCode, output, err := client.ExecuteCommandWithOutput("first")
+		require.NoError(t, err)
+		require.Equal(t, 0, exitCode)
+		require.Equal(t, "first", output)
+
+		server.dropConnections()
+		_ = client.Client.Wait()
+
+		exitCode, output, err = client.ExecuteCommandWithOutput("second")
+		require.NoError(t, err)
+		require.Equal(t, 0, exitCode)
+		require.Equal(t, "second", output)
+	})
+
+	t.Run("doesn't reconnect when the server refuses the session", func(t *testing.T) {
+		server := newTestServer(t)
+		server.rejectSessions = true
+		client := &Client{KeepaliveInterval: -1}
+		require.NoError(t, client.Connect(server.address(), clientConfig()))
+		defer client.Close()
+
+		exitCode, _, err := client.ExecuteCommandWithOutput("first")
+		require.Error(t, err)
+		require.Equal(t, -1, exitCode)
+		require.Contains(t, err.Error(), "too many sessions")
+		require.NotContains(t, err.Error(), "unable to reconnect")
+		require.Equal(t, 1, server.connectionCount())
+	})
+
+	t.Run("doesn't reconnect when disabled", func(t *testing.T) {
+		server := newTestServer(t)
+		client := &Client{KeepaliveInterval: -1, ReconnectAttempts: -1}
+		require.NoError(t, client.Connect(server.address(), clientConfig()))
+		defer client.Close()
+
+		server.dropConnections()
+		_ = client.Client.Wait()
+
+		exitCode, _, err := client.ExecuteCommandWithOutput("second")
+		require.Error(t, err)
+		require.Equal(t, -1, exitCode)
+	})
+}
+
+func TestClient_Keepalive(t *testing.T) {
+	t.Run("keeps an answering connection open", func(t *testing.T) {
+		server := newTestServer(t)
+		client := &Client{KeepaliveInterval: 5 * time.Millisecond}
+		require.NoError(t, client.Connect(server.address(), clientConfig()))
+		defer client.Close()
+
+		time.Sleep(50 * time.Millisecond)
+
+		_, output, err := client.ExecuteCommandWithOutput("still here")
+		require.NoError(t, err)
+		require.Equal(t, "still here", output)
+	})
+
+	t.Run("closes the connection when keepalives go unanswered", func(t *testing.T) {
+		server := newTestServer(t)
+		server.ignoreRequests = true
+		client := &Client{KeepaliveInterval: 5 * time.Millisecond, KeepaliveMaxMissed: 2}
+		require.NoError(t, client.Connect(server.address(), clientConfig()))
+		defer client.Close()
+
+		closed := make(chan struct{})
+		go func() {
+			_ = client.Client.Wait()
+			close(closed)
+		}()
+
+		select {
+		case <-closed:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the connection was not closed")
+		}
+	})
+}
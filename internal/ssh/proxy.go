@@ -0,0 +1,161 @@
+package ssh
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/rwx-cloud/rwx/internal/errors"
+
+	"golang.org/x/net/http/httpproxy"
+	"golang.org/x/net/proxy"
+)
+
+// ProxyFromEnvironment returns the proxy to reach address through, or nil to
+// connect directly. HTTPS_PROXY is used when set, falling back to ALL_PROXY,
+// and NO_PROXY is respected. Both socks5:// and http(s):// proxies are
+// supported; a proxy without a scheme is treated as http://.
+func ProxyFromEnvironment(address string) (*url.URL, error) {
+	cfg := httpproxy.FromEnvironment()
+	if cfg.HTTPSProxy == "" {
+		cfg.HTTPSProxy = getenvAny("ALL_PROXY", "all_proxy")
+	}
+	if cfg.HTTPSProxy == "" {
+		return nil, nil
+	}
+
+	proxyURL, err := cfg.ProxyFunc()(&url.URL{Scheme: "https", Host: address})
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid proxy configuration")
+	}
+	return proxyURL, nil
+}
+
+func getenvAny(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// dialThroughProxy opens a TCP connection to address, through proxyURL when
+// it is non-nil. tlsConfig, which may be nil, configures TLS with an https://
+// proxy.
+func dialThroughProxy(proxyURL *url.URL, address string, timeout time.Duration, tlsConfig *tls.Config) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	if proxyURL == nil {
+		return dialer.Dial("tcp", address)
+	}
+
+	switch proxyURL.Scheme {
+	case "socks5", "socks5h":
+		var auth *proxy.Auth
+		if proxyURL.User != nil {
+			password, _ := proxyURL.User.Password()
+			auth = &proxy.Auth{User: proxyURL.User.Username(), Password: password}
+		}
+
+		socks, err := proxy.SOCKS5("tcp", proxyURL.Host, auth, dialer)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to use SOCKS5 proxy %s", proxyURL.Redacted())
+		}
+		conn, err := socks.Dial("tcp", address)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to connect through SOCKS5 proxy %s", proxyURL.Redacted())
+		}
+		return conn, nil
+	case "http", "https":
+		return dialHTTPConnect(dialer, proxyURL, address, tlsConfig)
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q in %s", proxyURL.Scheme, proxyURL.Redacted())
+	}
+}
+
+// dialHTTPConnect tunnels to address through an HTTP proxy with CONNECT.
+func dialHTTPConnect(dialer *net.Dialer, proxyURL *url.URL, address string, tlsConfig *tls.Config) (net.Conn, error) {
+	proxyAddress := proxyURL.Host
+	if proxyURL.Port() == "" {
+		port := "80"
+		if proxyURL.Scheme == "https" {
+			port = "443"
+		}
+		proxyAddress = net.JoinHostPort(proxyURL.Hostname(), port)
+	}
+
+	conn, err := dialer.Dial("tcp", proxyAddress)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to connect to proxy %s", proxyURL.Redacted())
+	}
+	if proxyURL.Scheme == "https" {
+		proxyTLSConfig := &tls.Config{}
+		if tlsConfig != nil {
+			proxyTLSConfig = tlsConfig.Clone()
+		}
+		proxyTLSConfig.ServerName = proxyURL.Hostname()
+
+		tlsConn := tls.Client(conn, proxyTLSConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			conn.Close()
+			return nil, errors.Wrapf(err, "unable to establish TLS with proxy %s", proxyURL.Redacted())
+		}
+		conn = tlsConn
+	}
+
+	if dialer.Timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(dialer.Timeout))
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, errors.Wrapf(err, "unable to send CONNECT to proxy %s", proxyURL.Redacted())
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		conn.Close()
+		return nil, errors.Wrapf(err, "unable to read CONNECT response from proxy %s", proxyURL.Redacted())
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy %s refused to connect to %s: %s", proxyURL.Redacted(), address, resp.Status)
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+
+	if reader.Buffered() > 0 {
+		return &bufferedConn{Conn: conn, reader: reader}, nil
+	}
+	return conn, nil
+}
+
+// bufferedConn returns bytes the proxy sent after its CONNECT response before
+// reading from the connection itself.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+func (c *bufferedConn) Read(p []byte) (int, error) {
+	return c.reader.Read(p)
+}
@@ -0,0 +1,201 @@
+package ssh
+
+import (
+	"bufio"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestProxyFromEnvironment(t *testing.T) {
+	clearProxyEnv := func(t *testing.T) {
+		for _, name := range []string{"HTTPS_PROXY", "https_proxy", "ALL_PROXY", "all_proxy", "NO_PROXY", "no_proxy", "REQUEST_METHOD"} {
+			t.Setenv(name, "")
+		}
+	}
+
+	t.Run("connects directly without a proxy", func(t *testing.T) {
+		clearProxyEnv(t)
+
+		proxyURL, err := ProxyFromEnvironment("agent.example.com:22")
+		require.NoError(t, err)
+		require.Nil(t, proxyURL)
+	})
+
+	t.Run("uses ALL_PROXY", func(t *testing.T) {
+		clearProxyEnv(t)
+		t.Setenv("ALL_PROXY", "socks5://proxy.internal:1080")
+
+		proxyURL, err := ProxyFromEnvironment("agent.example.com:22")
+		require.NoError(t, err)
+		require.Equal(t, "socks5://proxy.internal:1080", proxyURL.String())
+	})
+
+	t.Run("prefers HTTPS_PROXY over ALL_PROXY", func(t *testing.T) {
+		clearProxyEnv(t)
+		t.Setenv("ALL_PROXY", "socks5://proxy.internal:1080")
+		t.Setenv("HTTPS_PROXY", "proxy.internal:3128")
+
+		proxyURL, err := ProxyFromEnvironment("agent.example.com:22")
+		require.NoError(t, err)
+		require.Equal(t, "http://proxy.internal:3128", proxyURL.String())
+	})
+
+	t.Run("respects NO_PROXY", func(t *testing.T) {
+		clearProxyEnv(t)
+		t.Setenv("ALL_PROXY", "socks5://proxy.internal:1080")
+		t.Setenv("NO_PROXY", ".example.com")
+
+		proxyURL, err := ProxyFromEnvironment("agent.example.com:22")
+		require.NoError(t, err)
+		require.Nil(t, proxyURL)
+	})
+}
+
+// connectProxy is an HTTP proxy that only supports CONNECT, answering with
+// status. The requests it receives are sent on the returned channel.
+func connectProxy(t *testing.T, status int) (*url.URL, <-chan *http.Request) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	return serveConnectProxy(listener, "http", status)
+}
+
+// tlsConnectProxy is an https:// connectProxy, with a certificate signed by
+// the CA in the returned pool.
+func tlsConnectProxy(t *testing.T) (*url.URL, <-chan *http.Request, *x509.CertPool) {
+	t.Helper()
+
+	// httptest's certificate is valid for 127.0.0.1
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	server.Close()
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: server.TLS.Certificates})
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	proxyURL, requests := serveConnectProxy(listener, "https", http.StatusOK)
+	return proxyURL, requests, pool
+}
+
+func serveConnectProxy(listener net.Listener, scheme string, status int) (*url.URL, <-chan *http.Request) {
+	requests := make(chan *http.Request, 10)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				req, err := http.ReadRequest(bufio.NewReader(conn))
+				if err != nil {
+					return
+				}
+				requests <- req
+
+				if status != http.StatusOK {
+					fmt.Fprintf(conn, "HTTP/1.1 %d %s\r\n\r\n", status, http.StatusText(status))
+					return
+				}
+
+				upstream, err := net.Dial("tcp", req.Host)
+				if err != nil {
+					return
+				}
+				defer upstream.Close()
+
+				_, _ = io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+				go func() { _, _ = io.Copy(upstream, conn) }()
+				_, _ = io.Copy(conn, upstream)
+			}()
+		}
+	}()
+
+	return &url.URL{Scheme: scheme, Host: listener.Addr().String(), User: url.UserPassword("user", "secret")}, requests
+}
+
+func TestDialThroughProxy(t *testing.T) {
+	t.Run("tunnels through an HTTP proxy with CONNECT", func(t *testing.T) {
+		server := newTestServer(t)
+		proxyURL, requests := connectProxy(t, http.StatusOK)
+
+		conn, err := dialThroughProxy(proxyURL, server.address(), 0, nil)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		req := <-requests
+		require.Equal(t, http.MethodConnect, req.Method)
+		require.Equal(t, server.address(), req.Host)
+		require.Equal(t, "Basic dXNlcjpzZWNyZXQ=", req.Header.Get("Proxy-Authorization"))
+
+		config := clientConfig()
+		sshConn, channels, globalRequests, err := ssh.NewClientConn(conn, server.address(), &config)
+		require.NoError(t, err)
+		client := &Client{Client: ssh.NewClient(sshConn, channels, globalRequests)}
+		defer client.Close()
+
+		_, output, err := client.ExecuteCommandWithOutput("through the proxy")
+		require.NoError(t, err)
+		require.Equal(t, "through the proxy", output)
+	})
+
+	t.Run("trusts the given CA certificates for an https:// proxy", func(t *testing.T) {
+		server := newTestServer(t)
+		proxyURL, requests, pool := tlsConnectProxy(t)
+
+		conn, err := dialThroughProxy(proxyURL, server.address(), 0, &tls.Config{RootCAs: pool})
+		require.NoError(t, err)
+		defer conn.Close()
+
+		req := <-requests
+		require.Equal(t, server.address(), req.Host)
+
+		config := clientConfig()
+		sshConn, channels, globalRequests, err := ssh.NewClientConn(conn, server.address(), &config)
+		require.NoError(t, err)
+		client := &Client{Client: ssh.NewClient(sshConn, channels, globalRequests)}
+		defer client.Close()
+
+		_, output, err := client.ExecuteCommandWithOutput("through the TLS proxy")
+		require.NoError(t, err)
+		require.Equal(t, "through the TLS proxy", output)
+	})
+
+	t.Run("rejects an https:// proxy with an untrusted certificate", func(t *testing.T) {
+		proxyURL, _, _ := tlsConnectProxy(t)
+
+		_, err := dialThroughProxy(proxyURL, "agent.example.com:22", 0, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unable to establish TLS with proxy")
+	})
+
+	t.Run("reports a refused CONNECT", func(t *testing.T) {
+		proxyURL, _ := connectProxy(t, http.StatusForbidden)
+
+		_, err := dialThroughProxy(proxyURL, "agent.example.com:22", 0, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "403 Forbidden")
+		require.NotContains(t, err.Error(), "secret")
+	})
+
+	t.Run("rejects unsupported schemes", func(t *testing.T) {
+		_, err := dialThroughProxy(&url.URL{Scheme: "ftp", Host: "proxy.internal:21"}, "agent.example.com:22", 0, nil)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), `unsupported proxy scheme "ftp"`)
+	})
+}
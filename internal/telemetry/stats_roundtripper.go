@@ -4,18 +4,23 @@ import (
 	"net/http"
 	"sync"
 	"time"
+
+	"github.com/rwx-cloud/rwx/internal/retry"
 )
 
-// CallStats records a single API round-trip.
+// CallStats records a single API round-trip. Retry is set for the attempts
+// after the first at a retried request.
 type CallStats struct {
 	Path       string `json:"path"`
 	Method     string `json:"method"`
 	StatusCode int    `json:"status_code"`
 	DurationMS int64  `json:"duration_ms"`
+	Retry      bool   `json:"retry"`
 }
 
 // StatsRoundTripper wraps an http.RoundTripper and accumulates per-call stats.
 // At flush time, call RecordSummary to emit an aggregated api.summary event.
+// Inside a retry.RoundTripper, each attempt is recorded as a call.
 type StatsRoundTripper struct {
 	http.RoundTripper
 	mu    sync.Mutex
@@ -45,6 +50,7 @@ func (s *StatsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 			Method:     req.Method,
 			StatusCode: 0,
 			DurationMS: durationMS,
+			Retry:      retry.Attempt(req) > 1,
 		}
 		s.mu.Lock()
 		s.calls = append(s.calls, cs)
@@ -57,6 +63,7 @@ func (s *StatsRoundTripper) RoundTrip(req *http.Request) (*http.Response, error)
 		Method:     req.Method,
 		StatusCode: resp.StatusCode,
 		DurationMS: durationMS,
+		Retry:      retry.Attempt(req) > 1,
 	}
 
 	s.mu.Lock()
@@ -73,6 +80,7 @@ type AggregatedCall struct {
 	Count       int    `json:"count"`
 	StatusCodes []int  `json:"status_codes"`
 	TotalMS     int64  `json:"total_ms"`
+	Retries     int    `json:"retries"`
 }
 
 // RecordSummary drains accumulated stats and records an api.summary event
@@ -110,6 +118,9 @@ func (s *StatsRoundTripper) RecordSummary(collector *Collector) {
 		entry.Count++
 		entry.TotalMS += c.DurationMS
 		entry.StatusCodes = append(entry.StatusCodes, c.StatusCode)
+		if c.Retry {
+			entry.Retries++
+		}
 	}
 
 	result := make([]AggregatedCall, 0, len(order))
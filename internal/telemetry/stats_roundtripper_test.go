@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/rwx-cloud/rwx/internal/retry"
 	"github.com/stretchr/testify/require"
 )
 
@@ -171,3 +172,35 @@ func TestStatsRoundTripper_PreservesPathOrder(t *testing.T) {
 	require.Equal(t, "/api/auth/whoami", calls[1].Path)
 	require.Equal(t, 1, calls[1].Count)
 }
+
+func TestStatsRoundTripper_RecordsRetries(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	srt := NewStatsRoundTripper(&testRoundTripper{server: server})
+	rt := retry.NewRoundTripper(srt)
+
+	req, _ := http.NewRequest(http.MethodGet, "/mint/api/runs", nil)
+	resp, err := rt.RoundTrip(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	collector := NewCollector()
+	srt.RecordSummary(collector)
+
+	events := collector.Drain()
+	calls := events[0].Props["calls"].([]AggregatedCall)
+	require.Len(t, calls, 1)
+	require.Equal(t, 3, calls[0].Count)
+	require.Equal(t, 2, calls[0].Retries)
+	require.Equal(t, []int{503, 503, 200}, calls[0].StatusCodes)
+}
@@ -0,0 +1,80 @@
+// Package transport builds the HTTP transport shared by every client that
+// talks to RWX, so that proxies and custom certificates apply to all of them.
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/rwx-cloud/rwx/internal/errors"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+type Config struct {
+	// CACertFile is a PEM bundle of certificates to trust in addition to the
+	// system's, such as the certificate of a TLS-inspecting proxy
+	CACertFile string
+	// ClientCertFile and ClientKeyFile are a PEM certificate and key to
+	// present to servers that require client certificates (mTLS)
+	ClientCertFile string
+	ClientKeyFile  string
+}
+
+func (c Config) Validate() error {
+	if (c.ClientCertFile == "") != (c.ClientKeyFile == "") {
+		return errors.New("a client certificate and a client key must be provided together")
+	}
+
+	return nil
+}
+
+// New returns a transport that sends requests through the proxy from
+// HTTPS_PROXY, HTTP_PROXY and NO_PROXY (or their lowercase forms), and that
+// uses the certificates from cfg.
+func New(cfg Config) (*http.Transport, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "validation failed")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	// http.ProxyFromEnvironment reads the environment only once per process,
+	// so read it here instead
+	proxyFunc := httpproxy.FromEnvironment().ProxyFunc()
+	transport.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if cfg.CACertFile != "" {
+		pem, err := os.ReadFile(cfg.CACertFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read CA certificates from %q", cfg.CACertFile)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.Errorf("no PEM certificates found in %q", cfg.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if cfg.ClientCertFile != "" {
+		certificate, err := tls.LoadX509KeyPair(cfg.ClientCertFile, cfg.ClientKeyFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to load client certificate %q", cfg.ClientCertFile)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
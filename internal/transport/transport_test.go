@@ -0,0 +1,137 @@
+package transport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeCertificatePEM(t *testing.T, cert *x509.Certificate) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0o600))
+	return file
+}
+
+func generateClientCertificate(t *testing.T) (*x509.Certificate, string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "rwx-test-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return cert, certFile, keyFile
+}
+
+func get(t *testing.T, transport *http.Transport, url string) error {
+	t.Helper()
+	resp, err := (&http.Client{Transport: transport}).Get(url)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	t.Run("trusts certificates from the CA file", func(t *testing.T) {
+		server := httptest.NewTLSServer(ok)
+		defer server.Close()
+
+		transport, err := New(Config{})
+		require.NoError(t, err)
+		require.Error(t, get(t, transport, server.URL))
+
+		transport, err = New(Config{CACertFile: writeCertificatePEM(t, server.Certificate())})
+		require.NoError(t, err)
+		require.NoError(t, get(t, transport, server.URL))
+	})
+
+	t.Run("presents the client certificate", func(t *testing.T) {
+		clientCert, certFile, keyFile := generateClientCertificate(t)
+
+		server := httptest.NewUnstartedServer(ok)
+		clientCAs := x509.NewCertPool()
+		clientCAs.AddCert(clientCert)
+		server.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+		server.StartTLS()
+		defer server.Close()
+		caFile := writeCertificatePEM(t, server.Certificate())
+
+		transport, err := New(Config{CACertFile: caFile})
+		require.NoError(t, err)
+		require.Error(t, get(t, transport, server.URL))
+
+		transport, err = New(Config{CACertFile: caFile, ClientCertFile: certFile, ClientKeyFile: keyFile})
+		require.NoError(t, err)
+		require.NoError(t, get(t, transport, server.URL))
+	})
+
+	t.Run("uses the proxy from the environment", func(t *testing.T) {
+		t.Setenv("HTTPS_PROXY", "http://proxy.example.com:3128")
+		t.Setenv("NO_PROXY", "internal.example.com")
+
+		transport, err := New(Config{})
+		require.NoError(t, err)
+
+		proxyURL, err := transport.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "cloud.rwx.com"}})
+		require.NoError(t, err)
+		require.Equal(t, "http://proxy.example.com:3128", proxyURL.String())
+
+		proxyURL, err = transport.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "internal.example.com"}})
+		require.NoError(t, err)
+		require.Nil(t, proxyURL)
+	})
+
+	t.Run("errors when the CA file has no certificates", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "empty.pem")
+		require.NoError(t, os.WriteFile(file, []byte("not a certificate"), 0o600))
+
+		_, err := New(Config{CACertFile: file})
+		require.ErrorContains(t, err, "no PEM certificates found")
+	})
+
+	t.Run("requires a client key with a client certificate", func(t *testing.T) {
+		_, err := New(Config{ClientCertFile: "client.pem"})
+		require.ErrorContains(t, err, "a client certificate and a client key must be provided together")
+	})
+}
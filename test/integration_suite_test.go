@@ -4,14 +4,18 @@ import (
 	"bytes"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/rwx-cloud/rwx/internal/fakecloud"
 	"github.com/stretchr/testify/require"
 )
 
 type input struct {
 	args []string
+	env  []string
 }
 
 type result struct {
@@ -26,8 +30,11 @@ func mintCmd(t *testing.T, input input) *exec.Cmd {
 	require.NoError(t, err, "integration tests depend on a built rwx binary at %s", rwxPath)
 
 	cmd := exec.Command(rwxPath, input.args...)
+	if input.env != nil {
+		cmd.Env = append(os.Environ(), input.env...)
+	}
 
-	t.Logf("Executing command: %s\n with env %s\n", cmd.String(), cmd.Env)
+	t.Logf("Executing command: %s\n with env %s\n", cmd.String(), input.env)
 
 	return cmd
 }
@@ -112,3 +119,103 @@ func TestMintRun(t *testing.T) {
 		require.Contains(t, result.stderr, "positional arguments are not supported")
 	})
 }
+
+// fakeCloud starts a fake RWX Cloud and returns the environment for rwx to
+// use it, with a config directory of its own.
+func fakeCloud(t *testing.T) (*fakecloud.Server, []string) {
+	server := fakecloud.New()
+	t.Cleanup(server.Close)
+
+	home := t.TempDir()
+	caCert := filepath.Join(home, "ca.pem")
+	require.NoError(t, server.WriteCACert(caCert))
+
+	return server, []string{
+		"HOME=" + home,
+		"RWX_HOST=" + server.Host(),
+		"RWX_CA_BUNDLE=" + caCert,
+		"RWX_ACCESS_TOKEN=" + fakecloud.DefaultAccessToken,
+	}
+}
+
+func TestFakeCloud(t *testing.T) {
+	t.Run("reports who is logged in", func(t *testing.T) {
+		_, env := fakeCloud(t)
+
+		result := runMint(t, input{args: []string{"whoami", "--output", "json"}, env: env})
+
+		require.Equal(t, 0, result.exitCode, result.stderr)
+		require.Contains(t, result.stdout, `"organization_slug":"fake-org"`)
+	})
+
+	t.Run("sets and shows vars", func(t *testing.T) {
+		server, env := fakeCloud(t)
+
+		result := runMint(t, input{args: []string{"vaults", "vars", "set", "REGION=us-east-1"}, env: env})
+		require.Equal(t, 0, result.exitCode, result.stderr)
+
+		result = runMint(t, input{args: []string{"vaults", "vars", "show", "REGION"}, env: env})
+		require.Equal(t, 0, result.exitCode, result.stderr)
+		require.Contains(t, result.stdout, "us-east-1")
+
+		server.View(func(state *fakecloud.State) {
+			require.Equal(t, "us-east-1", state.Vaults["default"].Vars["REGION"])
+		})
+
+		result = runMint(t, input{args: []string{"vaults", "vars", "show", "MISSING"}, env: env})
+		require.Equal(t, 1, result.exitCode)
+		require.Contains(t, result.stderr, `var "MISSING" not found`)
+	})
+
+	t.Run("waits for a run's results", func(t *testing.T) {
+		server, env := fakeCloud(t)
+		runID := server.AddRun(fakecloud.Run{Result: "succeeded", PendingPolls: 2})
+
+		result := runMint(t, input{args: []string{"results", runID, "--wait", "--output", "json"}, env: env})
+
+		require.Equal(t, 0, result.exitCode, result.stderr)
+		require.Contains(t, result.stdout, `"ResultStatus":"succeeded"`)
+	})
+	t.Run("retries while Cloud is unavailable", func(t *testing.T) {
+		server, env := fakeCloud(t)
+		server.Update(func(state *fakecloud.State) {
+			state.Unavailable = 2
+		})
+
+		result := runMint(t, input{args: []string{"vaults", "vars", "set", "REGION=us-east-1"}, env: env})
+		require.Equal(t, 0, result.exitCode, result.stderr)
+
+		server.View(func(state *fakecloud.State) {
+			require.Equal(t, 0, state.Unavailable)
+			require.Equal(t, "us-east-1", state.Vaults["default"].Vars["REGION"])
+		})
+	})
+
+	t.Run("stops waiting when interrupted", func(t *testing.T) {
+		server, env := fakeCloud(t)
+		server.Update(func(state *fakecloud.State) {
+			state.PollBackoffMs = 60_000
+		})
+		runID := server.AddRun(fakecloud.Run{})
+
+		cmd := mintCmd(t, input{args: []string{"results", runID, "--wait"}, env: env})
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		require.NoError(t, cmd.Start())
+
+		require.Eventually(t, func() bool {
+			for _, request := range server.Requests() {
+				if request.Path == "/mint/api/runs/"+runID {
+					return true
+				}
+			}
+			return false
+		}, 10*time.Second, 10*time.Millisecond)
+		require.NoError(t, cmd.Process.Signal(os.Interrupt))
+
+		err := cmd.Wait()
+		exitErr, ok := err.(*exec.ExitError)
+		require.True(t, ok, "rwx exited with an error that wasn't an ExitError: %v", err)
+		require.Equal(t, 130, exitErr.ExitCode(), stderr.String())
+	})
+}